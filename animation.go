@@ -0,0 +1,327 @@
+package furex
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keyframes is an animation declared by a @keyframes rule.
+type Keyframes struct {
+	Name   string
+	Frames []Keyframe
+}
+
+// Keyframe is the style of an animation at an offset from 0 to 1,
+// e.g. {Offset: 0.5, Style: "opacity: 0.2"} for `50% { opacity: 0.2 }`.
+// The properties that can be transitioned can be animated.
+type Keyframe struct {
+	Offset float64
+	Style  string
+
+	// values are the values of the animatable properties of the style.
+	values map[string][]float64
+}
+
+// AnimationInfinite is the iteration count of animations repeating forever.
+const AnimationInfinite = -1
+
+// Animation plays keyframes on a view while it is updated, like the CSS
+// animation property. Properties missing in the first or the last
+// keyframe animate from or to the value of the view, which is restored
+// when the animation finishes or is removed.
+type Animation struct {
+	Keyframes *Keyframes
+	Duration  time.Duration
+	Delay     time.Duration
+	// Easing is the timing function between keyframes, as in Transition.
+	Easing string
+	// Iterations is the number of times the animation plays. Zero plays
+	// it once, and AnimationInfinite repeats it forever.
+	Iterations int
+	// Alternate plays every other iteration backwards.
+	Alternate bool
+}
+
+// animationState is the state of the animation playing on a view.
+type animationState struct {
+	anim Animation
+	tick int
+	// base are the values of the animated properties before the animation.
+	base     map[string][]float64
+	finished bool
+	// iteration is the index of the iteration being played.
+	iteration int
+}
+
+// animatedValues returns the values of the animatable properties of the keyframe.
+func (k *Keyframe) animatedValues() map[string][]float64 {
+	if k.values != nil {
+		return k.values
+	}
+	decls := parseDecls(k.Style)
+	props := map[string]bool{}
+	for _, d := range decls {
+		props[d.property] = true
+	}
+	scratch := &View{}
+	applyDecls(scratch, decls)
+	k.values = map[string][]float64{}
+	for name, prop := range animatables {
+		if isRelatedProp(name, props) {
+			k.values[name] = prop.get(scratch)
+		}
+	}
+	return k.values
+}
+
+// advanceAnimation advances the animation of the view by one tick.
+func (v *View) advanceAnimation() {
+	s := v.animation
+	if s != nil && (v.Animation == nil || *v.Animation != s.anim) {
+		// the animation was removed or replaced
+		if !s.finished {
+			v.restoreAnimationBase()
+		}
+		v.animation, s = nil, nil
+	}
+	if v.Animation == nil || v.Animation.Keyframes == nil {
+		return
+	}
+	if s == nil {
+		s = &animationState{anim: *v.Animation, base: map[string][]float64{}}
+		for _, k := range s.anim.Keyframes.Frames {
+			for name := range k.animatedValues() {
+				if _, ok := s.base[name]; !ok {
+					s.base[name] = animatables[name].get(v)
+				}
+			}
+		}
+		v.animation = s
+	}
+	if s.finished {
+		return
+	}
+	s.tick++
+	a := &s.anim
+	delay, ticks := durationTicks(a.Delay), durationTicks(a.Duration)
+	if s.tick <= delay {
+		return
+	}
+	name := a.Keyframes.Name
+	if s.tick == delay+1 {
+		v.emitAnimationStart(name)
+	}
+	elapsed := 1.0
+	if ticks > 0 {
+		elapsed = float64(s.tick-delay) / float64(ticks)
+	}
+	iterations := float64(a.Iterations)
+	if a.Iterations == 0 {
+		iterations = 1
+	}
+	if a.Iterations != AnimationInfinite && elapsed >= iterations {
+		s.finished = true
+		v.restoreAnimationBase()
+		v.emitAnimationEnd(name)
+		return
+	}
+	iteration := math.Floor(elapsed)
+	if int(iteration) > s.iteration {
+		s.iteration = int(iteration)
+		v.emitAnimationIteration(name, s.iteration)
+	}
+	progress := elapsed - iteration
+	if a.Alternate && int(iteration)%2 == 1 {
+		progress = 1 - progress
+	}
+	v.setAnimationValues(s.values(progress, easingFunc(a.Easing)))
+}
+
+func (v *View) restoreAnimationBase() {
+	v.setAnimationValues(v.animation.base)
+}
+
+func (v *View) setAnimationValues(values map[string][]float64) {
+	relayout := false
+	for name, val := range values {
+		prop := animatables[name]
+		prop.set(v, val)
+		if v.transitions != nil {
+			// animated values are not transitioned
+			if _, ok := v.transitions.shown[name]; ok {
+				v.transitions.shown[name] = prop.get(v)
+			}
+		}
+		relayout = relayout || prop.layout
+	}
+	if relayout {
+		v.Layout()
+	}
+}
+
+// values returns the values of the animated properties at the progress
+// of an iteration.
+func (s *animationState) values(progress float64, easing func(float64) float64) map[string][]float64 {
+	ret := map[string][]float64{}
+	for name, base := range s.base {
+		// the keyframes of the property, with the base value at the
+		// ends if they are missing
+		var offsets []float64
+		var vals [][]float64
+		for _, k := range s.anim.Keyframes.Frames {
+			if val, ok := k.animatedValues()[name]; ok {
+				offsets = append(offsets, k.Offset)
+				vals = append(vals, val)
+			}
+		}
+		if offsets[0] > 0 {
+			offsets = append([]float64{0}, offsets...)
+			vals = append([][]float64{base}, vals...)
+		}
+		if offsets[len(offsets)-1] < 1 {
+			offsets = append(offsets, 1)
+			vals = append(vals, base)
+		}
+		i := sort.SearchFloat64s(offsets, progress)
+		switch {
+		case i == 0:
+			ret[name] = vals[0]
+		case i == len(offsets):
+			ret[name] = vals[len(vals)-1]
+		default:
+			from, to := offsets[i-1], offsets[i]
+			t := 1.0
+			if to > from {
+				t = easing((progress - from) / (to - from))
+			}
+			ret[name] = interpolate(vals[i-1], vals[i], t)
+		}
+	}
+	return ret
+}
+
+// IsAnimating returns true if the animation of the view is playing.
+func (v *View) IsAnimating() bool {
+	return v.animation != nil && !v.animation.finished
+}
+
+// parseKeyframes parses the body of a @keyframes rule.
+func parseKeyframes(name, body string) (*Keyframes, error) {
+	kf := &Keyframes{Name: name}
+	for {
+		open := strings.IndexByte(body, '{')
+		if open == -1 {
+			break
+		}
+		end := matchingBrace(body, open)
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed keyframe in %s", name)
+		}
+		style := strings.TrimSpace(body[open+1 : end])
+		for _, sel := range strings.Split(body[:open], ",") {
+			offset, err := parseKeyframeOffset(strings.TrimSpace(sel))
+			if err != nil {
+				return nil, fmt.Errorf("@keyframes %s: %w", name, err)
+			}
+			kf.Frames = append(kf.Frames, Keyframe{Offset: offset, Style: style})
+		}
+		body = body[end+1:]
+	}
+	sort.SliceStable(kf.Frames, func(i, j int) bool {
+		return kf.Frames[i].Offset < kf.Frames[j].Offset
+	})
+	return kf, nil
+}
+
+func parseKeyframeOffset(s string) (float64, error) {
+	switch s {
+	case "from":
+		return 0, nil
+	case "to":
+		return 1, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		if p, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64); err == nil && p >= 0 && p <= 100 {
+			return p / 100, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid keyframe selector: %s", s)
+}
+
+// cssAnimation is an animation property before the keyframes are resolved.
+type cssAnimation struct {
+	name string
+	anim Animation
+}
+
+// parseAnimation parses `<name> <duration> [<easing>] [<delay>]
+// [<iterations> | infinite] [alternate | normal]` or `none`.
+func parseAnimation(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "none" {
+		return cssAnimation{}, nil
+	}
+	ret := cssAnimation{}
+	durations := 0
+	for _, f := range strings.Fields(val) {
+		if d, err := time.ParseDuration(f); err == nil {
+			if durations == 0 {
+				ret.anim.Duration = d
+			} else {
+				ret.anim.Delay = d
+			}
+			durations++
+			continue
+		}
+		if _, ok := easings[f]; ok {
+			ret.anim.Easing = f
+			continue
+		}
+		if n, err := strconv.Atoi(f); err == nil && n > 0 {
+			ret.anim.Iterations = n
+			continue
+		}
+		switch f {
+		case "infinite":
+			ret.anim.Iterations = AnimationInfinite
+		case "alternate":
+			ret.anim.Alternate = true
+		case "normal":
+			ret.anim.Alternate = false
+		default:
+			if ret.name != "" {
+				return nil, fmt.Errorf("invalid animation: %s", val)
+			}
+			ret.name = f
+		}
+	}
+	if ret.name == "" {
+		return nil, fmt.Errorf("invalid animation: %s", val)
+	}
+	return ret, nil
+}
+
+// setAnimation sets the animation with the keyframes of the stylesheet
+// of the view.
+func (v *View) setAnimation(a cssAnimation) {
+	if a.name == "" {
+		v.Animation = nil
+		return
+	}
+	var kf *Keyframes
+	if v.style != nil && v.style.sheet != nil {
+		kf = v.style.sheet.keyframes[a.name]
+	}
+	if kf == nil {
+		println(fmt.Sprintf("animation: unknown keyframes: %s", a.name))
+		v.Animation = nil
+		return
+	}
+	anim := a.anim
+	anim.Keyframes = kf
+	v.Animation = &anim
+}
@@ -0,0 +1,92 @@
+package furex
+
+// animationEvents are the callbacks of the transitions and the animation
+// of a view.
+type animationEvents struct {
+	transitionStart    []func(property string)
+	transitionEnd      []func(property string)
+	animationStart     []func(name string)
+	animationIteration []func(name string, iteration int)
+	animationEnd       []func(name string)
+}
+
+// OnTransitionStart registers a function called with the property when a
+// transition of the view starts, i.e. when the property changes.
+func (v *View) OnTransitionStart(fn func(property string)) {
+	v.animationEventsOf().transitionStart = append(v.animationEventsOf().transitionStart, fn)
+}
+
+// OnTransitionEnd registers a function called with the property when a
+// transition of the view completes, e.g. to remove a toast after it has
+// faded out. A transition interrupted by another change of the property
+// or by the removal of the transitions does not end.
+func (v *View) OnTransitionEnd(fn func(property string)) {
+	v.animationEventsOf().transitionEnd = append(v.animationEventsOf().transitionEnd, fn)
+}
+
+// OnAnimationStart registers a function called with the name of the
+// keyframes when the animation of the view starts playing after its delay.
+func (v *View) OnAnimationStart(fn func(name string)) {
+	v.animationEventsOf().animationStart = append(v.animationEventsOf().animationStart, fn)
+}
+
+// OnAnimationIteration registers a function called with the name of the
+// keyframes and the index of the iteration when an iteration of the
+// animation of the view starts, from the second one.
+func (v *View) OnAnimationIteration(fn func(name string, iteration int)) {
+	v.animationEventsOf().animationIteration = append(v.animationEventsOf().animationIteration, fn)
+}
+
+// OnAnimationEnd registers a function called with the name of the
+// keyframes when the animation of the view finishes. An animation removed
+// or replaced before it finishes does not end.
+func (v *View) OnAnimationEnd(fn func(name string)) {
+	v.animationEventsOf().animationEnd = append(v.animationEventsOf().animationEnd, fn)
+}
+
+func (v *View) animationEventsOf() *animationEvents {
+	if v.animationEvents == nil {
+		v.animationEvents = &animationEvents{}
+	}
+	return v.animationEvents
+}
+
+func (v *View) emitTransitionStart(property string) {
+	if e := v.animationEvents; e != nil {
+		for _, fn := range e.transitionStart {
+			fn(property)
+		}
+	}
+}
+
+func (v *View) emitTransitionEnd(property string) {
+	if e := v.animationEvents; e != nil {
+		for _, fn := range e.transitionEnd {
+			fn(property)
+		}
+	}
+}
+
+func (v *View) emitAnimationStart(name string) {
+	if e := v.animationEvents; e != nil {
+		for _, fn := range e.animationStart {
+			fn(name)
+		}
+	}
+}
+
+func (v *View) emitAnimationIteration(name string, iteration int) {
+	if e := v.animationEvents; e != nil {
+		for _, fn := range e.animationIteration {
+			fn(name, iteration)
+		}
+	}
+}
+
+func (v *View) emitAnimationEnd(name string) {
+	if e := v.animationEvents; e != nil {
+		for _, fn := range e.animationEnd {
+			fn(name)
+		}
+	}
+}
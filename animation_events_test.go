@@ -0,0 +1,62 @@
+package furex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitionEvents(t *testing.T) {
+	box := &View{Transitions: []Transition{{Property: "all", Duration: 100 * time.Millisecond}}}
+	var events []string
+	box.OnTransitionStart(func(property string) { events = append(events, "start "+property) })
+	box.OnTransitionEnd(func(property string) { events = append(events, "end "+property) })
+	box.updateAnimations()
+
+	box.Opacity = Float(0)
+	box.Width = 10
+	box.updateAnimations()
+	require.Equal(t, []string{"start opacity", "start width"}, events)
+
+	// a change in the middle restarts the transition without ending it
+	box.Opacity = Float(0.5)
+	box.updateAnimations()
+	require.Equal(t, []string{"start opacity", "start width", "start opacity"}, events)
+
+	events = nil
+	for box.IsTransitioning() {
+		box.updateAnimations()
+	}
+	require.Equal(t, []string{"end width", "end opacity"}, events)
+}
+
+func TestAnimationEvents(t *testing.T) {
+	box := &View{Animation: &Animation{
+		Keyframes:  &Keyframes{Name: "blink", Frames: []Keyframe{{Offset: 0, Style: "opacity: 0"}}},
+		Duration:   100 * time.Millisecond,
+		Delay:      100 * time.Millisecond,
+		Iterations: 3,
+	}}
+	var events []string
+	box.OnAnimationStart(func(name string) { events = append(events, "start "+name) })
+	box.OnAnimationIteration(func(name string, iteration int) {
+		events = append(events, fmt.Sprintf("iteration %s %d", name, iteration))
+	})
+	box.OnAnimationEnd(func(name string) { events = append(events, "end "+name) })
+
+	ticks := ebiten.TPS() / 10
+	for i := 0; i < ticks; i++ {
+		box.updateAnimations()
+	}
+	require.Empty(t, events)
+	box.updateAnimations()
+	require.Equal(t, []string{"start blink"}, events)
+
+	for box.IsAnimating() {
+		box.updateAnimations()
+	}
+	require.Equal(t, []string{"start blink", "iteration blink 1", "iteration blink 2", "end blink"}, events)
+}
@@ -0,0 +1,98 @@
+package furex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyframes(t *testing.T) {
+	sheet, err := parseStylesheet(`
+		@keyframes blink {
+			to { opacity: 0 }
+			from, 50% { opacity: 1; width: 10px }
+		}
+		@keyframes broken { half { opacity: 0 } }`)
+	require.Error(t, err)
+	kf := sheet.keyframes["blink"]
+	require.NotNil(t, kf)
+	require.Equal(t, []float64{0, 0.5, 1}, []float64{kf.Frames[0].Offset, kf.Frames[1].Offset, kf.Frames[2].Offset})
+	require.Equal(t, map[string][]float64{"opacity": {1}, "width": {10}}, kf.Frames[0].animatedValues())
+	require.Nil(t, sheet.keyframes["broken"])
+}
+
+func TestParseAnimation(t *testing.T) {
+	val, err := parseAnimation("pulse 1s ease-in 200ms infinite alternate")
+	require.NoError(t, err)
+	require.Equal(t, cssAnimation{name: "pulse", anim: Animation{
+		Duration: time.Second, Delay: 200 * time.Millisecond, Easing: "ease-in",
+		Iterations: AnimationInfinite, Alternate: true,
+	}}, val)
+
+	val, err = parseAnimation("pulse 1s 3")
+	require.NoError(t, err)
+	require.Equal(t, 3, val.(cssAnimation).anim.Iterations)
+
+	_, err = parseAnimation("pulse blink 1s")
+	require.Error(t, err)
+	_, err = parseAnimation("1s")
+	require.Error(t, err)
+}
+
+func TestAnimation(t *testing.T) {
+	root := Parse(`
+		<style>
+			@keyframes grow { 50% { width: 30px } }
+			.box { width: 10px; height: 10px; animation: grow 100ms linear 2 }
+		</style>
+		<view style="width: 100px; height: 100px"><view id="box" class="box"></view></view>`, nil)
+	box := root.MustGetByID("box")
+	require.NotNil(t, box.Animation)
+	require.Equal(t, "grow", box.Animation.Keyframes.Name)
+
+	ticks := durationTicks(100 * time.Millisecond)
+	var widths []int
+	for i := 0; i < ticks*2-1; i++ {
+		root.updateAnimations()
+		widths = append(widths, box.Width)
+	}
+	require.True(t, box.IsAnimating())
+	// the width grows to 30px at the middle and shrinks back to the base
+	require.Equal(t, 30, widths[ticks/2-1])
+	require.Equal(t, widths[ticks/2-1], widths[ticks+ticks/2-1])
+	require.Equal(t, 10, widths[ticks-1])
+
+	root.updateAnimations()
+	require.False(t, box.IsAnimating())
+	require.Equal(t, 10, box.Width)
+}
+
+func TestAnimationAlternate(t *testing.T) {
+	box := &View{Animation: &Animation{
+		Keyframes:  &Keyframes{Frames: []Keyframe{{Offset: 0, Style: "opacity: 0"}}},
+		Duration:   time.Second,
+		Easing:     "linear",
+		Iterations: AnimationInfinite,
+		Alternate:  true,
+	}}
+	tps := ebiten.TPS()
+	for i := 0; i < tps/2; i++ {
+		box.updateAnimations()
+	}
+	require.InDelta(t, 0.5, *box.Opacity, 1e-9)
+	for i := 0; i < tps; i++ {
+		box.updateAnimations()
+	}
+	// backwards in the second iteration
+	require.InDelta(t, 0.5, *box.Opacity, 1e-9)
+	box.updateAnimations()
+	require.Less(t, *box.Opacity, 0.5)
+
+	// the base value is restored when the animation is removed
+	box.Animation = nil
+	box.updateAnimations()
+	require.Equal(t, 1.0, *box.Opacity)
+	require.False(t, box.IsAnimating())
+}
@@ -0,0 +1,113 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// drawBackdrop draws what is drawn behind the frame, blurred by
+// BackdropBlur, under the background of the view, e.g. for the frosted
+// glass of pause menus. The backdrop is copied from BackdropSource or
+// from the screen, so it must be drawn before the shadow of the view.
+func (v *View) drawBackdrop(screen *ebiten.Image, frame image.Rectangle, opacity float64) {
+	if v.BackdropBlur <= 0 || frame.Empty() || screen == nil {
+		v.releaseBackdropImages()
+		return
+	}
+	target := targetRect(frame)
+	if target.Empty() {
+		return
+	}
+	src := v.BackdropSource
+	if src == nil {
+		src = screen
+	}
+	levels := v.backdropLevels(target, batch.Transform.Length(v.BackdropBlur))
+	batch.Flush()
+
+	// the pixels behind the frame are copied to the first level in the
+	// coordinates of the screen
+	levels[0].Clear()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(src.Bounds().Min.X), float64(src.Bounds().Min.Y))
+	levels[0].DrawImage(src, op)
+
+	// halving the image and scaling it back up with the linear filter
+	// blurs it with the radius of the smallest level
+	for i := 1; i < len(levels); i++ {
+		levels[i].Clear()
+		levels[i].DrawImage(levels[i-1], scaleOptions(levels[i-1], levels[i]))
+	}
+	for i := len(levels) - 2; i > 0; i-- {
+		levels[i].Clear()
+		levels[i].DrawImage(levels[i+1], scaleOptions(levels[i+1], levels[i]))
+	}
+	op = scaleOptions(levels[1], levels[0])
+	op.GeoM.Translate(float64(target.Min.X), float64(target.Min.Y))
+	o := float32(opacity)
+	op.ColorScale.Scale(o, o, o, o)
+	levels[0].Clear()
+	levels[0].DrawImage(levels[1], op)
+
+	if v.BorderRadius > 0 {
+		batch.DrawRoundedImage(screen, levels[0], frame, v.BorderRadius)
+		return
+	}
+	batch.DrawImage(screen, levels[0], levels[0].Bounds(), frame, nil)
+}
+
+// backdropLevels returns the images to blur the backdrop of the target
+// rect: the first one has the bounds of the rect, and each of the others
+// is half the size of the previous one, down to 1/radius of the size.
+func (v *View) backdropLevels(target image.Rectangle, radius int) []*ebiten.Image {
+	n := 2
+	for 1<<(n-1) < radius {
+		n++
+	}
+	if len(v.backdropImages) == n && v.backdropImages[0].Bounds() == target {
+		return v.backdropImages
+	}
+	v.releaseBackdropImages()
+	v.backdropImages = []*ebiten.Image{ebiten.NewImageWithOptions(target, nil)}
+	for size := target.Size(); len(v.backdropImages) < n; {
+		size = image.Pt((size.X+1)/2, (size.Y+1)/2)
+		v.backdropImages = append(v.backdropImages, ebiten.NewImage(size.X, size.Y))
+	}
+	return v.backdropImages
+}
+
+func (v *View) releaseBackdropImages() {
+	for _, img := range v.backdropImages {
+		img.Dispose()
+	}
+	v.backdropImages = nil
+}
+
+// scaleOptions returns the options to draw the image from stretched to
+// the size of the image to with the linear filter.
+func scaleOptions(from, to *ebiten.Image) *ebiten.DrawImageOptions {
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(
+		float64(to.Bounds().Dx())/float64(from.Bounds().Dx()),
+		float64(to.Bounds().Dy())/float64(from.Bounds().Dy()))
+	return op
+}
+
+// parseBackdropFilter parses `blur(<radius>)` and `none`.
+func parseBackdropFilter(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "none" {
+		return 0, nil
+	}
+	if !strings.HasPrefix(val, "blur(") || !strings.HasSuffix(val, ")") {
+		return nil, fmt.Errorf("invalid backdrop-filter: %s", val)
+	}
+	n, err := parseNumber(strings.TrimSpace(val[len("blur(") : len(val)-1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid backdrop-filter: %s", val)
+	}
+	return n, nil
+}
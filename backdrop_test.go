@@ -0,0 +1,55 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackdropFilter(t *testing.T) {
+	for val, want := range map[string]int{"blur(8px)": 8, "blur( 3 )": 3, "none": 0} {
+		got, err := parseBackdropFilter(val)
+		require.NoError(t, err, val)
+		require.Equal(t, want, got, val)
+	}
+	for _, val := range []string{"blur()", "blur(8px", "grayscale(1)"} {
+		_, err := parseBackdropFilter(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestBackdrop(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px; background-color: #00f">
+		<view id="panel" style="width: 40px; height: 30px; margin-left: 10px; backdrop-filter: blur(8px); border-radius: 4px"></view>
+	</view>`, nil)
+	panel := root.MustGetByID("panel")
+	require.Equal(t, 8, panel.BackdropBlur)
+
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+	// the backdrop is copied in the coordinates of the screen and halved
+	// down to 1/8 of the size
+	require.Len(t, panel.backdropImages, 4)
+	require.Equal(t, image.Rect(10, 0, 50, 30), panel.backdropImages[0].Bounds())
+	require.Equal(t, image.Rect(0, 0, 5, 4), panel.backdropImages[3].Bounds())
+	require.Equal(t, int64((40*30+20*15+10*8+5*4)*4), panel.MemoryUsage().ImageBytes)
+
+	// the images are reused
+	images := panel.backdropImages
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Equal(t, images, panel.backdropImages)
+
+	// the source is blurred instead of the screen
+	panel.BackdropSource = ebiten.NewImage(100, 100)
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Equal(t, images, panel.backdropImages)
+
+	panel.Release()
+	require.Nil(t, panel.backdropImages)
+
+	panel.BackdropBlur = 0
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Nil(t, panel.backdropImages)
+}
@@ -0,0 +1,173 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// BackgroundRepeat is the 'background-repeat' property.
+// It decides how BackgroundImage fills the frame of the view.
+type BackgroundRepeat uint8
+
+const (
+	// BackgroundStretch stretches the image to the frame, keeping the
+	// corners of BackgroundSlice unscaled (nine-slice) if it is set.
+	BackgroundStretch BackgroundRepeat = iota
+	// BackgroundRepeatXY tiles the image at its size from the top left corner.
+	BackgroundRepeatXY
+	// BackgroundNoRepeat draws the image once at its size at the top left corner.
+	BackgroundNoRepeat
+)
+
+func (r BackgroundRepeat) String() string {
+	switch r {
+	case BackgroundStretch:
+		return "stretch"
+	case BackgroundRepeatXY:
+		return "repeat"
+	case BackgroundNoRepeat:
+		return "no-repeat"
+	}
+	return fmt.Sprintf("unknown background-repeat: %d", r)
+}
+
+// Insets are the widths of the edges of a rectangle.
+type Insets struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
+// ImageResolver returns the image of a path referenced by a document,
+// such as url(img/panel.png) in background-image, or nil if not found.
+type ImageResolver func(path string) *ebiten.Image
+
+// imageLoader loads the images referenced by a document with
+// ParseOptions.ImageResolver, or from ParseOptions.FS without a resolver.
+// The images are loaded once per path.
+type imageLoader struct {
+	resolver ImageResolver
+	fsys     fs.FS
+	images   map[string]*ebiten.Image
+}
+
+func (l *imageLoader) load(path string) (*ebiten.Image, error) {
+	if img, ok := l.images[path]; ok {
+		return img, nil
+	}
+	var img *ebiten.Image
+	switch {
+	case l.resolver != nil:
+		img = l.resolver(path)
+	case l.fsys != nil:
+		f, err := l.fsys.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		decoded, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		img = ebiten.NewImageFromImage(decoded)
+	}
+	if img == nil {
+		return nil, fmt.Errorf("image not found: %s", path)
+	}
+	if l.images == nil {
+		l.images = map[string]*ebiten.Image{}
+	}
+	l.images[path] = img
+	return img, nil
+}
+
+// setBackgroundImage sets the background image of the path.
+// The image is resolved with the options of the document of the view.
+func (v *View) setBackgroundImage(path string) {
+	v.BackgroundImage = nil
+	if path == "" {
+		return
+	}
+	if v.style == nil || v.style.images == nil {
+		println(fmt.Sprintf("background-image %s: no image resolver", path))
+		return
+	}
+	img, err := v.style.images.load(path)
+	if err != nil {
+		println(fmt.Sprintf("background-image: %v", err))
+		return
+	}
+	v.BackgroundImage = img
+}
+
+// drawBackgroundImage draws the background image of the view in the frame
+// faded by the opacity.
+func (v *View) drawBackgroundImage(screen *ebiten.Image, frame image.Rectangle, opacity float64) {
+	img := v.BackgroundImage
+	if img == nil {
+		return
+	}
+	var clr color.Color
+	if opacity < 1 {
+		clr = fade(nil, opacity)
+	}
+	switch v.BackgroundRepeat {
+	case BackgroundRepeatXY:
+		batch.DrawTiled(screen, img, frame, clr)
+	case BackgroundNoRepeat:
+		size := img.Bounds().Size()
+		dst := image.Rectangle{Min: frame.Min, Max: frame.Min.Add(size)}.Intersect(frame)
+		src := image.Rectangle{Min: img.Bounds().Min, Max: img.Bounds().Min.Add(dst.Size())}
+		batch.DrawImage(screen, img, src, dst, clr)
+	default:
+		s := v.BackgroundSlice
+		if s == (Insets{}) {
+			batch.DrawImage(screen, img, img.Bounds(), frame, clr)
+			return
+		}
+		insets := graphic.Insets{Left: s.Left, Top: s.Top, Right: s.Right, Bottom: s.Bottom}
+		batch.DrawNinePatch(screen, img, insets, frame, clr)
+	}
+}
+
+// parseURL parses url(path) with an optionally quoted path.
+func parseURL(val string) (string, error) {
+	val = strings.TrimSpace(val)
+	if !strings.HasPrefix(val, "url(") || !strings.HasSuffix(val, ")") {
+		return "", fmt.Errorf("invalid url: %s", val)
+	}
+	path := strings.TrimSpace(val[len("url(") : len(val)-1])
+	path = strings.Trim(path, `"'`)
+	if path == "" {
+		return "", fmt.Errorf("invalid url: %s", val)
+	}
+	return path, nil
+}
+
+func parseBackgroundImage(val string) (any, error) {
+	if strings.TrimSpace(val) == "none" {
+		return "", nil
+	}
+	return parseURL(val)
+}
+
+func parseBackgroundRepeat(val string) (any, error) {
+	switch val {
+	case "stretch":
+		return BackgroundStretch, nil
+	case "repeat":
+		return BackgroundRepeatXY, nil
+	case "no-repeat":
+		return BackgroundNoRepeat, nil
+	}
+	return BackgroundStretch, fmt.Errorf("unknown background-repeat: %s", val)
+}
@@ -0,0 +1,82 @@
+package furex
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackgroundImage(t *testing.T) {
+	panel := ebiten.NewImage(16, 16)
+	var resolved []string
+	opts := &ParseOptions{ImageResolver: func(path string) *ebiten.Image {
+		resolved = append(resolved, path)
+		if path == "img/panel.png" {
+			return panel
+		}
+		return nil
+	}}
+	view := Parse(`
+		<view style="width: 100px; height: 100px">
+			<view id="a" style="background-image: url('img/panel.png'); background-slice: 4px 6px"></view>
+			<view id="b" style="background: url(img/panel.png); background-repeat: repeat"></view>
+			<view id="c" style="background-image: url(img/missing.png); background-repeat: no-repeat"></view>
+		</view>`, opts)
+
+	a := view.MustGetByID("a")
+	require.Equal(t, panel, a.BackgroundImage)
+	require.Equal(t, BackgroundStretch, a.BackgroundRepeat)
+	require.Equal(t, Insets{Top: 4, Right: 6, Bottom: 4, Left: 6}, a.BackgroundSlice)
+	b := view.MustGetByID("b")
+	require.Equal(t, panel, b.BackgroundImage)
+	require.Equal(t, BackgroundRepeatXY, b.BackgroundRepeat)
+	c := view.MustGetByID("c")
+	require.Nil(t, c.BackgroundImage)
+	require.Equal(t, BackgroundNoRepeat, c.BackgroundRepeat)
+	// the images are resolved once per path
+	require.Equal(t, []string{"img/panel.png", "img/missing.png"}, resolved)
+}
+
+func TestBackgroundImageFromFS(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+	fsys := fstest.MapFS{"bg.png": {Data: buf.Bytes()}}
+	view := Parse(`<view style="background-image: url(bg.png)"></view>`, &ParseOptions{FS: fsys})
+	require.NotNil(t, view.BackgroundImage)
+	require.Equal(t, image.Rect(0, 0, 3, 2), view.BackgroundImage.Bounds())
+
+	_, err := parseBackgroundImage("url()")
+	require.Error(t, err)
+	_, err = parseBackgroundRepeat("round")
+	require.Error(t, err)
+}
+
+func TestDrawBackgroundImage(t *testing.T) {
+	screen := ebiten.NewImage(100, 100)
+	img := ebiten.NewImage(16, 16)
+	img.Fill(color.White)
+	frame := image.Rect(0, 0, 40, 20)
+
+	tests := []struct {
+		view *View
+		want int
+	}{
+		{&View{BackgroundImage: img}, 2},
+		{&View{BackgroundImage: img, BackgroundSlice: Insets{4, 4, 4, 4}}, 18},
+		// 3x2 tiles cut at the right and bottom edges
+		{&View{BackgroundImage: img, BackgroundRepeat: BackgroundRepeatXY}, 12},
+		{&View{BackgroundImage: img, BackgroundRepeat: BackgroundNoRepeat}, 2},
+	}
+	for _, tt := range tests {
+		batch.Flush()
+		tt.view.drawBackgroundImage(screen, frame, 1)
+		require.Equal(t, tt.want, batch.Len())
+	}
+	batch.Flush()
+}
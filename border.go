@@ -0,0 +1,126 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BorderStyle is the 'border-style' property.
+type BorderStyle uint8
+
+const (
+	BorderSolid BorderStyle = iota
+	// BorderDashed draws dashes three times as long as the width.
+	BorderDashed
+	// BorderDotted draws squares of the width.
+	BorderDotted
+)
+
+func (s BorderStyle) String() string {
+	switch s {
+	case BorderSolid:
+		return "solid"
+	case BorderDashed:
+		return "dashed"
+	case BorderDotted:
+		return "dotted"
+	}
+	return fmt.Sprintf("unknown border-style: %d", s)
+}
+
+func parseBorderStyle(val string) (any, error) {
+	switch val {
+	case "solid":
+		return BorderSolid, nil
+	case "dashed":
+		return BorderDashed, nil
+	case "dotted":
+		return BorderDotted, nil
+	}
+	return BorderSolid, fmt.Errorf("unknown border-style: %s", val)
+}
+
+// Border is the border of a side of a view, set by the border-top,
+// border-right, border-bottom and border-left properties.
+type Border struct {
+	Width int
+	Color color.Color
+	Style BorderStyle
+}
+
+// sides returns the borders of the top, right, bottom and left sides:
+// the border of the side if it is set, or the border of the view.
+func (v *View) sides() [4]Border {
+	all := Border{Width: v.BorderWidth, Color: v.BorderColor, Style: v.BorderStyle}
+	ret := [4]Border{all, all, all, all}
+	for i, b := range []*Border{v.BorderTop, v.BorderRight, v.BorderBottom, v.BorderLeft} {
+		if b != nil {
+			ret[i] = *b
+		}
+	}
+	return ret
+}
+
+// hasSideBorders returns true if the border is not the same solid line
+// on all the sides.
+func (v *View) hasSideBorders() bool {
+	return v.BorderStyle != BorderSolid || v.BorderTop != nil || v.BorderRight != nil ||
+		v.BorderBottom != nil || v.BorderLeft != nil
+}
+
+// drawSideBorders draws the borders of the sides inside the frame. The
+// top and bottom borders span the frame; the left and right borders are
+// drawn between them.
+func (v *View) drawSideBorders(screen *ebiten.Image, frame image.Rectangle) {
+	s := v.sides()
+	rects := sideRects(frame, s[0].Width, s[1].Width, s[2].Width, s[3].Width)
+	opacity := v.EffectiveOpacity()
+	for i, b := range s {
+		if b.Width <= 0 || rects[i].Empty() {
+			continue
+		}
+		c := b.Color
+		if c == nil {
+			c = color.Black
+		}
+		drawBorderLine(screen, rects[i], b.Width, b.Style, i%2 == 0, fade(c, opacity))
+	}
+}
+
+// sideRects returns the rects of the top, right, bottom and left sides of
+// the widths inside the frame.
+func sideRects(frame image.Rectangle, top, right, bottom, left int) [4]image.Rectangle {
+	return [4]image.Rectangle{
+		image.Rect(frame.Min.X, frame.Min.Y, frame.Max.X, frame.Min.Y+top),
+		image.Rect(frame.Max.X-right, frame.Min.Y+top, frame.Max.X, frame.Max.Y-bottom),
+		image.Rect(frame.Min.X, frame.Max.Y-bottom, frame.Max.X, frame.Max.Y),
+		image.Rect(frame.Min.X, frame.Min.Y+top, frame.Min.X+left, frame.Max.Y-bottom),
+	}
+}
+
+// drawBorderLine fills the rect of a border of the width with the style.
+// The dashes and the dots run horizontally if horizontal is true.
+func drawBorderLine(screen *ebiten.Image, rect image.Rectangle, width int, style BorderStyle, horizontal bool, clr color.Color) {
+	dash, gap := 0, 0
+	switch style {
+	case BorderDashed:
+		dash, gap = width*3, width*2
+	case BorderDotted:
+		dash, gap = width, width
+	default:
+		batch.FillRect(screen, rect, clr)
+		return
+	}
+	if horizontal {
+		for x := rect.Min.X; x < rect.Max.X; x += dash + gap {
+			batch.FillRect(screen, image.Rect(x, rect.Min.Y, x+dash, rect.Max.Y).Intersect(rect), clr)
+		}
+		return
+	}
+	for y := rect.Min.Y; y < rect.Max.Y; y += dash + gap {
+		batch.FillRect(screen, image.Rect(rect.Min.X, y, rect.Max.X, y+dash).Intersect(rect), clr)
+	}
+}
@@ -0,0 +1,183 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Button is the handler of the built-in <button> element. It draws an
+// image or a color for its state under the text of the view (View.Text),
+// and calls OnClick when it is pressed and released on it by the mouse
+// or a touch. A disabled view (View.Disabled) is not pressed.
+//
+// The images and the colors fall back to those of the normal state: the
+// pressed state to the hover state, then to the normal state, and the
+// disabled state to the normal state at half opacity.
+//
+// In HTML, the text of the element is the label, centered unless the
+// text-align or vertical-align properties are set, and the images are
+// loaded like background images from the src, hover-src, pressed-src and
+// disabled-src attributes:
+//
+//	<button id="play" src="button.png" pressed-src="button-pressed.png">Play</button>
+//
+// The colors can also be declared with the background-color property and
+// the :hover, :active and :disabled pseudo-classes.
+type Button struct {
+	// Text draws the label.
+	Text
+	// OnClick is called when the button is clicked or tapped.
+	OnClick func()
+
+	// Image, HoverImage, PressedImage and DisabledImage are stretched to
+	// the frame in their states.
+	Image         *ebiten.Image
+	HoverImage    *ebiten.Image
+	PressedImage  *ebiten.Image
+	DisabledImage *ebiten.Image
+	// Background, HoverBackground, PressedBackground and
+	// DisabledBackground fill the frame in their states, with the rounded
+	// corners of View.BorderRadius, if there is no image.
+	Background         color.Color
+	HoverBackground    color.Color
+	PressedBackground  color.Color
+	DisabledBackground color.Color
+
+	// srcs are the paths of the images of the states in HTML, loaded
+	// when the button is drawn first.
+	srcs    [buttonStates]string
+	loaded  bool
+	hovered bool
+	pressed bool
+}
+
+var _ ButtonHandler = (*Button)(nil)
+var _ MouseEnterLeaveHandler = (*Button)(nil)
+var _ Drawer = (*Button)(nil)
+
+// buttonState is the state of a button.
+type buttonState int
+
+const (
+	buttonNormal buttonState = iota
+	buttonHover
+	buttonPressed
+	buttonDisabled
+	buttonStates
+)
+
+// newButton creates the view of a <button> element from its attributes.
+func newButton(attrs map[string]string) *View {
+	b := &Button{}
+	for i, name := range [buttonStates]string{"src", "hover-src", "pressed-src", "disabled-src"} {
+		b.srcs[i] = attrs[name]
+	}
+	return &View{Handler: b, TextAlign: TextAlignCenter, VerticalAlign: VerticalAlignMiddle}
+}
+
+// HandlePress implements ButtonHandler.
+func (b *Button) HandlePress(x, y int, t ebiten.TouchID) {
+	b.pressed = true
+}
+
+// HandleRelease implements ButtonHandler.
+func (b *Button) HandleRelease(x, y int, isCancel bool) {
+	b.pressed = false
+	if !isCancel && b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+// HandleMouseEnter implements MouseEnterLeaveHandler.
+func (b *Button) HandleMouseEnter(x, y int) bool {
+	b.hovered = true
+	return true
+}
+
+// HandleMouseLeave implements MouseEnterLeaveHandler.
+func (b *Button) HandleMouseLeave() {
+	b.hovered = false
+}
+
+// state returns the state of the button of the view.
+func (b *Button) state(v *View) buttonState {
+	switch {
+	case v.Disabled:
+		return buttonDisabled
+	case b.pressed:
+		return buttonPressed
+	case b.hovered:
+		return buttonHover
+	}
+	return buttonNormal
+}
+
+// Draw implements Drawer.
+func (b *Button) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	b.load(v)
+	if !frame.Empty() {
+		b.drawState(screen, frame, v)
+		batch.Flush()
+	}
+	b.Text.Draw(screen, frame, v)
+}
+
+// drawState adds the image or the color of the state of the button to
+// the batch.
+func (b *Button) drawState(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	images := [buttonStates]*ebiten.Image{b.Image, b.HoverImage, b.PressedImage, b.DisabledImage}
+	colors := [buttonStates]color.Color{b.Background, b.HoverBackground, b.PressedBackground, b.DisabledBackground}
+	state := b.state(v)
+	opacity := v.EffectiveOpacity()
+	img, clr := images[state], colors[state]
+	for s := state; img == nil && clr == nil && s != buttonNormal; {
+		if s == buttonDisabled {
+			// the normal state is faded instead
+			opacity /= 2
+			s = buttonNormal
+		} else {
+			s--
+		}
+		img, clr = images[s], colors[s]
+	}
+	switch {
+	case img != nil:
+		var tint color.Color
+		if opacity < 1 {
+			tint = fade(nil, opacity)
+		}
+		batch.DrawImage(screen, img, img.Bounds(), frame, tint)
+	case clr != nil:
+		if v.BorderRadius > 0 {
+			batch.FillRoundedRect(screen, frame, v.BorderRadius, fade(clr, opacity))
+		} else {
+			batch.FillRect(screen, frame, fade(clr, opacity))
+		}
+	}
+}
+
+// load loads the images of the attributes of the element.
+func (b *Button) load(v *View) {
+	if b.loaded {
+		return
+	}
+	b.loaded = true
+	if v.style == nil || v.style.images == nil {
+		return
+	}
+	images := [buttonStates]**ebiten.Image{&b.Image, &b.HoverImage, &b.PressedImage, &b.DisabledImage}
+	for i, src := range b.srcs {
+		if src == "" || *images[i] != nil {
+			continue
+		}
+		img, err := v.style.images.load(src)
+		if err != nil {
+			println(fmt.Sprintf("button: %v", err))
+			continue
+		}
+		*images[i] = img
+	}
+}
@@ -0,0 +1,79 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestButton(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px; align-items: flex-start">
+		<button id="play" style="width: 80px; height: 20px">Play</button>
+		<button id="quit" style="width: 20px; height: 20px; text-align: left" disabled>Quit</button>
+	</view>`, nil)
+	root.Update()
+	play := root.MustGetByID("play")
+	b := play.Handler.(*Button)
+	require.Equal(t, "Play", play.Text)
+	require.Equal(t, TextAlignCenter, play.TextAlign)
+	require.Equal(t, VerticalAlignMiddle, play.VerticalAlign)
+	require.Equal(t, TextAlignLeft, root.MustGetByID("quit").TextAlign)
+	require.Empty(t, play.Children())
+
+	clicks := 0
+	b.OnClick = func() { clicks++ }
+	root.handleMouseEnterLeave(10, 10)
+	require.Equal(t, buttonHover, b.state(play))
+	root.handleMouseButtonLeftPressed(10, 10)
+	require.Equal(t, buttonPressed, b.state(play))
+	root.handleMouseButtonLeftReleased(10, 10)
+	require.Equal(t, 1, clicks)
+
+	// released outside the button
+	root.handleMouseButtonLeftPressed(10, 10)
+	root.handleMouseButtonLeftReleased(90, 90)
+	require.Equal(t, 1, clicks)
+	root.handleMouseEnterLeave(90, 90)
+	require.Equal(t, buttonNormal, b.state(play))
+
+	// the disabled button is not pressed
+	quit := root.MustGetByID("quit")
+	quit.Handler.(*Button).OnClick = func() { t.Fatal("clicked") }
+	root.handleMouseButtonLeftPressed(90, 10)
+	root.handleMouseButtonLeftReleased(90, 10)
+	require.Equal(t, buttonDisabled, quit.Handler.(*Button).state(quit))
+
+	// the states fall back to the hover and the normal states
+	screen := ebiten.NewImage(100, 100)
+	b.Background, b.HoverBackground = color.White, color.Black
+	b.pressed = true
+	batch.Flush()
+	b.drawState(screen, play.frame, play)
+	require.Equal(t, 2, batch.Len())
+	batch.Flush()
+	b.pressed, b.Background, b.HoverBackground = false, nil, nil
+	b.drawState(screen, play.frame, play)
+	require.Equal(t, 0, batch.Len())
+
+	root.Draw(screen)
+}
+
+func TestButtonImages(t *testing.T) {
+	img := ebiten.NewImage(4, 4)
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<button id="a" src="a.png" pressed-src="b.png" style="width: 10px; height: 10px"></button>
+	</view>`, &ParseOptions{ImageResolver: func(path string) *ebiten.Image {
+		if path == "a.png" {
+			return img
+		}
+		return nil
+	}})
+	root.Update()
+	a := root.MustGetByID("a")
+	b := a.Handler.(*Button)
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Equal(t, img, b.Image)
+	require.Nil(t, b.PressedImage)
+}
@@ -0,0 +1,68 @@
+package furex
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Canvas is the handler of the built-in <canvas> element.
+// It lets Go code draw freely inside the frame computed by the layout.
+//
+// A canvas created from HTML can be looked up by its id and configured like this:
+//
+//	c := view.MustGetByID("minimap").Handler.(*furex.Canvas)
+//	c.DrawFunc = func(screen *ebiten.Image, frame image.Rectangle) { ... }
+type Canvas struct {
+	// DrawFunc is called every frame with the current frame of the view,
+	// in the coordinates of the image being drawn (see DrawScale).
+	DrawFunc func(screen *ebiten.Image, frame image.Rectangle)
+	// OnMount is called once before the first draw. OnMount, OnResize and
+	// Frame have the frame of the layout.
+	OnMount func(frame image.Rectangle)
+	// OnResize is called when the size of the frame has changed
+	// since the previous draw.
+	OnResize func(old, new image.Rectangle)
+
+	mounted bool
+	frame   image.Rectangle
+}
+
+var _ Drawer = (*Canvas)(nil)
+
+// NewCanvas creates a canvas handler that draws with the given function.
+func NewCanvas(draw func(screen *ebiten.Image, frame image.Rectangle)) *Canvas {
+	return &Canvas{DrawFunc: draw}
+}
+
+// Draw implements Drawer.
+func (c *Canvas) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	c.checkFrame(frame)
+	if c.DrawFunc != nil {
+		c.DrawFunc(screen, targetRect(frame))
+	}
+}
+
+// drawsLayoutFrame implements layoutFrameDrawer.
+func (c *Canvas) drawsLayoutFrame() {}
+
+// Frame returns the frame the canvas was drawn in most recently.
+func (c *Canvas) Frame() image.Rectangle {
+	return c.frame
+}
+
+func (c *Canvas) checkFrame(frame image.Rectangle) {
+	if !c.mounted {
+		c.mounted = true
+		c.frame = frame
+		if c.OnMount != nil {
+			c.OnMount(frame)
+		}
+		return
+	}
+	old := c.frame
+	c.frame = frame
+	if old.Size() != frame.Size() && c.OnResize != nil {
+		c.OnResize(old, frame)
+	}
+}
@@ -0,0 +1,39 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanvas(t *testing.T) {
+	view := Parse(`
+		<view style="direction: row">
+			<canvas id="canvas" style="flex-grow: 1"></canvas>
+		</view>`, &ParseOptions{Width: 100, Height: 50})
+
+	c, ok := view.MustGetByID("canvas").Handler.(*Canvas)
+	require.True(t, ok)
+
+	var (
+		mounted image.Rectangle
+		resized [2]image.Rectangle
+		drawn   image.Rectangle
+	)
+	c.OnMount = func(frame image.Rectangle) { mounted = frame }
+	c.OnResize = func(old, new image.Rectangle) { resized = [2]image.Rectangle{old, new} }
+	c.DrawFunc = func(screen *ebiten.Image, frame image.Rectangle) { drawn = frame }
+
+	view.Update()
+	view.Draw(nil)
+	require.Equal(t, image.Rect(0, 0, 100, 50), mounted)
+	require.Equal(t, image.Rect(0, 0, 100, 50), drawn)
+	require.Equal(t, [2]image.Rectangle{}, resized)
+
+	view.UpdateWithSize(200, 50)
+	view.Draw(nil)
+	require.Equal(t, image.Rect(0, 0, 200, 50), drawn)
+	require.Equal(t, [2]image.Rectangle{image.Rect(0, 0, 100, 50), image.Rect(0, 0, 200, 50)}, resized)
+}
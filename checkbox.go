@@ -0,0 +1,198 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Checkbox is the handler of the built-in <checkbox> and <toggle>
+// elements. It draws a box with a check mark, or a switch with a knob if
+// Switch is true, and toggles Checked when it is clicked or tapped, or
+// when Space or Enter is pressed while the view has the focus. A disabled
+// view (View.Disabled) is not toggled.
+//
+// In HTML, the checked attribute checks it, and the :checked
+// pseudo-class styles the view while it is checked:
+//
+//	<checkbox id="vsync" checked></checkbox>
+//	<toggle id="sound"></toggle>
+//
+// The box and the track are filled with the color of the view (the color
+// property) while it is checked, unless CheckedColor is set.
+type Checkbox struct {
+	// Checked is the state of the checkbox.
+	Checked bool
+	// OnChange is called with the state when it is changed by the input.
+	OnChange func(checked bool)
+	// Switch draws a toggle switch instead of a box.
+	Switch bool
+
+	// Color fills the box or the track while it is unchecked. Gray is used
+	// if it is nil.
+	Color color.Color
+	// CheckedColor fills the box or the track while it is checked. The
+	// color of the view (View.ComputedColor) or blue is used if it is nil.
+	CheckedColor color.Color
+	// MarkColor is the color of the check mark and the knob. White is used
+	// if it is nil.
+	MarkColor color.Color
+
+	// styled is the state the view was last styled with.
+	styled  bool
+	focused bool
+	// knob is the position of the knob of the switch from 0 (unchecked)
+	// to 1 (checked), moved over a few updates.
+	knob float64
+}
+
+var _ ButtonHandler = (*Checkbox)(nil)
+var _ Updater = (*Checkbox)(nil)
+var _ Drawer = (*Checkbox)(nil)
+var _ Sizer = (*Checkbox)(nil)
+var _ FocusHandler = (*Checkbox)(nil)
+
+var defaultCheckboxColor = color.RGBA{0x80, 0x80, 0x80, 0xff}
+
+// knobSteps is the number of updates the knob takes to move across.
+const knobSteps = 6
+
+// newCheckbox creates the handler of a <checkbox> element from its attributes.
+func newCheckbox(attrs map[string]string) Handler {
+	c := &Checkbox{}
+	if val, ok := attrs["checked"]; ok {
+		c.Checked = val == "" || parseBool(val)
+	}
+	c.styled = c.Checked
+	if c.Checked {
+		c.knob = 1
+	}
+	return c
+}
+
+// newToggle creates the handler of a <toggle> element from its attributes.
+func newToggle(attrs map[string]string) Handler {
+	c := newCheckbox(attrs).(*Checkbox)
+	c.Switch = true
+	return c
+}
+
+// SetChecked sets the state without calling OnChange.
+func (c *Checkbox) SetChecked(checked bool) {
+	c.Checked = checked
+}
+
+// Toggle toggles the state and calls OnChange.
+func (c *Checkbox) Toggle() {
+	c.Checked = !c.Checked
+	if c.OnChange != nil {
+		c.OnChange(c.Checked)
+	}
+}
+
+// HandlePress implements ButtonHandler.
+func (c *Checkbox) HandlePress(x, y int, t ebiten.TouchID) {}
+
+// HandleRelease implements ButtonHandler.
+func (c *Checkbox) HandleRelease(x, y int, isCancel bool) {
+	if !isCancel {
+		c.Toggle()
+	}
+}
+
+// HandleFocus implements FocusHandler.
+func (c *Checkbox) HandleFocus() {
+	c.focused = true
+}
+
+// HandleBlur implements FocusHandler.
+func (c *Checkbox) HandleBlur() {
+	c.focused = false
+}
+
+// Size implements Sizer.
+func (c *Checkbox) Size(v *View) (width, height int) {
+	if c.Switch {
+		return 36, 20
+	}
+	return 16, 16
+}
+
+// Update implements Updater.
+func (c *Checkbox) Update(v *View) {
+	if c.focused && !v.Disabled &&
+		(isKeyJustPressed(ebiten.KeySpace) || isKeyJustPressed(ebiten.KeyEnter)) {
+		c.Toggle()
+	}
+	if c.styled != c.Checked {
+		// the state is also changed from Go
+		c.styled = c.Checked
+		v.restyle()
+	}
+	switch {
+	case c.Checked && c.knob < 1:
+		c.knob = clampOpacity(c.knob + 1.0/knobSteps)
+	case !c.Checked && c.knob > 0:
+		c.knob = clampOpacity(c.knob - 1.0/knobSteps)
+	}
+}
+
+// Draw implements Drawer.
+func (c *Checkbox) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	c.drawBatch(screen, frame, v)
+	batch.Flush()
+}
+
+func (c *Checkbox) drawBatch(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	if frame.Empty() {
+		return
+	}
+	opacity := v.EffectiveOpacity()
+	fill := c.Color
+	if fill == nil {
+		fill = defaultCheckboxColor
+	}
+	if c.Checked {
+		fill = c.CheckedColor
+		if fill == nil {
+			fill = v.ComputedColor()
+		}
+		if fill == nil {
+			fill = defaultSliderFillColor
+		}
+	}
+	mark := c.MarkColor
+	if mark == nil {
+		mark = color.White
+	}
+	w, h := frame.Dx(), frame.Dy()
+
+	if c.Switch {
+		batch.FillRoundedRect(screen, frame, h/2, fade(fill, opacity))
+		// the knob is inset by a tenth of the height
+		inset := h / 10
+		size := h - inset*2
+		x := frame.Min.X + inset + int(float64(w-size-inset*2)*c.knob+0.5)
+		knob := image.Rect(x, frame.Min.Y+inset, x+size, frame.Min.Y+inset+size)
+		batch.FillRoundedRect(screen, knob, size/2, fade(mark, opacity))
+		return
+	}
+
+	radius := v.BorderRadius
+	if radius == 0 {
+		radius = h / 6
+	}
+	batch.FillRoundedRect(screen, frame, radius, fade(fill, opacity))
+	if !c.Checked {
+		return
+	}
+	width := h / 8
+	if width < 2 {
+		width = 2
+	}
+	pt := func(fx, fy float64) image.Point {
+		return image.Pt(frame.Min.X+int(float64(w)*fx+0.5), frame.Min.Y+int(float64(h)*fy+0.5))
+	}
+	batch.StrokePolyline(screen, []image.Point{pt(0.25, 0.5), pt(0.42, 0.68), pt(0.75, 0.32)}, width, fade(mark, opacity))
+}
@@ -0,0 +1,68 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckbox(t *testing.T) {
+	root := Parse(`<html><head><style>
+		toggle:checked { margin-left: 4px; }
+	</style></head><body>
+	<view style="width: 100px; height: 100px; align-items: flex-start">
+		<checkbox id="vsync" checked></checkbox>
+		<toggle id="sound"></toggle>
+	</view></body></html>`, nil)
+	root.Update()
+	vsync := root.MustGetByID("vsync")
+	cb := vsync.Handler.(*Checkbox)
+	require.True(t, cb.Checked)
+	require.False(t, cb.Switch)
+	require.Equal(t, image.Rect(0, 0, 16, 16), vsync.frame)
+	sound := root.MustGetByID("sound")
+	toggle := sound.Handler.(*Checkbox)
+	require.False(t, toggle.Checked)
+	require.True(t, toggle.Switch)
+	require.Equal(t, image.Rect(16, 0, 52, 20), sound.frame)
+
+	var changes []bool
+	toggle.OnChange = func(checked bool) { changes = append(changes, checked) }
+	root.handleMouseButtonLeftPressed(20, 10)
+	root.handleMouseButtonLeftReleased(20, 10)
+	require.True(t, toggle.Checked)
+	require.Equal(t, []bool{true}, changes)
+	// released outside
+	root.handleMouseButtonLeftPressed(20, 10)
+	root.handleMouseButtonLeftReleased(90, 90)
+	require.True(t, toggle.Checked)
+
+	// the view is restyled with :checked and the knob moves across
+	root.Update()
+	require.Equal(t, 4, sound.MarginLeft)
+	require.InDelta(t, 1.0/knobSteps, toggle.knob, 1e-9)
+	for i := 0; i < knobSteps; i++ {
+		root.Update()
+	}
+	require.Equal(t, 1.0, toggle.knob)
+
+	// the state set from Go restyles the view without OnChange
+	toggle.SetChecked(false)
+	root.Update()
+	require.Equal(t, 0, sound.MarginLeft)
+	require.Equal(t, []bool{true}, changes)
+
+	// the check mark is drawn over the box
+	screen := ebiten.NewImage(100, 100)
+	batch.Flush()
+	cb.drawBatch(screen, vsync.frame, vsync)
+	checked := batch.Len()
+	batch.Flush()
+	cb.Checked = false
+	cb.drawBatch(screen, vsync.frame, vsync)
+	require.Greater(t, checked, batch.Len())
+	batch.Flush()
+	root.Draw(screen)
+}
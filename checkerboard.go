@@ -0,0 +1,89 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Checkerboard is a background of squares of two alternating colors,
+// drawn under the other backgrounds, e.g. to preview images with
+// transparency such as skins and textures.
+type Checkerboard struct {
+	// Size is the size of the squares in pixels. 8 is used if it is 0.
+	Size int
+	// Light and Dark are the colors of the squares. Light starts at the
+	// top left corner. White and light gray are used if they are nil.
+	Light color.Color
+	Dark  color.Color
+}
+
+var (
+	defaultCheckerboardLight = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	defaultCheckerboardDark  = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+)
+
+func (c *Checkerboard) colors() (light, dark color.Color) {
+	light, dark = c.Light, c.Dark
+	if light == nil {
+		light = defaultCheckerboardLight
+	}
+	if dark == nil {
+		dark = defaultCheckerboardDark
+	}
+	return light, dark
+}
+
+// draw fills the frame with the squares aligned to its top left corner.
+// The squares at the right and bottom edges are cut by the frame.
+func (c *Checkerboard) draw(screen *ebiten.Image, frame image.Rectangle, opacity float64) {
+	size := c.Size
+	if size <= 0 {
+		size = 8
+	}
+	light, dark := c.colors()
+	batch.FillRect(screen, frame, fade(light, opacity))
+	dark = fade(dark, opacity)
+	for row, y := 0, frame.Min.Y; y < frame.Max.Y; row, y = row+1, y+size {
+		for x := frame.Min.X + (1-row%2)*size; x < frame.Max.X; x += size * 2 {
+			batch.FillRect(screen, image.Rect(x, y, x+size, y+size).Intersect(frame), dark)
+		}
+	}
+}
+
+// parseCheckerboard parses `checkerboard([<size>] [, <light>, <dark>])`.
+func parseCheckerboard(val string) (*Checkerboard, error) {
+	val = strings.TrimSpace(val)
+	if !strings.HasPrefix(val, "checkerboard(") || !strings.HasSuffix(val, ")") {
+		return nil, fmt.Errorf("invalid checkerboard: %s", val)
+	}
+	c := &Checkerboard{}
+	inner := strings.TrimSpace(val[len("checkerboard(") : len(val)-1])
+	if inner == "" {
+		return c, nil
+	}
+	args := splitArgs(inner)
+	if size, err := parseNumber(args[0]); err == nil {
+		c.Size = size.(int)
+		args = args[1:]
+	}
+	switch len(args) {
+	case 0:
+		return c, nil
+	case 2:
+		light, err := parseColor(args[0])
+		if err != nil {
+			return nil, err
+		}
+		dark, err := parseColor(args[1])
+		if err != nil {
+			return nil, err
+		}
+		c.Light, c.Dark = light.(color.Color), dark.(color.Color)
+		return c, nil
+	}
+	return nil, fmt.Errorf("invalid checkerboard: %s", val)
+}
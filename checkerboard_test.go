@@ -0,0 +1,47 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCheckerboard(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	gray := color.RGBA{128, 128, 128, 255}
+	tests := []struct {
+		val  string
+		want *Checkerboard
+	}{
+		{"checkerboard()", &Checkerboard{}},
+		{"checkerboard(4px)", &Checkerboard{Size: 4}},
+		{"checkerboard(16, black, gray)", &Checkerboard{Size: 16, Light: black, Dark: gray}},
+		{"checkerboard(black, rgb(128, 128, 128))", &Checkerboard{Light: black, Dark: color.NRGBA{128, 128, 128, 255}}},
+	}
+	for _, tt := range tests {
+		got, err := parseCheckerboard(tt.val)
+		require.NoError(t, err, tt.val)
+		require.Equal(t, tt.want, got, tt.val)
+	}
+	for _, val := range []string{"checkerboard(black)", "checkerboard(4px, black, nocolor)", "checkerboard(1, 2, 3, 4)"} {
+		_, err := parseCheckerboard(val)
+		require.Error(t, err, val)
+	}
+
+	view := Parse(`<view style="background: checkerboard(10px)"><view id="a" style="background: #fff"></view></view>`, nil)
+	require.Equal(t, &Checkerboard{Size: 10}, view.BackgroundCheckerboard)
+	require.Nil(t, view.MustGetByID("a").BackgroundCheckerboard)
+}
+
+func TestDrawCheckerboard(t *testing.T) {
+	screen := ebiten.NewImage(30, 30)
+	batch.Flush()
+	// a 25x15 frame has 3x2 squares of 10px, 3 of them dark
+	c := &Checkerboard{Size: 10}
+	c.draw(screen, image.Rect(0, 0, 25, 15), 1)
+	require.Equal(t, (1+3)*2, batch.Len())
+	batch.Flush()
+}
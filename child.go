@@ -16,14 +16,17 @@ type child struct {
 	isMouseLeftButtonHandler bool
 	isMouseEntered           bool
 	handledTouchID           ebiten.TouchID
+	// removing is true if the child is removed after the update of the
+	// tree (see RemoveChild).
+	removing bool
 	swipe
 }
 
 type swipe struct {
 	downX, downY int
 	upX, upY     int
-	downTime     time.Time
-	upTime       time.Time
+	downTime     time.Duration
+	upTime       time.Duration
 	swipeDir     SwipeDirection
 	swipeTouchID ebiten.TouchID
 }
@@ -76,7 +79,7 @@ func (c *child) checkSwipeHandlerStart(frame *image.Rectangle, touchID ebiten.To
 	if ok {
 		if isInside(frame, x, y) {
 			c.swipeTouchID = touchID
-			c.swipe.downTime = time.Now()
+			c.swipe.downTime = inputTime()
 			c.swipe.downX, c.swipe.downY = x, y
 			return true
 		}
@@ -91,7 +94,7 @@ func (c *child) checkSwipeHandlerEnd(frame *image.Rectangle, touchID ebiten.Touc
 			return false
 		}
 		c.swipeTouchID = -1
-		c.upTime = time.Now()
+		c.upTime = inputTime()
 		c.upX, c.upY = x, y
 		if c.checkSwipe() {
 			swipeHandler.HandleSwipe(c.swipeDir)
@@ -105,7 +108,7 @@ const swipeThresholdDist = 50.
 const swipeThresholdTime = time.Millisecond * 300
 
 func (c *child) checkSwipe() bool {
-	dur := c.upTime.Sub(c.downTime)
+	dur := c.upTime - c.downTime
 	if dur > swipeThresholdTime {
 		return false
 	}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"image/color"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2"
+	"golang.org/x/net/html"
+)
+
+type config struct {
+	pkg    string
+	typ    string
+	source string
+	fsys   fs.FS
+	// bind generates a function looking up the views with an id in a
+	// view tree parsed at runtime instead of constructing the tree.
+	bind bool
+}
+
+// enumNames maps the values of the enum fields of View to their constant names.
+var enumNames = map[any]string{}
+
+func init() {
+	for name, v := range map[string]any{
+		"PositionStatic": furex.PositionStatic, "PositionAbsolute": furex.PositionAbsolute,
+		"Row": furex.Row, "Column": furex.Column,
+		"NoWrap": furex.NoWrap, "Wrap": furex.Wrap, "WrapReverse": furex.WrapReverse,
+		"JustifyStart": furex.JustifyStart, "JustifyEnd": furex.JustifyEnd, "JustifyCenter": furex.JustifyCenter,
+		"JustifySpaceBetween": furex.JustifySpaceBetween, "JustifySpaceAround": furex.JustifySpaceAround,
+		"AlignItemStretch": furex.AlignItemStretch, "AlignItemStart": furex.AlignItemStart,
+		"AlignItemEnd": furex.AlignItemEnd, "AlignItemCenter": furex.AlignItemCenter,
+		"AlignContentStart": furex.AlignContentStart, "AlignContentEnd": furex.AlignContentEnd,
+		"AlignContentCenter": furex.AlignContentCenter, "AlignContentSpaceBetween": furex.AlignContentSpaceBetween,
+		"AlignContentSpaceAround": furex.AlignContentSpaceAround, "AlignContentStretch": furex.AlignContentStretch,
+		"AlignContentSpaceEvenly": furex.AlignContentSpaceEvenly, "DisplayFlex": furex.DisplayFlex, "DisplayNone": furex.DisplayNone,
+		"OverflowVisible": furex.OverflowVisible, "OverflowHidden": furex.OverflowHidden,
+		"OverflowScroll":    furex.OverflowScroll,
+		"BackgroundStretch": furex.BackgroundStretch, "BackgroundRepeatXY": furex.BackgroundRepeatXY,
+		"BackgroundNoRepeat": furex.BackgroundNoRepeat,
+		"LengthPx":           furex.LengthPx, "LengthPercent": furex.LengthPercent, "LengthEm": furex.LengthEm,
+		"LengthVW": furex.LengthVW, "LengthVH": furex.LengthVH, "LengthVMin": furex.LengthVMin, "LengthVMax": furex.LengthVMax,
+		"WritingModeHorizontalTB": furex.WritingModeHorizontalTB, "WritingModeVerticalRL": furex.WritingModeVerticalRL,
+		"BorderSolid": furex.BorderSolid, "BorderDashed": furex.BorderDashed, "BorderDotted": furex.BorderDotted,
+		"ImageRenderingAuto": furex.ImageRenderingAuto, "ImageRenderingPixelated": furex.ImageRenderingPixelated,
+	} {
+		enumNames[v] = "furex." + name
+	}
+}
+
+// customTag is the handler of the custom tags while generating.
+type customTag struct{}
+
+// skippedFields are set by NewComponentView or the tree construction.
+var skippedFields = map[string]bool{"Handler": true, "Raw": true, "TagName": true, "Attrs": true}
+
+// generate returns the Go code constructing the view tree of the document.
+func generate(src string, cfg config) (code []byte, err error) {
+	defer func() {
+		// Parse panics on invalid markup
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	components := furex.ComponentsMap{}
+	for _, tag := range tagNames(src) {
+		if tag == "view" || tag == "div" {
+			continue
+		}
+		// custom tags are resolved at runtime by NewComponentView. They have
+		// a handler, so that their texts are kept as View.Text like the
+		// components with handlers, while the texts of the plain views are
+		// drawn by <text> views.
+		components[tag] = customTag{}
+	}
+	g := &generator{cfg: cfg, ids: map[string]string{}, names: map[string]bool{"Root": true}, images: map[*ebiten.Image]string{}}
+	root := furex.Parse(src, &furex.ParseOptions{Components: components, FS: cfg.fsys, ImageResolver: g.resolveImage})
+	if cfg.bind {
+		return generateBind(root, cfg)
+	}
+	expandLazy(root)
+	if g.imageErr != nil {
+		return nil, g.imageErr
+	}
+
+	g.body.WriteString(fmt.Sprintf("ui := &%s{}\n", cfg.typ))
+	rootVar, err := g.view(root)
+	if err != nil {
+		return nil, err
+	}
+	g.body.WriteString(fmt.Sprintf("%s.Layout()\n", rootVar))
+	g.body.WriteString(fmt.Sprintf("ui.Root = %s\n", rootVar))
+	g.body.WriteString("return ui\n}\n")
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by furexgen from %s. DO NOT EDIT.\n\n", cfg.source)
+	fmt.Fprintf(out, "package %s\n\n", cfg.pkg)
+	out.WriteString("import (\n")
+	if g.usesColor {
+		out.WriteString("\"image/color\"\n")
+	}
+	if g.usesTime {
+		out.WriteString("\"time\"\n")
+	}
+	if g.usesColor || g.usesTime {
+		out.WriteString("\n")
+	}
+	out.WriteString("\"github.com/yohamta/furex/v2\"\n)\n\n")
+	g.writeStruct(out)
+	fmt.Fprintf(out, "// New%s creates the view tree of %s.\n", cfg.typ, cfg.source)
+	out.WriteString("// components are the components of the custom tags as in furex.ParseOptions.\n")
+	if g.usesImages {
+		out.WriteString("// images returns the images referenced by the document as ParseOptions.ImageResolver.\n")
+		fmt.Fprintf(out, "func New%s(components furex.ComponentsMap, images furex.ImageResolver) *%s {\n", cfg.typ, cfg.typ)
+	} else {
+		fmt.Fprintf(out, "func New%s(components furex.ComponentsMap) *%s {\n", cfg.typ, cfg.typ)
+	}
+	out.Write(g.body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// generateBind returns the Go code binding the views with an id of a
+// view tree created at runtime with furex.Parse.
+func generateBind(root *furex.View, cfg config) ([]byte, error) {
+	g := &generator{cfg: cfg, ids: map[string]string{}, names: map[string]bool{"Root": true}}
+	g.collectIDs(root, false)
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by furexgen from %s. DO NOT EDIT.\n\n", cfg.source)
+	fmt.Fprintf(out, "package %s\n\n", cfg.pkg)
+	out.WriteString("import (\n\"fmt\"\n\"strings\"\n\n\"github.com/yohamta/furex/v2\"\n)\n\n")
+	g.writeStruct(out)
+	fmt.Fprintf(out, "// Bind%s looks up the views with an id in root, the view tree of %s.\n", cfg.typ, cfg.source)
+	out.WriteString("// It returns an error if a view is not found. The views inside lazy\n")
+	out.WriteString("// views are nil until the lazy view is expanded and Bind is called again.\n")
+	fmt.Fprintf(out, "func Bind%s(root *furex.View) (*%s, error) {\n", cfg.typ, cfg.typ)
+	fmt.Fprintf(out, "ui := &%s{Root: root}\n", cfg.typ)
+	out.WriteString("var missing []string\n")
+	for _, f := range g.fields {
+		if f.lazy {
+			fmt.Fprintf(out, "ui.%s, _ = root.GetByID(%q)\n", f.name, f.id)
+			continue
+		}
+		fmt.Fprintf(out, "if v, ok := root.GetByID(%q); ok {\nui.%s = v\n} else {\n", f.id, f.name)
+		fmt.Fprintf(out, "missing = append(missing, %q)\n}\n", f.id)
+	}
+	out.WriteString("if len(missing) > 0 {\n")
+	fmt.Fprintf(out, "return nil, fmt.Errorf(\"%s: views not found: %%s\", strings.Join(missing, \", \"))\n", cfg.source)
+	out.WriteString("}\nreturn ui, nil\n}\n")
+	return format.Source(out.Bytes())
+}
+
+// collectIDs adds the fields of the views with an id in the tree.
+// lazy is true inside a view with the lazy attribute.
+func (g *generator) collectIDs(v *furex.View, lazy bool) {
+	g.addField(v, lazy)
+	if v.IsLazy() {
+		v.ExpandLazy()
+		lazy = true
+	}
+	for _, c := range v.Children() {
+		g.collectIDs(c, lazy)
+	}
+}
+
+// addField adds the field of the view if it has an id and returns its name.
+func (g *generator) addField(v *furex.View, lazy bool) (string, bool) {
+	if v.ID == "" {
+		return "", false
+	}
+	if _, dup := g.ids[v.ID]; dup {
+		return "", false
+	}
+	field := g.fieldName(v.ID)
+	g.ids[v.ID] = field
+	g.fields = append(g.fields, idField{name: field, id: v.ID, lazy: lazy})
+	return field, true
+}
+
+func (g *generator) writeStruct(out *bytes.Buffer) {
+	fmt.Fprintf(out, "// %s is the view tree of %s.\n", g.cfg.typ, g.cfg.source)
+	fmt.Fprintf(out, "type %s struct {\n", g.cfg.typ)
+	out.WriteString("Root *furex.View\n")
+	for _, f := range g.fields {
+		fmt.Fprintf(out, "%s *furex.View // id=%q\n", f.name, f.id)
+	}
+	out.WriteString("}\n\n")
+}
+
+type idField struct {
+	name string
+	id   string
+	lazy bool
+}
+
+type generator struct {
+	cfg       config
+	body      bytes.Buffer
+	count     int
+	fields    []idField
+	ids       map[string]string
+	names     map[string]bool
+	usesColor bool
+	usesTime  bool
+	// images maps the placeholder images to their paths in the document.
+	images     map[*ebiten.Image]string
+	imageErr   error
+	usesImages bool
+}
+
+// resolveImage returns a placeholder of the image, which is loaded at
+// runtime by the images parameter of the generated constructor.
+func (g *generator) resolveImage(path string) *ebiten.Image {
+	if g.cfg.fsys != nil {
+		if _, err := fs.Stat(g.cfg.fsys, path); err != nil && g.imageErr == nil {
+			g.imageErr = err
+		}
+	}
+	img := ebiten.NewImage(1, 1)
+	g.images[img] = path
+	return img
+}
+
+// view writes the construction of the view and its children
+// and returns the variable name of the view. It returns an error if a
+// field of a view cannot be written as Go code.
+func (g *generator) view(v *furex.View) (string, error) {
+	name := fmt.Sprintf("v%d", g.count)
+	g.count++
+	// the components are created with the attributes, like by Parse
+	attrs := "nil"
+	if len(v.Attrs) > 0 {
+		attrs, _ = g.literal(reflect.ValueOf(v.Attrs))
+	}
+	fmt.Fprintf(&g.body, "%s := furex.NewComponentView(%q, %s, components)\n", name, v.TagName, attrs)
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() || f.Anonymous || skippedFields[f.Name] {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		lit, ok := g.literal(fv)
+		if !ok {
+			return "", fmt.Errorf("<%s>: cannot generate the field %s of type %s", v.TagName, f.Name, f.Type)
+		}
+		fmt.Fprintf(&g.body, "%s.%s = %s\n", name, f.Name, lit)
+	}
+	if field, ok := g.addField(v, false); ok {
+		fmt.Fprintf(&g.body, "ui.%s = %s\n", field, name)
+	}
+	for _, c := range v.Children() {
+		child, err := g.view(c)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&g.body, "%s.AddChild(%s)\n", name, child)
+	}
+	return name, nil
+}
+
+// literal returns the Go expression of the field value.
+func (g *generator) literal(v reflect.Value) (string, bool) {
+	if img, ok := v.Interface().(*ebiten.Image); ok {
+		path, ok := g.images[img]
+		g.usesImages = g.usesImages || ok
+		return fmt.Sprintf("images(%q)", path), ok
+	}
+	if v.Type().Comparable() {
+		if name, ok := enumNames[v.Interface()]; ok {
+			return name, true
+		}
+	}
+	if c, ok := v.Interface().(color.Color); ok {
+		g.usesColor = true
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		return fmt.Sprintf("color.NRGBA{%d, %d, %d, %d}", n.R, n.G, n.B, n.A), true
+	}
+	if d, ok := v.Interface().(time.Duration); ok {
+		g.usesTime = true
+		if d%time.Millisecond != 0 {
+			return fmt.Sprintf("time.Duration(%d)", d), true
+		}
+		return fmt.Sprintf("%d * time.Millisecond", d.Milliseconds()), true
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Float64, reflect.Bool, reflect.String:
+		lit := fmt.Sprintf("%#v", v.Interface())
+		if v.Type().PkgPath() != "" {
+			// a named type without a constant name
+			return fmt.Sprintf("furex.%s(%s)", v.Type().Name(), lit), true
+		}
+		return lit, true
+	case reflect.Uint8:
+		return fmt.Sprintf("furex.%s(%d)", v.Type().Name(), v.Uint()), true
+	case reflect.Ptr:
+		switch v.Elem().Kind() {
+		case reflect.Int:
+			return fmt.Sprintf("furex.Int(%d)", v.Elem().Int()), true
+		case reflect.Float64:
+			return fmt.Sprintf("furex.Float(%#v)", v.Elem().Float()), true
+		case reflect.Struct:
+			lit, ok := g.literal(v.Elem())
+			return "&" + lit, ok
+		}
+	case reflect.Slice:
+		elem := v.Type().Elem().Name()
+		if v.Type().Elem().PkgPath() != "" {
+			elem = "furex." + elem
+		}
+		sb := &strings.Builder{}
+		fmt.Fprintf(sb, "[]%s{", elem)
+		for i := 0; i < v.Len(); i++ {
+			lit, ok := g.literal(v.Index(i))
+			if !ok {
+				return "", false
+			}
+			// the element type is elided in the composite literal
+			sb.WriteString(strings.TrimPrefix(lit, elem))
+			sb.WriteString(", ")
+		}
+		sb.WriteString("}")
+		return sb.String(), true
+	case reflect.Struct:
+		// a struct of furex such as BoxShadow
+		sb := &strings.Builder{}
+		fmt.Fprintf(sb, "furex.%s{", v.Type().Name())
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			lit, ok := g.literal(v.Field(i))
+			if !ok {
+				return "", false
+			}
+			fmt.Fprintf(sb, "%s: %s, ", f.Name, lit)
+		}
+		sb.WriteString("}")
+		return sb.String(), true
+	case reflect.Map:
+		if m, ok := v.Interface().(map[string]string); ok {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			sb := &strings.Builder{}
+			sb.WriteString("map[string]string{")
+			for _, k := range keys {
+				fmt.Fprintf(sb, "%q: %q, ", k, m[k])
+			}
+			sb.WriteString("}")
+			return sb.String(), true
+		}
+	}
+	return "", false
+}
+
+func (g *generator) fieldName(id string) string {
+	name := goName(id)
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "ID" + name
+	}
+	base := name
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// goName converts a kebab-case or snake_case name to an exported Go name.
+func goName(s string) string {
+	sb := &strings.Builder{}
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func expandLazy(v *furex.View) {
+	v.ExpandLazy()
+	for _, c := range v.Children() {
+		expandLazy(c)
+	}
+}
+
+// tagNames returns the names of the tags in the document.
+func tagNames(src string) []string {
+	seen := map[string]bool{}
+	var ret []string
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ret
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tn, _ := z.TagName()
+			if name := string(tn); !seen[name] {
+				seen[name] = true
+				ret = append(ret, name)
+			}
+		}
+	}
+}
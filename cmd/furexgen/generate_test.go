@@ -0,0 +1,186 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/yohamta/furex/v2"
+)
+
+func TestGenerate(t *testing.T) {
+	fsys := fstest.MapFS{"ui.css": {Data: []byte(".gauge { width: 120px; background-color: #ff0000 }")}}
+	src := `
+		<html>
+			<head>
+				<link rel="stylesheet" href="ui.css">
+				<style>
+					container { flex-direction: column; align-items: center }
+				</style>
+			</head>
+			<body>
+				<container>
+					<view id="health-gauge" class="gauge" data-kind="hp" style="box-shadow: 0 2px 4px #000"></view>
+					<character id="player" tabindex="1">Hero</character>
+					<view lazy hidden style="background: linear-gradient(90deg, red, blue)"><view id="2nd"></view></view>
+				</container>
+			</body>
+		</html>`
+	code, err := generate(src, config{pkg: "ui", typ: "MainUI", source: "ui.html", fsys: fsys})
+	require.NoError(t, err)
+
+	s := string(code)
+	for _, want := range []string{
+		"// Code generated by furexgen from ui.html. DO NOT EDIT.",
+		"func NewMainUI(components furex.ComponentsMap) *MainUI {",
+		"package ui",
+		`"image/color"`,
+		"type MainUI struct {",
+		"HealthGauge *furex.View // id=\"health-gauge\"",
+		"ID2nd       *furex.View // id=\"2nd\"",
+		`v0 := furex.NewComponentView("container", nil, components)`,
+		"v0.Direction = furex.Column",
+		"v0.AlignItems = furex.AlignItemCenter",
+		"v1.Width = 120",
+		"v1.BackgroundColor = color.NRGBA{255, 0, 0, 255}",
+		`"data-kind": "hp"`,
+		"v1.BoxShadow = &furex.BoxShadow{OffsetY: 2, Blur: 4, Color: color.NRGBA{0, 0, 0, 255}}",
+		`v2 := furex.NewComponentView("character", map[string]string{"id": "player", "tabindex": "1"}, components)`,
+		"v2.TabIndex = furex.Int(1)",
+		`v2.Text = "Hero"`,
+		"v3.Hidden = true",
+		"v3.BackgroundGradient = &furex.LinearGradient{Angle: 90, Stops: []furex.GradientStop{{Color: color.NRGBA{255, 0, 0, 255}}, {Offset: 1, Color: color.NRGBA{0, 0, 255, 255}}}}",
+		"v0.AddChild(v1)",
+		"v0.Layout()",
+		"ui.Root = v0",
+	} {
+		require.True(t, strings.Contains(s, want), "missing %q in\n%s", want, s)
+	}
+}
+
+func TestGenerateBackgroundImage(t *testing.T) {
+	fsys := fstest.MapFS{"img/panel.png": {}}
+	src := `<view style="background-image: url(img/panel.png); background-repeat: repeat; background-slice: 4px"></view>`
+	code, err := generate(src, config{pkg: "ui", typ: "UI", source: "ui.html", fsys: fsys})
+	require.NoError(t, err)
+
+	s := string(code)
+	for _, want := range []string{
+		"func NewUI(components furex.ComponentsMap, images furex.ImageResolver) *UI {",
+		`v0.BackgroundImage = images("img/panel.png")`,
+		"v0.BackgroundRepeat = furex.BackgroundRepeatXY",
+		"v0.BackgroundSlice = furex.Insets{Top: 4, Right: 4, Bottom: 4, Left: 4}",
+	} {
+		require.True(t, strings.Contains(s, want), "missing %q in\n%s", want, s)
+	}
+
+	_, err = generate(`<view style="background-image: url(missing.png)"></view>`, config{pkg: "ui", typ: "UI", source: "ui.html", fsys: fsys})
+	require.Error(t, err)
+}
+
+func TestGenerateTransition(t *testing.T) {
+	src := `<view style="transition: opacity 200ms ease-out, width 1s"></view>`
+	code, err := generate(src, config{pkg: "ui", typ: "UI", source: "ui.html"})
+	require.NoError(t, err)
+
+	s := string(code)
+	for _, want := range []string{
+		`"time"`,
+		`v0.Transitions = []furex.Transition{{Property: "opacity", Duration: 200 * time.Millisecond, Easing: "ease-out"}, {Property: "width", Duration: 1000 * time.Millisecond}}`,
+	} {
+		require.True(t, strings.Contains(s, want), "missing %q in\n%s", want, s)
+	}
+}
+
+func TestGenerateFontFamily(t *testing.T) {
+	src := `<view style="font-family: 'Noto Sans JP', pixel"></view>`
+	code, err := generate(src, config{pkg: "ui", typ: "UI", source: "ui.html"})
+	require.NoError(t, err)
+
+	want := `v0.FontFamily = []string{"Noto Sans JP", "pixel"}`
+	require.True(t, strings.Contains(string(code), want), "missing %q in\n%s", want, code)
+}
+
+func TestGenerateInvalidMarkup(t *testing.T) {
+	_, err := generate(`<view></view><view></view>`, config{pkg: "ui", typ: "UI", source: "ui.html"})
+	require.Error(t, err)
+}
+
+func TestGoName(t *testing.T) {
+	require.Equal(t, "HealthGauge", goName("health-gauge"))
+	require.Equal(t, "MainMenu", goName("main_menu"))
+	require.Equal(t, "Ok", goName("ok"))
+}
+
+func TestGenerateBind(t *testing.T) {
+	src := `
+		<container>
+			<view id="health-gauge"></view>
+			<view id="health-gauge"></view>
+			<view lazy><view id="menu"></view></view>
+		</container>`
+	code, err := generate(src, config{pkg: "ui", typ: "MainUI", source: "ui.html", bind: true})
+	require.NoError(t, err)
+
+	s := string(code)
+	for _, want := range []string{
+		"type MainUI struct {",
+		"HealthGauge *furex.View // id=\"health-gauge\"",
+		"Menu        *furex.View // id=\"menu\"",
+		"func BindMainUI(root *furex.View) (*MainUI, error) {",
+		"if v, ok := root.GetByID(\"health-gauge\"); ok {",
+		"missing = append(missing, \"health-gauge\")",
+		"ui.Menu, _ = root.GetByID(\"menu\")",
+		`return nil, fmt.Errorf("ui.html: views not found: %s", strings.Join(missing, ", "))`,
+	} {
+		require.True(t, strings.Contains(s, want), "missing %q in\n%s", want, s)
+	}
+	require.False(t, strings.Contains(s, "NewComponentView"))
+	require.Equal(t, 1, strings.Count(s, "HealthGauge *furex.View"))
+}
+
+func TestGenerateTextViews(t *testing.T) {
+	code, err := generate(`<view><view>Score</view><badge>NEW</badge></view>`, config{pkg: "ui", typ: "UI", source: "ui.html"})
+	require.NoError(t, err)
+
+	s := string(code)
+	for _, want := range []string{
+		`v2 := furex.NewComponentView("text", nil, components)`,
+		`v2.Text = "Score"`,
+		`v3 := furex.NewComponentView("badge", nil, components)`,
+		`v3.Text = "NEW"`,
+	} {
+		require.True(t, strings.Contains(s, want), "missing %q in\n%s", want, s)
+	}
+	require.Equal(t, 4, strings.Count(s, "furex.NewComponentView("))
+}
+
+func TestGenerateComponentAttrs(t *testing.T) {
+	code, err := generate(`<view><slider min="0" max="10" value="8"></slider><spacer size="6"></spacer></view>`, config{pkg: "ui", typ: "UI", source: "ui.html"})
+	require.NoError(t, err)
+
+	s := string(code)
+	for _, want := range []string{
+		`v1 := furex.NewComponentView("slider", map[string]string{"max": "10", "min": "0", "value": "8"}, components)`,
+		`v2 := furex.NewComponentView("spacer", map[string]string{"size": "6"}, components)`,
+	} {
+		require.True(t, strings.Contains(s, want), "missing %q in\n%s", want, s)
+	}
+	require.False(t, strings.Contains(s, ".Attrs ="))
+
+	// the components are configured by the attributes like by Parse
+	slider := furex.NewComponentView("slider", map[string]string{"max": "10", "min": "0", "value": "8"}, nil)
+	require.Equal(t, 10.0, slider.Handler.(*furex.Slider).Max)
+	require.Equal(t, 8.0, slider.Handler.(*furex.Slider).Value)
+	require.Equal(t, "8", slider.Attrs["value"])
+}
+
+func TestGenerateUnknownField(t *testing.T) {
+	// an image set by the program is not referenced by the document
+	g := &generator{ids: map[string]string{}, names: map[string]bool{}, images: map[*ebiten.Image]string{}}
+	root := (&furex.View{TagName: "view"}).AddChild(&furex.View{TagName: "panel", BackdropSource: ebiten.NewImage(1, 1)})
+	_, err := g.view(root)
+	require.EqualError(t, err, "<panel>: cannot generate the field BackdropSource of type *ebiten.Image")
+}
@@ -0,0 +1,79 @@
+// Command furexgen generates Go code constructing the view tree of an HTML
+// document at build time, so the document is not parsed at runtime and
+// markup errors are reported when the code is generated.
+//
+// Usage:
+//
+//	//go:generate go run github.com/yohamta/furex/v2/cmd/furexgen -in ui.html -type MainUI
+//
+// The generated file declares a struct with the root view and a field for
+// every element with an id, and a constructor taking the components of
+// the custom tags:
+//
+//	ui := NewMainUI(furex.ComponentsMap{"character": &Sprite{}})
+//	ui.HealthGauge.SetWidth(10)
+//
+// Styles that depend on the state (pseudo-classes) or the screen size
+// (media queries) are resolved when the code is generated and are not
+// updated at runtime.
+//
+// With -bind, the document is still parsed at runtime and the generated
+// Bind function fills the struct from the parsed tree, reporting the
+// missing ids as an error instead of nil views later:
+//
+//	ui, err := BindMainUI(furex.Parse(html, opts))
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	in := flag.String("in", "", "input HTML file")
+	out := flag.String("out", "", "output Go file (default: <in>_gen.go)")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	typ := flag.String("type", "", "name of the generated struct (default: from the input file name)")
+	bind := flag.Bool("bind", false, "generate a Bind function for a view tree parsed at runtime instead of a constructor")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *out == "" {
+		*out = strings.TrimSuffix(*in, filepath.Ext(*in)) + "_gen.go"
+	}
+	if *pkg == "" {
+		*pkg = "main"
+	}
+	if *typ == "" {
+		*typ = goName(strings.TrimSuffix(filepath.Base(*in), filepath.Ext(*in)))
+	}
+
+	src, err := os.ReadFile(*in)
+	if err != nil {
+		fail(err)
+	}
+	code, err := generate(string(src), config{
+		pkg:    *pkg,
+		typ:    *typ,
+		source: filepath.Base(*in),
+		fsys:   os.DirFS(filepath.Dir(*in)),
+		bind:   *bind,
+	})
+	if err != nil {
+		fail(fmt.Errorf("%s: %w", *in, err))
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "furexgen:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,103 @@
+package furex
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+// parseColor parses a CSS color such as `#fff`, `#ff000080`,
+// `rgb(255, 0, 0)`, `rgba(255, 0, 0, 0.5)` or a named color.
+func parseColor(val string) (any, error) {
+	val = strings.ToLower(strings.TrimSpace(val))
+	switch {
+	case strings.HasPrefix(val, "#"):
+		return parseHexColor(val[1:])
+	case strings.HasPrefix(val, "rgb(") || strings.HasPrefix(val, "rgba("):
+		return parseRGBColor(val)
+	case val == "transparent":
+		return color.RGBA{}, nil
+	}
+	if c, ok := colornames.Map[val]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("unknown color: %s", val)
+}
+
+func parseHexColor(hex string) (color.Color, error) {
+	switch len(hex) {
+	case 3, 4:
+		// expand the short form: #rgb(a) -> #rrggbb(aa)
+		sb := strings.Builder{}
+		for _, r := range hex {
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		}
+		hex = sb.String()
+	case 6, 8:
+	default:
+		return nil, fmt.Errorf("invalid color: #%s", hex)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: #%s", hex)
+	}
+	c := color.NRGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}
+	return c, nil
+}
+
+func parseRGBColor(val string) (color.Color, error) {
+	open, end := strings.IndexByte(val, '('), strings.LastIndexByte(val, ')')
+	if end < open {
+		return nil, fmt.Errorf("invalid color: %s", val)
+	}
+	args := strings.Split(val[open+1:end], ",")
+	if len(args) != 3 && len(args) != 4 {
+		return nil, fmt.Errorf("invalid color: %s", val)
+	}
+	var rgb [3]uint8
+	for i := 0; i < 3; i++ {
+		v, err := parseColorComponent(args[i], 255)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color: %s", val)
+		}
+		rgb[i] = uint8(v + 0.5)
+	}
+	a := 1.0
+	if len(args) == 4 {
+		v, err := parseColorComponent(args[3], 1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color: %s", val)
+		}
+		a = v
+	}
+	return color.NRGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: uint8(a*255 + 0.5)}, nil
+}
+
+// parseColorComponent parses a number or a percentage clamped to [0, max].
+func parseColorComponent(s string, max float64) (float64, error) {
+	s = strings.TrimSpace(s)
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSuffix(s, "%")
+		scale = max / 100
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	v *= scale
+	if v < 0 {
+		v = 0
+	}
+	if v > max {
+		v = max
+	}
+	return v, nil
+}
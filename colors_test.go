@@ -0,0 +1,60 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		val  string
+		want color.Color
+	}{
+		{"#f00", color.NRGBA{255, 0, 0, 255}},
+		{"#f008", color.NRGBA{255, 0, 0, 136}},
+		{"#00FF00", color.NRGBA{0, 255, 0, 255}},
+		{"#0000ff80", color.NRGBA{0, 0, 255, 128}},
+		{"rgb(10, 20, 30)", color.NRGBA{10, 20, 30, 255}},
+		{"rgba(10, 20, 30, 0.5)", color.NRGBA{10, 20, 30, 128}},
+		{"rgb(100%, 0%, 300, 50%)", color.NRGBA{255, 0, 255, 128}},
+		{"red", color.RGBA{255, 0, 0, 255}},
+		{"CornflowerBlue", color.RGBA{100, 149, 237, 255}},
+		{"transparent", color.RGBA{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseColor(tt.val)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, val := range []string{"#12", "#ggg", "rgb(1, 2)", "rgb(a, b, c)", "no-such-color"} {
+		_, err := parseColor(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestBackgroundColor(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				.panel { background-color: #336699 }
+				.panel:disabled { background-color: gray }
+			</style>
+		</head>
+		<body>
+			<view><view id="panel" class="panel"></view></view>
+		</body>`, nil)
+
+	panel := view.MustGetByID("panel")
+	require.Equal(t, color.NRGBA{0x33, 0x66, 0x99, 0xff}, panel.BackgroundColor)
+
+	panel.SetDisabled(true)
+	require.Equal(t, color.RGBA{128, 128, 128, 255}, panel.BackgroundColor)
+
+	styleMapper["background-color"].setFunc(panel, nil)
+	require.Nil(t, panel.BackgroundColor)
+}
@@ -0,0 +1,58 @@
+package furex
+
+import (
+	"image"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// columnRuns returns the runs of the text flowed across the columns of
+// the view. The text is wrapped at the width of a column, and the lines
+// are balanced between the columns, filling them from left to right and
+// aligned in them by align.
+func columnRuns(v *View, face font.Face, frame image.Rectangle, align TextAlign) []textRun {
+	n := v.Columns
+	width := (frame.Dx() - v.ColumnGap*(n-1)) / n
+	if width <= 0 {
+		return nil
+	}
+	lines, ends := wrapText(v.Text, face, width)
+	perColumn := (len(lines) + n - 1) / n
+	var runs []textRun
+	for i := 0; i < n && i*perColumn < len(lines); i++ {
+		end := (i + 1) * perColumn
+		if end > len(lines) {
+			end = len(lines)
+		}
+		x := frame.Min.X + i*(width+v.ColumnGap)
+		col := image.Rect(x, frame.Min.Y, x+width, frame.Max.Y)
+		runs = append(runs, alignLines(lines[i*perColumn:end], ends[i*perColumn:end], face, col, align, VerticalAlignTop)...)
+	}
+	return runs
+}
+
+// wrapText breaks the text into lines that fit in the width, at spaces
+// and at the line breaks of the text. Words wider than the width are
+// put on their own lines. ends reports the lines ending a paragraph.
+func wrapText(s string, face font.Face, width int) (lines []string, ends []bool) {
+	space := font.MeasureString(face, " ").Ceil()
+	for _, para := range strings.Split(s, "\n") {
+		line, lineWidth := "", 0
+		for _, w := range strings.Fields(para) {
+			ww := font.MeasureString(face, w).Ceil()
+			if line != "" && lineWidth+space+ww > width {
+				lines, ends = append(lines, line), append(ends, false)
+				line, lineWidth = "", 0
+			}
+			if line != "" {
+				line += " "
+				lineWidth += space
+			}
+			line += w
+			lineWidth += ww
+		}
+		lines, ends = append(lines, line), append(ends, true)
+	}
+	return lines, ends
+}
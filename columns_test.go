@@ -0,0 +1,42 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestWrapText(t *testing.T) {
+	// 7px per glyph: 5 glyphs fit in 35px
+	face := basicfont.Face7x13
+	lines, ends := wrapText("aa bb cc\ndddddddd e", face, 35)
+	require.Equal(t, []string{"aa bb", "cc", "dddddddd", "e"}, lines)
+	require.Equal(t, []bool{false, true, false, true}, ends)
+}
+
+func TestColumnRuns(t *testing.T) {
+	face := basicfont.Face7x13
+	v := Parse(`<view style="columns: 2; column-gap: 10px"></view>`, nil)
+	require.Equal(t, 2, v.Columns)
+	require.Equal(t, 10, v.ColumnGap)
+
+	v.Text = "aa bb cc dd ee"
+	// two columns of 35px
+	frame := image.Rect(0, 0, 80, 100)
+	require.Equal(t, []textRun{
+		{"aa bb", 0, 11}, {"cc dd", 0, 24},
+		{"ee", 45, 11},
+	}, columnRuns(v, face, frame, v.TextAlign))
+
+	// the lines are justified except the last one of the text
+	v.TextAlign = TextAlignJustify
+	v.Text = "aa b cc d e"
+	require.Equal(t, []textRun{
+		{"aa", 0, 11}, {"b", 28, 11}, {"cc", 0, 24}, {"d", 28, 24},
+		{"e", 45, 11},
+	}, columnRuns(v, face, frame, v.TextAlign))
+
+	require.Nil(t, columnRuns(v, face, image.Rect(0, 0, 10, 10), v.TextAlign))
+}
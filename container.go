@@ -7,7 +7,6 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/yohamta/furex/v2/internal/graphic"
 )
 
@@ -16,6 +15,9 @@ type containerEmbed struct {
 	isDirty  bool
 	frame    image.Rectangle
 	touchIDs []ebiten.TouchID
+	// raised is the child drawn above the others, e.g. the child dragged
+	// by Reorderable.
+	raised *View
 
 	calculatedWidth  int
 	calculatedHeight int
@@ -28,17 +30,62 @@ func (ct *containerEmbed) processEvent() {
 
 // Draw draws it's children
 func (ct *containerEmbed) Draw(screen *ebiten.Image) {
+	var raised *child
 	for _, c := range ct.children {
+		if c.item == ct.raised {
+			raised = c
+			continue
+		}
 		ct.drawChild(screen, c)
 	}
+	if raised != nil {
+		ct.drawChild(screen, raised)
+	}
 }
 
 func (ct *containerEmbed) drawChild(screen *ebiten.Image, child *child) {
-	b := ct.computeBounds(child)
+	drawModalLayer(child.item)
+	if child.item.isTransparent() {
+		return
+	}
+	b, offset := child.item.flipFrame(ct.computeBounds(child).Add(drawOffset))
+	if offset != (image.Point{}) {
+		// the descendants move with the child
+		saved := drawOffset
+		drawOffset = drawOffset.Add(offset)
+		defer func() { drawOffset = saved }()
+	}
+	draw := func(target *ebiten.Image) {
+		ct.drawChildContent(target, b, child)
+	}
+	visible := screen != nil && !child.item.Hidden && child.item.isDisplayed()
+	if visible && child.item.isRenderScaled() {
+		content := draw
+		draw = func(target *ebiten.Image) {
+			child.item.drawRenderScaled(target, b, content)
+		}
+	}
+	if t := child.item.Transform; t != nil && !t.isIdentity() && visible {
+		child.item.drawTransformed(screen, b, draw)
+		return
+	}
+	draw(screen)
+}
+
+func (ct *containerEmbed) drawChildContent(screen *ebiten.Image, b image.Rectangle, child *child) {
+	if !child.item.Hidden && child.item.isDisplayed() {
+		child.item.drawBackground(screen, b)
+	}
 	if ct.shouldDrawChild(child) {
 		ct.handleDraw(screen, b, child)
 	}
+	if !child.item.Hidden && child.item.isDisplayed() {
+		child.item.drawBorder(screen, b)
+	}
 	child.item.Draw(screen)
+	if !child.item.Hidden && child.item.isDisplayed() {
+		child.item.drawOutline(screen, b)
+	}
 	ct.debugDraw(screen, b, child)
 }
 
@@ -50,21 +97,27 @@ func (ct *containerEmbed) computeBounds(child *child) image.Rectangle {
 }
 
 func (ct *containerEmbed) handleDraw(screen *ebiten.Image, b image.Rectangle, child *child) {
+	if h, ok := child.item.Handler.(batchDrawer); ok {
+		h.drawBatch(screen, b, child.item)
+		return
+	}
+	batch.Flush()
 	if h, ok := child.item.Handler.(DrawHandler); ok {
-		h.HandleDraw(screen, b)
+		h.HandleDraw(screen, handlerFrame(h, b))
 		return
 	}
 	if h, ok := child.item.Handler.(Drawer); ok {
-		h.Draw(screen, b, child.item)
+		h.Draw(screen, handlerFrame(h, b), child.item)
 	}
 }
 
 func (ct *containerEmbed) shouldDrawChild(child *child) bool {
-	return !child.item.Hidden && child.item.Display != DisplayNone && child.item.Handler != nil
+	return !child.item.Hidden && child.item.isDisplayed() && child.item.Handler != nil
 }
 
 func (ct *containerEmbed) debugDraw(screen *ebiten.Image, b image.Rectangle, child *child) {
 	if Debug {
+		batch.Flush()
 		pos := fmt.Sprintf("(%d, %d)-(%d, %d):%s:%s", b.Min.X, b.Min.Y, b.Max.X, b.Max.Y, child.item.TagName, child.item.ID)
 		graphic.FillRect(screen, &graphic.FillRectOpts{
 			Color: color.RGBA{0, 0, 0, 200},
@@ -78,9 +131,10 @@ func (ct *containerEmbed) HandleJustPressedTouchID(touchID ebiten.TouchID, x, y
 	for c := len(ct.children) - 1; c >= 0; c-- {
 		child := ct.children[c]
 		childFrame := ct.childFrame(child)
-		if child.item.Display == DisplayNone {
+		if !child.item.isDisplayed() || child.item.Disabled {
 			continue
 		}
+		x, y := child.item.untransform(*childFrame, x, y)
 		if child.HandleJustPressedTouchID(childFrame, touchID, x, y) {
 			return true
 		}
@@ -95,6 +149,7 @@ func (ct *containerEmbed) HandleJustReleasedTouchID(touchID ebiten.TouchID, x, y
 	for c := len(ct.children) - 1; c >= 0; c-- {
 		child := ct.children[c]
 		childFrame := ct.childFrame(child)
+		x, y := child.item.untransform(*childFrame, x, y)
 		child.HandleJustReleasedTouchID(childFrame, touchID, x, y)
 		child.item.HandleJustReleasedTouchID(touchID, x, y)
 	}
@@ -104,9 +159,10 @@ func (ct *containerEmbed) handleMouse(x, y int) bool {
 	for c := len(ct.children) - 1; c >= 0; c-- {
 		child := ct.children[c]
 		childFrame := ct.childFrame(child)
-		if child.item.Display == DisplayNone {
+		if !child.item.isDisplayed() || child.item.Disabled {
 			continue
 		}
+		x, y := child.item.untransform(*childFrame, x, y)
 		mouseHandler, ok := child.item.Handler.(MouseHandler)
 		if ok && mouseHandler != nil {
 			if isInside(childFrame, x, y) {
@@ -127,9 +183,10 @@ func (ct *containerEmbed) handleMouseEnterLeave(x, y int) bool {
 	for c := len(ct.children) - 1; c >= 0; c-- {
 		child := ct.children[c]
 		childFrame := ct.childFrame(child)
-		if child.item.Display == DisplayNone {
+		if !child.item.isDisplayed() || child.item.Disabled {
 			continue
 		}
+		x, y := child.item.untransform(*childFrame, x, y)
 		mouseHandler, ok := child.item.Handler.(MouseEnterLeaveHandler)
 		if ok {
 			if !result && !child.isMouseEntered && isInside(childFrame, x, y) {
@@ -158,9 +215,10 @@ func (ct *containerEmbed) handleMouseButtonLeftPressed(x, y int) bool {
 	for c := len(ct.children) - 1; c >= 0; c-- {
 		child := ct.children[c]
 		childFrame := ct.childFrame(child)
-		if child.item.Display == DisplayNone {
+		if !child.item.isDisplayed() || child.item.Disabled {
 			continue
 		}
+		x, y := child.item.untransform(*childFrame, x, y)
 		mouseLeftClickHandler, ok := child.item.Handler.(MouseLeftButtonHandler)
 		if ok {
 			if !result && isInside(childFrame, x, y) {
@@ -201,11 +259,13 @@ func (ct *containerEmbed) handleMouseButtonLeftPressed(x, y int) bool {
 func (ct *containerEmbed) handleMouseButtonLeftReleased(x, y int) {
 	for c := len(ct.children) - 1; c >= 0; c-- {
 		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		lx, ly := child.item.untransform(*childFrame, x, y)
 		mouseLeftClickHandler, ok := child.item.Handler.(MouseLeftButtonHandler)
 		if ok {
 			if child.isMouseLeftButtonHandler {
 				child.isMouseLeftButtonHandler = false
-				mouseLeftClickHandler.HandleJustReleasedMouseButtonLeft(x, y)
+				mouseLeftClickHandler.HandleJustReleasedMouseButtonLeft(lx, ly)
 			}
 		}
 
@@ -217,12 +277,12 @@ func (ct *containerEmbed) handleMouseButtonLeftReleased(x, y int) {
 				if x == 0 && y == 0 {
 					button.HandleRelease(x, y, true)
 				} else {
-					button.HandleRelease(x, y, !isInside(ct.childFrame(child), x, y))
+					button.HandleRelease(lx, ly, !isInside(childFrame, lx, ly))
 				}
 			}
 		}
 
-		child.item.handleMouseButtonLeftReleased(x, y)
+		child.item.handleMouseButtonLeftReleased(lx, ly)
 	}
 }
 
@@ -231,12 +291,12 @@ func isInside(r *image.Rectangle, x, y int) bool {
 }
 
 func (ct *containerEmbed) handleTouchEvents() {
-	justPressedTouchIds := inpututil.AppendJustPressedTouchIDs(nil)
+	justPressedTouchIds := appendJustPressedTouchIDs(nil)
 
 	if justPressedTouchIds != nil {
 		for i := 0; i < len(justPressedTouchIds); i++ {
 			touchID := justPressedTouchIds[i]
-			x, y := ebiten.TouchPosition(touchID)
+			x, y := currentTouchPosition(touchID)
 			recordTouchPosition(touchID, x, y)
 
 			ct.HandleJustPressedTouchID(touchID, x, y)
@@ -246,24 +306,24 @@ func (ct *containerEmbed) handleTouchEvents() {
 
 	touchIDs := ct.touchIDs
 	for t := range touchIDs {
-		if inpututil.IsTouchJustReleased(touchIDs[t]) {
+		if isTouchJustReleased(touchIDs[t]) {
 			pos := lastTouchPosition(touchIDs[t])
 			ct.HandleJustReleasedTouchID(touchIDs[t], pos.X, pos.Y)
 		} else {
-			x, y := ebiten.TouchPosition(touchIDs[t])
+			x, y := currentTouchPosition(touchIDs[t])
 			recordTouchPosition(touchIDs[t], x, y)
 		}
 	}
 }
 
 func (ct *containerEmbed) handleMouseEvents() {
-	x, y := ebiten.CursorPosition()
+	x, y := cursorPosition()
 	ct.handleMouse(x, y)
 	ct.handleMouseEnterLeave(x, y)
-	if inpututil.IsMouseButtonJustPressed((ebiten.MouseButtonLeft)) {
+	if isMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 		ct.handleMouseButtonLeftPressed(x, y)
 	}
-	if inpututil.IsMouseButtonJustReleased((ebiten.MouseButtonLeft)) {
+	if isMouseButtonJustReleased(ebiten.MouseButtonLeft) {
 		ct.handleMouseButtonLeftReleased(x, y)
 	}
 }
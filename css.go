@@ -0,0 +1,418 @@
+package furex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stylesheet is the list of rules declared in <style> elements.
+type stylesheet struct {
+	rules []*cssRule
+	// hasDynamicRules is true if some rules depend on the state of views
+	// or the size of the root view.
+	hasDynamicRules bool
+	// keyframes are the @keyframes rules by name.
+	keyframes map[string]*Keyframes
+	// matches and matchTime record the style calculations for StyleStats.
+	matches   int
+	matchTime time.Duration
+}
+
+// cssRule is a rule with a single selector.
+// A rule with a selector list is split into one rule per selector.
+type cssRule struct {
+	selector *cssSelector
+	media    *mediaQuery
+	decls    []cssDecl
+	order    int
+	// hits is the number of times the rule matched a view.
+	hits int
+}
+
+// isDynamic returns true if the rule is re-evaluated while the UI is running.
+func (r *cssRule) isDynamic() bool {
+	return r.media != nil || r.selector.isDynamic()
+}
+
+type cssDecl struct {
+	property  string
+	value     string
+	important bool
+}
+
+// cssSelector is a complex selector such as `.panel > div.item`.
+type cssSelector struct {
+	text string
+	// parts are the compound selectors from left to right.
+	parts       []cssCompound
+	specificity specificity
+}
+
+// cssCompound is a compound selector such as `div.item#id`.
+type cssCompound struct {
+	// combinator is the relation to the part on the left:
+	// ' ' for descendant and '>' for child.
+	combinator byte
+	tag        string
+	id         string
+	classes    []string
+	attrs      []cssAttrSelector
+	pseudos    []string
+}
+
+type cssAttrSelector struct {
+	name, value string
+	hasValue    bool
+}
+
+// specificity is the (id, class, type) triple of a selector.
+type specificity [3]int
+
+func (s specificity) less(o specificity) bool {
+	for i := range s {
+		if s[i] != o[i] {
+			return s[i] < o[i]
+		}
+	}
+	return false
+}
+
+func parseStylesheet(css string) (*stylesheet, error) {
+	sheet := &stylesheet{}
+	errs := &ErrorList{}
+	sheet.parseRules(stripComments(css), nil, errs)
+	if errs.HasErrors() {
+		return sheet, errs
+	}
+	return sheet, nil
+}
+
+func (sheet *stylesheet) parseRules(css string, media *mediaQuery, errs *ErrorList) {
+	for {
+		open := strings.IndexByte(css, '{')
+		if open == -1 {
+			break
+		}
+		end := matchingBrace(css, open)
+		if end == -1 {
+			errs.Add(fmt.Errorf("unclosed block: %s", strings.TrimSpace(css[:open])))
+			break
+		}
+		prelude := strings.TrimSpace(css[:open])
+		body := css[open+1 : end]
+		css = css[end+1:]
+		if strings.HasPrefix(prelude, "@media") {
+			mq, err := parseMediaQuery(strings.TrimPrefix(prelude, "@media"))
+			if err != nil {
+				errs.Add(err)
+				continue
+			}
+			sheet.parseRules(body, mq.and(media), errs)
+			continue
+		}
+		if strings.HasPrefix(prelude, "@keyframes") {
+			name := strings.TrimSpace(strings.TrimPrefix(prelude, "@keyframes"))
+			kf, err := parseKeyframes(name, body)
+			if err != nil {
+				errs.Add(err)
+				continue
+			}
+			if sheet.keyframes == nil {
+				sheet.keyframes = map[string]*Keyframes{}
+			}
+			sheet.keyframes[name] = kf
+			continue
+		}
+		if strings.HasPrefix(prelude, "@") {
+			// other at-rules are not supported
+			continue
+		}
+		decls := parseDecls(body)
+		for _, s := range strings.Split(prelude, ",") {
+			sel, err := parseSelector(s)
+			if err != nil {
+				errs.Add(err)
+				continue
+			}
+			r := &cssRule{
+				selector: sel,
+				media:    media,
+				decls:    decls,
+				order:    len(sheet.rules),
+			}
+			sheet.rules = append(sheet.rules, r)
+			if r.isDynamic() {
+				sheet.hasDynamicRules = true
+			}
+		}
+	}
+}
+
+func stripComments(css string) string {
+	for {
+		start := strings.Index(css, "/*")
+		if start == -1 {
+			return css
+		}
+		end := strings.Index(css[start+2:], "*/")
+		if end == -1 {
+			return css[:start]
+		}
+		css = css[:start] + css[start+2+end+2:]
+	}
+}
+
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseDecls parses a declaration block such as `width: 10px; height: 20px`.
+func parseDecls(block string) []cssDecl {
+	var decls []cssDecl
+	for _, pair := range strings.Split(block, ";") {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		d := cssDecl{
+			property: strings.ToLower(strings.TrimSpace(kv[0])),
+			value:    strings.TrimSpace(kv[1]),
+		}
+		if v := strings.TrimSuffix(d.value, "!important"); v != d.value {
+			d.value = strings.TrimSpace(v)
+			d.important = true
+		}
+		if d.property == "" {
+			continue
+		}
+		decls = append(decls, d)
+	}
+	return decls
+}
+
+func parseSelector(text string) (*cssSelector, error) {
+	text = strings.TrimSpace(text)
+	sel := &cssSelector{text: text}
+	if text == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+	combinator := byte(' ')
+	for _, tok := range strings.Fields(strings.ReplaceAll(text, ">", " > ")) {
+		if tok == ">" {
+			if len(sel.parts) == 0 {
+				return nil, fmt.Errorf("invalid selector: %s", text)
+			}
+			combinator = '>'
+			continue
+		}
+		c, err := parseCompound(tok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %s: %w", text, err)
+		}
+		c.combinator = combinator
+		combinator = ' '
+		sel.parts = append(sel.parts, c)
+		sel.specificity[0] += boolToInt(c.id != "")
+		sel.specificity[1] += len(c.classes) + len(c.attrs) + len(c.pseudos)
+		sel.specificity[2] += boolToInt(c.tag != "" && c.tag != "*")
+	}
+	if combinator == '>' {
+		return nil, fmt.Errorf("invalid selector: %s", text)
+	}
+	return sel, nil
+}
+
+func parseCompound(s string) (cssCompound, error) {
+	c := cssCompound{}
+	i := 0
+	readIdent := func() string {
+		start := i
+		for i < len(s) && !strings.ContainsRune(".#[:", rune(s[i])) {
+			i++
+		}
+		return s[start:i]
+	}
+	c.tag = strings.ToLower(readIdent())
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			i++
+			name := readIdent()
+			if name == "" {
+				return c, fmt.Errorf("empty class name")
+			}
+			c.classes = append(c.classes, name)
+		case '#':
+			i++
+			c.id = readIdent()
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end == -1 {
+				return c, fmt.Errorf("unclosed attribute selector")
+			}
+			a := cssAttrSelector{name: s[i+1 : i+end]}
+			if k, v, ok := strings.Cut(a.name, "="); ok {
+				a.name, a.value, a.hasValue = k, strings.Trim(v, `"'`), true
+			}
+			c.attrs = append(c.attrs, a)
+			i += end + 1
+		case ':':
+			i++
+			name := strings.ToLower(readIdent())
+			if !isPseudoClass(name) {
+				return c, fmt.Errorf("unsupported pseudo-class: %s", name)
+			}
+			c.pseudos = append(c.pseudos, name)
+		default:
+			return c, fmt.Errorf("unsupported selector: %s", s[i:])
+		}
+	}
+	return c, nil
+}
+
+// matchRules returns the rules that apply to the view in the cascade order.
+func (s *stylesheet) matchRules(v *View, ancestors []*View) []*cssRule {
+	if s == nil {
+		return nil
+	}
+	start := time.Now()
+	root := v
+	if len(ancestors) > 0 {
+		root = ancestors[0]
+	}
+	var matched []*cssRule
+	for _, r := range s.rules {
+		if r.media != nil && !r.media.matches(root.Width, root.Height) {
+			continue
+		}
+		if r.selector.matches(v, ancestors) {
+			r.hits++
+			matched = append(matched, r)
+		}
+	}
+	s.matches++
+	s.matchTime += time.Since(start)
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].selector.specificity.less(matched[j].selector.specificity)
+	})
+	return matched
+}
+
+// isDynamic returns true if the selector depends on the state of views.
+func (sel *cssSelector) isDynamic() bool {
+	for _, p := range sel.parts {
+		if len(p.pseudos) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (sel *cssSelector) matches(v *View, ancestors []*View) bool {
+	last := len(sel.parts) - 1
+	if !sel.parts[last].matches(v) {
+		return false
+	}
+	return sel.matchAncestors(last, ancestors)
+}
+
+// matchAncestors checks the parts on the left of parts[i] against the ancestors.
+func (sel *cssSelector) matchAncestors(i int, ancestors []*View) bool {
+	if i == 0 {
+		return true
+	}
+	part := sel.parts[i]
+	for j := len(ancestors) - 1; j >= 0; j-- {
+		if sel.parts[i-1].matches(ancestors[j]) && sel.matchAncestors(i-1, ancestors[:j]) {
+			return true
+		}
+		if part.combinator == '>' {
+			return false
+		}
+	}
+	return false
+}
+
+func (c *cssCompound) matches(v *View) bool {
+	if c.tag != "" && c.tag != "*" && c.tag != v.TagName {
+		return false
+	}
+	if c.id != "" && c.id != v.ID {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(v.Attrs["class"])
+		for _, cls := range c.classes {
+			if !containsString(classes, cls) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		val, ok := v.Attrs[a.name]
+		if !ok || (a.hasValue && val != a.value) {
+			return false
+		}
+	}
+	for _, p := range c.pseudos {
+		if !v.hasPseudoClass(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPseudoClass(name string) bool {
+	switch name {
+	case "hover", "active", "disabled", "focus", "checked", "selected":
+		return true
+	}
+	return false
+}
+
+// cascade orders the declarations from the stylesheet and the inline style.
+// Inline declarations override the stylesheet and important declarations
+// override normal ones.
+func cascade(sheetDecls, inlineDecls []cssDecl) []cssDecl {
+	var normal, important []cssDecl
+	for _, decls := range [][]cssDecl{sheetDecls, inlineDecls} {
+		for _, d := range decls {
+			if d.important {
+				important = append(important, d)
+			} else {
+				normal = append(normal, d)
+			}
+		}
+	}
+	return append(normal, important...)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,231 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCascade(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				/* later and more specific rules win */
+				.large { width: 200px; height: 100px }
+				.panel { width: 100px; height: 50px; margin-left: 1px }
+				div.panel { margin-left: 2px }
+				#main { margin-top: 3px }
+				.panel.large { margin-right: 4px }
+				.outer > .panel { margin-bottom: 5px }
+				.outer .inner { margin-bottom: 6px }
+				.missing .inner { margin-bottom: 7px }
+				[data-kind=hero] { flex-grow: 2 !important }
+			</style>
+		</head>
+		<body>
+			<div class="outer">
+				<div id="main" class="panel large" style="height: 10px; flex-grow: 1"></div>
+				<div id="second" class="large panel"></div>
+				<view>
+					<div id="third" class="panel inner" data-kind="hero"></div>
+				</view>
+			</div>
+		</body>`, nil)
+
+	main := view.MustGetByID("main")
+	require.Equal(t, 100, main.Width)
+	require.Equal(t, 10, main.Height)
+	require.Equal(t, 2, main.MarginLeft)
+	require.Equal(t, 3, main.MarginTop)
+	require.Equal(t, 4, main.MarginRight)
+	require.Equal(t, 5, main.MarginBottom)
+	require.Equal(t, 1., main.Grow)
+
+	second := view.MustGetByID("second")
+	require.Equal(t, 100, second.Width)
+	require.Equal(t, 50, second.Height)
+
+	third := view.MustGetByID("third")
+	require.Equal(t, 0, third.MarginRight)
+	require.Equal(t, 6, third.MarginBottom)
+	require.Equal(t, 2., third.Grow)
+}
+
+func TestParseSelector(t *testing.T) {
+	for _, tt := range []struct {
+		selector    string
+		specificity specificity
+		wantErr     bool
+	}{
+		{selector: "div", specificity: specificity{0, 0, 1}},
+		{selector: ".a.b", specificity: specificity{0, 2, 0}},
+		{selector: "div#id.a > .b [x]", specificity: specificity{1, 3, 1}},
+		{selector: "*", specificity: specificity{0, 0, 0}},
+		{selector: "> .a", wantErr: true},
+		{selector: ".a >", wantErr: true},
+		{selector: ".", wantErr: true},
+	} {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, err := parseSelector(tt.selector)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.specificity, sel.specificity)
+		})
+	}
+}
+
+func TestPseudoClasses(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				.button { width: 100px; height: 50px }
+				.button:hover { width: 110px }
+				.button:active { height: 40px }
+				.button:disabled { margin-left: 5px }
+				.button:hover .label { margin-top: 2px }
+				.button:focus { margin-right: 3px }
+			</style>
+		</head>
+		<body>
+			<view style="align-items: flex-start">
+				<view id="button" class="button" tabindex="0">
+					<view id="label" class="label"></view>
+				</view>
+			</view>
+		</body>`, &ParseOptions{Width: 200, Height: 200})
+	view.Draw(nil)
+
+	button := view.MustGetByID("button")
+	label := view.MustGetByID("label")
+	require.Equal(t, 100, button.Width)
+	require.Equal(t, 50, button.Height)
+
+	in := []image.Point{image.Pt(10, 10)}
+	out := []image.Point{image.Pt(150, 150)}
+
+	view.updatePseudoClasses(in, nil)
+	require.Equal(t, 110, button.Width)
+	require.Equal(t, 50, button.Height)
+	require.Equal(t, 2, label.MarginTop)
+
+	view.updatePseudoClasses(in, in)
+	require.Equal(t, 110, button.Width)
+	require.Equal(t, 40, button.Height)
+
+	view.updatePseudoClasses(out, nil)
+	require.Equal(t, 100, button.Width)
+	require.Equal(t, 50, button.Height)
+	require.Equal(t, 0, label.MarginTop)
+
+	button.SetDisabled(true)
+	require.Equal(t, 5, button.MarginLeft)
+	view.updatePseudoClasses(in, in)
+	require.Equal(t, 50, button.Height)
+	button.SetDisabled(false)
+	require.Equal(t, 0, button.MarginLeft)
+
+	button.Focus()
+	require.Equal(t, 3, button.MarginRight)
+	button.Blur()
+	require.Equal(t, 0, button.MarginRight)
+}
+
+func TestPseudoClassOffsets(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				.badge { position: absolute; left: 10px; top: 10px; width: 20px; height: 20px }
+				.badge:hover { right: 4px; bottom: 6px }
+			</style>
+		</head>
+		<body>
+			<view>
+				<view id="badge" class="badge"></view>
+			</view>
+		</body>`, &ParseOptions{Width: 200, Height: 200})
+	view.Draw(nil)
+
+	badge := view.MustGetByID("badge")
+	require.Nil(t, badge.Right)
+	require.Nil(t, badge.Bottom)
+
+	in := []image.Point{image.Pt(15, 15)}
+	out := []image.Point{image.Pt(150, 150)}
+	view.updatePseudoClasses(in, nil)
+	require.Equal(t, Int(4), badge.Right)
+	require.Equal(t, Int(6), badge.Bottom)
+
+	// the offsets are unset again rather than set to 0
+	view.updatePseudoClasses(out, nil)
+	require.Nil(t, badge.Right)
+	require.Nil(t, badge.Bottom)
+}
+
+func TestMediaQueries(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				.panel { width: 100px; height: 50px }
+				@media (max-width: 400px) {
+					.panel { width: 50px }
+				}
+				@media (min-width: 300px) and (orientation: portrait), print {
+					.panel { height: 80px }
+				}
+			</style>
+		</head>
+		<body>
+			<view style="align-items: flex-start">
+				<view id="panel" class="panel" style="margin-left: 1px"></view>
+			</view>
+		</body>`, &ParseOptions{Width: 800, Height: 600})
+
+	panel := view.MustGetByID("panel")
+	require.Equal(t, 100, panel.Width)
+	require.Equal(t, 50, panel.Height)
+
+	view.UpdateWithSize(320, 480)
+	require.Equal(t, 320, view.Width)
+	require.Equal(t, 50, panel.Width)
+	require.Equal(t, 80, panel.Height)
+	require.Equal(t, 1, panel.MarginLeft)
+
+	view.UpdateWithSize(200, 100)
+	require.Equal(t, 50, panel.Width)
+	require.Equal(t, 50, panel.Height)
+
+	view.UpdateWithSize(800, 600)
+	require.Equal(t, 100, panel.Width)
+	require.Equal(t, 50, panel.Height)
+}
+
+func TestParseMediaQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		width  int
+		height int
+		want   bool
+	}{
+		{"screen", 100, 100, true},
+		{"print", 100, 100, false},
+		{"(min-width: 100px)", 100, 10, true},
+		{"(min-width: 100px)", 99, 10, false},
+		{"screen and (max-height: 50px)", 10, 60, false},
+		{"(orientation: landscape)", 20, 10, true},
+		{"(max-width: 10px), (min-height: 100px)", 50, 100, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			mq, err := parseMediaQuery(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, mq.matches(tt.width, tt.height))
+		})
+	}
+
+	_, err := parseMediaQuery("(color: 8)")
+	require.Error(t, err)
+}
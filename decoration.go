@@ -0,0 +1,172 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// batch draws the backgrounds, borders and nine-patches of views with
+// a few DrawTriangles calls. It is flushed before other handlers draw.
+var batch = &graphic.Batch{}
+
+// Overflow is the 'overflow' property
+type Overflow uint8
+
+const (
+	// OverflowVisible draws the children outside the frame of the view.
+	OverflowVisible Overflow = iota
+	// OverflowHidden clips the children to the frame of the view
+	// inside the border, with the rounded corners of BorderRadius.
+	OverflowHidden
+	// OverflowScroll clips the children like OverflowHidden and scrolls
+	// them (see Scroll).
+	OverflowScroll
+)
+
+func (o Overflow) String() string {
+	switch o {
+	case OverflowVisible:
+		return "visible"
+	case OverflowHidden:
+		return "hidden"
+	case OverflowScroll:
+		return "scroll"
+	}
+	return fmt.Sprintf("unknown overflow: %d", o)
+}
+
+// drawBackground draws the background of the view in the frame.
+func (v *View) drawBackground(screen *ebiten.Image, frame image.Rectangle) {
+	opacity := v.EffectiveOpacity()
+	v.drawBackdrop(screen, frame, opacity)
+	v.drawShadow(screen, frame, opacity)
+	if frame.Empty() {
+		return
+	}
+	if c := v.BackgroundCheckerboard; c != nil {
+		c.draw(screen, frame, opacity)
+	}
+	if c := v.BackgroundColor; c != nil {
+		c = fade(c, opacity)
+		if _, _, _, a := c.RGBA(); a != 0 {
+			if v.BorderRadius > 0 {
+				batch.FillRoundedRect(screen, frame, v.BorderRadius, c)
+			} else {
+				batch.FillRect(screen, frame, c)
+			}
+		}
+	}
+	if g := v.BackgroundGradient; g != nil && len(g.Stops) > 0 {
+		stops := g.stops()
+		for i := range stops {
+			stops[i].Color = fade(stops[i].Color, opacity)
+		}
+		batch.FillLinearGradient(screen, frame, v.BorderRadius, g.Angle, stops)
+	}
+	v.drawBackgroundImage(screen, frame, opacity)
+}
+
+// drawBorder strokes the border of the view inside the frame.
+func (v *View) drawBorder(screen *ebiten.Image, frame image.Rectangle) {
+	if frame.Empty() {
+		return
+	}
+	if v.hasSideBorders() {
+		v.drawSideBorders(screen, frame)
+		return
+	}
+	if v.BorderWidth <= 0 {
+		return
+	}
+	c := v.BorderColor
+	if c == nil {
+		c = color.Black
+	}
+	c = fade(c, v.EffectiveOpacity())
+	if v.BorderRadius > 0 {
+		batch.StrokeRoundedRect(screen, frame, v.BorderRadius, v.BorderWidth, c)
+		return
+	}
+	batch.StrokeRect(screen, frame, v.BorderWidth, c)
+}
+
+// drawOutline strokes the outline of the view around the frame, rounded
+// like the border if the outline is solid.
+func (v *View) drawOutline(screen *ebiten.Image, frame image.Rectangle) {
+	w := v.OutlineWidth
+	if w <= 0 || frame.Empty() {
+		return
+	}
+	rect := frame.Inset(-v.OutlineOffset - w)
+	if rect.Empty() {
+		return
+	}
+	c := v.OutlineColor
+	if c == nil {
+		c = color.Black
+	}
+	c = fade(c, v.EffectiveOpacity())
+	if v.OutlineStyle != BorderSolid {
+		for i, r := range sideRects(rect, w, w, w, w) {
+			drawBorderLine(screen, r, w, v.OutlineStyle, i%2 == 0, c)
+		}
+		return
+	}
+	if v.BorderRadius > 0 {
+		if radius := v.BorderRadius + v.OutlineOffset + w; radius > 0 {
+			batch.StrokeRoundedRect(screen, rect, radius, w, c)
+			return
+		}
+	}
+	batch.StrokeRect(screen, rect, w, c)
+}
+
+// drawChildren draws the children clipping them if the overflow is hidden
+// or scroll.
+func (v *View) drawChildren(screen *ebiten.Image) {
+	v.detectLayoutChanges()
+	if v.Overflow == OverflowVisible || screen == nil {
+		v.containerEmbed.Draw(screen)
+		return
+	}
+	frame := v.frame.Add(drawOffset)
+	if o := v.ScrollOffset(); o != (image.Point{}) {
+		// the children are drawn scrolled
+		saved := drawOffset
+		drawOffset = drawOffset.Sub(o)
+		defer func() { drawOffset = saved }()
+	}
+	clip := targetRect(frame.Inset(v.BorderWidth)).Intersect(screen.Bounds())
+	if clip.Empty() {
+		return
+	}
+	radius := v.BorderRadius - v.BorderWidth
+	if radius <= 0 {
+		v.releaseClipImage()
+		batch.Flush()
+		v.containerEmbed.Draw(screen.SubImage(clip).(*ebiten.Image))
+		batch.Flush()
+		return
+	}
+	// the children are drawn offscreen and masked with the rounded rect
+	if v.clipImage == nil || v.clipImage.Bounds() != clip {
+		v.releaseClipImage()
+		v.clipImage = ebiten.NewImageWithOptions(clip, nil)
+	}
+	batch.Flush()
+	v.clipImage.Clear()
+	v.containerEmbed.Draw(v.clipImage)
+	batch.Flush()
+	batch.DrawRoundedImage(screen, v.clipImage, frame.Inset(v.BorderWidth), radius)
+}
+
+func (v *View) releaseClipImage() {
+	if v.clipImage != nil {
+		v.clipImage.Dispose()
+		v.clipImage = nil
+	}
+}
@@ -0,0 +1,123 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBorder(t *testing.T) {
+	tests := []struct {
+		val  string
+		want cssBorder
+	}{
+		{"1px solid red", cssBorder{1, color.RGBA{255, 0, 0, 255}, BorderSolid}},
+		{"2px", cssBorder{width: 2}},
+		{"3px solid rgba(0, 0, 0, 0.5)", cssBorder{3, color.NRGBA{0, 0, 0, 128}, BorderSolid}},
+		{"2px dashed red", cssBorder{2, color.RGBA{255, 0, 0, 255}, BorderDashed}},
+		{"1px dotted", cssBorder{width: 1, style: BorderDotted}},
+		{"none", cssBorder{}},
+	}
+	for _, tt := range tests {
+		got, err := parseBorder(tt.val)
+		require.NoError(t, err, tt.val)
+		require.Equal(t, tt.want, got, tt.val)
+	}
+	_, err := parseBorder("1px double")
+	require.Error(t, err)
+
+	view := Parse(`<view style="border-width: 2px; border-color: #fff"><view id="a" style="border: 1px solid blue"></view></view>`, nil)
+	require.Equal(t, 2, view.BorderWidth)
+	require.Equal(t, color.NRGBA{255, 255, 255, 255}, view.BorderColor)
+	require.Equal(t, 1, view.MustGetByID("a").BorderWidth)
+}
+
+func TestDrawBatching(t *testing.T) {
+	screen := ebiten.NewImage(200, 200)
+	root := &View{Width: 200, Height: 200, BackgroundColor: color.White, Wrap: Wrap}
+	for i := 0; i < 100; i++ {
+		root.AddChild(&View{Width: 10, Height: 10, BackgroundColor: color.Black, BorderWidth: 1})
+	}
+	calls := batch.DrawCalls
+	root.Draw(screen)
+	require.Equal(t, calls+1, batch.DrawCalls)
+
+	// other handlers flush the batch to keep the drawing order
+	root.AddChild(&View{Width: 10, Height: 10, Handler: &Canvas{}}, &View{Width: 10, Height: 10, BackgroundColor: color.Black})
+	calls = batch.DrawCalls
+	root.Draw(screen)
+	require.Equal(t, calls+2, batch.DrawCalls)
+}
+
+func TestBorderRadius(t *testing.T) {
+	view := Parse(`<view style="width: 100px; height: 100px"><view id="card" style="width: 50px; height: 40px; border-radius: 8px; border-width: 2px; overflow: hidden; background-color: red"><view style="width: 60px; height: 60px"></view></view></view>`, nil)
+	card := view.MustGetByID("card")
+	require.Equal(t, 8, card.BorderRadius)
+	require.Equal(t, OverflowHidden, card.Overflow)
+
+	screen := ebiten.NewImage(100, 100)
+	view.Draw(screen)
+	require.NotNil(t, card.clipImage)
+	require.Equal(t, image.Rect(2, 2, 48, 38), card.clipImage.Bounds())
+	require.Equal(t, int64(46*36*4), card.MemoryUsage().ImageBytes)
+
+	card.Release()
+	require.Nil(t, card.clipImage)
+
+	// rectangular clipping does not need an offscreen image
+	card.BorderRadius = 0
+	view.Draw(screen)
+	require.Nil(t, card.clipImage)
+}
+
+func TestSideBorders(t *testing.T) {
+	view := Parse(`<view style="border: 1px dashed #fff">
+		<view id="a" style="border-bottom: 2px dotted red; border-left: 3px solid"></view>
+		<view id="b" style="border-top: 2px solid blue; border: 1px solid"></view>
+	</view>`, nil)
+	require.Equal(t, BorderDashed, view.BorderStyle)
+	a := view.MustGetByID("a")
+	require.Equal(t, &Border{Width: 2, Color: color.RGBA{255, 0, 0, 255}, Style: BorderDotted}, a.BorderBottom)
+	require.Equal(t, [4]Border{{}, {}, *a.BorderBottom, {Width: 3}}, a.sides())
+	// the border shorthand resets the sides
+	require.Nil(t, view.MustGetByID("b").BorderTop)
+	require.False(t, view.MustGetByID("b").hasSideBorders())
+
+	screen := ebiten.NewImage(20, 20)
+	batch.Flush()
+	// dashes of 6 pixels 4 pixels apart, cut at the end
+	drawBorderLine(screen, image.Rect(0, 0, 18, 2), 2, BorderDashed, true, color.White)
+	require.Equal(t, 2*2, batch.Len())
+	batch.Flush()
+	drawBorderLine(screen, image.Rect(0, 0, 1, 10), 1, BorderDotted, false, color.White)
+	require.Equal(t, 5*2, batch.Len())
+	batch.Flush()
+
+	view.Draw(screen)
+}
+
+func TestOutline(t *testing.T) {
+	view := Parse(`<view style="width: 100px; height: 100px; align-items: flex-start">
+		<view id="a" style="width: 20px; height: 20px; outline: 2px solid red; outline-offset: 3px"></view>
+		<view id="b" style="width: 20px; height: 20px; outline-width: 1px; outline-style: dotted"></view>
+	</view>`, nil)
+	a := view.MustGetByID("a")
+	require.Equal(t, 2, a.OutlineWidth)
+	require.Equal(t, color.Color(color.RGBA{255, 0, 0, 255}), a.OutlineColor)
+	require.Equal(t, 3, a.OutlineOffset)
+	require.Equal(t, BorderDotted, view.MustGetByID("b").OutlineStyle)
+
+	screen := ebiten.NewImage(100, 100)
+	view.Draw(screen)
+	// the outline does not affect the layout
+	require.Equal(t, image.Rect(0, 0, 20, 20), a.frame)
+	require.Equal(t, image.Rect(20, 0, 40, 20), view.MustGetByID("b").frame)
+
+	batch.Flush()
+	a.drawOutline(screen, a.frame)
+	require.Equal(t, 4*2, batch.Len())
+	batch.Flush()
+}
@@ -0,0 +1,307 @@
+package furex
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// designLayer is a layer of a CSS file exported by a design tool.
+type designLayer struct {
+	name  string
+	decls []cssDecl
+	// rect is the position on the canvas, if the layer is positioned.
+	rect       image.Rectangle
+	positioned bool
+	flex       bool
+
+	parent   *designLayer
+	children []*designLayer
+}
+
+// designTextProps are kept as data-* attributes since they are not
+// styles of views but are useful to set up text components.
+var designTextProps = map[string]bool{
+	"font-family": true, "font-size": true, "font-weight": true, "font-style": true,
+	"line-height": true, "letter-spacing": true, "text-align": true, "color": true,
+}
+
+// designIgnoredProps are layout hints of design tools with no effect in furex.
+var designIgnoredProps = map[string]bool{
+	"position": true, "left": true, "top": true, "order": true, "flex": true, "box-sizing": true,
+}
+
+var fractionalPx = regexp.MustCompile(`-?\d*\.\d+px`)
+
+// ImportDesignCSS converts CSS exported by design tools into a furex HTML
+// document to bootstrap a screen from a mockup.
+//
+// The CSS is a list of layers, either rules (`.button { ... }`) or groups
+// of declarations named by a preceding comment (`/* Button */`) as copied
+// from Figma and similar tools. The left and top of positioned layers are
+// read as positions on the canvas: each layer is nested in the smallest
+// layer containing it and positioned absolutely in it. Layers without a
+// position are added to the preceding flex layer (auto layout), or to
+// the root.
+//
+// Fractional pixels are rounded, fonts and text colors are kept as data-*
+// attributes (e.g. data-font-size), and declarations furex does not support
+// are written in a comment inside the element.
+func ImportDesignCSS(css string) (string, error) {
+	layers, err := parseDesignLayers(css)
+	if err != nil {
+		return "", err
+	}
+	if len(layers) == 0 {
+		return "", fmt.Errorf("no layers found")
+	}
+	// the names of layers are not unique in design tools
+	seen := map[string]int{}
+	for _, l := range layers {
+		seen[l.name]++
+		if n := seen[l.name]; n > 1 {
+			l.name = fmt.Sprintf("%s-%d", l.name, n)
+		}
+	}
+	root := nestDesignLayers(layers)
+	sb := &strings.Builder{}
+	writeDesignLayer(sb, root, image.Point{}, 0)
+	return sb.String(), nil
+}
+
+// parseDesignLayers splits the CSS into layers named by selectors or comments.
+func parseDesignLayers(css string) ([]*designLayer, error) {
+	var layers []*designLayer
+	name := ""
+	var loose strings.Builder
+	flush := func() {
+		if decls := parseDecls(loose.String()); len(decls) > 0 {
+			layers = append(layers, newDesignLayer(name, decls, len(layers)))
+		}
+		loose.Reset()
+	}
+	for i := 0; i < len(css); {
+		switch {
+		case strings.HasPrefix(css[i:], "/*"):
+			end := strings.Index(css[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			// a comment names the following declarations
+			flush()
+			name = strings.TrimSpace(css[i+2 : i+2+end])
+			i += end + 4
+		case css[i] == '{':
+			end := strings.IndexByte(css[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated block")
+			}
+			// the text after the last declaration is the selector
+			text := loose.String()
+			selector := text[strings.LastIndexByte(text, ';')+1:]
+			loose.Reset()
+			loose.WriteString(text[:len(text)-len(selector)])
+			flush()
+			if n := designSelectorName(selector); n != "" {
+				name = n
+			}
+			if decls := parseDecls(css[i+1 : i+end]); len(decls) > 0 {
+				layers = append(layers, newDesignLayer(name, decls, len(layers)))
+			}
+			name = ""
+			i += end + 1
+		default:
+			loose.WriteByte(css[i])
+			i++
+		}
+	}
+	flush()
+	return layers, nil
+}
+
+// designSelectorName returns the last class or id of the selector.
+func designSelectorName(selector string) string {
+	fields := strings.Fields(selector)
+	if len(fields) == 0 {
+		return ""
+	}
+	last := fields[len(fields)-1]
+	if i := strings.LastIndexAny(last, ".#"); i != -1 {
+		return last[i+1:]
+	}
+	return last
+}
+
+func newDesignLayer(name string, decls []cssDecl, index int) *designLayer {
+	l := &designLayer{name: designID(name)}
+	if l.name == "" {
+		l.name = fmt.Sprintf("layer-%d", index+1)
+	}
+	var x, y, w, h int
+	var hasX, hasY bool
+	for _, d := range decls {
+		d.value = fractionalPx.ReplaceAllStringFunc(d.value, func(s string) string {
+			f, _ := strconv.ParseFloat(strings.TrimSuffix(s, "px"), 64)
+			return fmt.Sprintf("%dpx", int(math.Round(f)))
+		})
+		n, err := parseNumber(d.value)
+		switch d.property {
+		case "left":
+			x, hasX = asInt(n), err == nil
+		case "top":
+			y, hasY = asInt(n), err == nil
+		case "width":
+			w = asInt(n)
+		case "height":
+			h = asInt(n)
+		case "display":
+			l.flex = d.value == "flex"
+		case "border-radius":
+			// per-corner radii are approximated by the first one
+			d.value = strings.Fields(d.value + " 0")[0]
+		}
+		l.decls = append(l.decls, d)
+	}
+	if hasX && hasY {
+		l.rect = image.Rect(x, y, x+w, y+h)
+		l.positioned = true
+	}
+	return l
+}
+
+func asInt(n any) int {
+	if i, ok := n.(int); ok {
+		return i
+	}
+	return 0
+}
+
+// designID converts a layer name such as "Frame 1" to an id "frame-1".
+func designID(name string) string {
+	sb := &strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return sb.String()
+}
+
+// nestDesignLayers builds the tree of the layers and returns the root.
+func nestDesignLayers(layers []*designLayer) *designLayer {
+	var positioned []*designLayer
+	for _, l := range layers {
+		if l.positioned {
+			positioned = append(positioned, l)
+		}
+	}
+	// larger layers are candidate parents of smaller ones
+	sort.SliceStable(positioned, func(i, j int) bool {
+		return area(positioned[i].rect) > area(positioned[j].rect)
+	})
+	for i, l := range positioned {
+		for j := i - 1; j >= 0; j-- {
+			if l.rect.In(positioned[j].rect) {
+				l.parent = positioned[j]
+				break
+			}
+		}
+	}
+	var lastFlex *designLayer
+	for _, l := range layers {
+		if !l.positioned {
+			l.parent = lastFlex
+		}
+		if l.flex {
+			lastFlex = l
+		}
+	}
+	var roots []*designLayer
+	for _, l := range layers {
+		if l.parent != nil {
+			l.parent.children = append(l.parent.children, l)
+		} else {
+			roots = append(roots, l)
+		}
+	}
+	if len(roots) == 1 {
+		return roots[0]
+	}
+	// a root sized to the bounding box of the top level layers
+	root := &designLayer{name: "root", positioned: true}
+	for i, l := range roots {
+		if i == 0 {
+			root.rect = l.rect
+		} else {
+			root.rect = root.rect.Union(l.rect)
+		}
+		l.parent = root
+	}
+	root.children = roots
+	root.decls = []cssDecl{
+		{property: "width", value: fmt.Sprintf("%dpx", root.rect.Dx())},
+		{property: "height", value: fmt.Sprintf("%dpx", root.rect.Dy())},
+	}
+	return root
+}
+
+func area(r image.Rectangle) int {
+	return r.Dx() * r.Dy()
+}
+
+// writeDesignLayer writes the view of the layer. origin is the position
+// of the parent on the canvas.
+func writeDesignLayer(sb *strings.Builder, l *designLayer, origin image.Point, depth int) {
+	indent := strings.Repeat("  ", depth)
+	var styles, attrs, unsupported []string
+	if l.positioned && l.parent != nil {
+		p := l.rect.Min.Sub(origin)
+		styles = append(styles, "position: absolute", fmt.Sprintf("left: %dpx", p.X), fmt.Sprintf("top: %dpx", p.Y))
+	}
+	for _, d := range l.decls {
+		switch {
+		case designIgnoredProps[d.property]:
+		case designTextProps[d.property]:
+			attrs = append(attrs, fmt.Sprintf(`data-%s="%s"`, d.property, html.EscapeString(d.value)))
+		default:
+			m, ok := styleMapper[d.property]
+			if !ok {
+				unsupported = append(unsupported, d.property+": "+d.value)
+				continue
+			}
+			if _, err := m.parseFunc(d.value); err != nil {
+				unsupported = append(unsupported, d.property+": "+d.value)
+				continue
+			}
+			styles = append(styles, d.property+": "+d.value)
+		}
+	}
+	fmt.Fprintf(sb, `%s<view id="%s"`, indent, l.name)
+	if len(styles) > 0 {
+		fmt.Fprintf(sb, ` style="%s"`, html.EscapeString(strings.Join(styles, "; ")))
+	}
+	for _, a := range attrs {
+		sb.WriteString(" " + a)
+	}
+	sb.WriteString(">\n")
+	if len(unsupported) > 0 {
+		fmt.Fprintf(sb, "%s  <!-- unsupported: %s -->\n", indent, strings.ReplaceAll(strings.Join(unsupported, "; "), "--", "- -"))
+	}
+	origin = l.rect.Min
+	for _, c := range l.children {
+		writeDesignLayer(sb, c, origin, depth+1)
+	}
+	fmt.Fprintf(sb, "%s</view>\n", indent)
+}
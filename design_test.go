@@ -0,0 +1,105 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportDesignCSS(t *testing.T) {
+	css := `
+/* Login */
+position: absolute;
+width: 375px;
+height: 812px;
+left: 0px;
+top: 0px;
+background: #FFFFFF;
+
+/* Card */
+position: absolute;
+width: 327.5px;
+height: 200px;
+left: 24px;
+top: 100px;
+background: linear-gradient(180deg, #FF0000 0%, #0000FF 100%);
+border-radius: 12px 12px 0px 0px;
+box-shadow: 0px 4px 4px rgba(0, 0, 0, 0.25);
+
+.title {
+  position: absolute;
+  width: 100px;
+  height: 20px;
+  left: 40px;
+  top: 120px;
+  font-family: 'Inter';
+  font-size: 16px;
+  color: #333333;
+}
+
+/* Buttons */
+display: flex;
+flex-direction: row;
+gap: 8px;
+position: absolute;
+width: 200px;
+height: 40px;
+left: 24px;
+top: 400px;
+
+/* Button */
+width: 96px;
+height: 40px;
+flex-grow: 0;
+order: 0;
+
+/* Button */
+width: 96px;
+height: 40px;
+`
+	src, err := ImportDesignCSS(css)
+	require.NoError(t, err)
+	require.Contains(t, src, `<!-- unsupported: gap: 8px -->`)
+	require.Contains(t, src, `data-font-family="&#39;Inter&#39;"`)
+
+	view := Parse(src, nil)
+	require.Equal(t, "login", view.ID)
+	require.Equal(t, color.NRGBA{255, 255, 255, 255}, view.BackgroundColor)
+
+	card := view.MustGetByID("card")
+	require.Equal(t, PositionAbsolute, card.Position)
+	require.Equal(t, 24, card.Left)
+	require.Equal(t, 100, card.Top)
+	require.Equal(t, 328, card.Width)
+	require.Equal(t, 12, card.BorderRadius)
+	require.NotNil(t, card.BackgroundGradient)
+	require.NotNil(t, card.BoxShadow)
+
+	title := view.MustGetByID("title")
+	require.Equal(t, card, title.parent)
+	require.Equal(t, 16, title.Left)
+	require.Equal(t, 20, title.Top)
+	require.Equal(t, "16px", title.Attrs["data-font-size"])
+	require.Equal(t, "#333333", title.Attrs["data-color"])
+
+	buttons := view.MustGetByID("buttons")
+	require.Equal(t, view, buttons.parent)
+	require.Len(t, buttons.Children(), 2)
+	require.Equal(t, PositionStatic, view.MustGetByID("button-2").Position)
+}
+
+func TestImportDesignCSSTopLevelLayers(t *testing.T) {
+	src, err := ImportDesignCSS(`
+		.a { position: absolute; left: 10px; top: 10px; width: 10px; height: 10px }
+		.b { position: absolute; left: 30px; top: 20px; width: 10px; height: 10px }`)
+	require.NoError(t, err)
+	view := Parse(src, nil)
+	require.Equal(t, "root", view.ID)
+	require.Equal(t, 30, view.Width)
+	require.Equal(t, 20, view.Height)
+	require.Equal(t, 20, view.MustGetByID("b").Left)
+
+	_, err = ImportDesignCSS(`/* only a comment */`)
+	require.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package furex
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// EasingFunc maps the progress of an animation from 0 to 1 to the
+// progress of the value, which can overshoot for elastic and back easings.
+type EasingFunc func(t float64) float64
+
+// easings are the easings by name. Besides the CSS keywords, the standard
+// easings are named "ease-in-<name>", "ease-out-<name>" and
+// "ease-in-out-<name>" for quad, cubic, elastic, back and bounce.
+var easings = map[string]EasingFunc{
+	"linear":      func(t float64) float64 { return t },
+	"ease":        cubicBezier(0.25, 0.1, 0.25, 1),
+	"ease-in":     cubicBezier(0.42, 0, 1, 1),
+	"ease-out":    cubicBezier(0, 0, 0.58, 1),
+	"ease-in-out": cubicBezier(0.42, 0, 0.58, 1),
+}
+
+func init() {
+	for name, in := range map[string]EasingFunc{
+		"quad":    func(t float64) float64 { return t * t },
+		"cubic":   func(t float64) float64 { return t * t * t },
+		"elastic": easeInElastic,
+		"back":    easeInBack,
+		"bounce":  func(t float64) float64 { return 1 - easeOutBounce(1-t) },
+	} {
+		in := in
+		out := func(t float64) float64 { return 1 - in(1-t) }
+		easings["ease-in-"+name] = in
+		easings["ease-out-"+name] = out
+		easings["ease-in-out-"+name] = func(t float64) float64 {
+			if t < 0.5 {
+				return in(2*t) / 2
+			}
+			return 0.5 + out(2*t-1)/2
+		}
+	}
+}
+
+// RegisterEasing registers the easing by name, so that it can be used in
+// the transition and animation properties and in Tween. It replaces the
+// easing of the same name, including the built-in ones. Easings used in
+// a document are registered before it is parsed.
+func RegisterEasing(name string, f EasingFunc) {
+	easings[name] = f
+}
+
+// Ease returns the eased progress t by the easing of the name.
+// It panics if the easing is unknown.
+func Ease(name string, t float64) float64 {
+	f, ok := easings[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown easing: %s", name))
+	}
+	return f(t)
+}
+
+// easingFunc returns the easing of the name. Unknown names are reported
+// when the style is parsed and fall back to ease.
+func easingFunc(name string) EasingFunc {
+	if f, ok := easings[name]; ok {
+		return f
+	}
+	return easings["ease"]
+}
+
+// cubicBezier returns the timing function of a CSS cubic-bezier().
+func cubicBezier(x1, y1, x2, y2 float64) EasingFunc {
+	bezier := func(a, b, t float64) float64 {
+		return 3*a*t*(1-t)*(1-t) + 3*b*t*t*(1-t) + t*t*t
+	}
+	return func(x float64) float64 {
+		// find t for x by bisection; x(t) is monotonic for 0 <= x1, x2 <= 1
+		lo, hi := 0.0, 1.0
+		for i := 0; i < 32; i++ {
+			mid := (lo + hi) / 2
+			if bezier(x1, x2, mid) < x {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return bezier(y1, y2, (lo+hi)/2)
+	}
+}
+
+func easeInElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*2*math.Pi/3)
+}
+
+func easeInBack(t float64) float64 {
+	const c1 = 1.70158
+	return (c1+1)*t*t*t - c1*t*t
+}
+
+func easeOutBounce(t float64) float64 {
+	const n1, d1 = 7.5625, 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	}
+	t -= 2.625 / d1
+	return n1*t*t + 0.984375
+}
+
+// Tween interpolates a value from From to To over Duration, advanced by
+// one tick per Update like transitions, for animating values that are not
+// style properties, e.g. a score counter or a custom handler.
+type Tween struct {
+	From     float64
+	To       float64
+	Duration time.Duration
+	Delay    time.Duration
+	// Easing is the name of the easing. "linear" is used if it is empty.
+	Easing string
+
+	tick int
+}
+
+// Update advances the tween by one tick and returns the value.
+func (t *Tween) Update() float64 {
+	if !t.Done() {
+		t.tick++
+	}
+	return t.Value()
+}
+
+// Value returns the current value.
+func (t *Tween) Value() float64 {
+	delay, ticks := durationTicks(t.Delay), durationTicks(t.Duration)
+	progress := 1.0
+	if ticks > 0 {
+		progress = math.Max(0, math.Min(1, float64(t.tick-delay)/float64(ticks)))
+	}
+	easing := "linear"
+	if t.Easing != "" {
+		easing = t.Easing
+	}
+	return t.From + (t.To-t.From)*easingFunc(easing)(progress)
+}
+
+// Done returns true if the tween reached To.
+func (t *Tween) Done() bool {
+	return t.tick >= durationTicks(t.Delay)+durationTicks(t.Duration)
+}
+
+// Reset rewinds the tween to From.
+func (t *Tween) Reset() {
+	t.tick = 0
+}
@@ -0,0 +1,67 @@
+package furex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEasing(t *testing.T) {
+	for name, f := range easings {
+		require.InDelta(t, 0, f(0), 1e-6, name)
+		require.InDelta(t, 1, f(1), 1e-6, name)
+	}
+	require.Greater(t, easingFunc("ease-out")(0.5), 0.5)
+	require.Less(t, easingFunc("ease-in")(0.5), 0.5)
+	require.InDelta(t, 0.5, easingFunc("ease-in-out")(0.5), 1e-6)
+
+	require.Equal(t, 0.25, Ease("ease-in-quad", 0.5))
+	require.Equal(t, 0.875, Ease("ease-out-cubic", 0.5))
+	require.Equal(t, 0.5, Ease("ease-in-out-quad", 0.5))
+	// back overshoots below zero and elastic beyond one
+	require.Less(t, Ease("ease-in-back", 0.2), 0.0)
+	require.Greater(t, Ease("ease-out-elastic", 0.2), 1.0)
+	// bounce touches 1 at the first bounce
+	require.InDelta(t, 1, Ease("ease-out-bounce", 1/2.75), 1e-9)
+	require.Panics(t, func() { Ease("unknown", 0.5) })
+}
+
+func TestRegisterEasing(t *testing.T) {
+	RegisterEasing("step-half", func(t float64) float64 {
+		if t < 0.5 {
+			return 0
+		}
+		return 1
+	})
+	defer delete(easings, "step-half")
+
+	val, err := parseTransition("opacity 1s step-half")
+	require.NoError(t, err)
+	require.Equal(t, "step-half", val.([]Transition)[0].Easing)
+	require.Equal(t, 0.0, Ease("step-half", 0.4))
+}
+
+func TestTween(t *testing.T) {
+	tw := &Tween{From: 10, To: 20, Duration: time.Second}
+	require.Equal(t, 10.0, tw.Value())
+	for i := 0; i < ebiten.TPS()/2; i++ {
+		tw.Update()
+	}
+	require.InDelta(t, 15, tw.Value(), 1e-9)
+	require.False(t, tw.Done())
+	for i := 0; i < ebiten.TPS(); i++ {
+		tw.Update()
+	}
+	require.Equal(t, 20.0, tw.Value())
+	require.True(t, tw.Done())
+
+	tw.Reset()
+	tw.Easing = "ease-in-quad"
+	tw.Delay = time.Second
+	for i := 0; i < ebiten.TPS()*3/2; i++ {
+		tw.Update()
+	}
+	require.InDelta(t, 12.5, tw.Value(), 1e-9)
+}
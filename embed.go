@@ -0,0 +1,162 @@
+package furex
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Widget is a widget of another Ebitengine UI library, such as ebitenui,
+// hosted in a view by Embed. Widgets of libraries with a different
+// signature can be wrapped in a small adapter type.
+type Widget interface {
+	// SetLocation sets the location of the widget relative to the window (0,0).
+	SetLocation(rect image.Rectangle)
+	// Render draws the widget.
+	Render(screen *ebiten.Image)
+}
+
+// WidgetUpdater is implemented by a Widget that updates by one tick.
+type WidgetUpdater interface {
+	Update()
+}
+
+// PreferredSizer is implemented by a Widget with a preferred size.
+// It is used as the size of the view when its width or height is not set.
+type PreferredSizer interface {
+	PreferredSize() (width, height int)
+}
+
+// Embed is a handler hosting a Widget inside the frame of the view,
+// so the layout of furex and widgets of other libraries can be mixed.
+//
+// The widget is located at the frame of the view whenever it changes,
+// and is updated and drawn with the view. Input is forwarded when the
+// widget implements the input handler interfaces of furex (MouseHandler,
+// MouseLeftButtonHandler, MouseEnterLeaveHandler, TouchHandler or
+// ButtonHandler); widgets polling the input themselves receive it as usual.
+type Embed struct {
+	Widget Widget
+
+	located    image.Rectangle
+	hasLocated bool
+}
+
+var _ Drawer = (*Embed)(nil)
+var _ Updater = (*Embed)(nil)
+var _ MouseHandler = (*Embed)(nil)
+var _ MouseLeftButtonHandler = (*Embed)(nil)
+var _ MouseEnterLeaveHandler = (*Embed)(nil)
+var _ TouchHandler = (*Embed)(nil)
+var _ ButtonHandler = (*Embed)(nil)
+var _ NotButton = (*Embed)(nil)
+
+// Update implements Updater.
+func (e *Embed) Update(v *View) {
+	if e.Widget == nil {
+		return
+	}
+	if s, ok := e.Widget.(PreferredSizer); ok && (v.Width == 0 || v.Height == 0) {
+		w, h := s.PreferredSize()
+		if v.Width == 0 && w > 0 {
+			v.SetWidth(w)
+		}
+		if v.Height == 0 && h > 0 {
+			v.SetHeight(h)
+		}
+	}
+	e.locate(v.frame)
+	if u, ok := e.Widget.(WidgetUpdater); ok {
+		u.Update()
+	}
+}
+
+// Draw implements Drawer.
+func (e *Embed) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	if e.Widget == nil {
+		return
+	}
+	e.locate(frame)
+	e.Widget.Render(screen)
+}
+
+func (e *Embed) locate(frame image.Rectangle) {
+	if !e.hasLocated || frame != e.located {
+		e.located, e.hasLocated = frame, true
+		e.Widget.SetLocation(frame)
+	}
+}
+
+// HandleMouse implements MouseHandler.
+func (e *Embed) HandleMouse(x, y int) bool {
+	if h, ok := e.Widget.(MouseHandler); ok {
+		return h.HandleMouse(x, y)
+	}
+	return false
+}
+
+// HandleJustPressedMouseButtonLeft implements MouseLeftButtonHandler.
+func (e *Embed) HandleJustPressedMouseButtonLeft(x, y int) bool {
+	if h, ok := e.Widget.(MouseLeftButtonHandler); ok {
+		return h.HandleJustPressedMouseButtonLeft(x, y)
+	}
+	return false
+}
+
+// HandleJustReleasedMouseButtonLeft implements MouseLeftButtonHandler.
+func (e *Embed) HandleJustReleasedMouseButtonLeft(x, y int) {
+	if h, ok := e.Widget.(MouseLeftButtonHandler); ok {
+		h.HandleJustReleasedMouseButtonLeft(x, y)
+	}
+}
+
+// HandleMouseEnter implements MouseEnterLeaveHandler.
+func (e *Embed) HandleMouseEnter(x, y int) bool {
+	if h, ok := e.Widget.(MouseEnterLeaveHandler); ok {
+		return h.HandleMouseEnter(x, y)
+	}
+	return false
+}
+
+// HandleMouseLeave implements MouseEnterLeaveHandler.
+func (e *Embed) HandleMouseLeave() {
+	if h, ok := e.Widget.(MouseEnterLeaveHandler); ok {
+		h.HandleMouseLeave()
+	}
+}
+
+// HandleJustPressedTouchID implements TouchHandler.
+func (e *Embed) HandleJustPressedTouchID(touch ebiten.TouchID, x, y int) bool {
+	if h, ok := e.Widget.(TouchHandler); ok {
+		return h.HandleJustPressedTouchID(touch, x, y)
+	}
+	return false
+}
+
+// HandleJustReleasedTouchID implements TouchHandler.
+func (e *Embed) HandleJustReleasedTouchID(touch ebiten.TouchID, x, y int) {
+	if h, ok := e.Widget.(TouchHandler); ok {
+		h.HandleJustReleasedTouchID(touch, x, y)
+	}
+}
+
+// HandlePress implements ButtonHandler.
+func (e *Embed) HandlePress(x, y int, t ebiten.TouchID) {
+	if h, ok := e.Widget.(ButtonHandler); ok {
+		h.HandlePress(x, y, t)
+	}
+}
+
+// HandleRelease implements ButtonHandler.
+func (e *Embed) HandleRelease(x, y int, isCancel bool) {
+	if h, ok := e.Widget.(ButtonHandler); ok {
+		h.HandleRelease(x, y, isCancel)
+	}
+}
+
+// IsButton implements NotButton. The view is a button only if the
+// widget is.
+func (e *Embed) IsButton() bool {
+	_, ok := e.Widget.(ButtonHandler)
+	return ok
+}
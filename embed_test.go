@@ -0,0 +1,62 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWidget struct {
+	location image.Rectangle
+	located  int
+	updated  int
+	rendered int
+	pressed  image.Point
+}
+
+func (w *mockWidget) SetLocation(rect image.Rectangle) {
+	w.location = rect
+	w.located++
+}
+
+func (w *mockWidget) Render(screen *ebiten.Image) { w.rendered++ }
+
+func (w *mockWidget) Update() { w.updated++ }
+
+func (w *mockWidget) PreferredSize() (int, int) { return 40, 20 }
+
+func (w *mockWidget) HandleJustPressedMouseButtonLeft(x, y int) bool {
+	w.pressed = image.Pt(x, y)
+	return true
+}
+
+func (w *mockWidget) HandleJustReleasedMouseButtonLeft(x, y int) {}
+
+func TestEmbed(t *testing.T) {
+	widget := &mockWidget{}
+	embed := &Embed{Widget: widget}
+	root := &View{Width: 100, Height: 100, Direction: Column}
+	view := &View{Width: 0, Height: 0, Handler: embed}
+	root.AddChild(&View{Height: 10}, view)
+
+	embed.Update(view)
+	root.startLayout()
+	require.Equal(t, 40, view.Width)
+	require.Equal(t, 20, view.Height)
+
+	embed.Update(view)
+	require.Equal(t, image.Rect(0, 10, 40, 30), widget.location)
+	require.Equal(t, 2, widget.updated)
+
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Equal(t, 1, widget.rendered)
+	// the widget is located again only when the frame changes
+	require.Equal(t, 2, widget.located)
+
+	require.True(t, embed.HandleJustPressedMouseButtonLeft(5, 15))
+	require.Equal(t, image.Pt(5, 15), widget.pressed)
+	require.False(t, embed.HandleMouse(5, 15))
+	require.False(t, embed.IsButton())
+}
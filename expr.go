@@ -0,0 +1,317 @@
+package furex
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// expr is an expression of the directives evaluated against the data of
+// the document, e.g. if="save && save.level > 1".
+//
+// The expressions have data paths (see lookupData), number, string
+// ('...' or "...") and boolean literals, comparisons (== != < <= > >=),
+// !, && and ||, and parentheses. Undefined paths are nil.
+type expr interface {
+	eval(data map[string]any) any
+}
+
+type (
+	pathExpr    string
+	literalExpr struct{ val any }
+	notExpr     struct{ x expr }
+	binaryExpr  struct {
+		op   string
+		x, y expr
+	}
+)
+
+func (e pathExpr) eval(data map[string]any) any {
+	v, _ := lookupData(data, string(e))
+	return v
+}
+
+func (e literalExpr) eval(map[string]any) any { return e.val }
+
+func (e notExpr) eval(data map[string]any) any { return !truthy(e.x.eval(data)) }
+
+func (e binaryExpr) eval(data map[string]any) any {
+	switch e.op {
+	case "&&":
+		return truthy(e.x.eval(data)) && truthy(e.y.eval(data))
+	case "||":
+		return truthy(e.x.eval(data)) || truthy(e.y.eval(data))
+	}
+	x, y := e.x.eval(data), e.y.eval(data)
+	switch e.op {
+	case "==":
+		return equalData(x, y)
+	case "!=":
+		return !equalData(x, y)
+	}
+	c, ok := compareData(x, y)
+	if !ok {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// evalCondition evaluates the expression and returns its truthiness.
+func evalCondition(s string, data map[string]any) (bool, error) {
+	e, err := parseExpr(s)
+	if err != nil {
+		return false, err
+	}
+	return truthy(e.eval(data)), nil
+}
+
+// truthy returns false for nil, false, zeros, empty strings and empty
+// collections, and true otherwise.
+func truthy(v any) bool {
+	if v == nil {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() > 0
+	case reflect.Pointer, reflect.Interface:
+		return !rv.IsNil()
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+func equalData(x, y any) bool {
+	if fx, ok := toFloat(x); ok {
+		fy, ok := toFloat(y)
+		return ok && fx == fy
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// compareData compares numbers or strings.
+func compareData(x, y any) (int, bool) {
+	if fx, ok := toFloat(x); ok {
+		fy, ok := toFloat(y)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fx < fy:
+			return -1, true
+		case fx > fy:
+			return 1, true
+		}
+		return 0, true
+	}
+	sx, ok := x.(string)
+	if !ok {
+		return 0, false
+	}
+	sy, ok := y.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(sx, sy), true
+}
+
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// parseExpr parses an expression.
+func parseExpr(s string) (expr, error) {
+	toks, err := tokenizeExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("invalid expression: %s", s)
+	}
+	return e, nil
+}
+
+type exprToken struct {
+	kind byte // 'p'ath, 'l'iteral or 'o'perator
+	text string
+	val  any
+}
+
+func tokenizeExpr(s string) ([]exprToken, error) {
+	var toks []exprToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string: %s", s)
+			}
+			toks = append(toks, exprToken{kind: 'l', val: s[i+1 : i+1+end]})
+			i += end + 2
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			f, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number: %s", s[i:j])
+			}
+			toks = append(toks, exprToken{kind: 'l', val: f})
+			i = j
+		case isIdentByte(c):
+			j := i
+			for j < len(s) && (isIdentByte(s[j]) || s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			switch word := s[i:j]; word {
+			case "true", "false":
+				toks = append(toks, exprToken{kind: 'l', val: word == "true"})
+			case "nil", "null":
+				toks = append(toks, exprToken{kind: 'l'})
+			default:
+				toks = append(toks, exprToken{kind: 'p', text: word})
+			}
+			i = j
+		default:
+			op := ""
+			for _, o := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"} {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("invalid expression: %s", s)
+			}
+			toks = append(toks, exprToken{kind: 'o', text: op})
+			i += len(op)
+		}
+	}
+	return toks, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+type exprParser struct {
+	toks []exprToken
+	pos  int
+}
+
+func (p *exprParser) peekOp(ops ...string) string {
+	if p.pos >= len(p.toks) || p.toks[p.pos].kind != 'o' {
+		return ""
+	}
+	for _, o := range ops {
+		if p.toks[p.pos].text == o {
+			return o
+		}
+	}
+	return ""
+}
+
+func (p *exprParser) or() (expr, error) {
+	x, err := p.and()
+	for err == nil && p.peekOp("||") != "" {
+		p.pos++
+		var y expr
+		if y, err = p.and(); err == nil {
+			x = binaryExpr{"||", x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *exprParser) and() (expr, error) {
+	x, err := p.comparison()
+	for err == nil && p.peekOp("&&") != "" {
+		p.pos++
+		var y expr
+		if y, err = p.comparison(); err == nil {
+			x = binaryExpr{"&&", x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *exprParser) comparison() (expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peekOp("==", "!=", "<=", ">=", "<", ">"); op != "" {
+		p.pos++
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryExpr{op, x, y}, nil
+	}
+	return x, nil
+}
+
+func (p *exprParser) unary() (expr, error) {
+	if p.peekOp("!") != "" {
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	}
+	if p.peekOp("(") != "" {
+		p.pos++
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peekOp(")") == "" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	}
+	if p.pos >= len(p.toks) || p.toks[p.pos].kind == 'o' {
+		return nil, fmt.Errorf("missing operand")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	if t.kind == 'p' {
+		return pathExpr(t.text), nil
+	}
+	return literalExpr{t.val}, nil
+}
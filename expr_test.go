@@ -0,0 +1,65 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalCondition(t *testing.T) {
+	data := map[string]any{
+		"save":  map[string]any{"level": 3, "name": "slot1"},
+		"items": []string{},
+		"gold":  0,
+		"mode":  "hard",
+	}
+	for _, tt := range []struct {
+		expr string
+		want bool
+	}{
+		{"save", true},
+		{"missing", false},
+		{"!save", false},
+		{"items", false},
+		{"gold", false},
+		{"save.level > 2", true},
+		{"save.level >= 4", false},
+		{"save.level == 3 && mode == 'hard'", true},
+		{`mode != "hard" || gold < 1`, true},
+		{"!(save && gold)", true},
+		{"save.name < 'slot2'", true},
+		{"gold == -0", true},
+		{"true && !false", true},
+	} {
+		got, err := evalCondition(tt.expr, data)
+		require.NoError(t, err, tt.expr)
+		require.Equal(t, tt.want, got, tt.expr)
+	}
+
+	for _, s := range []string{"", "a &&", "(a", "a b", "'a", "a = b"} {
+		_, err := evalCondition(s, data)
+		require.Error(t, err, s)
+	}
+}
+
+func TestIfDirective(t *testing.T) {
+	root, err := ParseStrict(`
+		<view>
+			<view id="continue" if="save"><view id="label">Continue</view></view>
+			<view id="new" if="!save"></view>
+			<view id="hint" if="save.level > 1" />
+		</view>`, &ParseOptions{Data: map[string]any{"save": map[string]any{"level": 2}}})
+	require.NoError(t, err)
+	for _, id := range []string{"continue", "label", "hint"} {
+		_, ok := root.GetByID(id)
+		require.True(t, ok, id)
+	}
+	_, ok := root.GetByID("new")
+	require.False(t, ok)
+	require.Len(t, root.children, 2)
+
+	root = Parse(`<view><view id="continue" if="save"><view></view></view><view id="new"></view></view>`, nil)
+	_, ok = root.GetByID("continue")
+	require.False(t, ok)
+	require.Len(t, root.children, 1)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"image"
 	"math"
+	"sort"
 )
 
 // Direction is the direction in which flex items are laid out
@@ -138,6 +139,7 @@ const (
 	AlignContentSpaceBetween
 	AlignContentSpaceAround
 	AlignContentStretch
+	AlignContentSpaceEvenly
 )
 
 func (f AlignContent) String() string {
@@ -154,6 +156,8 @@ func (f AlignContent) String() string {
 		return "space-around"
 	case AlignContentStretch:
 		return "stretch"
+	case AlignContentSpaceEvenly:
+		return "space-evenly"
 	}
 	return fmt.Sprintf("unknown align-content: %d", f)
 }
@@ -201,6 +205,27 @@ type flexEmbed struct {
 // layout is the main routine that implements a subset of flexbox layout
 // https://www.w3.org/TR/css-flexbox-1/#layout-algorithm
 func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
+	for _, c := range container.children {
+		c.item.collapsed = false
+	}
+	for f.layoutItems(width, height, container) {
+	}
+}
+
+// layoutItems lays out the items of the container. It returns true without
+// positioning them if it collapsed an item, to be called again.
+func (f *flexEmbed) layoutItems(width, height int, container *containerEmbed) bool {
+	// The padding shrinks the content box in which the items are laid out.
+	width -= f.PaddingLeft + f.PaddingRight
+	if width < 0 {
+		width = 0
+	}
+	height -= f.PaddingTop + f.PaddingBottom
+	if height < 0 {
+		height = 0
+	}
+	padding := image.Pt(f.PaddingLeft, f.PaddingTop)
+
 	// 9.2. Line Length Determination
 	// Determine the available main and cross space for the flex items.
 	containerMainSize := float64(f.mainSize(width, height))
@@ -209,7 +234,7 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 	// Determine the flex base size and hypothetical main size of each item:
 	var children []element
 	for _, c := range container.children {
-		if c.item.Display == DisplayNone {
+		if !c.item.isDisplayed() {
 			continue
 		}
 		if c.item.Position == PositionAbsolute {
@@ -291,6 +316,22 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 		panic(fmt.Sprint("flex: bad direction ", f.Direction))
 	}
 
+	// The height of an item in a column may depend on its width, which
+	// is resolved before the main sizes: measure it first.
+	if f.Direction == Column {
+		for i := range children {
+			item := children[i].node.item
+			w := item.width()
+			if f.AlignItems == AlignItemStretch && !item.isWidthFixed() {
+				w = int(item.clampWidth(containerCrossSize - float64(item.MarginLeft+item.MarginRight)))
+			}
+			if h, ok := item.heightForWidth(w); ok {
+				item.calculatedHeight = h
+				children[i].flexBaseSize = float64(f.flexBaseSize(children[i].node))
+			}
+		}
+	}
+
 	// §9.3. Main Size Determination
 	// Collect flex items into flex lines
 	var lines []flexLine
@@ -351,6 +392,10 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 			}
 		}
 
+		if !grow {
+			f.freezeByPriority(line, containerMainSize)
+		}
+
 		// §9.7.3 calculate initial free space
 		freeSpace := float64(f.mainSize(width, height))
 		for _, child := range line.child {
@@ -439,11 +484,25 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 		}
 	}
 
+	// collapse-below: the item of the lowest priority shrunk below its
+	// threshold is hidden, and the others are laid out again without it
+	if c := collapsible(lines, containerMainSize); c != nil {
+		c.item.collapsed = true
+		return true
+	}
+
 	// §9.4. Cross Size Determination
 	// Determine the hypothetical cross size of each item
 	for l := range lines {
 		for _, c := range lines[l].child {
 			c.crossMargin = f.crossMargin(c.node)
+			if f.Direction == Row {
+				c.mainSize = c.node.item.clampWidth(c.mainSize)
+				// the height may depend on the width resolved above
+				if h, ok := c.node.item.heightForWidth(round(c.mainSize)); ok {
+					c.node.item.calculatedHeight = h
+				}
+			}
 			c.crossSize = float64(
 				f.crossSize(c.node.item.width(), c.node.item.height()),
 			)
@@ -496,6 +555,9 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 				child.crossSize < line.crossSize {
 				crossMargin := child.crossMargin[0] + child.crossMargin[1]
 				child.crossSize = line.crossSize - crossMargin
+				if f.Direction == Column {
+					child.crossSize = child.node.item.clampWidth(child.crossSize)
+				}
 			}
 		}
 	}
@@ -571,6 +633,9 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 		case AlignContentSpaceAround:
 			spacing = remFree / float64(len(lines))
 			off = spacing / 2
+		case AlignContentSpaceEvenly:
+			spacing = remFree / float64(len(lines)+1)
+			off = spacing
 		}
 		if f.AlignContent != AlignContentStart {
 			for l := range lines {
@@ -610,6 +675,9 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 		}
 
 		// 2. Add each item’s flex base size to the product of its flex grow/shrink factor and the largest max-content flex fraction.
+		// 3. Determine line size and update intrinsicMainSize.
+		// The sizes are the intrinsic ones; the items keep their resolved main sizes.
+		lineSize := 0.0
 		for _, child := range line.child {
 			var newMainSize float64
 			if largestMaxContentFlexFraction > 0 {
@@ -617,19 +685,13 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 			} else {
 				newMainSize = child.flexBaseSize - (child.node.item.Shrink * child.flexBaseSize * largestMaxContentFlexFraction)
 			}
-			child.mainSize = newMainSize
-		}
-
-		// 3. Determine line size and update intrinsicMainSize.
-		lineSize := 0.0
-		for _, child := range line.child {
-			lineSize += child.mainSize
+			lineSize += newMainSize + child.mainMargin[0] + child.mainMargin[1]
 		}
 		if lineSize > intrinsicMainSize {
 			intrinsicMainSize = lineSize
 		}
 	}
-	f.setMainSize(int(intrinsicMainSize))
+	f.setMainSize(int(intrinsicMainSize) + f.mainSize(f.PaddingLeft+f.PaddingRight, f.PaddingTop+f.PaddingBottom))
 
 	// §9.9.2. Flex Container Intrinsic Cross Sizes
 	// The min-content/max-content cross size of a single-line flex container
@@ -644,18 +706,30 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 		min := math.Inf(1)
 		max := -1.
 		for _, child := range line.child {
-			if child.crossOffset < min {
-				min = child.crossOffset
+			// the margin boxes of the items
+			if child.crossOffset-child.crossMargin[0] < min {
+				min = child.crossOffset - child.crossMargin[0]
 			}
-			if max == -1 || child.crossOffset+child.crossSize > max {
-				max = child.crossOffset + child.crossSize
+			if max == -1 || child.crossOffset+child.crossSize+child.crossMargin[1] > max {
+				max = child.crossOffset + child.crossSize + child.crossMargin[1]
 			}
 		}
 		if intrinsicCrossSize < max-min {
 			intrinsicCrossSize = max - min
 		}
 	}
-	f.setCrossSize(int(intrinsicCrossSize))
+	f.setCrossSize(int(intrinsicCrossSize) + f.crossSize(f.PaddingLeft+f.PaddingRight, f.PaddingTop+f.PaddingBottom))
+
+	// the size of the content of a view without children, such as a text
+	if s, ok := f.Handler.(Sizer); ok && len(children) == 0 {
+		w, h := s.Size(f.View)
+		f.calculatedWidth = w + f.PaddingLeft + f.PaddingRight
+		f.calculatedHeight = h + f.PaddingTop + f.PaddingBottom
+	}
+	f.calculatedWidth = int(f.clampWidth(float64(f.calculatedWidth)))
+	if h, ok := f.heightForWidth(f.frame.Dx()); ok {
+		f.calculatedHeight = h
+	}
 
 	// TODO: Calculate min-content/max-content cross size for multi-line flex container.
 	// For a multi-line flex container, the min-content/max-content cross size is
@@ -676,20 +750,97 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 					round(child.mainOffset),
 					round(child.crossOffset),
 					round(child.mainOffset+child.mainSize),
-					round(child.crossOffset+child.crossSize))
+					round(child.crossOffset+child.crossSize)).Add(padding)
 				child.node.item.setFrame(child.node.bounds.Add(f.frame.Min))
 			case Column:
 				child.node.bounds = image.Rect(
 					round(child.crossOffset),
 					round(child.mainOffset),
 					round(child.crossOffset+child.crossSize),
-					round(child.mainOffset+child.mainSize))
+					round(child.mainOffset+child.mainSize)).Add(padding)
 				child.node.item.setFrame(child.node.bounds.Add(f.frame.Min))
 			default:
 				panic(fmt.Sprint("flex: bad direction ", f.Direction))
 			}
 		}
 	}
+	return false
+}
+
+// freezeByPriority freezes the items of the overflowing line that do not
+// shrink: those of the priorities above the one absorbing the overflow,
+// at their sizes, and those of the priorities below it, at zero.
+func (f *flexEmbed) freezeByPriority(line *flexLine, containerMainSize float64) {
+	var priorities []int
+	for _, child := range line.child {
+		if !child.frozen {
+			priorities = appendPriority(priorities, child.node.item.ShrinkPriority)
+		}
+	}
+	if len(priorities) < 2 {
+		return
+	}
+	sort.Ints(priorities)
+	overflow := line.mainSize - containerMainSize
+	for _, p := range priorities {
+		size := 0.0
+		for _, child := range line.child {
+			if !child.frozen && child.node.item.ShrinkPriority == p {
+				size += float64(f.mainSize(child.node.item.width(), child.node.item.height()))
+			}
+		}
+		absorbed := overflow > 0 && size <= overflow
+		for _, child := range line.child {
+			if child.frozen || child.node.item.ShrinkPriority != p {
+				continue
+			}
+			switch {
+			case overflow <= 0:
+				child.frozen = true
+				child.mainSize = float64(f.mainSize(child.node.item.width(), child.node.item.height()))
+			case absorbed:
+				child.frozen = true
+				child.mainSize = 0
+			}
+		}
+		if absorbed {
+			overflow -= size
+		} else {
+			overflow = 0
+		}
+	}
+}
+
+func appendPriority(priorities []int, p int) []int {
+	for _, q := range priorities {
+		if q == p {
+			return priorities
+		}
+	}
+	return append(priorities, p)
+}
+
+// collapsible returns the item to collapse: the item of the lowest shrink
+// priority, and the last one among equals, of an overflowing line whose
+// main size is below its CollapseBelow.
+func collapsible(lines []flexLine, containerMainSize float64) *child {
+	var ret *element
+	for l := range lines {
+		if lines[l].mainSize <= containerMainSize {
+			continue
+		}
+		for _, c := range lines[l].child {
+			item := c.node.item
+			if item.CollapseBelow > 0 && c.mainSize < float64(item.CollapseBelow) &&
+				(ret == nil || item.ShrinkPriority <= ret.node.item.ShrinkPriority) {
+				ret = c
+			}
+		}
+	}
+	if ret == nil {
+		return nil
+	}
+	return ret.node
 }
 
 type element struct {
@@ -811,6 +962,26 @@ func (f *flexEmbed) flexBaseSize(c *child) int {
 	return f.mainSize(w, h)
 }
 
+// heightForWidth returns the height of the view at the width if its
+// height depends on the width: the height of its aspect ratio, or the
+// height of the content measured by a HeightMeasurer without children.
+func (v *View) heightForWidth(width int) (int, bool) {
+	if v.isHeightFixed() {
+		return 0, false
+	}
+	if v.AspectRatio > 0 {
+		return round(float64(width) / v.AspectRatio), true
+	}
+	if m, ok := v.Handler.(HeightMeasurer); ok && len(v.children) == 0 {
+		w := width - v.PaddingLeft - v.PaddingRight
+		if w < 0 {
+			w = 0
+		}
+		return m.MeasureHeight(v, w) + v.PaddingTop + v.PaddingBottom, true
+	}
+	return 0, false
+}
+
 func (f *flexEmbed) clampSize(size, width, height int) int {
 	minSize := f.mainSize(width, height)
 	if minSize > size {
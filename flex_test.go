@@ -1046,3 +1046,189 @@ func flexItemBounds(parent *View, child *View) image.Rectangle {
 
 	return mock.Frame
 }
+
+func TestPadding(t *testing.T) {
+	flex := &View{
+		Width:         100,
+		Height:        100,
+		Direction:     Row,
+		PaddingLeft:   10,
+		PaddingTop:    20,
+		PaddingRight:  30,
+		PaddingBottom: 40,
+	}
+
+	mocks := [2]mockHandler{}
+	flex.AddChild(
+		&View{Width: 20, Handler: &mocks[0]},
+		&View{Grow: 1, Handler: &mocks[1]},
+	)
+
+	flex.Update()
+	flex.Draw(nil)
+
+	require.Equal(t, image.Rect(10, 20, 30, 60), mocks[0].Frame)
+	require.Equal(t, image.Rect(30, 20, 70, 60), mocks[1].Frame)
+}
+
+func TestAlignContent(t *testing.T) {
+	tests := []struct {
+		align AlignContent
+		tops  [3]int
+	}{
+		{AlignContentStart, [3]int{0, 20, 40}},
+		{AlignContentEnd, [3]int{60, 80, 100}},
+		{AlignContentCenter, [3]int{30, 50, 70}},
+		{AlignContentSpaceBetween, [3]int{0, 50, 100}},
+		{AlignContentSpaceAround, [3]int{10, 50, 90}},
+		{AlignContentSpaceEvenly, [3]int{15, 50, 85}},
+		{AlignContentStretch, [3]int{0, 40, 80}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.align.String(), func(t *testing.T) {
+			flex := &View{
+				Width:        100,
+				Height:       120,
+				Direction:    Row,
+				AlignItems:   AlignItemStart,
+				AlignContent: tt.align,
+				Wrap:         Wrap,
+			}
+			// three lines of two items
+			mocks := [6]mockHandler{}
+			for i := range mocks {
+				flex.AddChild(&View{Width: 50, Height: 20, Handler: &mocks[i]})
+			}
+			flex.Update()
+			flex.Draw(nil)
+
+			for l, top := range tt.tops {
+				require.Equal(t, image.Rect(0, top, 50, top+20), mocks[l*2].Frame)
+				require.Equal(t, image.Rect(50, top, 100, top+20), mocks[l*2+1].Frame)
+			}
+		})
+	}
+}
+
+func TestShrinkWrap(t *testing.T) {
+	// the container without a size fits its children with their margins
+	root := &View{Width: 200, Height: 200, Direction: Column, AlignItems: AlignItemStart}
+	badge := &View{Direction: Row, PaddingLeft: 4, PaddingRight: 4, PaddingTop: 2, PaddingBottom: 2}
+	a := &View{Width: 10, Height: 10, MarginLeft: 3}
+	b := &View{Width: 10, Height: 12, MarginRight: 3, MarginBottom: 1}
+	badge.AddChild(a, b)
+	root.AddChild(badge)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 34, 17), badge.frame)
+	require.Equal(t, image.Rect(7, 2, 17, 12), a.frame)
+	require.Equal(t, image.Rect(17, 2, 27, 14), b.frame)
+
+	// the children wrap at the width available in the parent
+	root = &View{Width: 100, Height: 200, Direction: Column, AlignItems: AlignItemStart}
+	wrap := &View{Direction: Row, Wrap: Wrap}
+	for i := 0; i < 5; i++ {
+		wrap.AddChild(&View{Width: 30, Height: 10})
+	}
+	root.AddChild(wrap)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 90, 20), wrap.frame)
+	require.Equal(t, image.Rect(60, 0, 90, 10), wrap.Children()[2].frame)
+	require.Equal(t, image.Rect(0, 10, 30, 20), wrap.Children()[3].frame)
+}
+
+// wrappedText is a text of a number of characters of 10x10 pixels
+// wrapped at the width.
+type wrappedText struct {
+	chars int
+}
+
+func (w *wrappedText) MeasureHeight(v *View, width int) int {
+	perLine := width / 10
+	if perLine < 1 {
+		perLine = 1
+	}
+	return (w.chars + perLine - 1) / perLine * 10
+}
+
+func TestHeightForWidth(t *testing.T) {
+	// the height of an item in a column is measured at the stretched width
+	root := &View{Width: 100, Height: 200, Direction: Column, AlignItems: AlignItemStretch}
+	text := &View{Handler: &wrappedText{chars: 25}, PaddingLeft: 5, PaddingRight: 5, PaddingTop: 2, PaddingBottom: 2}
+	picture := &View{AspectRatio: 2}
+	root.AddChild(text, picture)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 100, 34), text.frame)
+	require.Equal(t, image.Rect(0, 34, 100, 84), picture.frame)
+
+	// the height of an item in a row is measured at its flexed width
+	root = &View{Width: 100, Height: 200, Direction: Row, AlignItems: AlignItemStart}
+	a := &View{Handler: &wrappedText{chars: 10}, Grow: 1}
+	b := &View{Handler: &wrappedText{chars: 10}, Width: 80}
+	root.AddChild(a, b)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 20, 50), a.frame)
+	require.Equal(t, image.Rect(20, 0, 100, 20), b.frame)
+
+	// a container is arranged with the heights of its content measured
+	// at the widths resolved for it
+	root = &View{Width: 100, Height: 200, Direction: Column}
+	card := &View{Direction: Column, PaddingTop: 5, PaddingBottom: 5, MarginLeft: 10, MarginRight: 10}
+	body := &View{Handler: &wrappedText{chars: 20}}
+	footer := &View{Height: 10}
+	card.AddChild(body)
+	root.AddChild(card, footer)
+	root.Update()
+	require.Equal(t, image.Rect(10, 0, 90, 40), card.frame)
+	require.Equal(t, image.Rect(10, 5, 90, 35), body.frame)
+	require.Equal(t, image.Rect(0, 40, 100, 50), footer.frame)
+}
+
+func TestShrinkPriority(t *testing.T) {
+	// the items of the lowest priority absorb the overflow
+	root := &View{Width: 100, Height: 20, Direction: Row}
+	a := &View{Width: 50, Shrink: 1}
+	b := &View{Width: 50, Shrink: 1, ShrinkPriority: 1}
+	c := &View{Width: 40, Shrink: 1, ShrinkPriority: 1}
+	root.AddChild(a, b, c)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 10, 20), a.frame)
+	require.Equal(t, image.Rect(10, 0, 60, 20), b.frame)
+	require.Equal(t, image.Rect(60, 0, 100, 20), c.frame)
+
+	// the next priority shrinks once the lower ones have no size left
+	a.Width, b.Width, c.Width = 30, 60, 50
+	root.Layout()
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 0, 20), a.frame)
+	require.Equal(t, image.Rect(0, 0, 55, 20), b.frame)
+	require.Equal(t, image.Rect(55, 0, 100, 20), c.frame)
+}
+
+func TestCollapseBelow(t *testing.T) {
+	root := &View{Width: 100, Height: 20, Direction: Row}
+	title := &View{Width: 60, Shrink: 1, ShrinkPriority: 1}
+	minimap := &View{Width: 60, Shrink: 1, CollapseBelow: 40}
+	gauge := &View{Width: 30, Shrink: 1, ShrinkPriority: 1}
+	root.AddChild(title, minimap, gauge)
+	root.Update()
+	require.False(t, minimap.isDisplayed())
+	require.Equal(t, image.Rect(0, 0, 60, 20), title.frame)
+	require.Equal(t, image.Rect(60, 0, 90, 20), gauge.frame)
+
+	// the item is shown again when there is room
+	root.Width = 200
+	root.Layout()
+	root.Update()
+	require.True(t, minimap.isDisplayed())
+	require.Equal(t, image.Rect(60, 0, 120, 20), minimap.frame)
+	require.Equal(t, image.Rect(120, 0, 150, 20), gauge.frame)
+
+	// without priorities, the items shrink together until one collapses
+	root = &View{Width: 100, Height: 20, Direction: Row}
+	a := &View{Width: 100, Shrink: 1, CollapseBelow: 70}
+	b := &View{Width: 50, Shrink: 1}
+	root.AddChild(a, b)
+	root.Update()
+	require.False(t, a.isDisplayed())
+	require.Equal(t, image.Rect(0, 0, 50, 20), b.frame)
+}
@@ -0,0 +1,373 @@
+package furex
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FocusHandler represents a component that can receive the keyboard focus.
+// Views with a FocusHandler take part in the focus traversal even without
+// a TabIndex.
+type FocusHandler interface {
+	// HandleFocus is called when the view gains the focus.
+	HandleFocus()
+	// HandleBlur is called when the view loses the focus.
+	HandleBlur()
+}
+
+type focusState struct {
+	focused *View
+	order   []string
+	// traps are the open modal views from the bottom to the top,
+	// with the views that had the focus before they were opened.
+	traps []focusTrap
+}
+
+type focusTrap struct {
+	modal   *View
+	restore *View
+}
+
+// FocusDirection is a direction of the spatial focus navigation.
+type FocusDirection int
+
+const (
+	FocusLeft FocusDirection = iota
+	FocusRight
+	FocusUp
+	FocusDown
+)
+
+// Focus moves the focus to the view.
+func (v *View) Focus() {
+	v.root().setFocus(v)
+}
+
+// Blur removes the focus from the view if it is focused.
+func (v *View) Blur() {
+	r := v.root()
+	if r.focus != nil && r.focus.focused == v {
+		r.setFocus(nil)
+	}
+}
+
+// IsFocused returns true if the view has the focus.
+func (v *View) IsFocused() bool {
+	r := v.root()
+	return r.focus != nil && r.focus.focused == v
+}
+
+// FocusedView returns the view that has the focus in the tree, or nil.
+func (v *View) FocusedView() *View {
+	r := v.root()
+	if r.focus == nil {
+		return nil
+	}
+	return r.focus.focused
+}
+
+// SetFocusOrder sets the order of the focus traversal by view ids.
+// It overrides the order given by tabindex and the document order,
+// which is useful when the visual order differs from the markup.
+// Disabled views and views with a negative tabindex are skipped, and
+// the default order is used in a modal view without any of the views.
+// Calling it without ids restores the default order.
+func (v *View) SetFocusOrder(ids ...string) {
+	r := v.root()
+	r.focusState().order = ids
+}
+
+// FocusNext moves the focus to the next view in the focus order.
+func (v *View) FocusNext() {
+	v.root().moveFocus(1)
+}
+
+// FocusPrev moves the focus to the previous view in the focus order.
+func (v *View) FocusPrev() {
+	v.root().moveFocus(-1)
+}
+
+// MoveFocus moves the focus to the nearest focusable view in the direction,
+// which is useful for gamepad or arrow key navigation.
+func (v *View) MoveFocus(dir FocusDirection) {
+	r := v.root()
+	order := r.focusOrder()
+	cur := r.FocusedView()
+	if cur == nil {
+		if len(order) > 0 {
+			r.setFocus(order[0])
+		}
+		return
+	}
+	from := cur.frame.Min.Add(cur.frame.Max).Div(2)
+	var best *View
+	bestDist := 0
+	for _, vv := range order {
+		if vv == cur {
+			continue
+		}
+		to := vv.frame.Min.Add(vv.frame.Max).Div(2)
+		dx, dy := to.X-from.X, to.Y-from.Y
+		var main, cross int
+		switch dir {
+		case FocusLeft:
+			main, cross = -dx, dy
+		case FocusRight:
+			main, cross = dx, dy
+		case FocusUp:
+			main, cross = -dy, dx
+		case FocusDown:
+			main, cross = dy, dx
+		}
+		if main <= 0 {
+			continue
+		}
+		if cross < 0 {
+			cross = -cross
+		}
+		// prefer the views aligned with the current one
+		dist := main + cross*2
+		if best == nil || dist < bestDist {
+			best, bestDist = vv, dist
+		}
+	}
+	if best != nil {
+		r.setFocus(best)
+	}
+}
+
+func (v *View) root() *View {
+	r := v
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+func (v *View) focusState() *focusState {
+	if v.focus == nil {
+		v.focus = &focusState{}
+	}
+	return v.focus
+}
+
+func (v *View) setFocus(target *View) {
+	fs := v.focusState()
+	if fs.focused == target {
+		return
+	}
+	prev := fs.focused
+	fs.focused = target
+	if prev != nil {
+		if h, ok := prev.Handler.(FocusHandler); ok {
+			h.HandleBlur()
+		}
+		prev.restyle()
+	}
+	if target != nil {
+		if h, ok := target.Handler.(FocusHandler); ok {
+			h.HandleFocus()
+		}
+		target.restyle()
+	}
+}
+
+func (v *View) moveFocus(delta int) {
+	order := v.focusOrder()
+	if len(order) == 0 {
+		return
+	}
+	i := -1
+	if v.focus != nil {
+		for j, vv := range order {
+			if vv == v.focus.focused {
+				i = j
+				break
+			}
+		}
+	}
+	switch {
+	case i == -1 && delta > 0:
+		i = 0
+	case i == -1:
+		i = len(order) - 1
+	default:
+		i = (i + delta + len(order)) % len(order)
+	}
+	v.setFocus(order[i])
+}
+
+// focusOrder returns the views that take part in the focus traversal.
+// Views with a positive tabindex come first in ascending order,
+// followed by the other views in document order, unless the order set
+// by SetFocusOrder has views in the scope.
+// When a modal view is open, only the views inside it are returned.
+func (v *View) focusOrder() []*View {
+	scope := v.focusScope()
+	if v.focus != nil && len(v.focus.order) > 0 {
+		var ret []*View
+		for _, id := range v.focus.order {
+			if vv, ok := scope.GetByID(id); ok && vv.canFocus() && vv.isVisible() {
+				ret = append(ret, vv)
+			}
+		}
+		if len(ret) > 0 {
+			return ret
+		}
+		// e.g. a modal dialog without the views of the order
+	}
+	var views []*View
+	scope.collectFocusable(&views)
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := views[i].tabIndex(), views[j].tabIndex()
+		if a > 0 && b > 0 {
+			return a < b
+		}
+		return a > 0 && b == 0
+	})
+	return views
+}
+
+// focusScope returns the top modal view, or the view itself if no modal is open.
+func (v *View) focusScope() *View {
+	if v.focus != nil && len(v.focus.traps) > 0 {
+		return v.focus.traps[len(v.focus.traps)-1].modal
+	}
+	return v
+}
+
+// updateFocusTrap traps the focus in the top modal view when it opens
+// and restores the previous focus when it closes.
+func (v *View) updateFocusTrap() {
+	var modals []*View
+	v.collectModals(&modals)
+	if len(modals) == 0 && (v.focus == nil || len(v.focus.traps) == 0) {
+		return
+	}
+	fs := v.focusState()
+	for len(fs.traps) > 0 {
+		top := fs.traps[len(fs.traps)-1]
+		if containsView(modals, top.modal) {
+			break
+		}
+		fs.traps = fs.traps[:len(fs.traps)-1]
+		restore := top.restore
+		if restore != nil && (restore.root() != v || !restore.isVisible()) {
+			restore = nil
+		}
+		v.setFocus(restore)
+	}
+	for _, m := range modals {
+		if containsTrap(fs.traps, m) {
+			continue
+		}
+		fs.traps = append(fs.traps, focusTrap{modal: m, restore: fs.focused})
+		v.setFocus(nil)
+		if order := v.focusOrder(); len(order) > 0 {
+			v.setFocus(order[0])
+		}
+	}
+}
+
+func (v *View) collectModals(views *[]*View) {
+	if v.Hidden || !v.isDisplayed() {
+		return
+	}
+	if v.Modal && v.hasParent {
+		*views = append(*views, v)
+	}
+	for _, c := range v.children {
+		c.item.collectModals(views)
+	}
+}
+
+func containsView(views []*View, v *View) bool {
+	for _, vv := range views {
+		if vv == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTrap(traps []focusTrap, v *View) bool {
+	for _, t := range traps {
+		if t.modal == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *View) collectFocusable(views *[]*View) {
+	if v.Hidden || !v.isDisplayed() {
+		return
+	}
+	if v.canFocus() {
+		*views = append(*views, v)
+	}
+	for _, c := range v.children {
+		c.item.collectFocusable(views)
+	}
+}
+
+// canFocus returns true if the view takes part in the focus traversal.
+func (v *View) canFocus() bool {
+	return v.isFocusable() && !v.Disabled && v.tabIndex() >= 0
+}
+
+func (v *View) isFocusable() bool {
+	if v.TabIndex != nil {
+		return true
+	}
+	_, ok := v.Handler.(FocusHandler)
+	return ok
+}
+
+func (v *View) tabIndex() int {
+	if v.TabIndex == nil {
+		return 0
+	}
+	return *v.TabIndex
+}
+
+func (v *View) isVisible() bool {
+	for vv := v; vv != nil; vv = vv.parent {
+		if vv.Hidden || !vv.isDisplayed() {
+			return false
+		}
+	}
+	return true
+}
+
+// focusableAt returns the front-most focusable view at the location.
+func (v *View) focusableAt(x, y int) *View {
+	if v.Hidden || !v.isDisplayed() {
+		return nil
+	}
+	x, y = v.untransform(v.frame, x, y)
+	for i := len(v.children) - 1; i >= 0; i-- {
+		if vv := v.children[i].item.focusableAt(x, y); vv != nil {
+			return vv
+		}
+	}
+	if v.isFocusable() && !v.Disabled && isInside(&v.frame, x, y) {
+		return v
+	}
+	return nil
+}
+
+func (v *View) handleFocusEvents() {
+	v.updateFocusTrap()
+	if isKeyJustPressed(ebiten.KeyTab) {
+		if isKeyPressed(ebiten.KeyShift) {
+			v.moveFocus(-1)
+		} else {
+			v.moveFocus(1)
+		}
+	}
+	if isMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		v.setFocus(v.focusScope().focusableAt(cursorPosition()))
+	}
+}
@@ -0,0 +1,135 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type focusMock struct {
+	focused bool
+}
+
+func (f *focusMock) HandleFocus() { f.focused = true }
+func (f *focusMock) HandleBlur()  { f.focused = false }
+
+func TestFocusOrder(t *testing.T) {
+	view := Parse(`
+		<view>
+			<view id="a" tabindex="0"></view>
+			<view id="b" tabindex="2"></view>
+			<view id="c" tabindex="-1"></view>
+			<view>
+				<view id="d" tabindex="1"></view>
+				<view id="e" tabindex="0"></view>
+			</view>
+			<view id="f"></view>
+		</view>`, nil)
+
+	ids := func() []string {
+		var ret []string
+		for range view.focusOrder() {
+			view.FocusNext()
+			ret = append(ret, view.FocusedView().ID)
+		}
+		return ret
+	}
+
+	require.Equal(t, []string{"d", "b", "a", "e"}, ids())
+
+	view.FocusPrev()
+	require.Equal(t, "a", view.FocusedView().ID)
+
+	// views with a negative tabindex and disabled views are skipped
+	view.SetFocusOrder("e", "c", "b", "a")
+	view.MustGetByID("b").SetDisabled(true)
+	view.MustGetByID("e").Focus()
+	require.Equal(t, []string{"a", "e"}, ids())
+	view.MustGetByID("b").SetDisabled(false)
+
+	view.SetFocusOrder()
+	view.MustGetByID("d").SetHidden(true)
+	view.MustGetByID("e").Blur()
+	require.Nil(t, view.FocusedView())
+	require.Equal(t, []string{"b", "a", "e"}, ids())
+}
+
+func TestFocusHandler(t *testing.T) {
+	h1, h2 := &focusMock{}, &focusMock{}
+	root := &View{}
+	v1 := &View{Handler: h1}
+	v2 := &View{Handler: h2}
+	root.AddChild(v1, v2)
+
+	root.FocusNext()
+	require.True(t, h1.focused)
+	require.True(t, v1.IsFocused())
+
+	root.FocusNext()
+	require.False(t, h1.focused)
+	require.True(t, h2.focused)
+
+	root.FocusNext()
+	require.True(t, h1.focused)
+	require.False(t, h2.focused)
+}
+
+func TestFocusTrap(t *testing.T) {
+	view := Parse(`
+		<view>
+			<view id="a" tabindex="0"></view>
+			<view id="b" tabindex="0"></view>
+			<view id="dialog" modal hidden>
+				<view id="c" tabindex="0"></view>
+				<view id="d" tabindex="0"></view>
+			</view>
+		</view>`, nil)
+
+	view.MustGetByID("b").Focus()
+	view.updateFocusTrap()
+	require.Equal(t, "b", view.FocusedView().ID)
+
+	view.MustGetByID("dialog").SetHidden(false)
+	view.updateFocusTrap()
+	require.Equal(t, "c", view.FocusedView().ID)
+
+	view.FocusNext()
+	require.Equal(t, "d", view.FocusedView().ID)
+	view.FocusNext()
+	require.Equal(t, "c", view.FocusedView().ID)
+	view.FocusPrev()
+	require.Equal(t, "d", view.FocusedView().ID)
+
+	view.MustGetByID("dialog").SetHidden(true)
+	view.updateFocusTrap()
+	require.Equal(t, "b", view.FocusedView().ID)
+
+	// the dialog has none of the views of the focus order
+	view.SetFocusOrder("b", "a")
+	view.MustGetByID("dialog").SetHidden(false)
+	view.updateFocusTrap()
+	require.Equal(t, "c", view.FocusedView().ID)
+	view.FocusNext()
+	require.Equal(t, "d", view.FocusedView().ID)
+}
+
+func TestMoveFocus(t *testing.T) {
+	view := Parse(`
+		<view style="flex-direction: row; flex-wrap: wrap; align-items: flex-start">
+			<view id="a" tabindex="0" style="width: 50px; height: 50px"></view>
+			<view id="b" tabindex="0" style="width: 50px; height: 50px"></view>
+			<view id="c" tabindex="0" style="width: 50px; height: 50px"></view>
+		</view>`, &ParseOptions{Width: 100, Height: 100})
+	view.Update()
+
+	view.MoveFocus(FocusRight)
+	require.Equal(t, "a", view.FocusedView().ID)
+	view.MoveFocus(FocusRight)
+	require.Equal(t, "b", view.FocusedView().ID)
+	view.MoveFocus(FocusRight)
+	require.Equal(t, "b", view.FocusedView().ID)
+	view.MoveFocus(FocusDown)
+	require.Equal(t, "c", view.FocusedView().ID)
+	view.MoveFocus(FocusUp)
+	require.Equal(t, "a", view.FocusedView().ID)
+}
@@ -0,0 +1,157 @@
+package furex
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+)
+
+// registeredFont is a font registered by name. It has a face of a fixed
+// size, or a function returning the faces of the sizes, kept by size.
+type registeredFont struct {
+	face     font.Face
+	faceFunc func(size int) font.Face
+	sized    map[int]font.Face
+}
+
+var (
+	fonts   = map[string]*registeredFont{}
+	fontsMu sync.Mutex
+)
+
+// RegisterFont registers the face by name, so that the texts of the views
+// with the name in their font-family are drawn with it. It replaces the
+// font of the same name. Fonts used in a document are registered before
+// it is drawn.
+func RegisterFont(name string, face font.Face) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	fonts[name] = &registeredFont{face: face}
+}
+
+// RegisterFontFunc registers the faces of a font by name like
+// RegisterFont. f returns the face of a size in pixels; it is called with
+// the font size of the view (View.ComputedFontSize) rounded to pixels,
+// once per size.
+func RegisterFontFunc(name string, f func(size int) font.Face) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	fonts[name] = &registeredFont{faceFunc: f, sized: map[int]font.Face{}}
+}
+
+// fontFace returns the face of the registered font of the name at the
+// size, if the font is registered.
+func fontFace(name string, size func() int) (font.Face, bool) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	f, ok := fonts[name]
+	if !ok {
+		return nil, false
+	}
+	if f.faceFunc == nil {
+		return f.face, f.face != nil
+	}
+	s := size()
+	face, ok := f.sized[s]
+	if !ok {
+		face = f.faceFunc(s)
+		f.sized[s] = face
+		if face != nil {
+			sizedFaces[face] = sizedFace{faceFunc: f.faceFunc, size: s}
+		}
+	}
+	return face, face != nil
+}
+
+// sizedFace is a face created by a function returning the faces of the
+// sizes, such as Text.FaceFunc.
+type sizedFace struct {
+	faceFunc func(size int) font.Face
+	size     int
+}
+
+type scaledFaceKey struct {
+	face font.Face
+	size int
+}
+
+var (
+	// sizedFaces are the faces created by the functions of the sizes, and
+	// scaledFaces are the faces of the functions at other sizes, so texts
+	// can be drawn at other scales with faces of the scaled sizes.
+	sizedFaces  = map[font.Face]sizedFace{}
+	scaledFaces = map[scaledFaceKey]font.Face{}
+)
+
+// recordSizedFace records that the face was created by f at the size.
+func recordSizedFace(face font.Face, f func(size int) font.Face, size int) {
+	if face == nil {
+		return
+	}
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	sizedFaces[face] = sizedFace{faceFunc: f, size: size}
+}
+
+// scaledFace returns the face of the size of the face multiplied by the
+// scale if the face was created by a function of the sizes.
+func scaledFace(face font.Face, scale float64) (font.Face, bool) {
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	src, ok := sizedFaces[face]
+	if !ok {
+		return nil, false
+	}
+	key := scaledFaceKey{face: face, size: int(math.Round(float64(src.size) * scale))}
+	scaled, ok := scaledFaces[key]
+	if !ok {
+		scaled = src.faceFunc(key.size)
+		scaledFaces[key] = scaled
+	}
+	return scaled, scaled != nil
+}
+
+// ComputedFace returns the face of the texts of the view: the face of the
+// first registered font of the font family, inherited from the parent if
+// FontFamily is nil, or the default face of the document
+// (ParseOptions.Face). It returns nil if there is none.
+func (v *View) ComputedFace() font.Face {
+	size := func() int { return int(math.Round(v.ComputedFontSize())) }
+	for p := v; p != nil; p = p.parent {
+		if p.FontFamily == nil {
+			continue
+		}
+		for _, name := range p.FontFamily {
+			if face, ok := fontFace(name, size); ok {
+				return face
+			}
+		}
+		break
+	}
+	for p := v; p != nil; p = p.parent {
+		if p.style != nil && p.style.face != nil {
+			return p.style.face
+		}
+	}
+	return nil
+}
+
+// parseFontFamily parses a comma-separated list of font names, which can
+// be quoted, such as `"Noto Sans JP", pixel`.
+func parseFontFamily(val string) (any, error) {
+	var ret []string
+	for _, name := range strings.Split(val, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) >= 2 && (name[0] == '"' || name[0] == '\'') && name[len(name)-1] == name[0] {
+			name = name[1 : len(name)-1]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid font-family: %s", val)
+		}
+		ret = append(ret, name)
+	}
+	return ret, nil
+}
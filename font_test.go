@@ -0,0 +1,56 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/inconsolata"
+)
+
+func TestParseFontFamily(t *testing.T) {
+	val, err := parseFontFamily(`"Noto Sans JP", 'pixel' , serif`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Noto Sans JP", "pixel", "serif"}, val)
+
+	_, err = parseFontFamily("pixel,")
+	require.Error(t, err)
+}
+
+func TestFontFamily(t *testing.T) {
+	bold, regular := inconsolata.Bold8x16, inconsolata.Regular8x16
+	RegisterFont("test-bold", bold)
+	var sizes []int
+	RegisterFontFunc("test-sized", func(size int) font.Face {
+		sizes = append(sizes, size)
+		return basicfont.Face7x13
+	})
+
+	root := Parse(`<view>
+		<view id="title" style="font-family: 'test-missing', test-bold">
+			<text id="name">Alice</text>
+		</view>
+		<text id="sized" style="font-family: test-sized; font-size: 20px">Bob</text>
+		<text id="missing" style="font-family: test-missing">Carol</text>
+		<text id="plain">Dave</text>
+	</view>`, &ParseOptions{Face: regular})
+
+	face := func(id string) font.Face {
+		v := root.MustGetByID(id)
+		return v.Handler.(*Text).face(v)
+	}
+	require.Equal(t, bold, face("name"))
+	require.Equal(t, basicfont.Face7x13, face("sized"))
+	require.Equal(t, basicfont.Face7x13, face("sized"))
+	require.Equal(t, []int{20}, sizes)
+	require.Equal(t, regular, face("missing"))
+	require.Equal(t, regular, face("plain"))
+
+	// the face of the handler comes first
+	name := root.MustGetByID("name")
+	require.Equal(t, basicfont.Face7x13, (&Text{Face: basicfont.Face7x13}).face(name))
+
+	// views without a font-family and a document face have none
+	require.Nil(t, (&View{}).ComputedFace())
+}
@@ -0,0 +1,156 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LengthUnit is the unit of a Length.
+type LengthUnit uint8
+
+const (
+	// LengthPx is a length in pixels.
+	LengthPx LengthUnit = iota
+	// LengthPercent is a percentage of the font size of the parent.
+	LengthPercent
+	// LengthEm is a multiple of the font size of the parent.
+	LengthEm
+	// LengthVW is a percentage of the width of the viewport.
+	LengthVW
+	// LengthVH is a percentage of the height of the viewport.
+	LengthVH
+	// LengthVMin is a percentage of the smaller side of the viewport.
+	LengthVMin
+	// LengthVMax is a percentage of the larger side of the viewport.
+	LengthVMax
+)
+
+var lengthUnits = map[string]LengthUnit{
+	"px": LengthPx, "%": LengthPercent, "em": LengthEm,
+	"vw": LengthVW, "vh": LengthVH, "vmin": LengthVMin, "vmax": LengthVMax,
+}
+
+func (u LengthUnit) String() string {
+	for name, unit := range lengthUnits {
+		if unit == u {
+			return name
+		}
+	}
+	return fmt.Sprintf("unknown length unit: %d", u)
+}
+
+// Length is a size relative to the viewport or the parent font size,
+// such as 2vh.
+type Length struct {
+	Value float64
+	Unit  LengthUnit
+}
+
+// resolve returns the length in pixels. viewport is the size of the
+// root view and parent is the font size of the parent view.
+func (l Length) resolve(viewport image.Point, parent float64) float64 {
+	w, h := float64(viewport.X), float64(viewport.Y)
+	switch l.Unit {
+	case LengthPercent:
+		return l.Value * parent / 100
+	case LengthEm:
+		return l.Value * parent
+	case LengthVW:
+		return l.Value * w / 100
+	case LengthVH:
+		return l.Value * h / 100
+	case LengthVMin:
+		return l.Value * math.Min(w, h) / 100
+	case LengthVMax:
+		return l.Value * math.Max(w, h) / 100
+	}
+	return l.Value
+}
+
+// FontSize is the 'font-size' property. Size can be relative to the
+// screen so that texts scale with it, between Min and Max if they are
+// set, as in `font-size: clamp(12px, 3vh, 32px)`.
+type FontSize struct {
+	Size Length
+	Min  *Length
+	Max  *Length
+}
+
+// DefaultFontSize is the font size in pixels of views without a font
+// size set on them or their ancestors, the size of basicfont.Face7x13.
+const DefaultFontSize = 13
+
+// ComputedFontSize returns the font size of the view in pixels. The font
+// size is inherited from the parent if FontSize is nil. Viewport lengths
+// (vw, vh, vmin and vmax) are relative to the frame of the root view,
+// which is usually the screen.
+func (v *View) ComputedFontSize() float64 {
+	var chain []*View
+	root := v
+	for p := v; p != nil; p = p.parent {
+		chain = append(chain, p)
+		root = p
+	}
+	viewport := root.frame.Size()
+	size := float64(DefaultFontSize)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if fs := chain[i].FontSize; fs != nil {
+			size = fs.resolve(viewport, size)
+		}
+	}
+	return size
+}
+
+func (fs *FontSize) resolve(viewport image.Point, parent float64) float64 {
+	size := fs.Size.resolve(viewport, parent)
+	if fs.Max != nil {
+		size = math.Min(size, fs.Max.resolve(viewport, parent))
+	}
+	if fs.Min != nil {
+		size = math.Max(size, fs.Min.resolve(viewport, parent))
+	}
+	return size
+}
+
+// parseFontSize parses a length such as `16px`, `2vh` or `120%`, or
+// `clamp(<min>, <size>, <max>)`.
+func parseFontSize(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, "clamp(") && strings.HasSuffix(val, ")") {
+		args := splitArgs(val[len("clamp(") : len(val)-1])
+		if len(args) != 3 {
+			return nil, fmt.Errorf("invalid font-size: %s", val)
+		}
+		var ls [3]Length
+		for i, a := range args {
+			l, err := parseFontLength(a)
+			if err != nil {
+				return nil, err
+			}
+			ls[i] = l
+		}
+		return &FontSize{Size: ls[1], Min: &ls[0], Max: &ls[2]}, nil
+	}
+	l, err := parseFontLength(val)
+	if err != nil {
+		return nil, err
+	}
+	return &FontSize{Size: l}, nil
+}
+
+func parseFontLength(val string) (Length, error) {
+	val = strings.TrimSpace(val)
+	num := strings.TrimRightFunc(val, func(r rune) bool { return (r >= 'a' && r <= 'z') || r == '%' })
+	unit, ok := lengthUnits[val[len(num):]]
+	if !ok && num != val {
+		return Length{}, fmt.Errorf("invalid length: %s", val)
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return Length{}, fmt.Errorf("invalid length: %s", val)
+	}
+	return Length{Value: f, Unit: unit}, nil
+}
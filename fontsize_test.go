@@ -0,0 +1,72 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestParseFontSize(t *testing.T) {
+	val, err := parseFontSize("2vh")
+	require.NoError(t, err)
+	require.Equal(t, &FontSize{Size: Length{Value: 2, Unit: LengthVH}}, val)
+
+	val, err = parseFontSize("clamp(12px, 3.5vmin, 2em)")
+	require.NoError(t, err)
+	require.Equal(t, &FontSize{
+		Size: Length{Value: 3.5, Unit: LengthVMin},
+		Min:  &Length{Value: 12},
+		Max:  &Length{Value: 2, Unit: LengthEm},
+	}, val)
+
+	for _, s := range []string{"big", "12pt", "-1px", "clamp(1px, 2px)"} {
+		_, err = parseFontSize(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestComputedFontSize(t *testing.T) {
+	root := Parse(`<view style="width: 400px; height: 200px; font-size: 20px">
+		<view id="panel" style="font-size: 150%">
+			<view id="title" style="font-size: clamp(12px, 10vh, 1em)"></view>
+			<view id="label"></view>
+		</view>
+		<view id="small" style="font-size: clamp(12px, 1vw, 40px)"></view>
+	</view>`, nil)
+	root.startLayout()
+
+	require.Equal(t, 30.0, root.MustGetByID("panel").ComputedFontSize())
+	require.Equal(t, 30.0, root.MustGetByID("label").ComputedFontSize())
+	// 10vh is 20px, within the bounds
+	require.Equal(t, 20.0, root.MustGetByID("title").ComputedFontSize())
+	// 1vw is 4px, clamped to the minimum
+	require.Equal(t, 12.0, root.MustGetByID("small").ComputedFontSize())
+	require.Equal(t, float64(DefaultFontSize), (&View{}).ComputedFontSize())
+
+	// the size follows the viewport
+	root.Height = 600
+	root.startLayout()
+	require.Equal(t, 30.0, root.MustGetByID("title").ComputedFontSize())
+}
+
+func TestTextFaceFunc(t *testing.T) {
+	var sizes []int
+	text := &Text{FaceFunc: func(size int) font.Face {
+		sizes = append(sizes, size)
+		return basicfont.Face7x13
+	}}
+	root := &View{Width: 100, Height: 100, FontSize: &FontSize{Size: Length{Value: 10, Unit: LengthVW}}}
+	root.AddChild(&View{Width: 100, Height: 20, Text: "Hello", Handler: text})
+	screen := ebiten.NewImage(100, 100)
+	root.Draw(screen)
+	root.Draw(screen)
+	require.Equal(t, []int{10}, sizes)
+
+	root.Width = 200
+	root.Layout()
+	root.Draw(screen)
+	require.Equal(t, []int{10, 20}, sizes)
+}
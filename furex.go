@@ -31,7 +31,7 @@ func debugBorders(screen *ebiten.Image, root containerEmbed) {
 			})
 
 			for _, c := range curr.children {
-				if c.item.Display == DisplayNone {
+				if !c.item.isDisplayed() {
 					continue
 				}
 				queue = append(queue, c.item.containerEmbed)
@@ -0,0 +1,99 @@
+package furex
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GameOptions represents the options for NewGame.
+type GameOptions struct {
+	// Width and Height is the fixed size of the screen.
+	// Ebitengine scales the screen to fit the window.
+	// If they are zero, the screen follows the size of the window.
+	Width  int
+	Height int
+	// Scale divides the window size to get the screen size when
+	// Width and Height are zero. The default is 1.
+	Scale float64
+	// Background is the color to fill the screen with before drawing.
+	Background color.Color
+	// Update is called every tick before the UI is updated.
+	Update func() error
+	// Draw is called every frame before the UI is drawn.
+	// It is useful to draw the game world behind the UI.
+	Draw func(screen *ebiten.Image)
+}
+
+// Game is an ebiten.Game that runs a view tree.
+// The root view is resized to the screen and updated and drawn every frame.
+type Game struct {
+	root          *View
+	opts          GameOptions
+	width, height int
+}
+
+var _ ebiten.Game = (*Game)(nil)
+
+// NewGame creates a Game that runs the root view.
+// If root is nil, an empty view is used that can be replaced by SetRoot.
+//
+//	ebiten.RunGame(furex.NewGame(furex.Parse(html, nil), nil))
+func NewGame(root *View, opts *GameOptions) *Game {
+	if opts == nil {
+		opts = &GameOptions{}
+	}
+	if root == nil {
+		root = &View{}
+	}
+	return &Game{root: root, opts: *opts}
+}
+
+// Root returns the root view.
+func (g *Game) Root() *View {
+	return g.root
+}
+
+// SetRoot replaces the root view, for example to switch screens.
+func (g *Game) SetRoot(root *View) {
+	g.root = root
+	g.root.Layout()
+}
+
+// Update implements ebiten.Game.
+func (g *Game) Update() error {
+	if g.opts.Update != nil {
+		if err := g.opts.Update(); err != nil {
+			return err
+		}
+	}
+	g.root.UpdateWithSize(g.width, g.height)
+	return nil
+}
+
+// Draw implements ebiten.Game.
+func (g *Game) Draw(screen *ebiten.Image) {
+	if g.opts.Background != nil {
+		screen.Fill(g.opts.Background)
+	}
+	if g.opts.Draw != nil {
+		g.opts.Draw(screen)
+	}
+	g.root.Draw(screen)
+}
+
+// Layout implements ebiten.Game.
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if g.opts.Width != 0 && g.opts.Height != 0 {
+		g.width, g.height = g.opts.Width, g.opts.Height
+		return g.width, g.height
+	}
+	scale := g.opts.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	g.width = int(math.Ceil(float64(outsideWidth) / scale))
+	g.height = int(math.Ceil(float64(outsideHeight) / scale))
+	return g.width, g.height
+}
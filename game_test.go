@@ -0,0 +1,33 @@
+package furex
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame(t *testing.T) {
+	mock := &mockHandler{}
+	root := (&View{Direction: Row}).AddChild(&View{Grow: 1, Handler: mock})
+
+	g := NewGame(root, &GameOptions{Scale: 2})
+	w, h := g.Layout(640, 480)
+	require.Equal(t, 320, w)
+	require.Equal(t, 240, h)
+
+	require.NoError(t, g.Update())
+	require.True(t, mock.IsUpdated)
+	g.Draw(nil)
+	require.Equal(t, image.Rect(0, 0, 320, 240), mock.Frame)
+
+	g = NewGame(nil, &GameOptions{Width: 100, Height: 50, Update: func() error {
+		return errors.New("quit")
+	}})
+	w, h = g.Layout(640, 480)
+	require.Equal(t, 100, w)
+	require.Equal(t, 50, h)
+	require.Error(t, g.Update())
+	require.NotNil(t, g.Root())
+}
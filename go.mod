@@ -5,21 +5,16 @@ go 1.18
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.6.3
 	github.com/stretchr/testify v1.8.1
-	github.com/vanng822/go-premailer v1.20.2
+	golang.org/x/image v0.18.0
 	golang.org/x/net v0.7.0
 )
 
 require (
-	github.com/PuerkitoBio/goquery v1.8.1 // indirect
-	github.com/andybalholm/cascadia v1.3.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/ebitengine/purego v0.5.0 // indirect
-	github.com/gorilla/css v1.0.0 // indirect
 	github.com/jezek/xgb v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/vanng822/css v1.0.1 // indirect
 	golang.org/x/exp/shiny v0.0.0-20230817173708-d852ddb80c63 // indirect
-	golang.org/x/image v0.12.0 // indirect
 	golang.org/x/mobile v0.0.0-20230922142353-e2f452493d57 // indirect
 	golang.org/x/sync v0.3.0 // indirect
 	golang.org/x/sys v0.12.0 // indirect
@@ -0,0 +1,229 @@
+package furex
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// LinearGradient is a background filled with colors changing along a line.
+type LinearGradient struct {
+	// Angle is the direction of the gradient line in degrees as in CSS:
+	// 0 goes to the top and 90 goes to the right.
+	Angle float64
+	// Stops are the colors sorted by the offsets.
+	Stops []GradientStop
+}
+
+// GradientStop is a color at an offset (0 to 1) of the gradient line.
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+func (g *LinearGradient) stops() []graphic.GradientStop {
+	ret := make([]graphic.GradientStop, len(g.Stops))
+	for i, s := range g.Stops {
+		c := s.Color
+		if c == nil {
+			c = color.Transparent
+		}
+		ret[i] = graphic.GradientStop{Offset: s.Offset, Color: c}
+	}
+	return ret
+}
+
+var gradientDirections = map[string]float64{
+	"to top": 0, "to right": 90, "to bottom": 180, "to left": 270,
+	"to top right": 45, "to right top": 45,
+	"to bottom right": 135, "to right bottom": 135,
+	"to bottom left": 225, "to left bottom": 225,
+	"to top left": 315, "to left top": 315,
+}
+
+// parseLinearGradient parses `linear-gradient([<angle> | to <side>,] <color> [<offset>], ...)`.
+func parseLinearGradient(val string) (*LinearGradient, error) {
+	val = strings.TrimSpace(val)
+	if !strings.HasPrefix(val, "linear-gradient(") || !strings.HasSuffix(val, ")") {
+		return nil, fmt.Errorf("invalid gradient: %s", val)
+	}
+	args := splitArgs(val[len("linear-gradient(") : len(val)-1])
+	g := &LinearGradient{Angle: 180}
+	if len(args) > 0 {
+		first := strings.ToLower(strings.Join(strings.Fields(args[0]), " "))
+		if a, ok := gradientDirections[first]; ok {
+			g.Angle = a
+			args = args[1:]
+		} else if a, err := parseAngle(first); err == nil {
+			g.Angle = a
+			args = args[1:]
+		}
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("invalid gradient: %s", val)
+	}
+	// offsets that are not specified are distributed evenly
+	offsets := make([]float64, len(args))
+	known := make([]bool, len(args))
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		colorPart, offset := arg, ""
+		if i := strings.LastIndexByte(arg, ' '); i != -1 && !strings.HasSuffix(arg, ")") {
+			colorPart, offset = arg[:i], arg[i+1:]
+		}
+		c, err := parseColor(colorPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gradient: %s", val)
+		}
+		if offset != "" {
+			if !strings.HasSuffix(offset, "%") {
+				return nil, fmt.Errorf("invalid gradient offset: %s", offset)
+			}
+			o, err := strconv.ParseFloat(strings.TrimSuffix(offset, "%"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid gradient offset: %s", offset)
+			}
+			offsets[i], known[i] = o/100, true
+		}
+		g.Stops = append(g.Stops, GradientStop{Color: c.(color.Color)})
+	}
+	if !known[0] {
+		offsets[0], known[0] = 0, true
+	}
+	if last := len(args) - 1; !known[last] {
+		offsets[last], known[last] = 1, true
+	}
+	for i := 1; i < len(args); i++ {
+		if known[i] {
+			// an offset smaller than the previous one is clamped as in CSS
+			if offsets[i] < offsets[i-1] {
+				offsets[i] = offsets[i-1]
+			}
+			continue
+		}
+		j := i
+		for !known[j] {
+			j++
+		}
+		for k := i; k < j; k++ {
+			offsets[k] = offsets[i-1] + (offsets[j]-offsets[i-1])*float64(k-i+1)/float64(j-i+1)
+		}
+		i = j - 1
+	}
+	for i := range g.Stops {
+		g.Stops[i].Offset = offsets[i]
+	}
+	return g, nil
+}
+
+func parseAngle(val string) (float64, error) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / 3.141592653589793}, {"turn", 360}}
+	for _, u := range units {
+		if strings.HasSuffix(val, u.suffix) {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(val, u.suffix), 64)
+			if err != nil {
+				return 0, err
+			}
+			return f * u.scale, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid angle: %s", val)
+}
+
+// splitArgs splits the arguments of a function by the commas
+// that are not inside parentheses.
+func splitArgs(s string) []string {
+	var ret []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				ret = append(ret, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(ret, strings.TrimSpace(s[start:]))
+}
+
+// splitFields splits the value at the spaces outside of the parentheses,
+// e.g. into the lengths and the color of box-shadow: rgba(0, 0, 0, .5) 0 2px.
+func splitFields(s string) []string {
+	var ret []string
+	depth, start := 0, -1
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case unicode.IsSpace(r) && depth == 0:
+			if start != -1 {
+				ret = append(ret, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		ret = append(ret, s[start:])
+	}
+	return ret
+}
+
+type cssBackground struct {
+	color        color.Color
+	gradient     *LinearGradient
+	image        string
+	checkerboard *Checkerboard
+}
+
+// parseBackground parses the background shorthand: a color, a gradient,
+// an image or a checkerboard.
+func parseBackground(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, "url(") {
+		path, err := parseURL(val)
+		if err != nil {
+			return nil, err
+		}
+		return cssBackground{image: path}, nil
+	}
+	if strings.HasPrefix(val, "linear-gradient(") {
+		g, err := parseLinearGradient(val)
+		if err != nil {
+			return nil, err
+		}
+		return cssBackground{gradient: g}, nil
+	}
+	if strings.HasPrefix(val, "checkerboard(") {
+		c, err := parseCheckerboard(val)
+		if err != nil {
+			return nil, err
+		}
+		return cssBackground{checkerboard: c}, nil
+	}
+	if val == "none" {
+		return cssBackground{}, nil
+	}
+	c, err := parseColor(val)
+	if err != nil {
+		return nil, err
+	}
+	return cssBackground{color: c.(color.Color)}, nil
+}
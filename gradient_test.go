@@ -0,0 +1,44 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLinearGradient(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 128, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	tests := []struct {
+		val  string
+		want *LinearGradient
+	}{
+		{"linear-gradient(red, blue)", &LinearGradient{Angle: 180, Stops: []GradientStop{{0, red}, {1, blue}}}},
+		{"linear-gradient(to right, red, green, blue)", &LinearGradient{Angle: 90, Stops: []GradientStop{{0, red}, {0.5, green}, {1, blue}}}},
+		{"linear-gradient(45deg, red 20%, green, blue 80%)", &LinearGradient{Angle: 45, Stops: []GradientStop{{0.2, red}, {0.5, green}, {0.8, blue}}}},
+		{"linear-gradient(0.5turn, red, rgba(0, 0, 0, 0.5) 40%)", &LinearGradient{Angle: 180, Stops: []GradientStop{{0, red}, {0.4, color.NRGBA{0, 0, 0, 128}}}}},
+		{"linear-gradient(to top left, red 50%, blue 10%)", &LinearGradient{Angle: 315, Stops: []GradientStop{{0.5, red}, {0.5, blue}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseLinearGradient(tt.val)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+	for _, val := range []string{"linear-gradient(red)", "linear-gradient(red, nocolor)", "radial-gradient(red, blue)", "linear-gradient(red 5px, blue)"} {
+		_, err := parseLinearGradient(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestBackgroundShorthand(t *testing.T) {
+	view := Parse(`<view style="background: linear-gradient(red, blue)"><view id="a" style="background: #fff"></view></view>`, nil)
+	require.Nil(t, view.BackgroundColor)
+	require.NotNil(t, view.BackgroundGradient)
+	a := view.MustGetByID("a")
+	require.Equal(t, color.NRGBA{255, 255, 255, 255}, a.BackgroundColor)
+	require.Nil(t, a.BackgroundGradient)
+}
@@ -0,0 +1,310 @@
+package furex
+
+import (
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Grid is the handler of a view showing cells of the same size in rows,
+// created from the data by NewCell, e.g. for inventories and level select
+// screens. The cells are CellWidth wide, as many in a row as fit in the
+// width of the view, or share the width of the view between Columns
+// columns. The grid owns the children of the view, laid out in a column of
+// rows of cells, and the view is as high as the rows.
+//
+// A cell is selected when it is clicked or tapped, or with the arrow keys
+// while the grid has the focus. The selected cell matches the :checked
+// pseudo-class. With a Selection, the cells clicked and tapped are also
+// selected in it, and match :selected:
+//
+//	inventory.Handler = &furex.Grid{
+//		Count:      len(items),
+//		CellWidth:  48,
+//		CellHeight: 48,
+//		Gap:        4,
+//		NewCell:    func(i int) *furex.View { return newItemSlot(items[i]) },
+//		OnSelect:   func(i int) { showDetails(items[i]) },
+//	}
+type Grid struct {
+	// Count is the number of cells.
+	Count int
+	// NewCell creates the view of the cell at the index. It is called when
+	// the cell is shown first, and for all the cells after Refresh.
+	NewCell func(index int) *View
+	// CellWidth is the width of the cells, which determines the number of
+	// columns fitting in the view if Columns is 0.
+	CellWidth int
+	// CellHeight is the height of the cells. The cells are square if it
+	// is 0.
+	CellHeight int
+	// Columns is the number of columns, sharing the width of the view. If
+	// it is 0, the number of columns depends on the width of the view.
+	Columns int
+	// Gap is the space between the cells.
+	Gap int
+	// Selected is the index of the selected cell, the first one by
+	// default. No cell is selected if it is -1.
+	Selected int
+	// OnSelect is called with the index of the cell selected by the input.
+	OnSelect func(index int)
+	// Selection selects the cells clicked, tapped or chosen with the arrow
+	// keys, e.g. several cells in SelectMulti mode.
+	Selection *Selection
+	// CellID returns the id of the cell at the index in the Selection. The
+	// index is the id if it is nil.
+	CellID func(index int) string
+
+	cells   []*View
+	gesture selectionGesture
+	// selected is the version of the Selection the cells were styled with.
+	selected int
+	// shape is the shape the cells were last laid out in.
+	shape   gridShape
+	stale   bool
+	styled  int
+	focused bool
+}
+
+var _ ButtonHandler = (*Grid)(nil)
+var _ Updater = (*Grid)(nil)
+var _ FocusHandler = (*Grid)(nil)
+
+// gridShape is the arrangement of the cells of a grid.
+type gridShape struct {
+	columns, width, height, gap int
+}
+
+// Refresh creates the cells again, e.g. after the data of the cells
+// changed.
+func (g *Grid) Refresh() {
+	g.stale = true
+}
+
+// Cell returns the view of the cell at the index, or nil if the index is
+// out of the cells.
+func (g *Grid) Cell(index int) *View {
+	if index < 0 || index >= len(g.cells) {
+		return nil
+	}
+	return g.cells[index]
+}
+
+// Select selects the cell at the index without calling OnSelect.
+func (g *Grid) Select(index int) {
+	g.Selected = index
+}
+
+// choose selects the cell and calls OnSelect if it has changed.
+func (g *Grid) choose(index int) {
+	if index == g.Selected {
+		return
+	}
+	g.Selected = index
+	if g.OnSelect != nil {
+		g.OnSelect(index)
+	}
+}
+
+// cellID returns the id of the cell at the index in the Selection.
+func (g *Grid) cellID(index int) string {
+	if g.CellID != nil {
+		return g.CellID(index)
+	}
+	return strconv.Itoa(index)
+}
+
+// cellAt returns the index of the cell at the point, or -1.
+func (g *Grid) cellAt(x, y int) int {
+	for i, c := range g.cells {
+		if !c.Hidden && c.isDisplayed() && !c.Disabled && isInside(&c.frame, x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandlePress implements ButtonHandler.
+func (g *Grid) HandlePress(x, y int, t ebiten.TouchID) {
+	if i := g.cellAt(x, y); i >= 0 {
+		g.gesture.press(i, t)
+	}
+}
+
+// HandleRelease implements ButtonHandler.
+func (g *Grid) HandleRelease(x, y int, isCancel bool) {
+	if !g.gesture.release(isCancel) {
+		return
+	}
+	if i := g.cellAt(x, y); i >= 0 {
+		g.choose(i)
+		if g.Selection != nil {
+			g.Selection.click(i, len(g.cells), g.cellID)
+		}
+	}
+}
+
+// HandleFocus implements FocusHandler.
+func (g *Grid) HandleFocus() {
+	g.focused = true
+}
+
+// HandleBlur implements FocusHandler.
+func (g *Grid) HandleBlur() {
+	g.focused = false
+}
+
+// Update implements Updater.
+func (g *Grid) Update(v *View) {
+	if g.NewCell == nil {
+		return
+	}
+	g.layout(v)
+	if g.Selection != nil {
+		g.gesture.update(g.Selection, g.cellID)
+	}
+	if g.focused && !v.Disabled && len(g.cells) > 0 {
+		keys := []ebiten.Key{ebiten.KeyArrowLeft, ebiten.KeyArrowRight, ebiten.KeyArrowUp, ebiten.KeyArrowDown}
+		moves := []int{-1, 1, -g.shape.columns, g.shape.columns}
+		for i, k := range keys {
+			if isKeyJustPressed(k) || isKeyRepeated(k) {
+				g.move(moves[i])
+			}
+		}
+	}
+	if g.styled != g.Selected || (g.Selection != nil && g.selected != g.Selection.version) {
+		// the selection is also changed from Go
+		g.styled = g.Selected
+		if g.Selection != nil {
+			g.selected = g.Selection.version
+		}
+		v.restyle()
+	}
+}
+
+// move selects the cell d cells away from the selected one, staying in
+// the cells.
+func (g *Grid) move(d int) {
+	i := g.Selected + d
+	if g.Selected < 0 || g.Selected >= len(g.cells) {
+		i = 0
+	} else if i < 0 || i >= len(g.cells) {
+		return
+	}
+	g.choose(i)
+	if g.Selection != nil {
+		g.Selection.click(i, len(g.cells), g.cellID)
+	}
+}
+
+// layout creates the cells and arranges them in rows for the width of the
+// view.
+func (g *Grid) layout(v *View) {
+	width := v.frame.Dx() - v.PaddingLeft - v.PaddingRight
+	shape := gridShape{columns: g.Columns, width: g.CellWidth, gap: g.Gap}
+	if shape.columns > 0 {
+		shape.width = (width - g.Gap*(shape.columns-1)) / shape.columns
+	} else if g.CellWidth > 0 {
+		shape.columns = (width + g.Gap) / (g.CellWidth + g.Gap)
+	}
+	if shape.columns < 1 {
+		shape.columns = 1
+	}
+	shape.height = g.CellHeight
+	if shape.height == 0 {
+		shape.height = shape.width
+	}
+	if shape == g.shape && len(g.cells) == g.Count && !g.stale {
+		return
+	}
+	g.shape = shape
+
+	// the removed cells are removed from the rows
+	n := g.Count
+	if g.stale {
+		n, g.stale = 0, false
+	}
+	for len(g.cells) > n {
+		c := g.cells[len(g.cells)-1]
+		g.cells = g.cells[:len(g.cells)-1]
+		if c.hasParent {
+			c.parent.removeChild(c)
+		}
+	}
+
+	// the cells are moved to their rows
+	entries := map[*View]*child{}
+	for _, r := range v.children {
+		for _, c := range r.item.children {
+			entries[c.item] = c
+		}
+		r.item.children = nil
+	}
+	rows := (g.Count + shape.columns - 1) / shape.columns
+	for len(v.children) > rows {
+		v.removeChild(v.children[len(v.children)-1].item)
+	}
+	for len(v.children) < rows {
+		v.addChild(&View{Direction: Row})
+	}
+	v.Direction = Column
+	for i, r := range v.children {
+		r.item.Height, r.item.MarginTop = shape.height, 0
+		if i > 0 {
+			r.item.MarginTop = g.Gap
+		}
+		r.item.isDirty = true
+	}
+	for i := 0; i < g.Count; i++ {
+		row := v.children[i/shape.columns].item
+		var cell *View
+		if i < len(g.cells) {
+			cell = g.cells[i]
+			if c, ok := entries[cell]; ok {
+				cell.parent = row
+				row.children = append(row.children, c)
+			} else {
+				row.addChild(cell)
+			}
+		} else {
+			cell = g.NewCell(i)
+			g.cells = append(g.cells, cell)
+			row.addChild(cell)
+		}
+		cell.Width, cell.Height = shape.width, shape.height
+		cell.MarginLeft = 0
+		if i%shape.columns > 0 {
+			cell.MarginLeft = g.Gap
+		}
+	}
+	v.Layout()
+	// the new cells are styled with the selection
+	v.restyle()
+}
+
+// isSelectedCell returns true if the view is the selected cell of a grid.
+func (v *View) isSelectedCell() bool {
+	if !v.hasParent || !v.parent.hasParent {
+		return false
+	}
+	g, ok := v.parent.parent.Handler.(*Grid)
+	return ok && g.Cell(g.Selected) == v
+}
+
+// inGridSelection returns true if the view is a cell of a grid selected in
+// the Selection of the grid.
+func (v *View) inGridSelection() bool {
+	if !v.hasParent || !v.parent.hasParent {
+		return false
+	}
+	g, ok := v.parent.parent.Handler.(*Grid)
+	if !ok || g.Selection == nil {
+		return false
+	}
+	for i, c := range g.cells {
+		if c == v {
+			return g.Selection.IsSelected(g.cellID(i))
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrid(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 200px; align-items: flex-start; justify-content: flex-start">
+		<view id="inventory" style="width: 100px"></view>
+	</view>`, nil)
+	inventory := root.MustGetByID("inventory")
+	var selected []int
+	grid := &Grid{
+		Count:     7,
+		CellWidth: 30,
+		Gap:       5,
+		NewCell:   func(i int) *View { return &View{ID: string(rune('a' + i))} },
+		OnSelect:  func(i int) { selected = append(selected, i) },
+	}
+	inventory.Handler = grid
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	tick(InputState{})
+	tick(InputState{})
+
+	// as many cells as fit in the width are in a row
+	require.Len(t, inventory.children, 3)
+	require.Equal(t, image.Rect(35, 35, 65, 65), root.MustGetByID("e").frame)
+	require.Equal(t, 100, inventory.frame.Dy())
+	require.True(t, grid.Cell(0).hasPseudoClass("checked"))
+
+	// a cell is selected when it is clicked
+	mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+	tick(InputState{CursorX: 40, CursorY: 40, MouseButtons: mouse})
+	tick(InputState{CursorX: 40, CursorY: 40})
+	require.Equal(t, 4, grid.Selected)
+	require.True(t, grid.Cell(4).hasPseudoClass("checked"))
+	require.False(t, grid.Cell(0).hasPseudoClass("checked"))
+
+	// and with the arrow keys while the grid has the focus
+	inventory.Focus()
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowUp}})
+	require.Equal(t, 1, grid.Selected)
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowRight}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowDown}})
+	require.Equal(t, 5, grid.Selected)
+	tick(InputState{})
+	// the selection stays in the cells
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowDown}})
+	require.Equal(t, 5, grid.Selected)
+	require.Equal(t, []int{4, 1, 2, 5}, selected)
+
+	// the columns share the width, and the cells are kept
+	e := grid.Cell(4)
+	grid.Columns = 4
+	tick(InputState{})
+	tick(InputState{})
+	require.Len(t, inventory.children, 2)
+	require.Same(t, e, grid.Cell(4))
+	require.Equal(t, image.Rect(0, 26, 21, 47), e.frame)
+
+	// Refresh creates the cells again
+	grid.Count = 2
+	grid.Refresh()
+	tick(InputState{})
+	require.Len(t, inventory.children, 1)
+	require.NotSame(t, e, grid.Cell(1))
+	require.Nil(t, grid.Cell(4))
+}
@@ -36,6 +36,34 @@ type UpdateHandler interface {
 	HandleUpdate()
 }
 
+// Sizer represents a component with a size of its own content, such as a
+// text. A view without children is sized to the content, plus its
+// padding, when its width or height is not set.
+type Sizer interface {
+	// Size returns the size of the content of the view.
+	Size(v *View) (width, height int)
+}
+
+// HeightMeasurer represents a component whose height depends on its
+// width, such as a text wrapped at the width of the view. The layout
+// measures the height of a view without children and without a height
+// set at the width resolved for it, before the items are arranged.
+type HeightMeasurer interface {
+	// MeasureHeight returns the height of the content of the view at
+	// the width of the content.
+	MeasureHeight(v *View, width int) int
+}
+
+// FrameChangedHandler represents a component that is notified when the
+// frame of its view changes, e.g. to rebuild the images derived from the
+// frame only when needed.
+type FrameChangedHandler interface {
+	// HandleFrameChanged is called after the layout when the frame of the
+	// view has changed since the previous layout. The old frame is empty
+	// after the first layout.
+	HandleFrameChanged(old, new image.Rectangle, v *View)
+}
+
 // ButtonHandler represents a button component.
 type ButtonHandler interface {
 	// HandlePress handle the event when user just started pressing the button
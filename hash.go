@@ -0,0 +1,184 @@
+package furex
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"image/color"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Revision returns a number that changes whenever the view or one of its
+// descendants changes through the setters, a restyle, the children added
+// or removed, or a layout. It is cheap to call every frame, while
+// StyleHash walks the whole tree, so the hash can be computed only when
+// the revision changed. Fields assigned directly are not counted unless
+// Layout is called after them.
+func (v *View) Revision() uint64 {
+	return v.revision
+}
+
+// touch counts a change of the view in its revision and the revisions of
+// its ancestors.
+func (v *View) touch() {
+	for vv := v; vv != nil; vv = vv.parent {
+		vv.revision++
+		if !vv.hasParent {
+			break
+		}
+	}
+}
+
+// StyleHash returns a hash of the state of the view and its descendants:
+// the exported fields of the views and their handlers, such as the style,
+// the texts and the values of sliders and checkboxes, and the frames of
+// the layout. External systems such as netcode mirroring the UI and
+// replays can compare it with the hash of the previous frame to detect
+// changes without comparing the trees.
+//
+// Colors, pointers and slices are hashed by their values, and images,
+// views and faces by their identities. Functions are ignored.
+//
+// It reflects on every field of every view of the subtree, so its cost
+// grows with the size of the tree. Check Revision first to skip it when
+// nothing changed through the API.
+func (v *View) StyleHash() uint64 {
+	h := &styleHasher{hash: fnv.New64a()}
+	h.view(v)
+	return h.hash.Sum64()
+}
+
+// styleHasher writes the values of views to the hash.
+type styleHasher struct {
+	hash hash.Hash64
+	buf  [8]byte
+}
+
+// maxHashDepth limits the depth of the values followed by the hash, e.g.
+// in handlers referring to themselves.
+const maxHashDepth = 8
+
+var (
+	viewType  = reflect.TypeOf(View{})
+	imageType = reflect.TypeOf(&ebiten.Image{})
+	// hashFields caches the indices of the exported fields of the types.
+	hashFields sync.Map
+)
+
+func (h *styleHasher) view(v *View) {
+	h.value(reflect.ValueOf(v).Elem(), 0)
+	for _, p := range []int{v.frame.Min.X, v.frame.Min.Y, v.frame.Max.X, v.frame.Max.Y} {
+		h.uint(uint64(p))
+	}
+	if v.Handler != nil {
+		h.value(reflect.ValueOf(v.Handler), 1)
+	}
+	h.uint(uint64(len(v.children)))
+	for _, c := range v.children {
+		h.view(c.item)
+	}
+}
+
+func (h *styleHasher) uint(n uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], n)
+	h.hash.Write(h.buf[:])
+}
+
+func (h *styleHasher) value(v reflect.Value, depth int) {
+	if depth > maxHashDepth {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.uint(1)
+		} else {
+			h.uint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.uint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.uint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.uint(math.Float64bits(v.Float()))
+	case reflect.String:
+		h.uint(uint64(v.Len()))
+		h.hash.Write([]byte(v.String()))
+	case reflect.Ptr:
+		switch {
+		case v.IsNil():
+			h.uint(0)
+		case v.Type() == imageType || v.Type().Elem() == viewType:
+			h.uint(uint64(v.Pointer()))
+		default:
+			h.uint(1)
+			h.value(v.Elem(), depth+1)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			h.uint(0)
+			return
+		}
+		if c, ok := v.Interface().(color.Color); ok {
+			r, g, b, a := c.RGBA()
+			h.uint(uint64(r)<<48 | uint64(g)<<32 | uint64(b)<<16 | uint64(a))
+			return
+		}
+		// other interfaces such as faces are hashed by their identities
+		h.identity(v.Elem())
+	case reflect.Slice, reflect.Array:
+		h.uint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			h.value(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		// the entries are combined regardless of their order
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			e := &styleHasher{hash: fnv.New64a()}
+			e.value(iter.Key(), depth+1)
+			e.value(iter.Value(), depth+1)
+			sum += e.hash.Sum64()
+		}
+		h.uint(uint64(v.Len()))
+		h.uint(sum)
+	case reflect.Struct:
+		for _, i := range exportedFields(v.Type()) {
+			h.value(v.Field(i), depth+1)
+		}
+	}
+}
+
+// identity hashes the value by its address, or by its value if it is
+// not a pointer.
+func (h *styleHasher) identity(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		h.uint(uint64(v.Pointer()))
+	default:
+		h.value(v, maxHashDepth)
+	}
+}
+
+// exportedFields returns the indices of the exported fields of the struct
+// type, including the embedded structs, except the handler of views.
+func exportedFields(t reflect.Type) []int {
+	if f, ok := hashFields.Load(t); ok {
+		return f.([]int)
+	}
+	var ret []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type.Kind() == reflect.Func || (t == viewType && f.Name == "Handler") {
+			continue
+		}
+		ret = append(ret, i)
+	}
+	hashFields.Store(t, ret)
+	return ret
+}
@@ -0,0 +1,83 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleHash(t *testing.T) {
+	html := `<view style="width: 100px; height: 100px">
+		<view id="panel" data-a="1" data-b="2" style="width: 50px; height: 20px; background-color: #f00; box-shadow: 0 2px 4px #000">Hello</view>
+		<slider id="volume" style="width: 50px; height: 10px"></slider>
+	</view>`
+	root := Parse(html, nil)
+	root.Update()
+	hash := root.StyleHash()
+	require.Equal(t, hash, root.StyleHash())
+	// the same tree has the same hash
+	other := Parse(html, nil)
+	other.Update()
+	require.Equal(t, hash, other.StyleHash())
+
+	panel := root.MustGetByID("panel")
+	panel.BackgroundColor = color.RGBA{0, 0xff, 0, 0xff}
+	require.NotEqual(t, hash, root.StyleHash())
+	panel.BackgroundColor = color.NRGBA{0xff, 0, 0, 0xff}
+	require.Equal(t, hash, root.StyleHash())
+
+	// pointers are hashed by their values
+	shadow := *panel.BoxShadow
+	panel.BoxShadow = &shadow
+	require.Equal(t, hash, root.StyleHash())
+	panel.BoxShadow.Blur = 8
+	require.NotEqual(t, hash, root.StyleHash())
+	panel.BoxShadow.Blur = 4
+
+	// handlers, images and the layout are included
+	root.MustGetByID("volume").Handler.(*Slider).Value = 10
+	require.NotEqual(t, hash, root.StyleHash())
+	root.MustGetByID("volume").Handler.(*Slider).Value = 50
+	require.Equal(t, hash, root.StyleHash())
+	panel.BackgroundImage = ebiten.NewImage(1, 1)
+	require.NotEqual(t, hash, root.StyleHash())
+	panel.BackgroundImage = nil
+	panel.Width = 60
+	root.Update()
+	require.NotEqual(t, hash, root.StyleHash())
+}
+
+func TestRevision(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<view id="list"><view id="item" style="width: 10px; height: 10px"></view></view>
+		<view id="other"></view>
+	</view>`, nil)
+	root.Update()
+	list, item, other := root.MustGetByID("list"), root.MustGetByID("item"), root.MustGetByID("other")
+	rev, otherRev := root.Revision(), other.Revision()
+	require.Equal(t, rev, root.Revision())
+
+	// the changes of the descendants are counted by the ancestors
+	item.SetWidth(20)
+	require.NotEqual(t, rev, root.Revision())
+	require.NotEqual(t, rev, list.Revision())
+	require.Equal(t, otherRev, other.Revision())
+
+	rev = root.Revision()
+	root.Update()
+	require.NotEqual(t, rev, root.Revision())
+	rev = root.Revision()
+	root.Update()
+	require.Equal(t, rev, root.Revision())
+
+	list.AddChild(&View{})
+	require.NotEqual(t, rev, root.Revision())
+	rev = root.Revision()
+	list.RemoveChild(item)
+	require.NotEqual(t, rev, root.Revision())
+	rev = root.Revision()
+	other.SetDisabled(true)
+	require.NotEqual(t, rev, root.Revision())
+}
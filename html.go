@@ -1,13 +1,17 @@
 package furex
 
 import (
+	"bytes"
 	"fmt"
+	"image/color"
 	"io"
+	"io/fs"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
 
-	"github.com/vanng822/go-premailer/premailer"
+	"golang.org/x/image/font"
 	"golang.org/x/net/html"
 )
 
@@ -16,6 +20,11 @@ import (
 // This allows flexibility in usage:
 // If you want to reuse the same handler instance for multiple HTML tags, pass the instance;
 // otherwise, pass the factory function to create separate handler instances for each tag.
+//
+// The factory functions and the function components can also take the
+// attributes of the element, as func(attrs map[string]string) furex.Handler
+// or func(attrs map[string]string) *furex.View, to make parameterized
+// components such as <gauge max="100" color="red">.
 type Component interface{}
 
 // ComponentsMap is a type alias for a dictionary that associates
@@ -45,28 +54,188 @@ type ParseOptions struct {
 
 	// Handler is the handler for the root view.
 	Handler Handler
+
+	// FS is used to load the files referenced by the document,
+	// such as stylesheets in <link rel="stylesheet" href="ui.css">.
+	// Paths are resolved relative to the root of FS.
+	FS fs.FS
+
+	// ImageResolver returns the images referenced by the document, such as
+	// background-image: url(img/panel.png), from the path in url().
+	// Without a resolver, PNG and JPEG images are loaded from FS.
+	ImageResolver ImageResolver
+
+	// Data has the values of the {{ path }} placeholders in the attributes
+	// and the texts of the document, e.g. {{ player.name }}. The paths can
+	// go through maps, struct fields and slices.
+	Data map[string]any
+
+	// Face is the default font face of the texts of the document drawn by
+	// the Text handlers without a face, when no font of the font-family
+	// is registered (see RegisterFont).
+	Face font.Face
 }
 
 func Parse(input string, opts *ParseOptions) *View {
+	return parseDocument(input, opts, nil)
+}
+
+// ParseStrict parses the HTML like Parse, but returns an error instead of
+// tolerating problems in the document: unknown tags, invalid markup,
+// stylesheets that cannot be loaded, invalid selectors and invalid
+// styles. The errors of the elements have their line number and tag, so
+// broken markup can be caught in tests.
+func ParseStrict(input string, opts *ParseOptions) (view *View, err error) {
+	errs := &ErrorList{}
+	defer func() {
+		if r := recover(); r != nil {
+			view, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	view = parseDocument(input, opts, errs)
+	if errs.HasErrors() {
+		return nil, errs
+	}
+	return view, nil
+}
+
+// parseDocument parses the document. The problems are collected in errs
+// if it is not nil, or printed otherwise.
+func parseDocument(input string, opts *ParseOptions, errs *ErrorList) *View {
 	if opts == nil {
 		opts = &ParseOptions{}
 	}
 
-	inlinedHTML := inlineCSS(input)
-	z := html.NewTokenizer(strings.NewReader(inlinedHTML))
+	doc, err := scanDocument(input, opts.FS)
+	if err != nil {
+		if errs != nil {
+			errs.Add(err)
+		} else {
+			println(fmt.Sprintf("load stylesheet errors: %v", err))
+		}
+	}
+	sheet, err := parseStylesheet(doc.styles)
+	if err != nil {
+		if errs != nil {
+			errs.Add(err)
+		} else {
+			println(fmt.Sprintf("parse css errors: %v", err))
+		}
+	}
+	p := &parser{
+		opts:   opts,
+		sheet:  sheet,
+		cms:    []ComponentsMap{opts.Components, registerdComponents},
+		images: &imageLoader{resolver: opts.ImageResolver, fsys: opts.FS},
+		errs:   errs,
+		data:   opts.Data,
+		line:   1,
+	}
+	// the lazy subtrees expanded later report the errors as usual
+	defer func() { p.errs = nil }()
+	z := html.NewTokenizer(strings.NewReader(input))
 	dummy := &View{}
-	stack := &stack{stack: []*View{dummy}}
-	depth := 0
-	inBody := false
-	cms := []ComponentsMap{opts.Components, registerdComponents}
-Loop:
+	// Without a <body> tag, the top level elements are converted to views.
+	p.parseTokens(z, &stack{stack: []*View{dummy}}, 0, !doc.hasBody)
+	if len(dummy.children) != 1 {
+		panic(fmt.Sprintf("invalid html: %s", input))
+	}
+	view := dummy.PopChild()
+	// the root view should be dirty for the first time
+	// even if the view does not have any children
+	view.isDirty = true
+	if opts.Handler != nil {
+		view.Handler = opts.Handler
+	}
+	return view
+}
+
+// ParseReader parses the HTML read from r like Parse. It returns an error
+// if r cannot be read.
+func ParseReader(r io.Reader, opts *ParseOptions) (*View, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(b), opts), nil
+}
+
+// ParseFS parses the HTML file at the path in fsys like Parse, e.g. from
+// an embed.FS. Unless opts.FS is set, the files referenced by the document
+// are loaded from fsys relative to the directory of the file.
+func ParseFS(fsys fs.FS, name string, opts *ParseOptions) (*View, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	o := ParseOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.FS == nil {
+		if o.FS, err = fs.Sub(fsys, path.Dir(name)); err != nil {
+			return nil, err
+		}
+	}
+	return ParseReader(f, &o)
+}
+
+// parser holds the state shared by the views created from a document.
+type parser struct {
+	opts   *ParseOptions
+	sheet  *stylesheet
+	cms    cms
+	images *imageLoader
+	// errs collects the errors of the elements in strict mode.
+	errs *ErrorList
+	// data is the data of the placeholders and the directives, with the
+	// variables of the loops.
+	data map[string]any
+	// line is the line of the current token.
+	line int
+	// file is the file included being parsed, if any.
+	file string
+	// includes are the files being included.
+	includes map[string]bool
+	// slotted has the number of the children of the templates of the
+	// component views being parsed, whose other children fill the slots.
+	slotted map[*View]int
+}
+
+// next reads the next token, counting the lines of the current one.
+func (p *parser) next(z *html.Tokenizer) html.TokenType {
+	p.line += bytes.Count(z.Raw(), []byte{'\n'})
+	return z.Next()
+}
+
+// error reports an error of the element of the view.
+func (p *parser) error(view *View, err error) {
+	if p.errs == nil {
+		println(fmt.Sprintf("parse style errors: %v", err))
+		return
+	}
+	if p.file != "" {
+		p.errs.Add(fmt.Errorf("%s: line %d: <%s>: %v", p.file, p.line, view.TagName, err))
+		return
+	}
+	p.errs.Add(fmt.Errorf("line %d: <%s>: %v", p.line, view.TagName, err))
+}
+
+// parseTokens converts the tokens to views and adds them to the view
+// at the top of the stack.
+func (p *parser) parseTokens(z *html.Tokenizer, stack *stack, depth int, inBody bool) {
 	for {
-		tt := z.Next()
+		tt := p.next(z)
 		tn, _ := z.TagName()
+		if tt == html.StartTagToken && isVoidTag(string(tn)) {
+			// void elements such as <hr> have no end tag
+			tt = html.SelfClosingTagToken
+		}
 		switch tt {
 		case html.ErrorToken:
 			if z.Err() == io.EOF {
-				break Loop
+				return
 			}
 			panic(z.Err())
 		case html.StartTagToken:
@@ -74,64 +243,280 @@ Loop:
 				inBody = true
 				continue
 			}
-			if !inBody {
+			if !inBody || isDocumentTag(string(tn)) {
+				if hasRawText(string(tn)) {
+					// the contents are not texts of the document
+					p.readInner(z)
+				}
+				continue
+			}
+			raw, rawAttrs := string(z.Raw()), readRawAttrs(z)
+			if string(tn) == "include" {
+				p.readInner(z)
+				p.include(rawAttrs, stack, depth)
 				continue
 			}
-			view := processTag(z, string(tn), opts, depth, cms)
+			if loop, ok := findAttr(rawAttrs, "for"); ok {
+				p.repeat(z, string(tn), raw, rawAttrs, loop, stack, depth, true)
+				continue
+			}
+			view := p.processTag(string(tn), raw, rawAttrs, depth, stack.ancestors())
 			if view == nil {
+				// the element is omitted with its children
+				p.readInner(z)
 				continue
 			}
 			stack.peek().AddChild(view)
+			if p.setInner(view, func() string { return p.readInner(z) }) {
+				continue
+			}
+			p.openSlots(view)
 			stack.push(view)
 
 			depth++
 		case html.SelfClosingTagToken:
-			view := processTag(z, string(tn), opts, depth, cms)
+			if !inBody || isDocumentTag(string(tn)) {
+				continue
+			}
+			raw, rawAttrs := string(z.Raw()), readRawAttrs(z)
+			if string(tn) == "include" {
+				p.include(rawAttrs, stack, depth)
+				continue
+			}
+			if loop, ok := findAttr(rawAttrs, "for"); ok {
+				p.repeat(z, string(tn), raw, rawAttrs, loop, stack, depth, false)
+				continue
+			}
+			view := p.processTag(string(tn), raw, rawAttrs, depth, stack.ancestors())
 			if view == nil {
 				continue
 			}
+			view.lazy = nil
 			stack.peek().AddChild(view)
+			fillSlots(view, len(view.children))
 		case html.TextToken:
-			if stack.len() > 0 {
-				stack.peek().Text = strings.TrimSpace(string(z.Text()))
+			if !inBody || stack.len() <= 1 {
+				continue
+			}
+			if text := strings.TrimSpace(string(z.Text())); text != "" {
+				view := stack.peek()
+				view.Text = p.interpolate(view, text)
+				if view.Handler == nil {
+					p.addTextView(view, view.Text, stack.ancestors())
+				}
 			}
 		case html.EndTagToken:
 			if string(tn) == "body" {
 				inBody = false
 				continue
 			}
-			if !inBody {
+			if !inBody || isDocumentTag(string(tn)) || isVoidTag(string(tn)) {
 				continue
 			}
-			stack.pop()
+			p.closeSlots(stack.pop())
 			depth--
 		}
 	}
-	if len(dummy.children) != 1 {
-		panic(fmt.Sprintf("invalid html: %s", input))
+}
+
+// setInner sets the inner markup of the element returned by inner as the
+// source of the lazy children or the markup of the rich text. It returns
+// false if the children are to be parsed.
+func (p *parser) setInner(view *View, inner func() string) bool {
+	if view.lazy != nil {
+		view.lazy.source = inner()
+		view.lazy.parser = p
+		view.lazy.data = p.data
+		return true
 	}
-	view := dummy.PopChild()
-	// the root view should be dirty for the first time
-	// even if the view does not have any children
-	view.isDirty = true
-	if opts.Handler != nil {
-		view.Handler = opts.Handler
+	if _, ok := view.Handler.(*RichText); ok {
+		// the markup of the rich text is its text
+		view.Text = strings.TrimSpace(p.interpolateMarkup(view, inner()))
+		return true
 	}
-	return view
+	return false
 }
 
-func inlineCSS(doc string) string {
-	prem, err := premailer.NewPremailerFromString(doc, &premailer.Options{})
-	if err != nil {
-		println(fmt.Errorf("invalid css: %s", err))
-		return doc
+// openSlots records the children of the template of the view, before the
+// children of the call site are parsed.
+func (p *parser) openSlots(view *View) {
+	if p.slotted == nil {
+		p.slotted = map[*View]int{}
+	}
+	p.slotted[view] = len(view.children)
+}
+
+// closeSlots fills the slots of the view with the children of the call
+// site once they are parsed.
+func (p *parser) closeSlots(view *View) {
+	n, ok := p.slotted[view]
+	if !ok {
+		return
+	}
+	delete(p.slotted, view)
+	fillSlots(view, n)
+}
+
+// addTextView adds a <text> view drawing a text of the plain view, so the
+// texts and the elements of the view are drawn in the document order.
+func (p *parser) addTextView(view *View, text string, ancestors []*View) {
+	tv, ok := lookupView("text", nil, p.cms)
+	if !ok {
+		return
+	}
+	tv.TagName = "text"
+	tv.Text = text
+	// the text is aligned like the text of the element it is written in
+	tv.TextAlign, tv.VerticalAlign = view.TextAlign, view.VerticalAlign
+	p.setStyleProps(tv, attrs{miscs: map[string]string{}}, ancestors)
+	view.AddChild(tv)
+}
+
+// readInner returns the source between the current start tag
+// and its end tag, consuming the tokens. Void elements such as <br>
+// have no end tag, so they do not nest.
+func (p *parser) readInner(z *html.Tokenizer) string {
+	sb := &strings.Builder{}
+	for nest := 1; ; {
+		switch p.next(z) {
+		case html.ErrorToken:
+			return sb.String()
+		case html.StartTagToken:
+			if tn, _ := z.TagName(); !isVoidTag(string(tn)) {
+				nest++
+			}
+		case html.EndTagToken:
+			if tn, _ := z.TagName(); isVoidTag(string(tn)) {
+				break
+			}
+			nest--
+			if nest == 0 {
+				return sb.String()
+			}
+		}
+		sb.Write(z.Raw())
+	}
+}
+
+type document struct {
+	styles  string
+	hasBody bool
+}
+
+// scanDocument collects the contents of <style> elements and
+// the stylesheets linked with <link> elements in the document order.
+func scanDocument(input string, fsys fs.FS) (document, error) {
+	return scanDocumentFile(input, fsys, map[string]bool{})
+}
+
+// scanDocumentFile scans the document including the styles of the
+// documents included with <include> elements. included are the files
+// being scanned.
+func scanDocumentFile(input string, fsys fs.FS, included map[string]bool) (document, error) {
+	doc := document{}
+	errs := &ErrorList{}
+	z := html.NewTokenizer(strings.NewReader(input))
+	inStyle := false
+	sb := &strings.Builder{}
+	for {
+		tt := z.Next()
+		tn, _ := z.TagName()
+		switch tt {
+		case html.ErrorToken:
+			doc.styles = sb.String()
+			if errs.HasErrors() {
+				return doc, errs
+			}
+			return doc, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch string(tn) {
+			case "body":
+				doc.hasBody = true
+			case "style":
+				inStyle = tt == html.StartTagToken
+			case "link":
+				css, err := loadStylesheet(readAttrs(z, nil), fsys)
+				if err != nil {
+					errs.Add(err)
+					continue
+				}
+				sb.WriteString(css)
+				sb.WriteString("\n")
+			case "include":
+				// the errors are reported when the elements are parsed
+				src := readAttrs(z, nil).miscs["src"]
+				if strings.Contains(src, "{{") || included[src] {
+					continue
+				}
+				b, err := readFile(fsys, src)
+				if err != nil {
+					continue
+				}
+				included[src] = true
+				sub, err := scanDocumentFile(string(b), fsys, included)
+				delete(included, src)
+				errs.Add(err)
+				sb.WriteString(sub.styles)
+			}
+		case html.EndTagToken:
+			if string(tn) == "style" {
+				inStyle = false
+			}
+		case html.TextToken:
+			if inStyle {
+				sb.Write(z.Text())
+				sb.WriteString("\n")
+			}
+		}
 	}
-	html, err := prem.Transform()
+}
+
+// loadStylesheet reads the stylesheet referenced by a <link> element.
+// It returns an empty string for links other than stylesheets.
+func loadStylesheet(attrs attrs, fsys fs.FS) (string, error) {
+	if !strings.EqualFold(attrs.miscs["rel"], "stylesheet") {
+		return "", nil
+	}
+	b, err := readFile(fsys, attrs.miscs["href"])
 	if err != nil {
-		println(fmt.Errorf("error transform html: %s", err))
-		return doc
+		return "", err
+	}
+	return string(b), nil
+}
+
+// readFile reads a file referenced by the document from the root of fsys.
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("ParseOptions.FS is required to load %s", name)
+	}
+	return fs.ReadFile(fsys, strings.TrimPrefix(path.Clean(name), "/"))
+}
+
+// isDocumentTag returns true for the tags that are not converted to views.
+func isDocumentTag(name string) bool {
+	switch name {
+	case "html", "head", "style", "title", "meta", "link", "script":
+		return true
+	}
+	return false
+}
+
+// isVoidTag returns true for the tags without contents nor end tag.
+func isVoidTag(name string) bool {
+	switch name {
+	case "br", "hr", "img", "input":
+		return true
 	}
-	return html
+	return false
+}
+
+// hasRawText returns true for the tags whose contents are not parsed.
+func hasRawText(name string) bool {
+	switch name {
+	case "style", "title", "script":
+		return true
+	}
+	return false
 }
 
 type stack struct {
@@ -150,6 +535,11 @@ func (s *stack) peek() *View {
 	return s.stack[len(s.stack)-1]
 }
 
+// ancestors returns the views in the stack except the dummy root.
+func (s *stack) ancestors() []*View {
+	return s.stack[1:]
+}
+
 func (s *stack) pop() *View {
 	v := s.peek()
 	s.stack = s.stack[:len(s.stack)-1]
@@ -157,7 +547,26 @@ func (s *stack) pop() *View {
 }
 
 var (
-	defaultComponents   = ComponentsMap{"div": nil, "view": nil}
+	defaultComponents = ComponentsMap{
+		"div":             nil,
+		"view":            nil,
+		"button":          newButton,
+		"canvas":          func() Handler { return &Canvas{} },
+		"checkbox":        newCheckbox,
+		"rich-text":       func() Handler { return &RichText{} },
+		"selectable-text": func() Handler { return &SelectableText{} },
+		"paged-text":      func() Handler { return &PagedText{} },
+		"radio":           func() Handler { return &Radio{} },
+		"radio-group":     newRadioGroup,
+		"search-box":      newSearchBox,
+		"hr":              newSeparator,
+		"input":           newTextInput,
+		"slot":            nil,
+		"slider":          newSlider,
+		"spacer":          newSpacer,
+		"text":            func() Handler { return &Text{} },
+		"toggle":          newToggle,
+	}
 	registerdComponents = defaultComponents
 )
 
@@ -172,27 +581,68 @@ func resetComponents()                  { registerdComponents = defaultComponent
 
 type cms []ComponentsMap
 
-func processTag(z *html.Tokenizer, tagName string, opts *ParseOptions, depth int, cms cms) *View {
-	view := createView(tagName, cms)
+// processTag creates the view of the tag. It returns nil if the element is
+// omitted by its if attribute.
+func (p *parser) processTag(tagName, raw string, rawAttrs []rawAttr, depth int, ancestors []*View) *View {
+	// the attributes are read before the view is created for the components
+	// taking them
+	el := &View{TagName: tagName}
+	attrs := parseAttrs(rawAttrs, func(s string) string { return p.interpolate(el, s) })
+	if cond, ok := attrs.miscs["if"]; ok {
+		show, err := evalCondition(cond, p.data)
+		if err != nil {
+			p.error(el, err)
+		}
+		if !show {
+			return nil
+		}
+	}
+
+	view, ok := lookupView(tagName, attrs.miscs, p.cms)
+	if !ok {
+		if p.errs == nil {
+			panic(fmt.Sprintf("unknown component: %s", tagName))
+		}
+		// a plain view keeps the structure of the document
+		view = &View{TagName: tagName}
+		p.error(view, fmt.Errorf("unknown component: %s", tagName))
+	}
 
 	if depth == 0 {
-		processRootView(view, opts)
+		processRootView(view, p.opts)
 	}
 
 	view.TagName = tagName
-	view.Raw = string(z.Raw())
-
-	setStyleProps(view, readAttrs(z))
+	view.Raw = raw
+	p.setStyleProps(view, attrs, ancestors)
+	// the placeholders of the style follow the data
+	if style, ok := findAttr(rawAttrs, "style"); ok && p.data != nil && strings.Contains(style, "{{") {
+		view.styleBinding = &styleBinding{template: style, data: p.data, decls: view.style.inline}
+	}
 
 	return view
 }
 
-func setStyleProps(view *View, attrs attrs) {
-	parseStyle(view, attrs.style)
-
+func (p *parser) setStyleProps(view *View, attrs attrs, ancestors []*View) {
 	view.ID = attrs.id
 	view.Attrs = attrs.miscs
+	view.Tooltip = attrs.miscs["tooltip"]
 	view.Hidden = attrs.hidden
+	view.TabIndex = attrs.tabIndex
+	view.Modal = attrs.modal
+	view.Disabled = attrs.disabled
+	if attrs.lazy {
+		view.lazy = &lazySubtree{}
+	}
+	if cond, ok := attrs.miscs["visible-if"]; ok {
+		if err := view.SetVisibleIf(cond, p.data); err != nil {
+			p.error(view, err)
+		}
+	}
+	view.style = &viewStyle{sheet: p.sheet, inline: parseDecls(attrs.style), images: p.images, face: p.opts.Face}
+	if err := view.applyStyle(ancestors); err != nil {
+		p.error(view, err)
+	}
 }
 
 func processRootView(view *View, opts *ParseOptions) {
@@ -204,50 +654,81 @@ func processRootView(view *View, opts *ParseOptions) {
 	}
 }
 
-func createView(name string, cms cms) *View {
+// NewComponentView creates a view for the tag with the attributes like
+// Parse does, using the components and the registered components. It is
+// used by the code generated by furexgen. It panics if the tag is unknown.
+func NewComponentView(tagName string, attrs map[string]string, components ComponentsMap) *View {
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	view := createView(tagName, attrs, []ComponentsMap{components, registerdComponents})
+	view.TagName = tagName
+	view.Attrs = attrs
+	return view
+}
+
+func createView(name string, attrs map[string]string, cms cms) *View {
+	view, ok := lookupView(name, attrs, cms)
+	if !ok {
+		panic(fmt.Sprintf("unknown component: %s", name))
+	}
+	return view
+}
+
+func lookupView(name string, attrs map[string]string, cms cms) (*View, bool) {
 	view := &View{}
 	for _, cm := range cms {
-		if ok := component(name, cm, view); ok {
-			return view
+		if ok := component(name, cm, attrs, view); ok {
+			return view, true
 		}
 	}
-	panic(fmt.Sprintf("unknown component: %s", name))
+	return nil, false
 }
 
-func component(name string, m ComponentsMap, v *View) bool {
+func component(name string, m ComponentsMap, attrs map[string]string, v *View) bool {
 	c, ok := m[name]
 	if c == nil {
 		return ok
 	}
-	if c, ok := c.(func() Handler); ok {
+	switch c := c.(type) {
+	case func() Handler:
 		v.Handler = c()
-		return true
-	}
-	if c, ok := c.(func() *View); ok {
+	case func(map[string]string) Handler:
+		v.Handler = c(attrs)
+	case func() *View:
 		*v = *c()
-		return true
+	case func(map[string]string) *View:
+		*v = *c(attrs)
+	default:
+		v.Handler = c
+	}
+	// the children of a function component are moved to the copy
+	for _, child := range v.children {
+		child.item.parent = v
 	}
-	v.Handler = c
 	return true
 }
 
 func parseStyle(view *View, style string) {
-	pairs := strings.Split(style, ";")
-	errs := &ErrorList{}
-	for _, pair := range pairs {
-		kv := strings.Split(pair, ":")
-		if len(kv) != 2 {
-			continue
-		}
-		k := strings.TrimSpace(kv[0])
-		v := strings.TrimSpace(kv[1])
+	applyDecls(view, parseDecls(style))
+}
 
-		mapper, ok := styleMapper[k]
+func applyDecls(view *View, decls []cssDecl) {
+	if err := setDecls(view, decls); err != nil {
+		println(fmt.Sprintf("parse style errors: %v", err))
+	}
+}
+
+// setDecls sets the declarations to the view, skipping the invalid ones.
+func setDecls(view *View, decls []cssDecl) error {
+	errs := &ErrorList{}
+	for _, d := range decls {
+		mapper, ok := styleMapper[d.property]
 		if !ok {
-			errs.Add(fmt.Errorf("unknown style: %s", k))
+			errs.Add(fmt.Errorf("unknown style: %s", d.property))
 			continue
 		}
-		parsed, err := mapper.parseFunc(v)
+		parsed, err := mapper.parseFunc(d.value)
 		if err != nil {
 			errs.Add(err)
 			continue
@@ -255,8 +736,9 @@ func parseStyle(view *View, style string) {
 		mapper.setFunc(view, parsed)
 	}
 	if errs.HasErrors() {
-		println(fmt.Sprintf("parse style errors: %v", errs))
+		return errs
 	}
+	return nil
 }
 
 func Int(i int) *int { return &i }
@@ -268,7 +750,7 @@ var styleMapper = map[string]mapper[View]{
 	},
 	"right": {
 		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Right = Int(val) }),
+		setFunc:   setFunc(func(v *View, val *int) { v.Right = val }),
 	},
 	"top": {
 		parseFunc: parseNumber,
@@ -276,7 +758,7 @@ var styleMapper = map[string]mapper[View]{
 	},
 	"bottom": {
 		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Bottom = Int(val) }),
+		setFunc:   setFunc(func(v *View, val *int) { v.Bottom = val }),
 	},
 	"width": {
 		parseFunc: parseLength,
@@ -284,6 +766,7 @@ var styleMapper = map[string]mapper[View]{
 			switch val.unit {
 			case cssUnitPx:
 				v.Width = int(val.val)
+				v.WidthInPct = 0
 			case cssUnitPct:
 				v.WidthInPct = val.val
 			}
@@ -295,11 +778,20 @@ var styleMapper = map[string]mapper[View]{
 			switch val.unit {
 			case cssUnitPx:
 				v.Height = int(val.val)
+				v.HeightInPct = 0
 			case cssUnitPct:
 				v.HeightInPct = val.val
 			}
 		}),
 	},
+	"max-width": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.MaxWidth = val }),
+	},
+	"aspect-ratio": {
+		parseFunc: parseAspectRatio,
+		setFunc:   setFunc(func(v *View, val float64) { v.AspectRatio = val }),
+	},
 	"margin-left": {
 		parseFunc: parseNumber,
 		setFunc:   setFunc(func(v *View, val int) { v.MarginLeft = val }),
@@ -316,6 +808,34 @@ var styleMapper = map[string]mapper[View]{
 		parseFunc: parseNumber,
 		setFunc:   setFunc(func(v *View, val int) { v.MarginBottom = val }),
 	},
+	"margin": {
+		parseFunc: parseBox,
+		setFunc: setFunc(func(v *View, val cssBox) {
+			v.MarginTop, v.MarginRight, v.MarginBottom, v.MarginLeft = val.top, val.right, val.bottom, val.left
+		}),
+	},
+	"padding-left": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.PaddingLeft = val }),
+	},
+	"padding-top": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.PaddingTop = val }),
+	},
+	"padding-right": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.PaddingRight = val }),
+	},
+	"padding-bottom": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.PaddingBottom = val }),
+	},
+	"padding": {
+		parseFunc: parseBox,
+		setFunc: setFunc(func(v *View, val cssBox) {
+			v.PaddingTop, v.PaddingRight, v.PaddingBottom, v.PaddingLeft = val.top, val.right, val.bottom, val.left
+		}),
+	},
 	"position": {
 		parseFunc: parsePosition,
 		setFunc:   setFunc(func(v *View, val Position) { v.Position = val }),
@@ -368,10 +888,181 @@ var styleMapper = map[string]mapper[View]{
 		parseFunc: parseFloat,
 		setFunc:   setFunc(func(v *View, val float64) { v.Shrink = val }),
 	},
+	"shrink-priority": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.ShrinkPriority = val }),
+	},
+	"collapse-below": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.CollapseBelow = val }),
+	},
 	"display": {
 		parseFunc: parseDisplay,
 		setFunc:   setFunc(func(v *View, val Display) { v.Display = val }),
 	},
+	"background-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.BackgroundColor = val }),
+	},
+	"background": {
+		parseFunc: parseBackground,
+		setFunc: setFunc(func(v *View, val cssBackground) {
+			v.BackgroundColor, v.BackgroundGradient = val.color, val.gradient
+			v.BackgroundCheckerboard = val.checkerboard
+			v.setBackgroundImage(val.image)
+		}),
+	},
+	"background-image": {
+		parseFunc: parseBackgroundImage,
+		setFunc:   setFunc(func(v *View, val string) { v.setBackgroundImage(val) }),
+	},
+	"background-repeat": {
+		parseFunc: parseBackgroundRepeat,
+		setFunc:   setFunc(func(v *View, val BackgroundRepeat) { v.BackgroundRepeat = val }),
+	},
+	"background-slice": {
+		parseFunc: parseBox,
+		setFunc: setFunc(func(v *View, val cssBox) {
+			v.BackgroundSlice = Insets{Top: val.top, Right: val.right, Bottom: val.bottom, Left: val.left}
+		}),
+	},
+	"border-width": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.BorderWidth = val }),
+	},
+	"border-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.BorderColor = val }),
+	},
+	"border-radius": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.BorderRadius = val }),
+	},
+	"box-shadow": {
+		parseFunc: parseBoxShadow,
+		setFunc:   setFunc(func(v *View, val *BoxShadow) { v.BoxShadow = val }),
+	},
+	"backdrop-filter": {
+		parseFunc: parseBackdropFilter,
+		setFunc:   setFunc(func(v *View, val int) { v.BackdropBlur = val }),
+	},
+	"opacity": {
+		parseFunc: parseOpacity,
+		setFunc:   setFunc(func(v *View, val *float64) { v.Opacity = val }),
+	},
+	"transform": {
+		parseFunc: parseTransform,
+		setFunc:   setFunc(func(v *View, val *Transform) { v.Transform = val }),
+	},
+	"render-scale": {
+		parseFunc: parseRenderScale,
+		setFunc:   setFunc(func(v *View, val float64) { v.RenderScale = val }),
+	},
+	"image-rendering": {
+		parseFunc: parseImageRendering,
+		setFunc:   setFunc(func(v *View, val ImageRendering) { v.ImageRendering = val }),
+	},
+	"text-align": {
+		parseFunc: parseTextAlign,
+		setFunc:   setFunc(func(v *View, val TextAlign) { v.TextAlign = val }),
+	},
+	"text-wrap": {
+		parseFunc: parseTextWrap,
+		setFunc:   setFunc(func(v *View, val TextWrap) { v.TextWrap = val }),
+	},
+	"vertical-align": {
+		parseFunc: parseVerticalAlign,
+		setFunc:   setFunc(func(v *View, val VerticalAlign) { v.VerticalAlign = val }),
+	},
+	"color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.Color = val }),
+	},
+	"writing-mode": {
+		parseFunc: parseWritingMode,
+		setFunc:   setFunc(func(v *View, val WritingMode) { v.WritingMode = val }),
+	},
+	"columns": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Columns = val }),
+	},
+	"column-gap": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.ColumnGap = val }),
+	},
+	"font-size": {
+		parseFunc: parseFontSize,
+		setFunc:   setFunc(func(v *View, val *FontSize) { v.FontSize = val }),
+	},
+	"font-family": {
+		parseFunc: parseFontFamily,
+		setFunc:   setFunc(func(v *View, val []string) { v.FontFamily = val }),
+	},
+	"animation": {
+		parseFunc: parseAnimation,
+		setFunc:   setFunc(func(v *View, val cssAnimation) { v.setAnimation(val) }),
+	},
+	"layout-animation": {
+		parseFunc: parseLayoutAnimation,
+		setFunc:   setFunc(func(v *View, val *LayoutAnimation) { v.LayoutAnimation = val }),
+	},
+	"transition": {
+		parseFunc: parseTransition,
+		setFunc:   setFunc(func(v *View, val []Transition) { v.Transitions = val }),
+	},
+	"overflow": {
+		parseFunc: parseOverflow,
+		setFunc:   setFunc(func(v *View, val Overflow) { v.Overflow = val }),
+	},
+	"border": {
+		parseFunc: parseBorder,
+		setFunc: setFunc(func(v *View, val cssBorder) {
+			v.BorderWidth, v.BorderColor, v.BorderStyle = val.width, val.color, val.style
+			v.BorderTop, v.BorderRight, v.BorderBottom, v.BorderLeft = nil, nil, nil, nil
+		}),
+	},
+	"border-style": {
+		parseFunc: parseBorderStyle,
+		setFunc:   setFunc(func(v *View, val BorderStyle) { v.BorderStyle = val }),
+	},
+	"border-top": {
+		parseFunc: parseSideBorder,
+		setFunc:   setFunc(func(v *View, val *Border) { v.BorderTop = val }),
+	},
+	"border-right": {
+		parseFunc: parseSideBorder,
+		setFunc:   setFunc(func(v *View, val *Border) { v.BorderRight = val }),
+	},
+	"border-bottom": {
+		parseFunc: parseSideBorder,
+		setFunc:   setFunc(func(v *View, val *Border) { v.BorderBottom = val }),
+	},
+	"border-left": {
+		parseFunc: parseSideBorder,
+		setFunc:   setFunc(func(v *View, val *Border) { v.BorderLeft = val }),
+	},
+	"outline": {
+		parseFunc: parseBorder,
+		setFunc: setFunc(func(v *View, val cssBorder) {
+			v.OutlineWidth, v.OutlineColor, v.OutlineStyle = val.width, val.color, val.style
+		}),
+	},
+	"outline-width": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.OutlineWidth = val }),
+	},
+	"outline-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.OutlineColor = val }),
+	},
+	"outline-style": {
+		parseFunc: parseBorderStyle,
+		setFunc:   setFunc(func(v *View, val BorderStyle) { v.OutlineStyle = val }),
+	},
+	"outline-offset": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.OutlineOffset = val }),
+	},
 }
 
 // setFunc creates a function that takes an entity and a value as an interface{}.
@@ -392,9 +1083,9 @@ func setFunc[T, U any](f func(entity T, value U)) func(T, any) {
 				f(e, nilValue)
 			} else {
 				// pass deafult value if the type is not pointer
+				// (nil for interfaces such as color.Color)
 				var u U
-				defaultValue := reflect.Zero(reflect.TypeOf(u))
-				f(e, defaultValue.Interface().(U))
+				f(e, u)
 			}
 			return
 		}
@@ -434,10 +1125,85 @@ func parseNumber(val string) (any, error) {
 	return strconv.Atoi(val)
 }
 
+type cssBorder struct {
+	width int
+	color color.Color
+	style BorderStyle
+}
+
+// parseBorder parses the border shorthand such as `1px solid #fff` or
+// `2px dashed red`; `none` removes the border.
+func parseBorder(val string) (any, error) {
+	b := cssBorder{}
+	// rgb() colors may contain spaces
+	if i := strings.Index(val, "rgb"); i != -1 {
+		end := strings.IndexByte(val[i:], ')')
+		if end == -1 {
+			return nil, fmt.Errorf("invalid border: %s", val)
+		}
+		c, err := parseColor(val[i : i+end+1])
+		if err != nil {
+			return nil, err
+		}
+		b.color = c.(color.Color)
+		val = val[:i] + val[i+end+1:]
+	}
+	for _, f := range strings.Fields(val) {
+		switch f {
+		case "none", "hidden":
+			return cssBorder{}, nil
+		}
+		if s, err := parseBorderStyle(f); err == nil {
+			b.style = s.(BorderStyle)
+			continue
+		}
+		if w, err := parseNumber(f); err == nil {
+			b.width = w.(int)
+			continue
+		}
+		c, err := parseColor(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid border: %s", val)
+		}
+		b.color = c.(color.Color)
+	}
+	return b, nil
+}
+
+// parseSideBorder parses the border shorthand of a side.
+func parseSideBorder(val string) (any, error) {
+	b, err := parseBorder(val)
+	if err != nil {
+		return nil, err
+	}
+	c := b.(cssBorder)
+	return &Border{Width: c.width, Color: c.color, Style: c.style}, nil
+}
+
 func parseFloat(val string) (any, error) {
 	return strconv.ParseFloat(val, 64)
 }
 
+// parseAspectRatio parses a ratio such as `16 / 9` or `1.5`, or `auto`.
+func parseAspectRatio(val string) (any, error) {
+	if val == "auto" {
+		return 0.0, nil
+	}
+	w, h, ok := strings.Cut(val, "/")
+	if !ok {
+		h = "1"
+	}
+	wf, err := strconv.ParseFloat(strings.TrimSpace(w), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aspect-ratio: %s", val)
+	}
+	hf, err := strconv.ParseFloat(strings.TrimSpace(h), 64)
+	if err != nil || wf <= 0 || hf <= 0 {
+		return nil, fmt.Errorf("invalid aspect-ratio: %s", val)
+	}
+	return wf / hf, nil
+}
+
 func parsePosition(val string) (any, error) {
 	switch val {
 	case "absolute":
@@ -512,6 +1278,8 @@ func parseAlignContent(val string) (any, error) {
 		return AlignContentSpaceBetween, nil
 	case "space-around":
 		return AlignContentSpaceAround, nil
+	case "space-evenly":
+		return AlignContentSpaceEvenly, nil
 	}
 	return AlignContentStart, fmt.Errorf("unknown align-content: %s", val)
 }
@@ -526,6 +1294,18 @@ func parseDisplay(val string) (any, error) {
 	return DisplayFlex, fmt.Errorf("unknown display: %s", val)
 }
 
+func parseOverflow(val string) (any, error) {
+	switch val {
+	case "visible":
+		return OverflowVisible, nil
+	case "hidden", "clip":
+		return OverflowHidden, nil
+	case "scroll", "auto":
+		return OverflowScroll, nil
+	}
+	return OverflowVisible, fmt.Errorf("unknown overflow: %s", val)
+}
+
 type cssLength struct {
 	unit cssUnit
 	val  float64
@@ -550,19 +1330,89 @@ func parseLength(val string) (any, error) {
 	}
 }
 
+// cssBox is the value of the shorthand properties such as margin and padding.
+type cssBox struct {
+	top, right, bottom, left int
+}
+
+// parseBox parses the 1, 2, 3 or 4 value forms of a box shorthand property.
+func parseBox(val string) (any, error) {
+	fields := strings.Fields(val)
+	vals := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := parseNumber(f)
+		if err != nil {
+			return cssBox{}, err
+		}
+		vals[i] = v.(int)
+	}
+	switch len(vals) {
+	case 1:
+		return cssBox{vals[0], vals[0], vals[0], vals[0]}, nil
+	case 2:
+		return cssBox{vals[0], vals[1], vals[0], vals[1]}, nil
+	case 3:
+		return cssBox{vals[0], vals[1], vals[2], vals[1]}, nil
+	case 4:
+		return cssBox{vals[0], vals[1], vals[2], vals[3]}, nil
+	}
+	return cssBox{}, fmt.Errorf("invalid box value: %s", val)
+}
+
 type attrs struct {
-	id     string
-	style  string
-	hidden bool
-	miscs  map[string]string
+	id       string
+	style    string
+	hidden   bool
+	tabIndex *int
+	modal    bool
+	disabled bool
+	lazy     bool
+	miscs    map[string]string
+}
+
+// rawAttr is an attribute of a tag as written.
+type rawAttr struct {
+	key, val string
+}
+
+func readRawAttrs(z *html.Tokenizer) []rawAttr {
+	var ret []rawAttr
+	for {
+		key, val, more := z.TagAttr()
+		// a tag without attributes reads an empty one
+		if len(key) > 0 {
+			ret = append(ret, rawAttr{string(key), string(val)})
+		}
+		if !more {
+			return ret
+		}
+	}
 }
 
-func readAttrs(z *html.Tokenizer) attrs {
+func findAttr(attrs []rawAttr, key string) (string, bool) {
+	for _, a := range attrs {
+		if a.key == key {
+			return a.val, true
+		}
+	}
+	return "", false
+}
+
+func readAttrs(z *html.Tokenizer, interp func(string) string) attrs {
+	return parseAttrs(readRawAttrs(z), interp)
+}
+
+// parseAttrs parses the attributes of a tag, with the values converted by
+// interp if it is not nil.
+func parseAttrs(raw []rawAttr, interp func(string) string) attrs {
 	attr := attrs{
 		miscs: make(map[string]string),
 	}
-	for {
-		key, val, more := z.TagAttr()
+	for _, a := range raw {
+		key, val := []byte(a.key), []byte(a.val)
+		if interp != nil {
+			val = []byte(interp(string(val)))
+		}
 		attr.miscs[string(key)] = string(val)
 		switch string(key) {
 		case "id":
@@ -576,9 +1426,16 @@ func readAttrs(z *html.Tokenizer) attrs {
 			} else {
 				attr.hidden = parseBool(v)
 			}
-		}
-		if !more {
-			break
+		case "disabled":
+			attr.disabled = string(val) == "" || parseBool(string(val))
+		case "lazy":
+			attr.lazy = string(val) == "" || parseBool(string(val))
+		case "modal":
+			attr.modal = string(val) == "" || parseBool(string(val))
+		case "tabindex":
+			if i, err := strconv.Atoi(string(val)); err == nil {
+				attr.tabIndex = Int(i)
+			}
 		}
 	}
 	return attr
@@ -1,7 +1,11 @@
 package furex
 
 import (
+	"image"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -110,6 +114,20 @@ func TestParseHTML(t *testing.T) {
 				Height: 300,
 			},
 		},
+		{
+			name: "aspect-ratio",
+			html: `
+				<view>
+					<view style="aspect-ratio: 16 / 9" />
+					<view style="aspect-ratio: 2" />
+				</view>
+						`,
+			expected: (&View{}).
+				AddChild(
+					&View{AspectRatio: 16. / 9},
+					&View{AspectRatio: 2},
+				),
+		},
 		{
 			name: "with-handlers",
 			html: `
@@ -551,6 +569,30 @@ func TestParseHTML(t *testing.T) {
 				Position: PositionAbsolute, Width: 100, Height: 100,
 			})),
 		},
+		{
+			name: "margin and padding shorthands",
+			html: `
+				<view style="padding: 1px 2px 3px">
+					<view style="margin: 10px"></view>
+					<view style="margin: 10px 20px; padding: 5px"></view>
+					<view style="margin: 1px 2px 3px 4px; padding: 6px 7px"></view>
+				</view>`,
+			expected: (&View{
+				PaddingTop: 1, PaddingRight: 2, PaddingBottom: 3, PaddingLeft: 2,
+			}).AddChild(
+				&View{
+					MarginTop: 10, MarginRight: 10, MarginBottom: 10, MarginLeft: 10,
+				},
+				&View{
+					MarginTop: 10, MarginRight: 20, MarginBottom: 10, MarginLeft: 20,
+					PaddingTop: 5, PaddingRight: 5, PaddingBottom: 5, PaddingLeft: 5,
+				},
+				&View{
+					MarginTop: 1, MarginRight: 2, MarginBottom: 3, MarginLeft: 4,
+					PaddingTop: 6, PaddingRight: 7, PaddingBottom: 6, PaddingLeft: 7,
+				},
+			),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -580,3 +622,157 @@ func styleConfig(cfg ViewConfig) ViewConfig {
 	}
 	return cfg
 }
+
+func TestParseLinkedStylesheet(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/ui.css": {Data: []byte(".panel { width: 100px; height: 20px }")},
+	}
+	view := Parse(`
+		<html>
+			<head>
+				<link rel="stylesheet" href="css/ui.css">
+				<link rel="icon" href="icon.png">
+				<style>.panel.wide { width: 200px }</style>
+			</head>
+			<body>
+				<view>
+					<view id="a" class="panel"></view>
+					<view id="b" class="panel wide"></view>
+				</view>
+			</body>
+		</html>`, &ParseOptions{FS: fsys})
+
+	require.Equal(t, 100, view.MustGetByID("a").Width)
+	require.Equal(t, 20, view.MustGetByID("a").Height)
+	require.Equal(t, 200, view.MustGetByID("b").Width)
+
+	_, err := scanDocument(`<link rel="stylesheet" href="missing.css" />`, fsys)
+	require.Error(t, err)
+	_, err = scanDocument(`<link rel="stylesheet" href="css/ui.css" />`, nil)
+	require.Error(t, err)
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ui/main.html": {Data: []byte(`<link rel="stylesheet" href="main.css"><view id="root" class="root"></view>`)},
+		"ui/main.css":  {Data: []byte(".root { width: 100px }")},
+	}
+	view, err := ParseFS(fsys, "ui/main.html", nil)
+	require.NoError(t, err)
+	require.Equal(t, "root", view.ID)
+	require.Equal(t, 100, view.Width)
+
+	_, err = ParseFS(fsys, "ui/missing.html", nil)
+	require.Error(t, err)
+
+	view, err = ParseReader(strings.NewReader(`<view id="root"></view>`), nil)
+	require.NoError(t, err)
+	require.Equal(t, "root", view.ID)
+}
+
+func TestParseStrict(t *testing.T) {
+	view, err := ParseStrict(`<view id="root"><view style="width: 10px"></view></view>`, nil)
+	require.NoError(t, err)
+	require.Equal(t, "root", view.ID)
+
+	_, err = ParseStrict(`<style>.a { colr: red } [x { width: 1px }</style>
+<view>
+	<view class="a">
+	</view>
+	<unknown-tag></unknown-tag>
+	<view style="opacity: abc"></view>
+</view>`, nil)
+	require.Error(t, err)
+	errs := err.(*ErrorList).errors
+	require.Len(t, errs, 4)
+	require.Contains(t, errs[0].Error(), "[x")
+	require.Equal(t, "line 3: <view>: unknown style: colr", errs[1].Error())
+	require.Equal(t, "line 5: <unknown-tag>: unknown component: unknown-tag", errs[2].Error())
+	require.Contains(t, errs[3].Error(), "line 6: <view>: ")
+
+	_, err = ParseStrict(`<view></view><view></view>`, nil)
+	require.Error(t, err)
+}
+
+type gauge struct {
+	max   int
+	color string
+}
+
+func TestParseComponentAttrs(t *testing.T) {
+	var created []string
+	opts := &ParseOptions{
+		Components: ComponentsMap{
+			"gauge": func(attrs map[string]string) Handler {
+				max, _ := strconv.Atoi(attrs["max"])
+				return &gauge{max: max, color: attrs["color"]}
+			},
+			"badge": func(attrs map[string]string) *View {
+				created = append(created, attrs["label"])
+				return &View{Text: attrs["label"], Width: 10}
+			},
+		},
+		Data: map[string]any{"hp": 100, "show": false},
+	}
+	view := Parse(`<view>
+		<gauge id="hp" max="{{ hp }}" color="red"></gauge>
+		<gauge id="mp" max="50"></gauge>
+		<badge id="new" label="NEW"></badge>
+		<badge label="hidden" if="show"></badge>
+	</view>`, opts)
+	require.Equal(t, &gauge{max: 100, color: "red"}, view.MustGetByID("hp").Handler)
+	require.Equal(t, &gauge{max: 50}, view.MustGetByID("mp").Handler)
+	badge := view.MustGetByID("new")
+	require.Equal(t, "NEW", badge.Text)
+	require.Equal(t, 10, badge.Width)
+	require.Equal(t, "badge", badge.TagName)
+	// the components of omitted elements are not created
+	require.Equal(t, []string{"NEW"}, created)
+}
+
+func TestParseDataAttrs(t *testing.T) {
+	view := Parse(`<view><view id="slot" data-item-id="{{ item }}" data-screen="inventory"></view></view>`,
+		&ParseOptions{Data: map[string]any{"item": "potion"}})
+	slot := view.MustGetByID("slot")
+	require.Equal(t, "potion", slot.Attrs["data-item-id"])
+	id, ok := slot.Data("item-id")
+	require.True(t, ok)
+	require.Equal(t, "potion", id)
+	screen, _ := slot.Data("screen")
+	require.Equal(t, "inventory", screen)
+	_, ok = slot.Data("missing")
+	require.False(t, ok)
+}
+
+func TestParseTextViews(t *testing.T) {
+	view := Parse(`<view style="width: 200px; height: 100px; flex-direction: column">
+		<view id="label">Hello</view>
+		<view id="mixed">Score: <view id="value"></view> pts</view>
+		<character id="hero">Hero</character>
+	</view>`, &ParseOptions{Components: ComponentsMap{"character": &mockHandler{}}})
+
+	// the text of a plain view is drawn by a text view
+	label := view.MustGetByID("label")
+	require.Equal(t, "Hello", label.Text)
+	require.Len(t, label.Children(), 1)
+	text := label.Children()[0]
+	require.Equal(t, "text", text.TagName)
+	require.Equal(t, "Hello", text.Text)
+	require.IsType(t, &Text{}, text.Handler)
+
+	// the texts and the elements are kept in order
+	mixed := view.MustGetByID("mixed")
+	require.Equal(t, []string{"text", "view", "text"}, tagNames(mixed.Children()))
+	require.Equal(t, "Score:", mixed.Children()[0].Text)
+	require.Equal(t, "pts", mixed.Children()[2].Text)
+
+	// the text of a component is left to its handler
+	hero := view.MustGetByID("hero")
+	require.Equal(t, "Hero", hero.Text)
+	require.Empty(t, hero.Children())
+
+	// the text views are sized to the texts
+	view.Update()
+	require.Equal(t, image.Rect(0, 0, 35, 13), text.frame)
+	require.Equal(t, 13, label.frame.Dy())
+}
@@ -0,0 +1,38 @@
+package furex
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/net/html"
+)
+
+// include parses the document of the src attribute of an <include>
+// element in place of the element. The path is resolved from the root of
+// ParseOptions.FS and can have placeholders.
+func (p *parser) include(rawAttrs []rawAttr, stack *stack, depth int) {
+	view := &View{TagName: "include"}
+	src, _ := findAttr(rawAttrs, "src")
+	if src = p.interpolate(view, src); src == "" {
+		p.error(view, fmt.Errorf("include without src"))
+		return
+	}
+	if p.includes[src] {
+		p.error(view, fmt.Errorf("recursive include: %s", src))
+		return
+	}
+	b, err := readFile(p.opts.FS, src)
+	if err != nil {
+		p.error(view, err)
+		return
+	}
+	if p.includes == nil {
+		p.includes = map[string]bool{}
+	}
+	p.includes[src] = true
+	file, line := p.file, p.line
+	p.file, p.line = src, 1
+	p.parseTokens(html.NewTokenizer(bytes.NewReader(b)), stack, depth, true)
+	p.file, p.line = file, line
+	delete(p.includes, src)
+}
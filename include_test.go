@@ -0,0 +1,39 @@
+package furex
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hud/healthbar.html": {Data: []byte(`<style>.bar { height: 8px }</style>
+<view id="{{ id }}" class="bar"></view>`)},
+		"hud/coins.html": {Data: []byte(`<view id="coins">{{ coins }}</view><include src="hud/icon.html" />`)},
+		"hud/icon.html":  {Data: []byte(`<view id="icon"></view>`)},
+		"loop.html":      {Data: []byte(`<include src="loop.html"></include>`)},
+		"broken.html":    {Data: []byte("<view>\n<view style=\"colr: red\"></view></view>")},
+	}
+	root, err := ParseStrict(`
+		<view>
+			<include src="hud/healthbar.html"></include>
+			<view id="middle"></view>
+			<include src="hud/{{ hud }}.html" />
+		</view>`, &ParseOptions{FS: fsys, Data: map[string]any{"id": "hp", "hud": "coins", "coins": 12}})
+	require.NoError(t, err)
+	require.Len(t, root.children, 4)
+	// the styles of the included documents apply
+	require.Equal(t, 8, root.MustGetByID("hp").Height)
+	require.Equal(t, "middle", root.children[1].item.ID)
+	require.Equal(t, "12", root.MustGetByID("coins").Text)
+	require.Equal(t, "icon", root.children[3].item.ID)
+
+	_, err = ParseStrict(`<view><include src="loop.html"></include></view>`, &ParseOptions{FS: fsys})
+	require.EqualError(t, err, "loop.html: line 1: <include>: recursive include: loop.html")
+	_, err = ParseStrict(`<view><include src="missing.html"></include></view>`, &ParseOptions{FS: fsys})
+	require.Error(t, err)
+	_, err = ParseStrict(`<view><include src="broken.html"></include></view>`, &ParseOptions{FS: fsys})
+	require.EqualError(t, err, "broken.html: line 2: <view>: unknown style: colr")
+}
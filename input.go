@@ -0,0 +1,255 @@
+package furex
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/textinput"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// InputState is the state of the mouse, the keyboard and the touches in a
+// tick, e.g. recorded for replays and injected in the deterministic mode
+// (see SetDeterministic).
+type InputState struct {
+	CursorX, CursorY int
+	// MouseButtons are the pressed mouse buttons.
+	MouseButtons []ebiten.MouseButton
+	// Keys are the pressed keys.
+	Keys []ebiten.Key
+	// Touches are the touches on the screen.
+	Touches []TouchState
+	// Chars are the characters input in the tick, e.g. for text inputs.
+	Chars []rune
+	// WheelX and WheelY are the movement of the mouse wheel in the tick.
+	WheelX, WheelY float64
+}
+
+// TouchState is a touch on the screen.
+type TouchState struct {
+	ID   ebiten.TouchID
+	X, Y int
+}
+
+// CaptureInput returns the state of the input devices from Ebitengine in
+// the current tick, e.g. to record it for replays.
+func CaptureInput() InputState {
+	s := InputState{}
+	s.CursorX, s.CursorY = ebiten.CursorPosition()
+	for b := ebiten.MouseButton0; b <= ebiten.MouseButtonMax; b++ {
+		if ebiten.IsMouseButtonPressed(b) {
+			s.MouseButtons = append(s.MouseButtons, b)
+		}
+	}
+	s.Keys = inpututil.AppendPressedKeys(nil)
+	for _, id := range ebiten.AppendTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		s.Touches = append(s.Touches, TouchState{ID: id, X: x, Y: y})
+	}
+	s.Chars = ebiten.AppendInputChars(nil)
+	s.WheelX, s.WheelY = ebiten.Wheel()
+	return s
+}
+
+// deterministicInput is the input injected in the deterministic mode.
+type deterministicInput struct {
+	tps       int
+	tick      int
+	cur, prev InputState
+	// keyTicks are the numbers of the ticks the keys have been pressed.
+	keyTicks map[ebiten.Key]int
+}
+
+// deterministic is the state of the deterministic mode, or nil.
+var deterministic *deterministicInput
+
+// SetDeterministic makes the behavior of the views driven purely by the
+// ticks and the input injected by InjectInput, so that games with
+// deterministic lockstep or replays can include the UI in their
+// guarantees: the views read no input from Ebitengine, and durations such
+// as those of animations, transitions and gestures are counted in ticks
+// at tps ticks per second instead of ebiten.TPS() and the clock.
+// A tps of 0 or less returns to the normal mode.
+func SetDeterministic(tps int) {
+	if tps <= 0 {
+		deterministic = nil
+		return
+	}
+	deterministic = &deterministicInput{tps: tps, keyTicks: map[ebiten.Key]int{}}
+}
+
+// IsDeterministic returns true in the deterministic mode.
+func IsDeterministic() bool {
+	return deterministic != nil
+}
+
+// InjectInput advances the deterministic mode by a tick with the state of
+// the input in the tick. It must be called once per tick before the root
+// views are updated. It does nothing in the normal mode.
+func InjectInput(s InputState) {
+	d := deterministic
+	if d == nil {
+		return
+	}
+	d.tick++
+	d.prev, d.cur = d.cur, s
+	pressed := map[ebiten.Key]bool{}
+	for _, k := range s.Keys {
+		pressed[k] = true
+		d.keyTicks[k]++
+	}
+	for k := range d.keyTicks {
+		if !pressed[k] {
+			delete(d.keyTicks, k)
+		}
+	}
+}
+
+func containsButton(buttons []ebiten.MouseButton, b ebiten.MouseButton) bool {
+	for _, bb := range buttons {
+		if bb == b {
+			return true
+		}
+	}
+	return false
+}
+
+func findTouch(touches []TouchState, id ebiten.TouchID) (TouchState, bool) {
+	for _, t := range touches {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return TouchState{}, false
+}
+
+// The functions below read the input from Ebitengine, or from the
+// injected input in the deterministic mode.
+
+func tps() int {
+	if d := deterministic; d != nil {
+		return d.tps
+	}
+	return ebiten.TPS()
+}
+
+// startTime is the origin of inputTime in the normal mode.
+var startTime = time.Now()
+
+// inputTime returns the time elapsed, counted in ticks in the
+// deterministic mode, e.g. to measure gestures.
+func inputTime() time.Duration {
+	if d := deterministic; d != nil {
+		return time.Duration(d.tick) * time.Second / time.Duration(d.tps)
+	}
+	return time.Since(startTime)
+}
+
+func cursorPosition() (int, int) {
+	if d := deterministic; d != nil {
+		return d.cur.CursorX, d.cur.CursorY
+	}
+	return ebiten.CursorPosition()
+}
+
+func wheel() (float64, float64) {
+	if d := deterministic; d != nil {
+		return d.cur.WheelX, d.cur.WheelY
+	}
+	return ebiten.Wheel()
+}
+
+func isMouseButtonPressed(b ebiten.MouseButton) bool {
+	if d := deterministic; d != nil {
+		return containsButton(d.cur.MouseButtons, b)
+	}
+	return ebiten.IsMouseButtonPressed(b)
+}
+
+func isMouseButtonJustPressed(b ebiten.MouseButton) bool {
+	if d := deterministic; d != nil {
+		return containsButton(d.cur.MouseButtons, b) && !containsButton(d.prev.MouseButtons, b)
+	}
+	return inpututil.IsMouseButtonJustPressed(b)
+}
+
+func isMouseButtonJustReleased(b ebiten.MouseButton) bool {
+	if d := deterministic; d != nil {
+		return !containsButton(d.cur.MouseButtons, b) && containsButton(d.prev.MouseButtons, b)
+	}
+	return inpututil.IsMouseButtonJustReleased(b)
+}
+
+func isKeyPressed(k ebiten.Key) bool {
+	return keyPressDuration(k) > 0 || (deterministic == nil && ebiten.IsKeyPressed(k))
+}
+
+func isKeyJustPressed(k ebiten.Key) bool {
+	return keyPressDuration(k) == 1
+}
+
+func keyPressDuration(k ebiten.Key) int {
+	if d := deterministic; d != nil {
+		return d.keyTicks[k]
+	}
+	return inpututil.KeyPressDuration(k)
+}
+
+func appendInputChars(runes []rune) []rune {
+	if d := deterministic; d != nil {
+		return append(runes, d.cur.Chars...)
+	}
+	return ebiten.AppendInputChars(runes)
+}
+
+// startIME starts the text input by the input method of the platform.
+var startIME = textinput.Start
+
+// startTextInput starts the text input by the input method at (x, y), e.g.
+// to compose Japanese. It returns nil where it is not supported, and in
+// the deterministic mode, where the characters are injected.
+func startTextInput(x, y int) (chan textinput.State, func()) {
+	if deterministic != nil {
+		return nil, nil
+	}
+	return startIME(x, y)
+}
+
+func appendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	if d := deterministic; d != nil {
+		for _, t := range d.cur.Touches {
+			ids = append(ids, t.ID)
+		}
+		return ids
+	}
+	return ebiten.AppendTouchIDs(ids)
+}
+
+func appendJustPressedTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	if d := deterministic; d != nil {
+		for _, t := range d.cur.Touches {
+			if _, ok := findTouch(d.prev.Touches, t.ID); !ok {
+				ids = append(ids, t.ID)
+			}
+		}
+		return ids
+	}
+	return inpututil.AppendJustPressedTouchIDs(ids)
+}
+
+func isTouchJustReleased(id ebiten.TouchID) bool {
+	if d := deterministic; d != nil {
+		_, cur := findTouch(d.cur.Touches, id)
+		_, prev := findTouch(d.prev.Touches, id)
+		return prev && !cur
+	}
+	return inpututil.IsTouchJustReleased(id)
+}
+
+func currentTouchPosition(id ebiten.TouchID) (int, int) {
+	if d := deterministic; d != nil {
+		t, _ := findTouch(d.cur.Touches, id)
+		return t.X, t.Y
+	}
+	return ebiten.TouchPosition(id)
+}
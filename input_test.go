@@ -0,0 +1,72 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministic(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+	require.True(t, IsDeterministic())
+	require.Equal(t, 60, tps())
+
+	h := &mockHandler{}
+	root := &View{Width: 100, Height: 100}
+	root.AddChild(&View{Width: 50, Height: 50, Handler: h})
+
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+
+	// a click is driven by the injected input
+	tick(InputState{CursorX: 10, CursorY: 10, MouseButtons: []ebiten.MouseButton{ebiten.MouseButtonLeft}})
+	require.True(t, h.IsPressed)
+	require.False(t, h.IsReleased)
+	tick(InputState{CursorX: 10, CursorY: 10, MouseButtons: []ebiten.MouseButton{ebiten.MouseButtonLeft}})
+	require.True(t, isMouseButtonPressed(ebiten.MouseButtonLeft))
+	require.False(t, isMouseButtonJustPressed(ebiten.MouseButtonLeft))
+	tick(InputState{CursorX: 10, CursorY: 10})
+	require.True(t, h.IsReleased)
+	require.False(t, h.IsCancel)
+
+	// keys are counted in ticks
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowDown}})
+	require.True(t, isKeyJustPressed(ebiten.KeyArrowDown))
+	for i := 0; i < 32; i++ {
+		tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowDown}})
+	}
+	require.Equal(t, 33, keyPressDuration(ebiten.KeyArrowDown))
+	require.True(t, isKeyRepeated(ebiten.KeyArrowDown))
+	tick(InputState{})
+	require.False(t, isKeyPressed(ebiten.KeyArrowDown))
+
+	// a swipe within the time threshold in ticks
+	h.Init()
+	tick(InputState{Touches: []TouchState{{ID: 1, X: 10, Y: 10}}})
+	for x := 20; x <= 70; x += 10 {
+		tick(InputState{Touches: []TouchState{{ID: 1, X: x, Y: 10}}})
+	}
+	tick(InputState{})
+	require.True(t, h.IsSwiped)
+	require.Equal(t, SwipeDirectionRight, h.SwipeDir)
+
+	// a slow swipe is ignored
+	h.Init()
+	tick(InputState{Touches: []TouchState{{ID: 2, X: 10, Y: 10}}})
+	for i := 0; i < 20; i++ {
+		tick(InputState{Touches: []TouchState{{ID: 2, X: 70, Y: 10}}})
+	}
+	tick(InputState{})
+	require.False(t, h.IsSwiped)
+}
+
+func TestInjectInputNormalMode(t *testing.T) {
+	// the injected input is ignored in the normal mode
+	InjectInput(InputState{CursorX: 10, CursorY: 10})
+	require.False(t, IsDeterministic())
+	require.Nil(t, deterministic)
+}
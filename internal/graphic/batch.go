@@ -0,0 +1,160 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// maxBatchVertices is the number of vertices a single DrawTriangles call
+// can index with uint16 indices.
+const maxBatchVertices = 1 << 16
+
+var whiteImage = func() *ebiten.Image {
+	img := ebiten.NewImage(3, 3)
+	img.Fill(color.White)
+	return img
+}()
+
+// whiteSubImage avoids sampling the edges of the white image.
+var whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+
+// Batch accumulates quads drawn onto the same target image and draws them
+// with a single DrawTriangles call per source image.
+// Drawing with other functions onto the target must be preceded by Flush
+// to keep the drawing order.
+type Batch struct {
+	target   *ebiten.Image
+	source   *ebiten.Image
+	vertices []ebiten.Vertex
+	indices  []uint16
+
+	// DrawCalls is the number of DrawTriangles calls issued by the batch.
+	DrawCalls int
+	// Transform transforms the shapes added to the batch if it is not nil.
+	Transform *Transform
+}
+
+// FillRect adds a rectangle filled with the color.
+func (b *Batch) FillRect(target *ebiten.Image, rect image.Rectangle, clr color.Color) {
+	b.DrawImage(target, whiteSubImage, whiteSubImage.Bounds(), rect, clr)
+}
+
+// StrokeRect adds the outline of a rectangle drawn inside the rect.
+func (b *Batch) StrokeRect(target *ebiten.Image, r image.Rectangle, width int, clr color.Color) {
+	b.FillRect(target, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), clr)
+	b.FillRect(target, image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), clr)
+	b.FillRect(target, image.Rect(r.Min.X, r.Min.Y+width, r.Min.X+width, r.Max.Y-width), clr)
+	b.FillRect(target, image.Rect(r.Max.X-width, r.Min.Y+width, r.Max.X, r.Max.Y-width), clr)
+}
+
+// DrawImage adds the src rectangle of the source image scaled to the dst rectangle.
+// The color multiplies the source; nil means white.
+func (b *Batch) DrawImage(target, source *ebiten.Image, src, dst image.Rectangle, clr color.Color) {
+	dst = b.Transform.Rect(dst)
+	if target == nil || source == nil || src.Empty() || dst.Empty() {
+		return
+	}
+	if b.target != target || b.source != source || len(b.vertices)+4 > maxBatchVertices {
+		b.Flush()
+		b.target, b.source = target, source
+	}
+	cr, cg, cbl, ca := colorScale(clr)
+	i := uint16(len(b.vertices))
+	for _, p := range [4][4]int{
+		{dst.Min.X, dst.Min.Y, src.Min.X, src.Min.Y},
+		{dst.Max.X, dst.Min.Y, src.Max.X, src.Min.Y},
+		{dst.Min.X, dst.Max.Y, src.Min.X, src.Max.Y},
+		{dst.Max.X, dst.Max.Y, src.Max.X, src.Max.Y},
+	} {
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX: float32(p[0]), DstY: float32(p[1]),
+			SrcX: float32(p[2]), SrcY: float32(p[3]),
+			ColorR: cr, ColorG: cg, ColorB: cbl, ColorA: ca,
+		})
+	}
+	b.indices = append(b.indices, i, i+1, i+2, i+1, i+3, i+2)
+}
+
+// DrawTiled adds the source image repeated at its size from the top left
+// corner of the dst rectangle. The tiles at the right and bottom edges are cut.
+// The color multiplies the source; nil means white.
+func (b *Batch) DrawTiled(target, source *ebiten.Image, dst image.Rectangle, clr color.Color) {
+	if source == nil {
+		return
+	}
+	sb := source.Bounds()
+	w, h := sb.Dx(), sb.Dy()
+	if w == 0 || h == 0 {
+		return
+	}
+	for y := dst.Min.Y; y < dst.Max.Y; y += h {
+		for x := dst.Min.X; x < dst.Max.X; x += w {
+			tile := image.Rect(x, y, x+w, y+h).Intersect(dst)
+			src := image.Rectangle{Min: sb.Min, Max: sb.Min.Add(tile.Size())}
+			b.DrawImage(target, source, src, tile, clr)
+		}
+	}
+}
+
+// Insets are the widths of the borders of a nine-patch image.
+type Insets struct {
+	Left, Top, Right, Bottom int
+}
+
+// DrawNinePatch adds the source image stretched to the dst rectangle
+// keeping the corners defined by the insets unscaled.
+func (b *Batch) DrawNinePatch(target, source *ebiten.Image, insets Insets, dst image.Rectangle, clr color.Color) {
+	if source == nil {
+		return
+	}
+	sb := source.Bounds()
+	sx := [4]int{sb.Min.X, sb.Min.X + insets.Left, sb.Max.X - insets.Right, sb.Max.X}
+	sy := [4]int{sb.Min.Y, sb.Min.Y + insets.Top, sb.Max.Y - insets.Bottom, sb.Max.Y}
+	dx := [4]int{dst.Min.X, dst.Min.X + insets.Left, dst.Max.X - insets.Right, dst.Max.X}
+	dy := [4]int{dst.Min.Y, dst.Min.Y + insets.Top, dst.Max.Y - insets.Bottom, dst.Max.Y}
+	// the corners overlap if the destination is smaller than the insets
+	if dx[1] > dx[2] {
+		dx[1] = (dx[1] + dx[2]) / 2
+		dx[2] = dx[1]
+	}
+	if dy[1] > dy[2] {
+		dy[1] = (dy[1] + dy[2]) / 2
+		dy[2] = dy[1]
+	}
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			b.DrawImage(target, source,
+				image.Rect(sx[i], sy[j], sx[i+1], sy[j+1]),
+				image.Rect(dx[i], dy[j], dx[i+1], dy[j+1]), clr)
+		}
+	}
+}
+
+// Len returns the number of triangles waiting to be drawn.
+func (b *Batch) Len() int {
+	return len(b.indices) / 3
+}
+
+// colorScale returns the premultiplied color scale of the vertices.
+// nil means white.
+func colorScale(clr color.Color) (float32, float32, float32, float32) {
+	if clr == nil {
+		return 1, 1, 1, 1
+	}
+	r, g, b, a := clr.RGBA()
+	return float32(r) / 0xffff, float32(g) / 0xffff, float32(b) / 0xffff, float32(a) / 0xffff
+}
+
+// Flush draws the accumulated quads.
+func (b *Batch) Flush() {
+	if len(b.indices) > 0 {
+		op := &ebiten.DrawTrianglesOptions{ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha}
+		b.target.DrawTriangles(b.vertices, b.indices, b.source, op)
+		b.DrawCalls++
+	}
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+	b.target, b.source = nil, nil
+}
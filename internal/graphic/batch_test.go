@@ -0,0 +1,115 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	target := ebiten.NewImage(100, 100)
+	source := ebiten.NewImage(30, 30)
+	b := &Batch{}
+
+	for i := 0; i < 10; i++ {
+		b.FillRect(target, image.Rect(i, i, i+10, i+10), color.White)
+	}
+	b.StrokeRect(target, image.Rect(0, 0, 50, 50), 2, color.Black)
+	require.Equal(t, 28, b.Len())
+	require.Equal(t, 0, b.DrawCalls)
+
+	// switching the source image flushes the batch
+	b.DrawNinePatch(target, source, Insets{10, 10, 10, 10}, image.Rect(0, 0, 100, 50), nil)
+	require.Equal(t, 1, b.DrawCalls)
+	require.Equal(t, 18, b.Len())
+
+	b.Flush()
+	require.Equal(t, 2, b.DrawCalls)
+	require.Equal(t, 0, b.Len())
+
+	// empty rectangles and nil targets are skipped
+	b.FillRect(target, image.Rectangle{}, color.White)
+	b.FillRect(nil, image.Rect(0, 0, 1, 1), color.White)
+	require.Equal(t, 0, b.Len())
+}
+
+func TestNinePatchSmallerThanInsets(t *testing.T) {
+	target := ebiten.NewImage(100, 100)
+	source := ebiten.NewImage(30, 30)
+	b := &Batch{}
+	b.DrawNinePatch(target, source, Insets{10, 10, 10, 10}, image.Rect(0, 0, 10, 10), nil)
+	for _, v := range b.vertices {
+		require.True(t, v.DstX >= 0 && v.DstX <= 10, v.DstX)
+		require.True(t, v.DstY >= 0 && v.DstY <= 10, v.DstY)
+	}
+}
+
+func TestRoundedRect(t *testing.T) {
+	target := ebiten.NewImage(100, 100)
+	b := &Batch{}
+	rect := image.Rect(10, 20, 50, 40)
+	b.FillRoundedRect(target, rect, 100, color.White)
+	b.StrokeRoundedRect(target, rect, 8, 2, color.Black)
+	require.Greater(t, b.Len(), 0)
+	for _, v := range b.vertices {
+		require.True(t, v.DstX >= 10-0.01 && v.DstX <= 50+0.01, v.DstX)
+		require.True(t, v.DstY >= 20-0.01 && v.DstY <= 40+0.01, v.DstY)
+	}
+}
+
+func TestPolyline(t *testing.T) {
+	target := ebiten.NewImage(100, 100)
+	b := &Batch{}
+	b.StrokePolyline(target, []image.Point{{10, 50}, {40, 80}, {90, 20}}, 4, color.White)
+	require.Greater(t, b.Len(), 0)
+	for _, v := range b.vertices {
+		require.True(t, v.DstX >= 8-0.01 && v.DstX <= 92+0.01, v.DstX)
+		require.True(t, v.DstY >= 18-0.01 && v.DstY <= 82+0.01, v.DstY)
+	}
+	b.Flush()
+	b.StrokePolyline(target, []image.Point{{10, 50}}, 4, color.White)
+	require.Equal(t, 0, b.Len())
+}
+
+func TestLinearGradient(t *testing.T) {
+	target := ebiten.NewImage(100, 100)
+	b := &Batch{}
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	stops := []GradientStop{{0.25, red}, {0.75, blue}}
+	b.FillLinearGradient(target, image.Rect(0, 0, 100, 10), 0, 90, stops)
+	// three bands: solid red, red to blue and solid blue
+	require.Equal(t, 6, b.Len())
+	for _, v := range b.vertices {
+		switch {
+		case v.DstX <= 25:
+			require.Equal(t, float32(1), v.ColorR, v.DstX)
+		case v.DstX >= 75:
+			require.Equal(t, float32(1), v.ColorB, v.DstX)
+		}
+	}
+
+	require.Equal(t, color.RGBA64{0x7fff + 1, 0, 0x7fff + 1, 0xffff}, gradientColor(stops, 0.5))
+	require.Equal(t, red, gradientColor(stops, 0))
+	require.Equal(t, blue, gradientColor(stops, 1))
+}
+
+func TestTransform(t *testing.T) {
+	tr := &Transform{Origin: image.Pt(10, 20), Scale: 2}
+	require.Equal(t, image.Pt(0, 0), tr.Point(image.Pt(10, 20)))
+	require.Equal(t, image.Rect(2, 4, 20, 40), tr.Rect(image.Rect(11, 22, 20, 40)))
+	require.Equal(t, 6, tr.Length(3))
+	var identity *Transform
+	require.Equal(t, image.Rect(1, 2, 3, 4), identity.Rect(image.Rect(1, 2, 3, 4)))
+
+	target := ebiten.NewImage(100, 100)
+	b := &Batch{Transform: tr}
+	b.FillRect(target, image.Rect(10, 20, 20, 30), color.White)
+	require.Equal(t, []float32{0, 0, 20, 0, 0, 20, 20, 20}, []float32{
+		b.vertices[0].DstX, b.vertices[0].DstY, b.vertices[1].DstX, b.vertices[1].DstY,
+		b.vertices[2].DstX, b.vertices[2].DstY, b.vertices[3].DstX, b.vertices[3].DstY,
+	})
+	b.Flush()
+}
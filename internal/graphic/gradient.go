@@ -0,0 +1,125 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GradientStop is a color at an offset (0 to 1) of a gradient line.
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+type point struct {
+	x, y float64
+}
+
+// FillLinearGradient adds a rectangle, with rounded corners if the radius
+// is positive, filled with a linear gradient. The angle is in degrees as
+// in CSS: 0 goes to the top and 90 goes to the right. The stops must be
+// sorted by the offsets.
+func (b *Batch) FillLinearGradient(target *ebiten.Image, rect image.Rectangle, radius int, angle float64, stops []GradientStop) {
+	rect, radius = b.Transform.Rect(rect), b.Transform.Length(radius)
+	if rect.Empty() || len(stops) == 0 {
+		return
+	}
+	var poly []point
+	if radius > 0 {
+		vs, _ := rectPath(rect, radius).AppendVerticesAndIndicesForFilling(nil, nil)
+		for _, v := range vs {
+			poly = append(poly, point{float64(v.DstX), float64(v.DstY)})
+		}
+	} else {
+		poly = []point{
+			{float64(rect.Min.X), float64(rect.Min.Y)},
+			{float64(rect.Max.X), float64(rect.Min.Y)},
+			{float64(rect.Max.X), float64(rect.Max.Y)},
+			{float64(rect.Min.X), float64(rect.Max.Y)},
+		}
+	}
+
+	// t is the position on the gradient line: 0 at the start and 1 at the end
+	rad := angle * math.Pi / 180
+	dx, dy := math.Sin(rad), -math.Cos(rad)
+	w, h := float64(rect.Dx()), float64(rect.Dy())
+	length := math.Abs(w*dx) + math.Abs(h*dy)
+	cx, cy := float64(rect.Min.X)+w/2, float64(rect.Min.Y)+h/2
+	t := func(p point) float64 {
+		return ((p.x-cx)*dx+(p.y-cy)*dy)/length + 0.5
+	}
+
+	// bands between the stops; the colors are constant outside of the stops
+	bounds := []float64{math.Inf(-1)}
+	for _, s := range stops {
+		bounds = append(bounds, s.Offset)
+	}
+	bounds = append(bounds, math.Inf(1))
+	for i := 0; i+1 < len(bounds); i++ {
+		band := clipPolygon(poly, t, bounds[i], bounds[i+1])
+		if len(band) < 3 {
+			continue
+		}
+		vs := make([]ebiten.Vertex, len(band))
+		for j, p := range band {
+			vs[j] = ebiten.Vertex{DstX: float32(p.x), DstY: float32(p.y)}
+			setWhiteSource(&vs[j], gradientColor(stops, t(p)))
+		}
+		is := make([]uint16, 0, (len(band)-2)*3)
+		for j := 2; j < len(band); j++ {
+			is = append(is, 0, uint16(j-1), uint16(j))
+		}
+		b.appendTriangles(target, whiteSubImage, vs, is)
+	}
+}
+
+// gradientColor returns the premultiplied color at the position.
+func gradientColor(stops []GradientStop, t float64) color.Color {
+	if t <= stops[0].Offset {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		s0, s1 := stops[i-1], stops[i]
+		if t > s1.Offset {
+			continue
+		}
+		f := 0.0
+		if s1.Offset > s0.Offset {
+			f = (t - s0.Offset) / (s1.Offset - s0.Offset)
+		}
+		r0, g0, b0, a0 := s0.Color.RGBA()
+		r1, g1, b1, a1 := s1.Color.RGBA()
+		lerp := func(x, y uint32) uint16 {
+			return uint16(float64(x) + (float64(y)-float64(x))*f + 0.5)
+		}
+		return color.RGBA64{lerp(r0, r1), lerp(g0, g1), lerp(b0, b1), lerp(a0, a1)}
+	}
+	return stops[len(stops)-1].Color
+}
+
+// clipPolygon clips a convex polygon to the points where t is in [min, max].
+func clipPolygon(poly []point, t func(point) float64, min, max float64) []point {
+	poly = clipHalf(poly, func(p point) float64 { return t(p) - min })
+	return clipHalf(poly, func(p point) float64 { return max - t(p) })
+}
+
+// clipHalf keeps the part of the polygon where f is not negative.
+// f must be linear.
+func clipHalf(poly []point, f func(point) float64) []point {
+	var ret []point
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		fp, fq := f(p), f(q)
+		if fp >= 0 {
+			ret = append(ret, p)
+		}
+		if (fp < 0) != (fq < 0) {
+			k := fp / (fp - fq)
+			ret = append(ret, point{p.x + (q.x-p.x)*k, p.y + (q.y-p.y)*k})
+		}
+	}
+	return ret
+}
@@ -52,7 +52,7 @@ func DrawRect(target *ebiten.Image, opts *DrawRectOpts) {
 		Rect: image.Rect(r.Min.X, r.Min.Y, r.Min.X+sw, r.Max.Y), Color: *c,
 	})
 	FillRect(target, &FillRectOpts{
-		Rect: image.Rect(r.Min.X, r.Min.Y, r.Min.X+sw, r.Max.Y), Color: *c,
+		Rect: image.Rect(r.Max.X-sw, r.Min.Y, r.Max.X, r.Max.Y), Color: *c,
 	})
 	FillRect(target, &FillRectOpts{
 		Rect: image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+sw), Color: *c,
@@ -0,0 +1,143 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// roundedRectPath returns the path of a rectangle with rounded corners.
+// The radius is clamped to half of the shorter side.
+func roundedRectPath(x0, y0, x1, y1, radius float32) *vector.Path {
+	if max := (x1 - x0) / 2; radius > max {
+		radius = max
+	}
+	if max := (y1 - y0) / 2; radius > max {
+		radius = max
+	}
+	if radius < 0 {
+		radius = 0
+	}
+	r := radius
+	p := &vector.Path{}
+	p.MoveTo(x0+r, y0)
+	p.LineTo(x1-r, y0)
+	p.Arc(x1-r, y0+r, r, -math.Pi/2, 0, vector.Clockwise)
+	p.LineTo(x1, y1-r)
+	p.Arc(x1-r, y1-r, r, 0, math.Pi/2, vector.Clockwise)
+	p.LineTo(x0+r, y1)
+	p.Arc(x0+r, y1-r, r, math.Pi/2, math.Pi, vector.Clockwise)
+	p.LineTo(x0, y0+r)
+	p.Arc(x0+r, y0+r, r, math.Pi, math.Pi*3/2, vector.Clockwise)
+	p.Close()
+	return p
+}
+
+func rectPath(rect image.Rectangle, radius int) *vector.Path {
+	return roundedRectPath(float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Max.X), float32(rect.Max.Y), float32(radius))
+}
+
+// FillRoundedRect adds a rectangle with rounded corners filled with the color.
+func (b *Batch) FillRoundedRect(target *ebiten.Image, rect image.Rectangle, radius int, clr color.Color) {
+	rect, radius = b.Transform.Rect(rect), b.Transform.Length(radius)
+	if rect.Empty() {
+		return
+	}
+	vs, is := rectPath(rect, radius).AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		setWhiteSource(&vs[i], clr)
+	}
+	b.appendTriangles(target, whiteSubImage, vs, is)
+}
+
+// StrokeRoundedRect adds the outline of a rectangle with rounded corners
+// drawn inside the rect.
+func (b *Batch) StrokeRoundedRect(target *ebiten.Image, rect image.Rectangle, radius, width int, clr color.Color) {
+	rect, radius, width = b.Transform.Rect(rect), b.Transform.Length(radius), b.Transform.Length(width)
+	if rect.Empty() || width <= 0 {
+		return
+	}
+	half := float32(width) / 2
+	p := roundedRectPath(
+		float32(rect.Min.X)+half, float32(rect.Min.Y)+half,
+		float32(rect.Max.X)-half, float32(rect.Max.Y)-half,
+		float32(radius)-half)
+	vs, is := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:      float32(width),
+		LineJoin:   vector.LineJoinRound,
+		MiterLimit: 10,
+	})
+	for i := range vs {
+		setWhiteSource(&vs[i], clr)
+	}
+	b.appendTriangles(target, whiteSubImage, vs, is)
+}
+
+// StrokePolyline adds the lines through the points with round joins and
+// caps, e.g. to draw check marks.
+func (b *Batch) StrokePolyline(target *ebiten.Image, points []image.Point, width int, clr color.Color) {
+	width = b.Transform.Length(width)
+	if len(points) < 2 || width <= 0 {
+		return
+	}
+	p := &vector.Path{}
+	for i, pt := range points {
+		pt = b.Transform.Point(pt)
+		if i == 0 {
+			p.MoveTo(float32(pt.X), float32(pt.Y))
+		} else {
+			p.LineTo(float32(pt.X), float32(pt.Y))
+		}
+	}
+	vs, is := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:    float32(width),
+		LineJoin: vector.LineJoinRound,
+		LineCap:  vector.LineCapRound,
+	})
+	for i := range vs {
+		setWhiteSource(&vs[i], clr)
+	}
+	b.appendTriangles(target, whiteSubImage, vs, is)
+}
+
+// DrawRoundedImage adds the part of the source image inside a rectangle
+// with rounded corners. The source must share the coordinates of the
+// target, e.g. an offscreen image created with the bounds of the rect
+// transformed by the transform of the batch.
+func (b *Batch) DrawRoundedImage(target, source *ebiten.Image, rect image.Rectangle, radius int) {
+	rect, radius = b.Transform.Rect(rect), b.Transform.Length(radius)
+	if rect.Empty() {
+		return
+	}
+	vs, is := rectPath(rect, radius).AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		vs[i].SrcX, vs[i].SrcY = vs[i].DstX, vs[i].DstY
+	}
+	b.appendTriangles(target, source, vs, is)
+}
+
+// setWhiteSource makes the vertex sample the white image with the color.
+func setWhiteSource(v *ebiten.Vertex, clr color.Color) {
+	c := whiteSubImage.Bounds().Min
+	v.SrcX, v.SrcY = float32(c.X)+0.5, float32(c.Y)+0.5
+	v.ColorR, v.ColorG, v.ColorB, v.ColorA = colorScale(clr)
+}
+
+// appendTriangles adds the triangles as they are.
+func (b *Batch) appendTriangles(target, source *ebiten.Image, vs []ebiten.Vertex, is []uint16) {
+	if target == nil || source == nil || len(is) == 0 {
+		return
+	}
+	if b.target != target || b.source != source || len(b.vertices)+len(vs) > maxBatchVertices {
+		b.Flush()
+		b.target, b.source = target, source
+	}
+	base := uint16(len(b.vertices))
+	b.vertices = append(b.vertices, vs...)
+	for _, i := range is {
+		b.indices = append(b.indices, base+i)
+	}
+}
@@ -0,0 +1,44 @@
+package graphic
+
+import (
+	"image"
+	"math"
+)
+
+// Transform maps the coordinates of the shapes added to a batch to the
+// coordinates of the target: Origin is moved to (0, 0) and the shapes are
+// scaled by Scale around it, e.g. to draw at a higher resolution.
+type Transform struct {
+	Origin image.Point
+	Scale  float64
+}
+
+// Point returns the point in the coordinates of the target.
+func (t *Transform) Point(p image.Point) image.Point {
+	if t == nil {
+		return p
+	}
+	return image.Pt(t.coord(p.X, t.Origin.X), t.coord(p.Y, t.Origin.Y))
+}
+
+// Rect returns the rectangle in the coordinates of the target. The edges
+// are rounded, so adjacent rectangles stay adjacent.
+func (t *Transform) Rect(r image.Rectangle) image.Rectangle {
+	if t == nil {
+		return r
+	}
+	return image.Rectangle{Min: t.Point(r.Min), Max: t.Point(r.Max)}
+}
+
+// Length returns the length, such as a width or a radius, in the
+// coordinates of the target.
+func (t *Transform) Length(n int) int {
+	if t == nil {
+		return n
+	}
+	return int(math.Round(float64(n) * t.Scale))
+}
+
+func (t *Transform) coord(v, origin int) int {
+	return int(math.Round(float64(v-origin) * t.Scale))
+}
@@ -0,0 +1,143 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"time"
+)
+
+// LayoutAnimation animates the children of a view from their old frames
+// to the new ones when a relayout moves or resizes them (FLIP), so that
+// insertions and reorderings slide instead of teleporting.
+//
+// The children are drawn at the animated frames, their descendants moving
+// with them, while input is hit-tested at the new frames.
+type LayoutAnimation struct {
+	Duration time.Duration
+	// Easing is the name of the easing (see RegisterEasing).
+	// "ease" is used if it is empty.
+	Easing string
+}
+
+// flipState is the layout animation of a child.
+type flipState struct {
+	// last is the frame of the child relative to the parent last drawn.
+	last image.Rectangle
+	// from is the frame the child animates from, relative to the parent.
+	from  image.Rectangle
+	tick  int
+	ticks int
+	// progress is the eased progress of the animation.
+	progress float64
+	running  bool
+}
+
+// drawOffset is the offset of the views drawn in the subtree of views
+// moved by layout animations.
+var drawOffset image.Point
+
+// detectLayoutChanges starts the layout animations of the children whose
+// frames have changed since they were last drawn.
+func (v *View) detectLayoutChanges() {
+	a := v.LayoutAnimation
+	for _, c := range v.children {
+		if a == nil {
+			c.item.flip = nil
+			continue
+		}
+		rel := v.computeBounds(c).Sub(v.frame.Min)
+		f := c.item.flip
+		if f == nil {
+			c.item.flip = &flipState{last: rel}
+			continue
+		}
+		if rel == f.last {
+			continue
+		}
+		// the animation starts from the frame shown, which may be in
+		// the middle of another animation
+		f.from = f.current(f.last)
+		f.last = rel
+		f.tick, f.ticks, f.running = 0, durationTicks(a.Duration), true
+		f.progress = 0
+		if f.ticks <= 0 {
+			f.running = false
+		}
+	}
+}
+
+// current returns the frame shown for the target frame.
+func (f *flipState) current(target image.Rectangle) image.Rectangle {
+	if !f.running {
+		return target
+	}
+	lerp := func(a, b int) int {
+		return a + int(math.Round(float64(b-a)*f.progress))
+	}
+	return image.Rect(
+		lerp(f.from.Min.X, target.Min.X), lerp(f.from.Min.Y, target.Min.Y),
+		lerp(f.from.Max.X, target.Max.X), lerp(f.from.Max.Y, target.Max.Y),
+	)
+}
+
+// advanceLayoutAnimations advances the layout animations of the children
+// of the view by one tick.
+func (v *View) advanceLayoutAnimations() {
+	easing := easingFunc(v.LayoutAnimation.Easing)
+	for _, c := range v.children {
+		f := c.item.flip
+		if f == nil || !f.running {
+			continue
+		}
+		f.tick++
+		if f.tick >= f.ticks {
+			f.running = false
+			continue
+		}
+		f.progress = easing(float64(f.tick) / float64(f.ticks))
+	}
+}
+
+// flipFrame returns the frame of the view to draw for the frame of the
+// layout and the offset of its descendants.
+func (v *View) flipFrame(frame image.Rectangle) (image.Rectangle, image.Point) {
+	f := v.flip
+	if f == nil || !f.running {
+		return frame, image.Point{}
+	}
+	cur := f.current(f.last)
+	offset := cur.Min.Sub(f.last.Min)
+	return image.Rectangle{Min: frame.Min.Add(offset), Max: frame.Min.Add(offset).Add(cur.Size())}, offset
+}
+
+// IsLayoutAnimating returns true if the view is moving to its new frame.
+func (v *View) IsLayoutAnimating() bool {
+	return v.flip != nil && v.flip.running
+}
+
+// parseLayoutAnimation parses `<duration> [<easing>]` or `none`.
+func parseLayoutAnimation(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "none" {
+		return (*LayoutAnimation)(nil), nil
+	}
+	a := &LayoutAnimation{}
+	hasDuration := false
+	for _, f := range strings.Fields(val) {
+		if d, err := time.ParseDuration(f); err == nil && !hasDuration {
+			a.Duration, hasDuration = d, true
+			continue
+		}
+		if _, ok := easings[f]; ok {
+			a.Easing = f
+			continue
+		}
+		return nil, fmt.Errorf("invalid layout-animation: %s", val)
+	}
+	if !hasDuration {
+		return nil, fmt.Errorf("invalid layout-animation: %s", val)
+	}
+	return a, nil
+}
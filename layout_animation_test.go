@@ -0,0 +1,62 @@
+package furex
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayoutAnimation(t *testing.T) {
+	val, err := parseLayoutAnimation("250ms ease-out")
+	require.NoError(t, err)
+	require.Equal(t, &LayoutAnimation{Duration: 250 * time.Millisecond, Easing: "ease-out"}, val)
+
+	val, err = parseLayoutAnimation("none")
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	_, err = parseLayoutAnimation("ease-out")
+	require.Error(t, err)
+	_, err = parseLayoutAnimation("1s 2s")
+	require.Error(t, err)
+}
+
+func TestLayoutAnimation(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px; flex-direction: column; justify-content: start; align-items: start; layout-animation: 100ms linear">
+		<view id="first" style="width: 10px; height: 10px"></view>
+		<view id="second" style="width: 10px; height: 10px"></view>
+	</view>`, nil)
+	screen := ebiten.NewImage(100, 100)
+	root.Draw(screen)
+	second := root.MustGetByID("second")
+	require.False(t, second.IsLayoutAnimating())
+
+	// the first item grows and pushes the second one down by 30px
+	root.MustGetByID("first").SetHeight(40)
+	root.Draw(screen)
+	require.True(t, second.IsLayoutAnimating())
+	// the first frame is the old one
+	frame := root.computeBounds(root.children[1])
+	require.Equal(t, image.Rect(0, 40, 10, 50), frame)
+	b, offset := second.flipFrame(frame)
+	require.Equal(t, image.Rect(0, 10, 10, 20), b)
+	require.Equal(t, image.Pt(0, -30), offset)
+
+	ticks := durationTicks(100 * time.Millisecond)
+	for i := 0; i < ticks/2; i++ {
+		root.updateAnimations()
+	}
+	b, _ = second.flipFrame(frame)
+	require.InDelta(t, 25, b.Min.Y, 3)
+
+	for i := 0; i < ticks; i++ {
+		root.updateAnimations()
+	}
+	require.False(t, second.IsLayoutAnimating())
+	b, offset = second.flipFrame(frame)
+	require.Equal(t, frame, b)
+	require.Equal(t, image.Point{}, offset)
+}
@@ -0,0 +1,50 @@
+package furex
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// lazySubtree is the source of the children of a view with the lazy
+// attribute. The children are created when the view is shown first.
+type lazySubtree struct {
+	source string
+	parser *parser
+	// data is the data of the parser where the view is, with the
+	// variables of the loops.
+	data map[string]any
+}
+
+// IsLazy returns true if the children of the view have not been
+// created yet because the view has the lazy attribute and has not
+// been shown.
+func (v *View) IsLazy() bool {
+	return v.lazy != nil
+}
+
+// ExpandLazy creates the children of a view with the lazy attribute.
+// It is called automatically when the view is drawn while visible for
+// the first time, and can be called earlier to access the children
+// (e.g. with GetByID) before the view is shown.
+func (v *View) ExpandLazy() {
+	l := v.lazy
+	if l == nil {
+		return
+	}
+	v.lazy = nil
+	z := html.NewTokenizer(strings.NewReader(l.source))
+	// the first element of the stack is a placeholder for the dummy root
+	views := append([]*View{nil}, v.ancestors()...)
+	st := &stack{stack: append(views, v)}
+	data := l.parser.data
+	l.parser.data = l.data
+	n := len(v.children)
+	l.parser.parseTokens(z, st, st.len()-1, true)
+	l.parser.data = data
+	fillSlots(v, n)
+	// the size of the ancestors may depend on the new children
+	for p := v; p != nil; p = p.parent {
+		p.isDirty = true
+	}
+}
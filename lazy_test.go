@@ -0,0 +1,68 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				.tab .item { width: 10px; height: 20px }
+			</style>
+		</head>
+		<body>
+			<view id="root">
+				<view id="tab" class="tab" lazy hidden>
+					<view id="item" class="item">Item</view>
+					<view class="item"><view id="nested"></view></view>
+				</view>
+				<view id="after"></view>
+			</view>
+		</body>`, &ParseOptions{Width: 100, Height: 100})
+	// the text of the item is drawn once it is created
+	screen := ebiten.NewImage(100, 100)
+	view.Draw(screen)
+
+	tab := view.MustGetByID("tab")
+	require.True(t, tab.IsLazy())
+	require.Empty(t, tab.getChildren())
+	require.NotNil(t, view.MustGetByID("after"))
+	_, ok := view.GetByID("item")
+	require.False(t, ok)
+
+	tab.Hidden = false
+	view.Draw(screen)
+	require.False(t, tab.IsLazy())
+	require.Len(t, tab.getChildren(), 2)
+
+	item := view.MustGetByID("item")
+	require.Equal(t, "Item", item.Text)
+	require.Equal(t, 10, item.Width)
+	require.Equal(t, 20, item.Height)
+	require.NotNil(t, view.MustGetByID("nested"))
+
+	view.Draw(screen)
+	require.Equal(t, 10, item.frame.Dx())
+	require.Equal(t, 20, item.frame.Dy())
+}
+
+func TestExpandLazy(t *testing.T) {
+	view := Parse(`<view>
+		<view id="dialog" lazy style="display: none"><input id="name"><hr><view id="ok"></view></view>
+		<view id="after"></view>
+	</view>`, nil)
+	dialog := view.MustGetByID("dialog")
+	require.True(t, dialog.IsLazy())
+	// the void elements in the lazy element do not swallow its siblings
+	require.Equal(t, view, view.MustGetByID("after").parent)
+
+	dialog.ExpandLazy()
+	require.False(t, dialog.IsLazy())
+	require.NotNil(t, view.MustGetByID("ok"))
+	require.Len(t, dialog.getChildren(), 3)
+	require.Equal(t, dialog, view.MustGetByID("name").parent)
+}
@@ -0,0 +1,304 @@
+package furex
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// forLoop is a for attribute: `item in items` or `(item, index) in items`.
+type forLoop struct {
+	item, index string
+	path        string
+}
+
+func parseForLoop(s string) (forLoop, error) {
+	i := strings.LastIndex(s, " in ")
+	if i == -1 {
+		return forLoop{}, fmt.Errorf("invalid for: %s", s)
+	}
+	l := forLoop{path: strings.TrimSpace(s[i+4:])}
+	vars := strings.TrimSpace(s[:i])
+	vars = strings.TrimSuffix(strings.TrimPrefix(vars, "("), ")")
+	names := strings.Split(vars, ",")
+	if len(names) > 2 {
+		return forLoop{}, fmt.Errorf("invalid for: %s", s)
+	}
+	l.item = strings.TrimSpace(names[0])
+	if len(names) == 2 {
+		l.index = strings.TrimSpace(names[1])
+	}
+	if l.item == "" || l.path == "" || len(names) == 2 && l.index == "" {
+		return forLoop{}, fmt.Errorf("invalid for: %s", s)
+	}
+	return l, nil
+}
+
+// repeat creates the element with the for attribute once per item of the
+// data, with the variables of the loop set for the attributes, the texts
+// and the children. hasInner is false for self-closing tags. With the key
+// attribute, the views follow the items of the data (see keyedList).
+func (p *parser) repeat(z *html.Tokenizer, tagName, raw string, rawAttrs []rawAttr, loop string, stack *stack, depth int, hasInner bool) {
+	line := p.line + strings.Count(raw, "\n")
+	inner := ""
+	if hasInner {
+		inner = p.readInner(z)
+	}
+	end := p.line
+	p.line = line
+	defer func() { p.line = end }()
+
+	l, err := parseForLoop(loop)
+	if err != nil {
+		p.error(&View{TagName: tagName}, err)
+		return
+	}
+	val, ok := lookupData(p.data, l.path)
+	if !ok {
+		p.error(&View{TagName: tagName}, fmt.Errorf("undefined data: %s", l.path))
+		return
+	}
+	items, ok := loopItems(val)
+	if !ok {
+		p.error(&View{TagName: tagName}, fmt.Errorf("cannot iterate over %s: %T", l.path, val))
+		return
+	}
+
+	var list *keyedList
+	if key, ok := findAttr(rawAttrs, "key"); ok {
+		e, err := parseExpr(key)
+		if err != nil {
+			p.error(&View{TagName: tagName}, err)
+			return
+		}
+		list = &keyedList{
+			loop: l, key: e, tagName: tagName, raw: raw, rawAttrs: rawAttrs,
+			inner: inner, hasInner: hasInner, parser: p, data: p.data, line: line,
+		}
+		parent := stack.peek()
+		if n := len(parent.children); n > 0 {
+			list.prev = parent.children[n-1].item
+		}
+		parent.keyedLists = append(parent.keyedLists, list)
+	}
+
+	data := p.data
+	defer func() { p.data = data }()
+	for i := 0; i < items.Len(); i++ {
+		scope := loopScope(data)
+		setLoopVars(scope, l, items.Index(i).Interface(), i)
+		p.data, p.line = scope, line
+		view := p.repeatItem(tagName, raw, rawAttrs, inner, hasInner, stack, depth)
+		if list != nil {
+			list.items = append(list.items, keyedItem{key: hashKey(list.key.eval(scope)), view: view, scope: scope})
+		}
+	}
+}
+
+// repeatItem creates the element for an item of the loop with p.data as
+// the scope, and adds it to the view at the top of the stack. It returns
+// nil if the element is omitted by its if attribute.
+func (p *parser) repeatItem(tagName, raw string, rawAttrs []rawAttr, inner string, hasInner bool, stack *stack, depth int) *View {
+	view := p.processTag(tagName, raw, rawAttrs, depth, stack.ancestors())
+	if view == nil {
+		return nil
+	}
+	stack.peek().AddChild(view)
+	if !hasInner {
+		view.lazy = nil
+		fillSlots(view, len(view.children))
+		return view
+	}
+	if p.setInner(view, func() string { return inner }) {
+		return view
+	}
+	p.openSlots(view)
+	stack.push(view)
+	p.parseTokens(html.NewTokenizer(strings.NewReader(inner)), stack, depth+1, true)
+	p.closeSlots(stack.pop())
+	return view
+}
+
+// loopItems returns the items of a slice, an array or a listSource such
+// as a Projection. nil has no items.
+func loopItems(val any) (reflect.Value, bool) {
+	if l, ok := val.(listSource); ok {
+		val = l.loopItems()
+	}
+	items := reflect.ValueOf(val)
+	switch items.Kind() {
+	case reflect.Slice, reflect.Array:
+		return items, true
+	case reflect.Invalid:
+		return reflect.ValueOf([]any(nil)), true
+	}
+	return items, false
+}
+
+// loopScope returns a copy of the data for the variables of the loop.
+func loopScope(data map[string]any) map[string]any {
+	scope := make(map[string]any, len(data)+2)
+	for k, v := range data {
+		scope[k] = v
+	}
+	return scope
+}
+
+func setLoopVars(scope map[string]any, l forLoop, item any, index int) {
+	scope[l.item] = item
+	if l.index != "" {
+		scope[l.index] = index
+	}
+}
+
+// keyedList is an element with the for and key attributes, e.g.
+// `<view for="item in items" key="item.ID">`. Its views follow the items
+// of the data every update: the views of the keys still in the data are
+// reused and moved to the positions of their items, keeping their state
+// and animations, the views of new keys are created and the others
+// removed. The variables of the loop of the reused views are set to the
+// current items for their bindings such as visible-if.
+type keyedList struct {
+	loop         forLoop
+	key          expr
+	tagName, raw string
+	rawAttrs     []rawAttr
+	inner        string
+	hasInner     bool
+	parser       *parser
+	data         map[string]any
+	line         int
+	// prev is the sibling before the views of the list, nil if they are
+	// the first children.
+	prev  *View
+	items []keyedItem
+}
+
+// keyedItem is an item of a keyed list. view is nil if the element is
+// omitted by its if attribute.
+type keyedItem struct {
+	key   any
+	view  *View
+	scope map[string]any
+}
+
+// update makes the views of the list follow the items of the data.
+func (l *keyedList) update(parent *View) {
+	val, _ := lookupData(l.data, l.loop.path)
+	items, ok := loopItems(val)
+	if !ok {
+		items, _ = loopItems(nil)
+	}
+
+	old := map[any][]keyedItem{}
+	for _, it := range l.items {
+		old[it.key] = append(old[it.key], it)
+	}
+	next := make([]keyedItem, 0, items.Len())
+	changed := items.Len() != len(l.items)
+	tmp := loopScope(l.data)
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i).Interface()
+		setLoopVars(tmp, l.loop, item, i)
+		key := hashKey(l.key.eval(tmp))
+		if reused := old[key]; len(reused) > 0 {
+			it := reused[0]
+			old[key] = reused[1:]
+			setLoopVars(it.scope, l.loop, item, i)
+			changed = changed || l.items[len(next)].view != it.view
+			next = append(next, it)
+			continue
+		}
+		scope := loopScope(l.data)
+		setLoopVars(scope, l.loop, item, i)
+		next = append(next, keyedItem{key: key, view: l.create(parent, scope), scope: scope})
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	for _, its := range old {
+		for _, it := range its {
+			if it.view != nil {
+				parent.RemoveChild(it.view)
+			}
+		}
+	}
+	l.items = next
+	l.place(parent)
+}
+
+// create creates the view of an item, added to the parent.
+func (l *keyedList) create(parent *View, scope map[string]any) *View {
+	p := l.parser
+	data, line := p.data, p.line
+	defer func() { p.data, p.line = data, line }()
+	p.data, p.line = scope, l.line
+	// the first element of the stack is a placeholder for the dummy root
+	views := append([]*View{nil}, parent.ancestors()...)
+	st := &stack{stack: append(views, parent)}
+	return p.repeatItem(l.tagName, l.raw, l.rawAttrs, l.inner, l.hasInner, st, st.len()-1)
+}
+
+// place moves the views of the list to the order of the items after prev.
+func (l *keyedList) place(parent *View) {
+	inList := map[*View]*child{}
+	for _, it := range l.items {
+		if it.view != nil {
+			inList[it.view] = nil
+		}
+	}
+	for _, c := range parent.children {
+		if _, ok := inList[c.item]; ok {
+			inList[c.item] = c
+		}
+	}
+	children := make([]*child, 0, len(parent.children))
+	placed := false
+	insert := func() {
+		for _, it := range l.items {
+			if c := inList[it.view]; c != nil {
+				children = append(children, c)
+			}
+		}
+		placed = true
+	}
+	if l.prev == nil {
+		insert()
+	}
+	for _, c := range parent.children {
+		if _, ok := inList[c.item]; ok {
+			continue
+		}
+		children = append(children, c)
+		if !placed && c.item == l.prev {
+			insert()
+		}
+	}
+	if !placed {
+		insert()
+	}
+	parent.children = children
+	parent.isDirty = true
+}
+
+// hashKey returns the key usable in a map, formatted if it is not
+// comparable.
+func hashKey(key any) any {
+	if t := reflect.TypeOf(key); t != nil && !t.Comparable() {
+		return fmt.Sprint(key)
+	}
+	return key
+}
+
+// updateKeyedLists updates the keyed lists of the subtree.
+func (v *View) updateKeyedLists() {
+	for _, l := range v.keyedLists {
+		l.update(v)
+	}
+	for _, c := range v.children {
+		c.item.updateKeyedLists()
+	}
+}
@@ -0,0 +1,113 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForLoop(t *testing.T) {
+	l, err := parseForLoop("item in shop.items")
+	require.NoError(t, err)
+	require.Equal(t, forLoop{item: "item", path: "shop.items"}, l)
+	l, err = parseForLoop("(entry, rank) in board")
+	require.NoError(t, err)
+	require.Equal(t, forLoop{item: "entry", index: "rank", path: "board"}, l)
+	for _, s := range []string{"items", "in items", "(a, ) in items", "a, b, c in items"} {
+		_, err := parseForLoop(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestForDirective(t *testing.T) {
+	type entry struct {
+		Name  string
+		Score int
+	}
+	root, err := ParseStrict(`
+		<view>
+			<view id="row-{{ i }}" for="(e, i) in board" if="e.Score > 0">
+				<view id="name-{{ i }}">{{ e.Name }}</view>
+				<view>{{ e.Score }} pts ({{ title }})</view>
+			</view>
+			<view id="tag-{{ t }}" for="t in tags" />
+			<view id="none" for="x in empty"></view>
+			<view id="after"></view>
+		</view>`, &ParseOptions{Data: map[string]any{
+		"title": "weekly",
+		"board": []entry{{"Alice", 30}, {"Bob", 0}, {"Carol", 10}},
+		"tags":  [2]string{"new", "hot"},
+		"empty": []int(nil),
+	}})
+	require.NoError(t, err)
+	require.Len(t, root.children, 5)
+	require.Equal(t, "Alice", root.MustGetByID("name-0").Text)
+	require.Equal(t, "Carol", root.MustGetByID("name-2").Text)
+	_, ok := root.GetByID("row-1")
+	require.False(t, ok)
+	require.Equal(t, "10 pts (weekly)", root.MustGetByID("row-2").children[1].item.Text)
+	require.Equal(t, "tag-hot", root.children[3].item.ID)
+	require.Equal(t, "after", root.children[4].item.ID)
+
+	_, err = ParseStrict(`<view><view for="x in missing"></view></view>`, nil)
+	require.EqualError(t, err, "line 1: <view>: undefined data: missing")
+}
+
+func TestForDirectiveLazy(t *testing.T) {
+	root := Parse(`<view><view for="item in items" id="{{ item }}" lazy><view id="{{ item }}-child"></view></view></view>`,
+		&ParseOptions{Data: map[string]any{"items": []string{"a", "b"}}})
+	b := root.MustGetByID("b")
+	require.True(t, b.IsLazy())
+	b.ExpandLazy()
+	require.NotNil(t, root.MustGetByID("b-child"))
+}
+
+func TestKeyedForDirective(t *testing.T) {
+	type item struct {
+		ID     int
+		Name   string
+		Hidden bool
+	}
+	data := map[string]any{"items": []item{{1, "a", false}, {2, "b", false}, {3, "c", false}}}
+	root := Parse(`<view>
+		<view id="head"></view>
+		<view for="it in items" key="it.ID" visible-if="!it.Hidden">{{ it.Name }}</view>
+		<view id="tail"></view>
+	</view>`, &ParseOptions{Data: data})
+	var removed []*View
+	root.OnNodeRemoved(func(v *View) { removed = append(removed, v) })
+	texts := func() []string {
+		var ret []string
+		for _, c := range root.children {
+			ret = append(ret, c.item.ID+c.item.Text)
+		}
+		return ret
+	}
+	root.Update()
+	require.Equal(t, []string{"head", "a", "b", "c", "tail"}, texts())
+	a, b, c := root.children[1].item, root.children[2].item, root.children[3].item
+
+	// the views of the keys are reused and moved, the others are created
+	// and removed
+	data["items"] = []item{{3, "c", false}, {1, "a", true}, {4, "d", false}}
+	root.Update()
+	require.Equal(t, []string{"head", "c", "a", "d", "tail"}, texts())
+	require.Same(t, c, root.children[1].item)
+	require.Same(t, a, root.children[2].item)
+	require.Contains(t, removed, b)
+	require.NotContains(t, removed, a)
+	require.NotContains(t, removed, c)
+	// the bindings of the reused views see the current items
+	require.True(t, a.Hidden)
+
+	// the list is kept while it is empty
+	data["items"] = nil
+	root.Update()
+	require.Equal(t, []string{"head", "tail"}, texts())
+	data["items"] = []item{{5, "e", false}}
+	root.Update()
+	require.Equal(t, []string{"head", "e", "tail"}, texts())
+
+	_, err := ParseStrict(`<view><view for="x in xs" key="x &&"></view></view>`, &ParseOptions{Data: map[string]any{"xs": []int{1}}})
+	require.Error(t, err)
+}
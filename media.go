@@ -0,0 +1,118 @@
+package furex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mediaQuery is the condition of a @media rule evaluated against
+// the size of the root view. It is a list of alternatives and
+// each alternative is a list of features that must all match.
+type mediaQuery struct {
+	alternatives [][]mediaFeature
+}
+
+type mediaFeature struct {
+	name  string
+	value string
+	px    int
+}
+
+func parseMediaQuery(s string) (*mediaQuery, error) {
+	mq := &mediaQuery{}
+	for _, alt := range strings.Split(s, ",") {
+		var features []mediaFeature
+		for _, part := range strings.Split(strings.ToLower(alt), " and ") {
+			part = strings.TrimSpace(part)
+			switch part {
+			case "", "all", "screen", "only screen":
+				continue
+			}
+			if !strings.HasPrefix(part, "(") || !strings.HasSuffix(part, ")") {
+				// media types such as print never match
+				features = append(features, mediaFeature{name: "never"})
+				continue
+			}
+			f, err := parseMediaFeature(strings.TrimSuffix(strings.TrimPrefix(part, "("), ")"))
+			if err != nil {
+				return nil, err
+			}
+			features = append(features, f)
+		}
+		mq.alternatives = append(mq.alternatives, features)
+	}
+	return mq, nil
+}
+
+func parseMediaFeature(s string) (mediaFeature, error) {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return mediaFeature{}, fmt.Errorf("invalid media feature: %s", s)
+	}
+	f := mediaFeature{name: strings.TrimSpace(name), value: strings.TrimSpace(value)}
+	switch f.name {
+	case "min-width", "max-width", "min-height", "max-height":
+		px, err := parseNumber(f.value)
+		if err != nil {
+			return f, fmt.Errorf("invalid media feature: %s", s)
+		}
+		f.px = px.(int)
+	case "orientation":
+		if f.value != "portrait" && f.value != "landscape" {
+			return f, fmt.Errorf("invalid orientation: %s", f.value)
+		}
+	default:
+		return f, fmt.Errorf("unsupported media feature: %s", f.name)
+	}
+	return f, nil
+}
+
+// and combines the query with the query of an enclosing @media rule.
+func (mq *mediaQuery) and(outer *mediaQuery) *mediaQuery {
+	if outer == nil {
+		return mq
+	}
+	ret := &mediaQuery{}
+	for _, a := range outer.alternatives {
+		for _, b := range mq.alternatives {
+			features := append(append([]mediaFeature{}, a...), b...)
+			ret.alternatives = append(ret.alternatives, features)
+		}
+	}
+	return ret
+}
+
+func (mq *mediaQuery) matches(width, height int) bool {
+	for _, features := range mq.alternatives {
+		ok := true
+		for _, f := range features {
+			if !f.matches(width, height) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *mediaFeature) matches(width, height int) bool {
+	switch f.name {
+	case "min-width":
+		return width >= f.px
+	case "max-width":
+		return width <= f.px
+	case "min-height":
+		return height >= f.px
+	case "max-height":
+		return height <= f.px
+	case "orientation":
+		if f.value == "portrait" {
+			return height >= width
+		}
+		return width > height
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package furex
+
+import (
+	"unsafe"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MemoryUsage is the estimated memory retained by a subtree in bytes.
+type MemoryUsage struct {
+	// Views is the number of views in the subtree.
+	Views int
+	// ViewBytes is the memory used by the views themselves.
+	ViewBytes int64
+	// ImageBytes is the memory used by offscreen and cached images.
+	ImageBytes int64
+	// GlyphBytes is the memory used by text glyph caches.
+	GlyphBytes int64
+	// OtherBytes is the memory reported by handlers that fits
+	// none of the other categories.
+	OtherBytes int64
+}
+
+// Total returns the sum of the memory in all categories.
+func (m MemoryUsage) Total() int64 {
+	return m.ViewBytes + m.ImageBytes + m.GlyphBytes + m.OtherBytes
+}
+
+// Add adds the memory usage of another subtree.
+func (m *MemoryUsage) Add(o MemoryUsage) {
+	m.Views += o.Views
+	m.ViewBytes += o.ViewBytes
+	m.ImageBytes += o.ImageBytes
+	m.GlyphBytes += o.GlyphBytes
+	m.OtherBytes += o.OtherBytes
+}
+
+// MemoryReporter represents a component that retains memory such as
+// offscreen images, so it can be included in View.MemoryUsage.
+type MemoryReporter interface {
+	// ReportMemory adds the memory retained by the component to usage.
+	ReportMemory(usage *MemoryUsage)
+}
+
+// ImageBytes returns the estimated memory used by an image.
+func ImageBytes(img *ebiten.Image) int64 {
+	if img == nil {
+		return 0
+	}
+	b := img.Bounds()
+	return int64(b.Dx()) * int64(b.Dy()) * 4
+}
+
+// MemoryUsage estimates the memory retained by the view and its descendants,
+// including children of lazy views that have not been created yet.
+// It can be compared before opening and after closing a screen
+// to find subtrees leaking offscreen images.
+func (v *View) MemoryUsage() MemoryUsage {
+	usage := MemoryUsage{
+		Views: 1,
+		ViewBytes: int64(unsafe.Sizeof(*v)) +
+			int64(len(v.children))*int64(unsafe.Sizeof(child{})) +
+			int64(len(v.Text)+len(v.Raw)+len(v.ID)+len(v.TagName)),
+	}
+	for k, val := range v.Attrs {
+		usage.ViewBytes += int64(len(k) + len(val))
+	}
+	if v.lazy != nil {
+		usage.ViewBytes += int64(len(v.lazy.source))
+	}
+	usage.ImageBytes += ImageBytes(v.clipImage) + ImageBytes(v.shadowImage) + ImageBytes(v.transformImage) +
+		ImageBytes(v.renderScaleImage)
+	for _, img := range v.backdropImages {
+		usage.ImageBytes += ImageBytes(img)
+	}
+	if s := v.modalBackdrop; s != nil {
+		usage.ImageBytes += ImageBytes(s.screenImage) + ImageBytes(s.gameImage)
+	}
+	if r, ok := v.Handler.(MemoryReporter); ok {
+		r.ReportMemory(&usage)
+	}
+	for _, c := range v.children {
+		usage.Add(c.item.MemoryUsage())
+	}
+	return usage
+}
@@ -0,0 +1,39 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMemoryReporter struct {
+	image *ebiten.Image
+}
+
+func (m *mockMemoryReporter) ReportMemory(usage *MemoryUsage) {
+	usage.ImageBytes += ImageBytes(m.image)
+	usage.GlyphBytes += 100
+}
+
+func TestMemoryUsage(t *testing.T) {
+	reporter := &mockMemoryReporter{image: ebiten.NewImage(10, 20)}
+	screen := &View{ID: "screen"}
+	screen.AddChild(&View{Handler: reporter}, &View{})
+	root := (&View{}).AddChild(screen)
+
+	usage := screen.MemoryUsage()
+	require.Equal(t, 3, usage.Views)
+	require.Equal(t, int64(10*20*4), usage.ImageBytes)
+	require.Equal(t, int64(100), usage.GlyphBytes)
+	require.Greater(t, usage.ViewBytes, int64(0))
+	require.Equal(t, usage.ViewBytes+usage.ImageBytes+usage.GlyphBytes, usage.Total())
+
+	total := root.MemoryUsage()
+	require.Equal(t, 4, total.Views)
+	require.Greater(t, total.Total(), usage.Total())
+
+	root.RemoveChild(screen)
+	require.Equal(t, 1, root.MemoryUsage().Views)
+	require.Equal(t, int64(0), ImageBytes(nil))
+}
@@ -0,0 +1,126 @@
+package furex
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Modal is a dialog shown over the views by View.ShowModal.
+type Modal struct {
+	// Dialog is the view shown, centered over the root view.
+	Dialog *View
+	// Overlay is the view covering the root view beneath the dialog. It
+	// blocks the input to the views beneath it, and dims them with its
+	// BackgroundColor unless the root view has a modal backdrop (see
+	// SetModalBackdrop).
+	Overlay *View
+	// Persistent keeps the modal open when Escape is pressed or
+	// DismissModal is called, e.g. for a dialog waiting for a choice. It
+	// is still closed by Close.
+	Persistent bool
+	// OnClose is called when the modal is closed.
+	OnClose func()
+
+	root *View
+}
+
+// ShowModal shows the dialog over the views of the root view of v, on top
+// of the modals already open, and returns the modal. The views beneath it
+// receive no input and their presses are canceled, and the focus is trapped
+// in the dialog (see View.Modal). Escape closes the top modal, and
+// DismissModal closes it from Go, e.g. for the back button of a gamepad:
+//
+//	m := view.ShowModal(confirmDialog)
+//	m.OnClose = func() { game.Resume() }
+func (v *View) ShowModal(dialog *View) *Modal {
+	root := v.root()
+	overlay := &View{
+		Position:   PositionAbsolute,
+		Width:      root.frame.Dx(),
+		Height:     root.frame.Dy(),
+		Direction:  Column,
+		Justify:    JustifyCenter,
+		AlignItems: AlignItemCenter,
+		Modal:      true,
+	}
+	if s := root.modalBackdrop; s == nil || (s.Color == nil && s.Desaturate <= 0) {
+		overlay.BackgroundColor = color.RGBA{0, 0, 0, 0x80}
+	}
+	overlay.AddChild(dialog)
+	m := &Modal{Dialog: dialog, Overlay: overlay, root: root}
+	// the presses beneath the modal are not released on them
+	root.cancelPresses()
+	root.AddChild(overlay)
+	root.modals = append(root.modals, m)
+	return m
+}
+
+// Close closes the modal, removing its overlay and the dialog.
+func (m *Modal) Close() {
+	r := m.root
+	for i, mm := range r.modals {
+		if mm == m {
+			r.modals = append(r.modals[:i], r.modals[i+1:]...)
+			r.RemoveChild(m.Overlay)
+			if m.OnClose != nil {
+				m.OnClose()
+			}
+			return
+		}
+	}
+}
+
+// TopModal returns the modal shown last over the root view of v, or nil if
+// no modal is open.
+func (v *View) TopModal() *Modal {
+	r := v.root()
+	if len(r.modals) == 0 {
+		return nil
+	}
+	return r.modals[len(r.modals)-1]
+}
+
+// DismissModal closes the top modal of the root view of v unless it is
+// Persistent, and returns true if it was closed.
+func (v *View) DismissModal() bool {
+	m := v.TopModal()
+	if m == nil || m.Persistent {
+		return false
+	}
+	m.Close()
+	return true
+}
+
+// updateModals keeps the overlays over the root view and closes the top
+// modal when Escape is pressed.
+func (v *View) updateModals() {
+	if len(v.modals) == 0 {
+		return
+	}
+	w, h := v.frame.Dx(), v.frame.Dy()
+	for _, m := range v.modals {
+		if m.Overlay.Width != w || m.Overlay.Height != h {
+			m.Overlay.Width, m.Overlay.Height = w, h
+			m.Overlay.Layout()
+		}
+	}
+	if isKeyJustPressed(ebiten.KeyEscape) {
+		v.DismissModal()
+	}
+}
+
+// isBelowModal returns true if the view is beneath the top modal of its
+// root view, so it receives no input.
+func (v *View) isBelowModal() bool {
+	m := v.TopModal()
+	if m == nil {
+		return false
+	}
+	for p := v; p != nil; p = p.parent {
+		if p == m.Overlay {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package furex
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// ModalBackdrop is a layer drawn over everything behind the top modal
+// view (View.Modal), so that the screens under pause menus and dialogs
+// look the same without backdrop views in each of them.
+type ModalBackdrop struct {
+	// Color is drawn over the screen, e.g. translucent black to dim it.
+	Color color.Color
+	// Desaturate removes the colors of the screen, from 0 (none) to 1
+	// (grayscale).
+	Desaturate float64
+	// Game is the image the game is rendered into, if it is not drawn onto
+	// the screen before the views. The layer is drawn onto it as well,
+	// after the game is rendered into it and before it is displayed.
+	Game *ebiten.Image
+}
+
+// modalBackdropState is the modal backdrop of a root view.
+type modalBackdropState struct {
+	ModalBackdrop
+	// images are the copies of the screen and the game image to
+	// desaturate them.
+	screenImage *ebiten.Image
+	gameImage   *ebiten.Image
+}
+
+// modalLayer is the modal backdrop of the root view being drawn and the
+// modal view it is drawn beneath.
+var modalLayer struct {
+	state  *modalBackdropState
+	top    *View
+	screen *ebiten.Image
+}
+
+// SetModalBackdrop sets the layer drawn behind the top modal view.
+// It must be called on the root view. The zero ModalBackdrop removes it.
+func (v *View) SetModalBackdrop(b ModalBackdrop) {
+	if v.modalBackdrop == nil {
+		v.modalBackdrop = &modalBackdropState{}
+	}
+	v.modalBackdrop.ModalBackdrop = b
+	if b.Desaturate <= 0 {
+		v.releaseModalBackdropImages()
+	}
+}
+
+// beginModalLayer prepares the modal backdrop of the root view to be
+// drawn beneath the top modal view, if a modal view is open.
+func (v *View) beginModalLayer(screen *ebiten.Image) {
+	s := v.modalBackdrop
+	if s == nil || screen == nil || (s.Color == nil && s.Desaturate <= 0) {
+		return
+	}
+	var modals []*View
+	v.collectModals(&modals)
+	if len(modals) == 0 {
+		return
+	}
+	modalLayer.state = s
+	modalLayer.top = modals[len(modals)-1]
+	modalLayer.screen = screen
+}
+
+func endModalLayer() {
+	modalLayer.state = nil
+	modalLayer.top = nil
+	modalLayer.screen = nil
+}
+
+// drawModalLayer draws the modal backdrop if the view is the top modal view.
+func drawModalLayer(v *View) {
+	s := modalLayer.state
+	if s == nil || modalLayer.top != v {
+		return
+	}
+	batch.Flush()
+	s.screenImage = s.draw(modalLayer.screen, s.screenImage)
+	if s.Game != nil {
+		s.gameImage = s.draw(s.Game, s.gameImage)
+	}
+	// the layer is drawn only once per frame
+	endModalLayer()
+}
+
+// draw draws the layer onto the target. The target is copied to the
+// image, recreated if it does not fit, to be desaturated.
+func (s *modalBackdropState) draw(target, img *ebiten.Image) *ebiten.Image {
+	bounds := target.Bounds()
+	if s.Desaturate > 0 {
+		if img == nil || img.Bounds().Size() != bounds.Size() {
+			if img != nil {
+				img.Dispose()
+			}
+			img = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+		}
+		img.Clear()
+		img.DrawImage(target, nil)
+		target.Clear()
+		var cm colorm.ColorM
+		cm.ChangeHSV(0, 1-clampOpacity(s.Desaturate), 1)
+		op := &colorm.DrawImageOptions{}
+		op.GeoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y))
+		colorm.DrawImage(target, img, cm, op)
+	}
+	if s.Color != nil {
+		batch.FillRect(target, bounds, s.Color)
+		batch.Flush()
+	}
+	return img
+}
+
+func (v *View) releaseModalBackdropImages() {
+	s := v.modalBackdrop
+	if s == nil {
+		return
+	}
+	for _, img := range []*ebiten.Image{s.screenImage, s.gameImage} {
+		if img != nil {
+			img.Dispose()
+		}
+	}
+	s.screenImage, s.gameImage = nil, nil
+}
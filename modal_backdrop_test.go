@@ -0,0 +1,49 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModalBackdrop(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<view id="hud" style="width: 100px; height: 20px; background-color: #f00"></view>
+		<view id="dialog" modal="true" style="width: 50px; height: 50px; display: none"></view>
+	</view>`, nil)
+	dialog := root.MustGetByID("dialog")
+	game := ebiten.NewImage(60, 40)
+	root.SetModalBackdrop(ModalBackdrop{Color: color.RGBA{0, 0, 0, 0x80}, Desaturate: 1, Game: game})
+
+	// nothing is drawn without a modal
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Nil(t, root.modalBackdrop.screenImage)
+	require.Nil(t, root.modalBackdrop.gameImage)
+
+	var top *View
+	dialog.Display = DisplayFlex
+	dialog.Handler = &Canvas{DrawFunc: func(screen *ebiten.Image, frame image.Rectangle) {
+		top = modalLayer.top
+	}}
+	root.Layout()
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+	// the layer has been drawn before the modal
+	require.Nil(t, top)
+	require.Nil(t, modalLayer.state)
+	require.Equal(t, image.Rect(0, 0, 100, 100), root.modalBackdrop.screenImage.Bounds())
+	require.Equal(t, image.Rect(0, 0, 60, 40), root.modalBackdrop.gameImage.Bounds())
+	require.Equal(t, int64((100*100+60*40)*4), root.MemoryUsage().ImageBytes)
+
+	root.Release()
+	require.Nil(t, root.modalBackdrop.screenImage)
+
+	// the images are not needed to dim
+	root.SetModalBackdrop(ModalBackdrop{Color: color.RGBA{0, 0, 0, 0x80}})
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Nil(t, root.modalBackdrop.screenImage)
+}
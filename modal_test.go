@@ -0,0 +1,69 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowModal(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := &View{Width: 200, Height: 100}
+	below := &mockHandler{}
+	root.AddChild(&View{Width: 200, Height: 100, Handler: below})
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	click := func(x, y int) {
+		mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+		tick(InputState{CursorX: x, CursorY: y, MouseButtons: mouse})
+		tick(InputState{CursorX: x, CursorY: y})
+	}
+	tick(InputState{})
+
+	button := &mockHandler{}
+	dialog := &View{Width: 60, Height: 40, Handler: button}
+	m := root.ShowModal(dialog)
+	closed := 0
+	m.OnClose = func() { closed++ }
+	tick(InputState{})
+
+	// the dialog is centered over the dimmed views
+	require.Equal(t, image.Rect(70, 30, 130, 70), dialog.frame)
+	require.Equal(t, image.Rect(0, 0, 200, 100), m.Overlay.frame)
+	require.NotNil(t, m.Overlay.BackgroundColor)
+	require.Same(t, m, root.TopModal())
+
+	// the views beneath the modal receive no input
+	click(10, 10)
+	require.False(t, below.IsPressed)
+	require.False(t, below.IsReleased)
+	click(100, 50)
+	require.True(t, button.IsReleased)
+
+	// Escape closes the top modal, and the other modals stay open
+	second := root.ShowModal(&View{Width: 20, Height: 20})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyEscape}})
+	tick(InputState{})
+	require.Same(t, m, root.TopModal())
+	require.False(t, second.Overlay.hasParent)
+	require.Equal(t, 0, closed)
+
+	// a persistent modal is only closed by Close
+	m.Persistent = true
+	require.False(t, root.DismissModal())
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyEscape}})
+	require.Same(t, m, root.TopModal())
+	m.Close()
+	require.Equal(t, 1, closed)
+	require.Nil(t, root.TopModal())
+	require.Len(t, root.children, 1)
+
+	click(10, 10)
+	require.True(t, below.IsReleased)
+}
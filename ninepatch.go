@@ -0,0 +1,46 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// batchDrawer is implemented by the built-in handlers whose drawing
+// is added to the shared batch instead of drawing immediately.
+type batchDrawer interface {
+	drawBatch(screen *ebiten.Image, frame image.Rectangle, v *View)
+}
+
+// NinePatch is a handler that stretches an image to the frame keeping
+// its corners unscaled. The insets are the sizes of the corners in pixels.
+// Nine-patches of many views are drawn together with the backgrounds and
+// borders in a few DrawTriangles calls.
+type NinePatch struct {
+	Image  *ebiten.Image
+	Left   int
+	Top    int
+	Right  int
+	Bottom int
+	// Color multiplies the image. It is not applied if nil.
+	Color color.Color
+}
+
+var _ Drawer = (*NinePatch)(nil)
+
+// Draw implements Drawer.
+func (n *NinePatch) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	n.drawBatch(screen, frame, v)
+	batch.Flush()
+}
+
+func (n *NinePatch) drawBatch(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	insets := graphic.Insets{Left: n.Left, Top: n.Top, Right: n.Right, Bottom: n.Bottom}
+	clr := n.Color
+	if o := v.EffectiveOpacity(); o < 1 {
+		clr = fade(clr, o)
+	}
+	batch.DrawNinePatch(screen, n.Image, insets, frame, clr)
+}
@@ -0,0 +1,189 @@
+package furex
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NotifyCorner is the corner of the screen the notifications are stacked
+// in.
+type NotifyCorner int
+
+const (
+	NotifyTopRight NotifyCorner = iota
+	NotifyTopLeft
+	NotifyBottomRight
+	NotifyBottomLeft
+)
+
+// NotifyStyle is how the notifications are shown (see SetNotifyStyle).
+type NotifyStyle struct {
+	// Corner is the corner the notifications are stacked in, the newest
+	// nearest to it.
+	Corner NotifyCorner
+	// Margin is the space between the notifications and the edges of the
+	// root view. 8 is used if it is 0.
+	Margin int
+	// Gap is the space between the notifications. 8 is used if it is 0.
+	Gap int
+	// Duration is the duration of the notifications sliding in and out.
+	// 200ms is used if it is 0.
+	Duration time.Duration
+}
+
+const (
+	defaultNotifySpace    = 8
+	defaultNotifyDuration = 200 * time.Millisecond
+)
+
+// Notification is a message shown by Notify.
+type Notification struct {
+	wrapper *View
+	ticks   int
+	// age is the time the notification has been shown, and leaving the
+	// time it has been sliding out.
+	age     int
+	leaving int
+	closing bool
+	// offset is the distance of the notification from the corner.
+	offset *Tween
+}
+
+// notifier is the layer of the notifications, over the root view updated
+// first while they are shown.
+var notifier struct {
+	style         NotifyStyle
+	notifications []*Notification
+	root          *View
+	layer         *View
+}
+
+// SetNotifyStyle sets how the notifications are shown.
+func SetNotifyStyle(s NotifyStyle) {
+	notifier.style = s
+}
+
+// Notify shows the view as a transient message stacked in a corner of the
+// screen over the root view, e.g. for achievements and received items. It
+// slides in, and out after the duration, or when it is dismissed if the
+// duration is 0. The view must have a size:
+//
+//	furex.Notify(furex.Parse(`<view class="toast"><text>Quest complete</text></view>`, nil), 3*time.Second)
+func Notify(view *View, duration time.Duration) *Notification {
+	n := &Notification{
+		ticks:   durationTicks(duration),
+		wrapper: &View{Position: PositionAbsolute, Width: view.Width, Height: view.Height},
+	}
+	n.wrapper.AddChild(view)
+	notifier.notifications = append(notifier.notifications, n)
+	return n
+}
+
+// Dismiss slides the notification out.
+func (n *Notification) Dismiss() {
+	n.closing = true
+}
+
+// updateNotifications animates the notifications if the view is the root
+// view showing them.
+func (v *View) updateNotifications() {
+	if len(notifier.notifications) == 0 {
+		notifier.root, notifier.layer = nil, nil
+		return
+	}
+	if notifier.root == nil {
+		notifier.root = v
+		notifier.layer = &View{}
+	}
+	if notifier.root != v {
+		return
+	}
+	s := notifier.style
+	margin, gap := s.Margin, s.Gap
+	if margin == 0 {
+		margin = defaultNotifySpace
+	}
+	if gap == 0 {
+		gap = defaultNotifySpace
+	}
+	duration := s.Duration
+	if duration == 0 {
+		duration = defaultNotifyDuration
+	}
+	slide := durationTicks(duration)
+
+	// the notifications leave after their time, and are removed once they
+	// slid out
+	var shown []*Notification
+	for _, n := range notifier.notifications {
+		n.age++
+		if n.ticks > 0 && n.age >= slide+n.ticks {
+			n.closing = true
+		}
+		if n.closing {
+			n.leaving++
+		}
+		if n.leaving <= slide {
+			shown = append(shown, n)
+		} else {
+			notifier.layer.RemoveChild(n.wrapper)
+		}
+	}
+	notifier.notifications = shown
+
+	layer := notifier.layer
+	frame := v.frame
+	layer.Left, layer.Top, layer.Width, layer.Height = frame.Min.X, frame.Min.Y, frame.Dx(), frame.Dy()
+	offset := margin
+	for i := len(shown) - 1; i >= 0; i-- {
+		n := shown[i]
+		w, h := n.wrapper.Width, n.wrapper.Height
+		// the notifications slide to their places in the stack
+		if n.offset == nil {
+			n.offset = &Tween{From: float64(offset), To: float64(offset)}
+			layer.AddChild(n.wrapper)
+		} else if n.offset.To != float64(offset) {
+			n.offset = &Tween{From: n.offset.Value(), To: float64(offset), Duration: duration, Easing: "ease"}
+		}
+		y := int(n.offset.Update())
+		offset += h + gap
+
+		// and in and out of the edge
+		in, out := 1.0, 0.0
+		if slide > 0 {
+			in = Ease("ease-out", math.Min(1, float64(n.age)/float64(slide)))
+			out = Ease("ease-in", math.Min(1, float64(n.leaving)/float64(slide)))
+		} else if n.leaving > 0 {
+			out = 1
+		}
+		progress := in * (1 - out)
+		x := margin - int(float64(w+margin)*(1-progress))
+		opacity := progress
+		n.wrapper.Opacity = &opacity
+
+		switch s.Corner {
+		case NotifyTopRight, NotifyBottomRight:
+			n.wrapper.Left = frame.Dx() - w - x
+		default:
+			n.wrapper.Left = x
+		}
+		switch s.Corner {
+		case NotifyBottomRight, NotifyBottomLeft:
+			n.wrapper.Top = frame.Dy() - h - y
+		default:
+			n.wrapper.Top = y
+		}
+	}
+	layer.Layout()
+}
+
+// drawNotifications draws the notifications over the views if the view is
+// the root view showing them.
+func (v *View) drawNotifications(screen *ebiten.Image) {
+	if notifier.root != v || notifier.layer == nil {
+		return
+	}
+	notifier.layer.Draw(screen)
+}
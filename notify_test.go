@@ -0,0 +1,67 @@
+package furex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 100px"><view style="width: 10px; height: 10px"></view></view>`, nil)
+	other := Parse(`<view style="width: 200px; height: 100px"><view style="width: 10px; height: 10px"></view></view>`, nil)
+	tick := func(n int) {
+		for i := 0; i < n; i++ {
+			InjectInput(InputState{})
+			root.Update()
+			other.Update()
+		}
+	}
+	tick(1)
+
+	// a notification slides in at the corner
+	first := Notify(&View{Width: 50, Height: 20}, time.Second)
+	tick(1)
+	require.Same(t, root, notifier.root)
+	require.Greater(t, first.wrapper.Left, 200-50-8)
+	require.Less(t, *first.wrapper.Opacity, 1.0)
+	tick(12)
+	require.Equal(t, 200-50-8, first.wrapper.Left)
+	require.Equal(t, 8, first.wrapper.Top)
+	require.Equal(t, 1.0, *first.wrapper.Opacity)
+	root.Draw(ebiten.NewImage(200, 100))
+
+	// the newest one is nearest to the corner, and the others slide away
+	second := Notify(&View{Width: 60, Height: 30}, 0)
+	tick(13)
+	require.Equal(t, 8, second.wrapper.Top)
+	require.Equal(t, 8+30+8, first.wrapper.Top)
+	require.Len(t, notifier.layer.children, 2)
+
+	// a notification leaves after its time, and the others take its place
+	tick(60)
+	require.Len(t, notifier.layer.children, 1)
+	require.Equal(t, 8, second.wrapper.Top)
+
+	// and when it is dismissed
+	second.Dismiss()
+	tick(13)
+	require.Empty(t, notifier.notifications)
+	tick(1)
+	require.Nil(t, notifier.root)
+
+	// in the corner of the style
+	SetNotifyStyle(NotifyStyle{Corner: NotifyBottomLeft, Margin: 4})
+	defer SetNotifyStyle(NotifyStyle{})
+	third := Notify(&View{Width: 50, Height: 20}, time.Second)
+	tick(13)
+	require.Equal(t, 4, third.wrapper.Left)
+	require.Equal(t, 100-20-4, third.wrapper.Top)
+	third.Dismiss()
+	tick(14)
+	require.Nil(t, notifier.root)
+}
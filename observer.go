@@ -0,0 +1,48 @@
+package furex
+
+// NodeObserver is called with a view that has been added to or removed from a tree.
+// The view is the root of the subtree that has been added or removed.
+type NodeObserver func(v *View)
+
+type observers struct {
+	added   []NodeObserver
+	removed []NodeObserver
+}
+
+// OnNodeAdded registers a function called when a view is added anywhere in the tree.
+// It must be called on the root view.
+func (v *View) OnNodeAdded(fn NodeObserver) {
+	v.observersOf().added = append(v.observersOf().added, fn)
+}
+
+// OnNodeRemoved registers a function called when a view is removed from anywhere in the tree.
+// It must be called on the root view.
+func (v *View) OnNodeRemoved(fn NodeObserver) {
+	v.observersOf().removed = append(v.observersOf().removed, fn)
+}
+
+func (v *View) observersOf() *observers {
+	if v.observers == nil {
+		v.observers = &observers{}
+	}
+	return v.observers
+}
+
+func (v *View) notifyAdded(child *View) {
+	if r := v.root(); r.observers != nil {
+		for _, fn := range r.observers.added {
+			fn(child)
+		}
+	}
+}
+
+func (v *View) notifyRemoved(root *View, child *View) {
+	if p := root.releasePolicy; p != nil && p.OnUnmount {
+		child.releaseSubtree(p)
+	}
+	if root.observers != nil {
+		for _, fn := range root.observers.removed {
+			fn(child)
+		}
+	}
+}
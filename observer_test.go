@@ -0,0 +1,35 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeObservers(t *testing.T) {
+	root := &View{}
+	parent := &View{ID: "parent"}
+	root.AddChild(parent)
+
+	var added, removed []string
+	root.OnNodeAdded(func(v *View) { added = append(added, v.ID) })
+	root.OnNodeRemoved(func(v *View) { removed = append(removed, v.ID) })
+
+	subtree := (&View{ID: "a"}).AddChild(&View{ID: "a-child"})
+	parent.AddChild(subtree, &View{ID: "b"})
+	require.Equal(t, []string{"a", "b"}, added)
+
+	subtree.AddChild(&View{ID: "c"})
+	require.Equal(t, []string{"a", "b", "c"}, added)
+
+	parent.RemoveChild(subtree)
+	require.Equal(t, []string{"a"}, removed)
+
+	// the removed subtree is no longer observed
+	subtree.AddChild(&View{ID: "d"})
+	require.Equal(t, []string{"a", "b", "c"}, added)
+
+	parent.PopChild()
+	root.RemoveAll()
+	require.Equal(t, []string{"a", "b", "parent"}, removed)
+}
@@ -0,0 +1,75 @@
+package furex
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Float returns a pointer to the float64 value, for optional fields
+// such as Opacity.
+func Float(f float64) *float64 { return &f }
+
+// EffectiveOpacity returns the opacity of the view multiplied by the
+// opacities of its ancestors. The built-in drawing (backgrounds, borders,
+// shadows, nine-patches and texts) is faded by it, and custom handlers
+// can use it to fade their drawing with the rest of the subtree, e.g.
+// with DrawImageOptions.ColorScale.ScaleAlpha.
+func (v *View) EffectiveOpacity() float64 {
+	o := 1.0
+	for p := v; p != nil; p = p.parent {
+		if p.Opacity != nil {
+			o *= clampOpacity(*p.Opacity)
+		}
+	}
+	return o
+}
+
+// isTransparent returns true if the view and its children are not drawn
+// because the opacity of the view is zero.
+func (v *View) isTransparent() bool {
+	return v.Opacity != nil && *v.Opacity <= 0
+}
+
+func clampOpacity(o float64) float64 {
+	if o < 0 {
+		return 0
+	}
+	if o > 1 {
+		return 1
+	}
+	return o
+}
+
+// fade returns the color with the alpha multiplied by the opacity.
+// nil is treated as white, the color of untinted images.
+func fade(c color.Color, opacity float64) color.Color {
+	if c == nil {
+		c = color.White
+	}
+	if opacity >= 1 {
+		return c
+	}
+	r, g, b, a := c.RGBA()
+	return color.RGBA64{
+		R: uint16(float64(r) * opacity),
+		G: uint16(float64(g) * opacity),
+		B: uint16(float64(b) * opacity),
+		A: uint16(float64(a) * opacity),
+	}
+}
+
+// parseOpacity parses a number or a percentage.
+func parseOpacity(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	scale := 1.0
+	if strings.HasSuffix(val, "%") {
+		val, scale = strings.TrimSuffix(val, "%"), 0.01
+	}
+	o, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid opacity: %s", val)
+	}
+	return Float(clampOpacity(o * scale)), nil
+}
@@ -0,0 +1,52 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpacity(t *testing.T) {
+	view := Parse(`
+		<view id="dialog" style="width: 100px; height: 100px; opacity: 0.5">
+			<view id="panel" style="width: 50px; height: 50px; opacity: 50%">
+				<view id="label" style="width: 10px; height: 10px"></view>
+			</view>
+		</view>`, nil)
+	require.Equal(t, 0.5, *view.Opacity)
+	require.Equal(t, 0.25, view.MustGetByID("panel").EffectiveOpacity())
+	require.Equal(t, 0.25, view.MustGetByID("label").EffectiveOpacity())
+
+	view.Opacity = nil
+	require.Equal(t, 0.5, view.MustGetByID("label").EffectiveOpacity())
+
+	_, err := parseOpacity("half")
+	require.Error(t, err)
+	o, err := parseOpacity("1.5")
+	require.NoError(t, err)
+	require.Equal(t, 1.0, *o.(*float64))
+}
+
+func TestFade(t *testing.T) {
+	require.Equal(t, color.RGBA64{0x7fff, 0, 0, 0x7fff}, fade(color.RGBA{255, 0, 0, 255}, 0.5))
+	require.Equal(t, color.RGBA{255, 0, 0, 255}, fade(color.RGBA{255, 0, 0, 255}, 1))
+	require.Equal(t, color.White, fade(nil, 1))
+}
+
+func TestTransparentSubtree(t *testing.T) {
+	screen := ebiten.NewImage(100, 100)
+	root := &View{Width: 100, Height: 100}
+	dialog := &View{Width: 50, Height: 50, BackgroundColor: color.White, Opacity: Float(0)}
+	dialog.AddChild(&View{Width: 10, Height: 10, BackgroundColor: color.Black})
+	root.AddChild(dialog)
+
+	calls := batch.DrawCalls
+	root.Draw(screen)
+	require.Equal(t, calls, batch.DrawCalls)
+
+	dialog.Opacity = Float(0.5)
+	root.Draw(screen)
+	require.Equal(t, calls+1, batch.DrawCalls)
+}
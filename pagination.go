@@ -0,0 +1,149 @@
+package furex
+
+import (
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// PagedText is a Text that splits long text into pages fitting the frame,
+// e.g. for in-game books and tutorials. The text is wrapped at the width
+// of the frame, and a form feed ("\f") starts a new page.
+//
+// The pages are split when the text is drawn, so PageCount is zero until
+// the view has been drawn once. The page is kept when the frame changes,
+// within the new page count.
+type PagedText struct {
+	// Text has the face and the color of the text.
+	Text
+	// OnPageChange is called when the page or the page count changes.
+	OnPageChange func(page, count int)
+
+	page  int
+	pages [][]textLine
+	// source, size and pagedFace are the text, the size of the frame and
+	// the face the pages were split for.
+	source    string
+	size      image.Point
+	pagedFace font.Face
+}
+
+var _ Drawer = (*PagedText)(nil)
+
+// textLine is a wrapped line of text. end is true if the line ends a
+// paragraph.
+type textLine struct {
+	text string
+	end  bool
+}
+
+// Draw implements Drawer.
+func (p *PagedText) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	face, clr := p.face(v), p.color(v)
+	if v.Text != p.source || frame.Size() != p.size || face != p.pagedFace {
+		p.source, p.size, p.pagedFace = v.Text, frame.Size(), face
+		p.pages = paginateText(v.Text, face, frame.Dx(), frame.Dy())
+		p.page = p.clampPage(p.page)
+		p.changed()
+	}
+	if p.page >= len(p.pages) {
+		return
+	}
+	page := p.pages[p.page]
+	lines, ends := make([]string, len(page)), make([]bool, len(page))
+	for i, l := range page {
+		lines[i], ends[i] = l.text, l.end
+	}
+	opacity := v.EffectiveOpacity()
+	p.keys = p.keys[:0]
+	align, valign := p.align(v)
+	for _, r := range alignLines(lines, ends, face, frame, align, valign) {
+		p.keys = append(p.keys, newTextKey(face, r.text, clr))
+		drawText(screen, r.text, face, r.x, r.y, clr, opacity)
+	}
+	p.holdKeys()
+}
+
+// Page returns the index of the current page.
+func (p *PagedText) Page() int {
+	return p.page
+}
+
+// PageCount returns the number of pages.
+func (p *PagedText) PageCount() int {
+	return len(p.pages)
+}
+
+// NextPage turns to the next page. It returns false on the last page.
+func (p *PagedText) NextPage() bool {
+	if p.page+1 >= len(p.pages) {
+		return false
+	}
+	p.SetPage(p.page + 1)
+	return true
+}
+
+// PrevPage turns to the previous page. It returns false on the first page.
+func (p *PagedText) PrevPage() bool {
+	if p.page == 0 {
+		return false
+	}
+	p.SetPage(p.page - 1)
+	return true
+}
+
+// SetPage turns to the page, clamped to the pages. Before the text is
+// drawn, the page is kept until the pages are split.
+func (p *PagedText) SetPage(page int) {
+	if p.pages != nil {
+		page = p.clampPage(page)
+	}
+	if page != p.page {
+		p.page = page
+		p.changed()
+	}
+}
+
+func (p *PagedText) clampPage(page int) int {
+	if page >= len(p.pages) {
+		page = len(p.pages) - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	return page
+}
+
+func (p *PagedText) changed() {
+	if p.OnPageChange != nil {
+		p.OnPageChange(p.page, len(p.pages))
+	}
+}
+
+// paginateText wraps the text at the width and splits the lines into
+// pages of the height. A form feed starts a new page. Each page has at
+// least a line.
+func paginateText(s string, face font.Face, width, height int) [][]textLine {
+	perPage := height / face.Metrics().Height.Ceil()
+	if perPage < 1 {
+		perPage = 1
+	}
+	var pages [][]textLine
+	for _, section := range strings.Split(s, "\f") {
+		lines, ends := wrapText(strings.Trim(section, "\n"), face, width)
+		for i := 0; i < len(lines); i += perPage {
+			end := i + perPage
+			if end > len(lines) {
+				end = len(lines)
+			}
+			page := make([]textLine, end-i)
+			for j := range page {
+				page[j] = textLine{lines[i+j], ends[i+j]}
+			}
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,50 @@
+package furex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestPaginateText(t *testing.T) {
+	face := basicfont.Face7x13
+	// 3 lines of 13px per page, 5 characters per line
+	pages := paginateText("aa bb cc dd ee\nff\fgg", face, 35, 40)
+	require.Equal(t, [][]textLine{
+		{{"aa bb", false}, {"cc dd", false}, {"ee", true}},
+		{{"ff", true}},
+		{{"gg", true}},
+	}, pages)
+
+	// a page has a line even if the frame is shorter than a line
+	pages = paginateText("a b", face, 7, 5)
+	require.Len(t, pages, 2)
+}
+
+func TestPagedText(t *testing.T) {
+	var changes []int
+	p := &PagedText{OnPageChange: func(page, count int) { changes = append(changes, page) }}
+	// the page is kept until the pages are split
+	p.SetPage(5)
+	p.pages = paginateText(strings.Repeat("word ", 10), basicfont.Face7x13, 35, 13)
+	p.page = p.clampPage(p.page)
+	require.Equal(t, 10, p.PageCount())
+	require.Equal(t, 5, p.Page())
+
+	require.True(t, p.NextPage())
+	require.Equal(t, 6, p.Page())
+	p.SetPage(100)
+	require.Equal(t, 9, p.Page())
+	require.False(t, p.NextPage())
+	p.SetPage(0)
+	require.False(t, p.PrevPage())
+	require.Equal(t, []int{5, 6, 9, 0}, changes)
+}
+
+func TestPagedTextComponent(t *testing.T) {
+	root := Parse(`<view><paged-text id="book"></paged-text></view>`, nil)
+	_, ok := root.MustGetByID("book").Handler.(*PagedText)
+	require.True(t, ok)
+}
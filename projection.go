@@ -0,0 +1,123 @@
+package furex
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Projection presents the items of a slice filtered and sorted, without
+// copying them into a slice for the UI, e.g. for leaderboards and
+// searchable inventories. A projection in ParseOptions.Data is iterated
+// by the for attribute like a slice, and Len and At give the Count and
+// the rows of a VirtualList or the cells of a Grid.
+//
+// The filter and the order are applied again when the slice returned by
+// Source changes (another slice or length), or after Refresh when the
+// items changed in place. The sort is stable, so the items comparing
+// equal keep the order of the slice.
+type Projection[T any] struct {
+	// Source returns the slice, e.g. func() []Entry { return game.Board }.
+	Source func() []T
+	// Filter reports whether an item is shown. All the items are shown if
+	// it is nil.
+	Filter func(item T) bool
+	// Less orders the items. The items keep the order of the slice if it
+	// is nil.
+	Less func(a, b T) bool
+
+	source  []T
+	indices []int
+	items   []T
+	applied bool
+}
+
+// NewProjection creates a projection of the slice returned by source.
+func NewProjection[T any](source func() []T) *Projection[T] {
+	return &Projection[T]{Source: source}
+}
+
+// SetFilter sets the filter and applies it.
+func (p *Projection[T]) SetFilter(filter func(item T) bool) {
+	p.Filter = filter
+	p.Refresh()
+}
+
+// SetLess sets the order and applies it.
+func (p *Projection[T]) SetLess(less func(a, b T) bool) {
+	p.Less = less
+	p.Refresh()
+}
+
+// Refresh applies the filter and the order again, e.g. after the items
+// changed in place.
+func (p *Projection[T]) Refresh() {
+	p.applied = false
+}
+
+// Items returns the items shown, in order. The slice is not changed when
+// the projection is applied again, and must not be modified.
+func (p *Projection[T]) Items() []T {
+	p.apply()
+	return p.items
+}
+
+// Len returns the number of the items shown.
+func (p *Projection[T]) Len() int {
+	p.apply()
+	return len(p.items)
+}
+
+// At returns the item shown at the index.
+func (p *Projection[T]) At(index int) T {
+	p.apply()
+	return p.items[index]
+}
+
+// SourceIndex returns the index in the slice of the item shown at the
+// index, e.g. to change the item.
+func (p *Projection[T]) SourceIndex(index int) int {
+	p.apply()
+	return p.indices[index]
+}
+
+func (p *Projection[T]) apply() {
+	var source []T
+	if p.Source != nil {
+		source = p.Source()
+	}
+	if p.applied && sameSlice(source, p.source) {
+		return
+	}
+	p.source, p.applied = source, true
+	p.indices = nil
+	for i, item := range source {
+		if p.Filter == nil || p.Filter(item) {
+			p.indices = append(p.indices, i)
+		}
+	}
+	if p.Less != nil {
+		sort.SliceStable(p.indices, func(i, j int) bool {
+			return p.Less(source[p.indices[i]], source[p.indices[j]])
+		})
+	}
+	p.items = make([]T, 0, len(p.indices))
+	for _, i := range p.indices {
+		p.items = append(p.items, source[i])
+	}
+}
+
+// loopItems implements listSource.
+func (p *Projection[T]) loopItems() any {
+	return p.Items()
+}
+
+// listSource is a value iterated by the for attribute as the slice it
+// returns, such as a Projection.
+type listSource interface {
+	loopItems() any
+}
+
+// sameSlice returns true if the slices have the same elements in memory.
+func sameSlice[T any](a, b []T) bool {
+	return len(a) == len(b) && reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
@@ -0,0 +1,52 @@
+package furex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjection(t *testing.T) {
+	type entry struct {
+		Name  string
+		Score int
+	}
+	board := []entry{{"alice", 30}, {"bob", 10}, {"carol", 30}, {"dave", 20}}
+	p := NewProjection(func() []entry { return board })
+	p.Less = func(a, b entry) bool { return a.Score > b.Score }
+	names := func() string {
+		var ret []string
+		for _, e := range p.Items() {
+			ret = append(ret, e.Name)
+		}
+		return strings.Join(ret, ",")
+	}
+	// the items comparing equal keep the order of the slice
+	require.Equal(t, "alice,carol,dave,bob", names())
+	require.Equal(t, 3, p.SourceIndex(2))
+
+	p.SetFilter(func(e entry) bool { return e.Score >= 20 })
+	require.Equal(t, "alice,carol,dave", names())
+	require.Equal(t, 3, p.Len())
+	require.Equal(t, "dave", p.At(2).Name)
+
+	// applied again when the slice changes, or after Refresh
+	board = append(board, entry{"erin", 40})
+	require.Equal(t, "erin,alice,carol,dave", names())
+	board[0].Score = 0
+	require.Equal(t, "erin,alice,carol,dave", names())
+	p.Refresh()
+	require.Equal(t, "erin,carol,dave", names())
+
+	// the for attribute iterates the items
+	root := Parse(`<view><view for="e in board" key="e.Name" id="{{ e.Name }}"></view></view>`,
+		&ParseOptions{Data: map[string]any{"board": p}})
+	require.Len(t, root.children, 3)
+	require.Equal(t, "erin", root.children[0].item.ID)
+	carol := root.children[1].item
+	p.SetLess(func(a, b entry) bool { return a.Name < b.Name })
+	root.Update()
+	require.Equal(t, "carol", root.children[0].item.ID)
+	require.Same(t, carol, root.children[0].item)
+}
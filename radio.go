@@ -0,0 +1,246 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// RadioGroup is the handler of the built-in <radio-group> element. Its
+// children are the options, of which only one is selected, e.g. for
+// difficulty pickers and option lists. An option is selected when it is
+// clicked or tapped, or with the arrow keys while the group has the
+// focus. Hidden and disabled options are skipped.
+//
+// The selected option matches the :checked pseudo-class, and <radio>
+// elements inside the options draw the state:
+//
+//	<radio-group id="difficulty" selected="1">
+//		<view class="option"><radio></radio><text>Easy</text></view>
+//		<view class="option"><radio></radio><text>Normal</text></view>
+//		<view class="option"><radio></radio><text>Hard</text></view>
+//	</radio-group>
+type RadioGroup struct {
+	// Selected is the index of the selected child. No option is selected
+	// if it is -1.
+	Selected int
+	// OnSelect is called with the index of the option selected by the input.
+	OnSelect func(index int)
+
+	// options are the options that can be selected in the last update.
+	options []radioOption
+	// styled is the selection the options were last styled with.
+	styled  int
+	focused bool
+}
+
+var _ ButtonHandler = (*RadioGroup)(nil)
+var _ Updater = (*RadioGroup)(nil)
+var _ FocusHandler = (*RadioGroup)(nil)
+
+// radioOption is a child of a radio group at the index.
+type radioOption struct {
+	index int
+	frame image.Rectangle
+}
+
+// newRadioGroup creates the handler of a <radio-group> element from its
+// attributes.
+func newRadioGroup(attrs map[string]string) Handler {
+	g := &RadioGroup{Selected: -1}
+	if val, ok := attrs["selected"]; ok {
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			println(fmt.Sprintf("radio-group: %v", err))
+		} else {
+			g.Selected = i
+		}
+	}
+	g.styled = g.Selected
+	return g
+}
+
+// Select selects the option at the index without calling OnSelect.
+func (g *RadioGroup) Select(index int) {
+	g.Selected = index
+}
+
+// choose selects the option and calls OnSelect if it has changed.
+func (g *RadioGroup) choose(index int) {
+	if index == g.Selected {
+		return
+	}
+	g.Selected = index
+	if g.OnSelect != nil {
+		g.OnSelect(index)
+	}
+}
+
+// HandlePress implements ButtonHandler.
+func (g *RadioGroup) HandlePress(x, y int, t ebiten.TouchID) {}
+
+// HandleRelease implements ButtonHandler.
+func (g *RadioGroup) HandleRelease(x, y int, isCancel bool) {
+	if isCancel {
+		return
+	}
+	for _, o := range g.options {
+		if isInside(&o.frame, x, y) {
+			g.choose(o.index)
+			return
+		}
+	}
+}
+
+// HandleFocus implements FocusHandler.
+func (g *RadioGroup) HandleFocus() {
+	g.focused = true
+}
+
+// HandleBlur implements FocusHandler.
+func (g *RadioGroup) HandleBlur() {
+	g.focused = false
+}
+
+// Update implements Updater.
+func (g *RadioGroup) Update(v *View) {
+	g.options = g.options[:0]
+	for i, c := range v.children {
+		if o := c.item; !o.Hidden && o.isDisplayed() && !o.Disabled {
+			g.options = append(g.options, radioOption{index: i, frame: o.frame})
+		}
+	}
+	if g.focused && !v.Disabled {
+		for _, k := range []ebiten.Key{ebiten.KeyArrowLeft, ebiten.KeyArrowUp, ebiten.KeyArrowRight, ebiten.KeyArrowDown} {
+			if isKeyJustPressed(k) || isKeyRepeated(k) {
+				g.move(k == ebiten.KeyArrowRight || k == ebiten.KeyArrowDown)
+			}
+		}
+	}
+	if g.styled != g.Selected {
+		// the selection is also changed from Go
+		g.styled = g.Selected
+		v.restyle()
+	}
+}
+
+// move selects the next or the previous option, wrapping around.
+func (g *RadioGroup) move(next bool) {
+	n := len(g.options)
+	if n == 0 {
+		return
+	}
+	cur := -1
+	for i, o := range g.options {
+		if o.index == g.Selected {
+			cur = i
+		}
+	}
+	switch {
+	case cur == -1 && next:
+		cur = 0
+	case cur == -1:
+		cur = n - 1
+	case next:
+		cur = (cur + 1) % n
+	default:
+		cur = (cur + n - 1) % n
+	}
+	g.choose(g.options[cur].index)
+}
+
+// isSelectedOption returns true if the view is the selected option of a
+// radio group.
+func (v *View) isSelectedOption() bool {
+	if !v.hasParent {
+		return false
+	}
+	g, ok := v.parent.Handler.(*RadioGroup)
+	if !ok || g.Selected < 0 || g.Selected >= len(v.parent.children) {
+		return false
+	}
+	return v.parent.children[g.Selected].item == v
+}
+
+// inSelectedOption returns true if the view or its ancestor is the
+// selected option of a radio group.
+func (v *View) inSelectedOption() bool {
+	for p := v; p != nil; p = p.parent {
+		if p.isSelectedOption() {
+			return true
+		}
+		if p.hasParent {
+			if _, ok := p.parent.Handler.(*RadioGroup); ok {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// Radio is the handler of the built-in <radio> element. It draws a ring
+// with a dot while the option of a radio group (RadioGroup) containing it
+// is selected.
+type Radio struct {
+	// Color is the color of the ring while it is not selected. Gray is used
+	// if it is nil.
+	Color color.Color
+	// CheckedColor is the color of the ring and the dot while it is
+	// selected. The color of the view (View.ComputedColor) or blue is used
+	// if it is nil.
+	CheckedColor color.Color
+}
+
+var _ Drawer = (*Radio)(nil)
+var _ Sizer = (*Radio)(nil)
+
+// Size implements Sizer.
+func (r *Radio) Size(v *View) (width, height int) {
+	return 16, 16
+}
+
+// Draw implements Drawer.
+func (r *Radio) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	r.drawBatch(screen, frame, v)
+	batch.Flush()
+}
+
+func (r *Radio) drawBatch(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	size := frame.Dx()
+	if frame.Dy() < size {
+		size = frame.Dy()
+	}
+	if size <= 0 {
+		return
+	}
+	// the circle is centered in the frame
+	x, y := frame.Min.X+(frame.Dx()-size)/2, frame.Min.Y+(frame.Dy()-size)/2
+	circle := image.Rect(x, y, x+size, y+size)
+	opacity := v.EffectiveOpacity()
+	width := size / 8
+	if width < 1 {
+		width = 1
+	}
+	if !v.inSelectedOption() {
+		c := r.Color
+		if c == nil {
+			c = defaultCheckboxColor
+		}
+		batch.StrokeRoundedRect(screen, circle, size/2, width, fade(c, opacity))
+		return
+	}
+	c := r.CheckedColor
+	if c == nil {
+		c = v.ComputedColor()
+	}
+	if c == nil {
+		c = defaultSliderFillColor
+	}
+	c = fade(c, opacity)
+	batch.StrokeRoundedRect(screen, circle, size/2, width, c)
+	dot := circle.Inset(size / 4)
+	batch.FillRoundedRect(screen, dot, dot.Dx()/2, c)
+}
@@ -0,0 +1,63 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRadioGroup(t *testing.T) {
+	root := Parse(`<html><head><style>
+		.option { width: 100px; height: 20px; }
+		.option:checked { margin-left: 4px; }
+	</style></head><body>
+	<view style="width: 200px; height: 200px; align-items: flex-start">
+		<radio-group id="difficulty" selected="1" style="direction: column; align-items: flex-start">
+			<view class="option"><radio id="easy"></radio></view>
+			<view class="option"><radio id="normal"></radio></view>
+			<view class="option" disabled><radio></radio></view>
+			<view class="option"><radio></radio></view>
+		</radio-group>
+	</view></body></html>`, nil)
+	root.Update()
+	group := root.MustGetByID("difficulty")
+	g := group.Handler.(*RadioGroup)
+	require.Equal(t, 1, g.Selected)
+	require.Len(t, group.Children(), 4)
+	require.Len(t, g.options, 3)
+	require.Equal(t, 4, group.Children()[1].MarginLeft)
+	require.True(t, root.MustGetByID("normal").inSelectedOption())
+	require.False(t, root.MustGetByID("easy").inSelectedOption())
+	// the radio is sized to 16px and stretched by the option
+	require.Equal(t, image.Rect(0, 0, 16, 20), root.MustGetByID("easy").frame)
+
+	var selected []int
+	g.OnSelect = func(index int) { selected = append(selected, index) }
+	root.handleMouseButtonLeftPressed(10, 5)
+	root.handleMouseButtonLeftReleased(10, 5)
+	require.Equal(t, []int{0}, selected)
+	// the disabled option is not selected
+	root.handleMouseButtonLeftPressed(10, 45)
+	root.handleMouseButtonLeftReleased(10, 45)
+	require.Equal(t, []int{0}, selected)
+
+	// the options are restyled
+	root.Update()
+	require.Equal(t, 4, group.Children()[0].MarginLeft)
+	require.Equal(t, 0, group.Children()[1].MarginLeft)
+
+	// the arrow keys skip the disabled option and wrap around
+	g.move(true)
+	g.move(true)
+	require.Equal(t, []int{0, 1, 3}, selected)
+	g.move(true)
+	g.move(false)
+	require.Equal(t, []int{0, 1, 3, 0, 3}, selected)
+
+	g.Select(-1)
+	root.Update()
+	require.Equal(t, 0, group.Children()[3].MarginLeft)
+	root.Draw(ebiten.NewImage(200, 200))
+}
@@ -0,0 +1,82 @@
+package furex
+
+import (
+	"time"
+)
+
+// Releaser represents a component that holds cached resources which can
+// be recreated when needed, such as offscreen images or glyph caches.
+type Releaser interface {
+	// Release releases the cached resources.
+	Release()
+}
+
+// ReleasePolicy decides when the cached resources of a subtree are
+// released automatically.
+type ReleasePolicy struct {
+	// HiddenFor releases the resources of a subtree after it has been
+	// hidden for the duration. Zero disables releasing hidden subtrees.
+	HiddenFor time.Duration
+	// OnUnmount releases the resources of a subtree when it is removed
+	// from the tree.
+	OnUnmount bool
+	// OnRelease is called with the root of a subtree whose resources
+	// have been released, e.g. to return pooled views.
+	OnRelease func(v *View)
+}
+
+// SetReleasePolicy sets the policy to release cached resources of subtrees.
+// It must be called on the root view.
+func (v *View) SetReleasePolicy(p ReleasePolicy) {
+	v.releasePolicy = &p
+}
+
+// Release releases the cached resources of the view and its descendants
+// by calling the handlers implementing Releaser.
+func (v *View) Release() {
+	for _, c := range v.children {
+		c.item.Release()
+	}
+	if r, ok := v.Handler.(Releaser); ok {
+		r.Release()
+	}
+	v.releaseClipImage()
+	v.releaseShadowImage()
+	v.releaseTransformImage()
+	v.releaseBackdropImages()
+	v.releaseRenderScaleImage()
+	v.releaseModalBackdropImages()
+}
+
+func (v *View) releaseSubtree(p *ReleasePolicy) {
+	v.Release()
+	if p.OnRelease != nil {
+		p.OnRelease(v)
+	}
+}
+
+func (v *View) handleRelease() {
+	p := v.releasePolicy
+	if p == nil || p.HiddenFor <= 0 {
+		return
+	}
+	ticks := int(p.HiddenFor.Seconds() * float64(tps()))
+	if ticks < 1 {
+		ticks = 1
+	}
+	v.updateHiddenTicks(p, ticks)
+}
+
+func (v *View) updateHiddenTicks(p *ReleasePolicy, ticks int) {
+	if v.Hidden || !v.isDisplayed() {
+		v.hiddenTicks++
+		if v.hiddenTicks == ticks {
+			v.releaseSubtree(p)
+		}
+		return
+	}
+	v.hiddenTicks = 0
+	for _, c := range v.children {
+		c.item.updateHiddenTicks(p, ticks)
+	}
+}
@@ -0,0 +1,73 @@
+package furex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReleaser struct {
+	released int
+}
+
+func (m *mockReleaser) Release() {
+	m.released++
+}
+
+func TestReleaseHidden(t *testing.T) {
+	cache := &mockReleaser{}
+	screen := (&View{}).AddChild(&View{Handler: cache})
+	root := (&View{Width: 100, Height: 100}).AddChild(screen)
+
+	var released []*View
+	root.SetReleasePolicy(ReleasePolicy{
+		HiddenFor: time.Second,
+		OnRelease: func(v *View) { released = append(released, v) },
+	})
+
+	ticks := ebiten.TPS()
+	for i := 0; i < ticks; i++ {
+		root.handleRelease()
+	}
+	require.Equal(t, 0, cache.released)
+
+	screen.Hidden = true
+	for i := 0; i < ticks-1; i++ {
+		root.handleRelease()
+	}
+	require.Equal(t, 0, cache.released)
+	root.handleRelease()
+	require.Equal(t, 1, cache.released)
+	require.Equal(t, []*View{screen}, released)
+
+	// released only once while the subtree stays hidden
+	for i := 0; i < ticks*2; i++ {
+		root.handleRelease()
+	}
+	require.Equal(t, 1, cache.released)
+
+	// the timer restarts after the subtree is shown again
+	screen.Hidden = false
+	root.handleRelease()
+	screen.Display = DisplayNone
+	for i := 0; i < ticks; i++ {
+		root.handleRelease()
+	}
+	require.Equal(t, 2, cache.released)
+}
+
+func TestReleaseOnUnmount(t *testing.T) {
+	cache := &mockReleaser{}
+	screen := (&View{}).AddChild(&View{Handler: cache})
+	root := (&View{}).AddChild(screen)
+
+	root.RemoveChild(screen)
+	require.Equal(t, 0, cache.released)
+
+	root.AddChild(screen)
+	root.SetReleasePolicy(ReleasePolicy{OnUnmount: true})
+	root.RemoveChild(screen)
+	require.Equal(t, 1, cache.released)
+}
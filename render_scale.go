@@ -0,0 +1,114 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// ImageRendering is the 'image-rendering' property: the filter used to
+// scale the views drawn at a render scale (View.RenderScale) up to the
+// screen.
+type ImageRendering uint8
+
+const (
+	// ImageRenderingAuto smooths the pixels with the linear filter.
+	ImageRenderingAuto ImageRendering = iota
+	// ImageRenderingPixelated keeps the pixels sharp with the nearest filter.
+	ImageRenderingPixelated
+)
+
+func (r ImageRendering) String() string {
+	switch r {
+	case ImageRenderingAuto:
+		return "auto"
+	case ImageRenderingPixelated:
+		return "pixelated"
+	}
+	return fmt.Sprintf("unknown image-rendering: %d", r)
+}
+
+func parseImageRendering(val string) (any, error) {
+	switch val {
+	case "auto", "smooth":
+		return ImageRenderingAuto, nil
+	case "pixelated", "crisp-edges":
+		return ImageRenderingPixelated, nil
+	}
+	return ImageRenderingAuto, fmt.Errorf("unknown image-rendering: %s", val)
+}
+
+func (r ImageRendering) filter() ebiten.Filter {
+	if r == ImageRenderingPixelated {
+		return ebiten.FilterNearest
+	}
+	return ebiten.FilterLinear
+}
+
+// parseRenderScale parses a number from 0 to 1 or a percentage.
+func parseRenderScale(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	pct := strings.HasSuffix(val, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("invalid render-scale: %s", val)
+	}
+	if pct {
+		f /= 100
+	}
+	if f <= 0 || f > 1 {
+		return 0.0, fmt.Errorf("invalid render-scale: %s", val)
+	}
+	return f, nil
+}
+
+// isRenderScaled returns true if the view is drawn at a render scale.
+func (v *View) isRenderScaled() bool {
+	return v.RenderScale > 0 && v.RenderScale < 1 && v.hasParent
+}
+
+// drawRenderScaled draws the subtree of the view with draw onto an
+// offscreen image at the render scale and scales it up onto the screen.
+func (v *View) drawRenderScaled(screen *ebiten.Image, frame image.Rectangle, draw func(target *ebiten.Image)) {
+	bounds := frame
+	if s := v.BoxShadow; s != nil {
+		ext := s.Blur + s.Spread
+		bounds = bounds.Union(frame.Inset(-ext).Add(image.Pt(s.OffsetX, s.OffsetY)))
+	}
+	// the image is scaled from the coordinates of the screen, which are
+	// scaled in a snapshot
+	target := targetRect(bounds)
+	w := int(math.Ceil(float64(target.Dx()) * v.RenderScale))
+	h := int(math.Ceil(float64(target.Dy()) * v.RenderScale))
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if v.renderScaleImage == nil || v.renderScaleImage.Bounds().Size() != image.Pt(w, h) {
+		v.releaseRenderScaleImage()
+		v.renderScaleImage = ebiten.NewImage(w, h)
+	}
+	batch.Flush()
+	v.renderScaleImage.Clear()
+	saved := batch.Transform
+	batch.Transform = &graphic.Transform{Origin: bounds.Min, Scale: DrawScale() * v.RenderScale}
+	draw(v.renderScaleImage)
+	batch.Flush()
+	batch.Transform = saved
+
+	op := &ebiten.DrawImageOptions{Filter: v.ImageRendering.filter()}
+	op.GeoM.Scale(float64(target.Dx())/float64(w), float64(target.Dy())/float64(h))
+	op.GeoM.Translate(float64(target.Min.X), float64(target.Min.Y))
+	screen.DrawImage(v.renderScaleImage, op)
+}
+
+func (v *View) releaseRenderScaleImage() {
+	if v.renderScaleImage != nil {
+		v.renderScaleImage.Dispose()
+		v.renderScaleImage = nil
+	}
+}
@@ -0,0 +1,60 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRenderScale(t *testing.T) {
+	for val, want := range map[string]float64{"0.5": 0.5, "50%": 0.5, "1": 1} {
+		got, err := parseRenderScale(val)
+		require.NoError(t, err, val)
+		require.Equal(t, want, got, val)
+	}
+	for _, val := range []string{"0", "2", "-0.5", "half"} {
+		_, err := parseRenderScale(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestRenderScale(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<view id="layer" style="width: 60px; height: 40px; margin-left: 10px; render-scale: 0.5; image-rendering: pixelated; background-color: #f00">
+			<canvas id="canvas" style="width: 20px; height: 10px"></canvas>
+		</view>
+	</view>`, nil)
+	layer := root.MustGetByID("layer")
+	require.Equal(t, 0.5, layer.RenderScale)
+	require.Equal(t, ImageRenderingPixelated, layer.ImageRendering)
+
+	var drawn image.Rectangle
+	var scale float64
+	canvas := root.MustGetByID("canvas").Handler.(*Canvas)
+	canvas.DrawFunc = func(screen *ebiten.Image, frame image.Rectangle) {
+		drawn, scale = frame, DrawScale()
+	}
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+	// the layer is drawn at half the size of the frame
+	require.Equal(t, image.Rect(0, 0, 30, 20), layer.renderScaleImage.Bounds())
+	require.Equal(t, image.Rect(0, 0, 10, 5), drawn)
+	require.Equal(t, 0.5, scale)
+	require.Nil(t, batch.Transform)
+	require.Equal(t, image.Rect(10, 0, 30, 10), canvas.Frame())
+	require.Equal(t, int64(30*20*4), layer.MemoryUsage().ImageBytes)
+
+	// the render scale is multiplied by the scale of a snapshot
+	root.Snapshot(2)
+	require.Equal(t, image.Rect(0, 0, 60, 40), layer.renderScaleImage.Bounds())
+	require.Equal(t, 1.0, scale)
+
+	layer.Release()
+	require.Nil(t, layer.renderScaleImage)
+	layer.RenderScale = 1
+	root.Draw(ebiten.NewImage(100, 100))
+	require.Nil(t, layer.renderScaleImage)
+	require.Equal(t, image.Rect(10, 0, 30, 10), drawn)
+}
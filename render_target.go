@@ -0,0 +1,116 @@
+package furex
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// RenderTarget is a handler that displays an offscreen image, such as
+// a world render or a character preview, scaled into the frame of the view.
+// Input on the view is forwarded to the callbacks in the coordinates
+// of the image, so the image can be treated as a viewport.
+type RenderTarget struct {
+	// Image is the render target to display.
+	Image *ebiten.Image
+	// KeepAspectRatio letterboxes the image instead of stretching it to the frame.
+	KeepAspectRatio bool
+	// Filter is the filter used when the image is scaled.
+	Filter ebiten.Filter
+
+	// OnPress is called when the image is pressed.
+	// The parameter (x, y) is the location in the image.
+	OnPress func(x, y int, t ebiten.TouchID)
+	// OnRelease is called when the press is released.
+	// The parameter (x, y) is the location in the image.
+	OnRelease func(x, y int, isCancel bool)
+	// OnMouseMove is called when the mouse moves over the image.
+	// The parameter (x, y) is the location in the image.
+	OnMouseMove func(x, y int) bool
+
+	dest image.Rectangle
+}
+
+var (
+	_ Drawer        = (*RenderTarget)(nil)
+	_ ButtonHandler = (*RenderTarget)(nil)
+	_ MouseHandler  = (*RenderTarget)(nil)
+)
+
+// Draw implements Drawer.
+func (r *RenderTarget) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	if r.Image == nil {
+		return
+	}
+	r.dest = r.destRect(frame)
+	if r.dest.Empty() {
+		return
+	}
+	sw, sh := r.Image.Bounds().Dx(), r.Image.Bounds().Dy()
+	op := &ebiten.DrawImageOptions{Filter: r.Filter}
+	op.GeoM.Scale(float64(r.dest.Dx())/float64(sw), float64(r.dest.Dy())/float64(sh))
+	op.GeoM.Translate(float64(r.dest.Min.X), float64(r.dest.Min.Y))
+	screen.DrawImage(r.Image, op)
+}
+
+// ToTarget converts a location on the screen to the location in the image.
+// It returns false if the location is outside of the displayed image.
+func (r *RenderTarget) ToTarget(x, y int) (int, int, bool) {
+	// the right and bottom edges are outside of the image
+	if r.Image == nil || !image.Pt(x, y).In(r.dest) {
+		return 0, 0, false
+	}
+	sw, sh := r.Image.Bounds().Dx(), r.Image.Bounds().Dy()
+	tx := (x - r.dest.Min.X) * sw / r.dest.Dx()
+	ty := (y - r.dest.Min.Y) * sh / r.dest.Dy()
+	return tx, ty, true
+}
+
+// HandlePress implements ButtonHandler.
+func (r *RenderTarget) HandlePress(x, y int, t ebiten.TouchID) {
+	if tx, ty, ok := r.ToTarget(x, y); ok && r.OnPress != nil {
+		r.OnPress(tx, ty, t)
+	}
+}
+
+// HandleRelease implements ButtonHandler.
+func (r *RenderTarget) HandleRelease(x, y int, isCancel bool) {
+	if r.OnRelease == nil {
+		return
+	}
+	tx, ty, ok := r.ToTarget(x, y)
+	r.OnRelease(tx, ty, isCancel || !ok)
+}
+
+// HandleMouse implements MouseHandler.
+func (r *RenderTarget) HandleMouse(x, y int) bool {
+	if tx, ty, ok := r.ToTarget(x, y); ok && r.OnMouseMove != nil {
+		return r.OnMouseMove(tx, ty)
+	}
+	return false
+}
+
+// ReportMemory implements MemoryReporter.
+func (r *RenderTarget) ReportMemory(usage *MemoryUsage) {
+	usage.ImageBytes += ImageBytes(r.Image)
+}
+
+func (r *RenderTarget) destRect(frame image.Rectangle) image.Rectangle {
+	return fitRect(frame, r.Image.Bounds().Size(), r.KeepAspectRatio)
+}
+
+// fitRect returns the rectangle to draw an image of the given size into frame.
+// If keepAspect is true, the image is scaled uniformly and centered.
+func fitRect(frame image.Rectangle, size image.Point, keepAspect bool) image.Rectangle {
+	if !keepAspect || size.X == 0 || size.Y == 0 {
+		return frame
+	}
+	fw, fh := frame.Dx(), frame.Dy()
+	w, h := fw, size.Y*fw/size.X
+	if h > fh {
+		w, h = size.X*fh/size.Y, fh
+	}
+	x := frame.Min.X + (fw-w)/2
+	y := frame.Min.Y + (fh-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
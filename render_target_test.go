@@ -0,0 +1,118 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFitRect(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		frame      image.Rectangle
+		size       image.Point
+		keepAspect bool
+		want       image.Rectangle
+	}{
+		{
+			name:  "stretch",
+			frame: image.Rect(10, 10, 110, 60),
+			size:  image.Pt(20, 20),
+			want:  image.Rect(10, 10, 110, 60),
+		},
+		{
+			name:       "pillarbox",
+			frame:      image.Rect(10, 10, 110, 60),
+			size:       image.Pt(20, 20),
+			keepAspect: true,
+			want:       image.Rect(35, 10, 85, 60),
+		},
+		{
+			name:       "letterbox",
+			frame:      image.Rect(0, 0, 100, 100),
+			size:       image.Pt(40, 20),
+			keepAspect: true,
+			want:       image.Rect(0, 25, 100, 75),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, fitRect(tt.frame, tt.size, tt.keepAspect))
+		})
+	}
+}
+
+func TestRenderTargetToTarget(t *testing.T) {
+	r := &RenderTarget{Image: ebiten.NewImage(40, 20)}
+	screen := ebiten.NewImage(100, 100)
+
+	// stretched to the frame, 2.5 times wider and 5 times higher
+	r.Draw(screen, image.Rect(0, 0, 100, 100), nil)
+	for _, tt := range []struct {
+		x, y, wantX, wantY int
+	}{
+		{0, 0, 0, 0},
+		{50, 50, 20, 10},
+		{99, 99, 39, 19},
+	} {
+		x, y, ok := r.ToTarget(tt.x, tt.y)
+		require.True(t, ok)
+		require.Equal(t, image.Pt(tt.wantX, tt.wantY), image.Pt(x, y))
+	}
+	_, _, ok := r.ToTarget(100, 50)
+	require.False(t, ok)
+
+	// letterboxed at 2.5 times, with bars above and below the image
+	r.KeepAspectRatio = true
+	r.Draw(screen, image.Rect(0, 0, 100, 100), nil)
+	x, y, ok := r.ToTarget(50, 50)
+	require.True(t, ok)
+	require.Equal(t, image.Pt(20, 10), image.Pt(x, y))
+	x, y, ok = r.ToTarget(99, 74)
+	require.True(t, ok)
+	require.Equal(t, image.Pt(39, 19), image.Pt(x, y))
+	for _, p := range []image.Point{{50, 10}, {50, 24}, {50, 75}} {
+		_, _, ok := r.ToTarget(p.X, p.Y)
+		require.False(t, ok, p)
+	}
+}
+
+func TestRenderTargetPress(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	r := &RenderTarget{Image: ebiten.NewImage(40, 20), KeepAspectRatio: true}
+	root := &View{Width: 100, Height: 100}
+	root.AddChild(&View{Width: 100, Height: 100, Handler: r})
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	var pressed, released []image.Point
+	cancelled := false
+	r.OnPress = func(x, y int, _ ebiten.TouchID) { pressed = append(pressed, image.Pt(x, y)) }
+	r.OnRelease = func(x, y int, isCancel bool) {
+		released = append(released, image.Pt(x, y))
+		cancelled = isCancel
+	}
+	click := func(x, y int) {
+		mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+		tick(InputState{CursorX: x, CursorY: y, MouseButtons: mouse})
+		tick(InputState{CursorX: x, CursorY: y})
+	}
+	tick(InputState{})
+	root.Draw(ebiten.NewImage(100, 100))
+
+	// the presses are routed in the coordinates of the image
+	click(25, 30)
+	require.Equal(t, []image.Point{{10, 2}}, pressed)
+	require.Equal(t, []image.Point{{10, 2}}, released)
+	require.False(t, cancelled)
+
+	// the letterbox bars are outside of the image
+	click(50, 10)
+	require.Len(t, pressed, 1)
+	require.Len(t, released, 2)
+	require.True(t, cancelled)
+}
@@ -0,0 +1,339 @@
+package furex
+
+import (
+	"image"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Reorderable is the handler of a view whose children are reordered by
+// dragging them, e.g. in loadout and priority order screens. A child is
+// grabbed by its handle, a descendant with the drag-handle attribute, or
+// by a long press anywhere on it if it has no handle. The dragged child
+// follows the pointer along the direction of the view, the other children
+// slide out of its way, and the child is moved into the gap when it is
+// dropped:
+//
+//	<view id="skills" style="direction: column">
+//		<view for="skill in skills" key="skill.ID" class="slot">
+//			<view class="grip" drag-handle></view>
+//			<text>{{skill.Name}}</text>
+//		</view>
+//	</view>
+//
+//	skills.Handler = &furex.Reorderable{
+//		OnReorder: func(from, to int) { game.MoveSkill(from, to) },
+//	}
+type Reorderable struct {
+	// OnReorder is called with the old and the new index of the dropped
+	// child, after it is moved. The children of a for attribute with a key
+	// follow the data, so the items should be moved the same way.
+	OnReorder func(from, to int)
+	// Duration is the duration of the children sliding. 150ms is used if
+	// it is 0.
+	Duration time.Duration
+
+	drag *reorderDrag
+}
+
+var _ Updater = (*Reorderable)(nil)
+
+const defaultReorderDuration = 150 * time.Millisecond
+
+// reorderDrag is a child pressed or dragged in a Reorderable.
+type reorderDrag struct {
+	child         *View
+	index, target int
+	touch         ebiten.TouchID
+	// start is the position of the press in the view.
+	startX, startY int
+	// ticks is the duration of the press before the drag.
+	ticks    int
+	dragging bool
+	// dropping is true while the dropped child slides into the gap.
+	dropping bool
+	// offsets are the offsets of the children along the direction.
+	offsets map[*View]*Tween
+	// transforms are the transforms of the children before the drag.
+	transforms map[*View]*Transform
+}
+
+// Dragging returns true while a child is dragged.
+func (r *Reorderable) Dragging() bool {
+	return r.drag != nil && r.drag.dragging
+}
+
+// Update implements Updater.
+func (r *Reorderable) Update(v *View) {
+	d := r.drag
+	if d == nil {
+		if !v.Disabled && !v.isBelowModal() {
+			r.press(v)
+		}
+		return
+	}
+	if d.dropping {
+		if r.animate(v) {
+			r.commit(v)
+		}
+		return
+	}
+	x, y, pressed := pointerPosition(d.touch)
+	if !pressed {
+		if d.dragging {
+			r.drop(v)
+		} else {
+			r.drag = nil
+		}
+		return
+	}
+	x, y = reorderPoint(v, x, y)
+	if !d.dragging {
+		if absInt(x-d.startX) > scrollDragThreshold || absInt(y-d.startY) > scrollDragThreshold {
+			// the pointer moved before a long press, e.g. to scroll
+			r.drag = nil
+			return
+		}
+		if d.ticks++; d.ticks < tps()/2 {
+			return
+		}
+		r.start(v)
+	}
+	delta := y - d.startY
+	if v.Direction == Row {
+		delta = x - d.startX
+	}
+	d.target = r.targetIndex(v, delta)
+	for i, c := range v.children {
+		if c.item != d.child {
+			r.slide(c.item, r.childOffset(v, i))
+		}
+	}
+	d.offsets[d.child] = &Tween{From: float64(delta), To: float64(delta)}
+	r.animate(v)
+}
+
+// press starts tracking a press on a child, by its handle or for a long
+// press if it has no handle.
+func (r *Reorderable) press(v *View) {
+	grab := func(touch ebiten.TouchID, x, y int) bool {
+		x, y = reorderPoint(v, x, y)
+		for i, c := range v.children {
+			cv := c.item
+			if cv.Hidden || !cv.isDisplayed() || cv.Disabled || !isInside(&cv.frame, x, y) {
+				continue
+			}
+			handles := cv.dragHandles(nil)
+			onHandle := false
+			for _, h := range handles {
+				onHandle = onHandle || isInside(&h.frame, x, y)
+			}
+			if len(handles) > 0 && !onHandle {
+				return false
+			}
+			r.drag = &reorderDrag{child: cv, index: i, target: i, touch: touch, startX: x, startY: y}
+			if onHandle {
+				r.start(v)
+			}
+			return true
+		}
+		return false
+	}
+	if isMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := cursorPosition()
+		if grab(-1, x, y) {
+			return
+		}
+	}
+	for _, id := range appendJustPressedTouchIDs(nil) {
+		x, y := currentTouchPosition(id)
+		if grab(id, x, y) {
+			return
+		}
+	}
+}
+
+// start starts dragging the pressed child.
+func (r *Reorderable) start(v *View) {
+	d := r.drag
+	d.dragging = true
+	d.offsets = map[*View]*Tween{}
+	d.transforms = map[*View]*Transform{}
+	for _, c := range v.children {
+		d.transforms[c.item] = c.item.Transform
+	}
+	// the scroll views do not scroll with the drag, and the buttons in the
+	// children are not clicked
+	scrollDrags[d.touch] = v
+	v.cancelPresses()
+	v.raised = d.child
+}
+
+// drop slides the dragged child into the gap.
+func (r *Reorderable) drop(v *View) {
+	d := r.drag
+	d.dropping = true
+	r.slide(d.child, r.childOffset(v, d.index))
+}
+
+// commit moves the dropped child to its new index and calls OnReorder.
+func (r *Reorderable) commit(v *View) {
+	d := r.drag
+	r.drag = nil
+	v.raised = nil
+	for _, c := range v.children {
+		if t, ok := d.transforms[c.item]; ok {
+			c.item.Transform = t
+		}
+		// the children are already drawn at their new frames
+		c.item.flip = nil
+	}
+	if d.index == d.target || d.index >= len(v.children) || v.children[d.index].item != d.child {
+		return
+	}
+	c := v.children[d.index]
+	children := append(v.children[:d.index:d.index], v.children[d.index+1:]...)
+	children = append(children[:d.target], append([]*child{c}, children[d.target:]...)...)
+	v.children = children
+	v.Layout()
+	if r.OnReorder != nil {
+		r.OnReorder(d.index, d.target)
+	}
+}
+
+// targetIndex returns the index the dragged child moves to when it is
+// dragged by delta pixels: past the centers of the other children.
+func (r *Reorderable) targetIndex(v *View, delta int) int {
+	d := r.drag
+	center := mainCenter(v, d.child.frame) + delta
+	target := d.index
+	for i, c := range v.children {
+		if c.item == d.child || !c.item.isDisplayed() {
+			continue
+		}
+		mid := mainCenter(v, c.item.frame)
+		if i > d.index && center > mid || i < d.index && center < mid && i < target {
+			target = i
+		}
+	}
+	return target
+}
+
+// childOffset returns the offset of the child at the index for the dragged
+// child at the target index: the children between them make room for it,
+// and it takes the room of those children.
+func (r *Reorderable) childOffset(v *View, i int) int {
+	d := r.drag
+	span := mainSpan(v, d.child)
+	switch {
+	case i == d.index:
+		offset := 0
+		for j := d.index + 1; j <= d.target; j++ {
+			offset += mainSpan(v, v.children[j].item)
+		}
+		for j := d.target; j < d.index; j++ {
+			offset -= mainSpan(v, v.children[j].item)
+		}
+		return offset
+	case i > d.index && i <= d.target:
+		return -span
+	case i < d.index && i >= d.target:
+		return span
+	}
+	return 0
+}
+
+// slide starts moving the child to the offset unless it is moving there.
+func (r *Reorderable) slide(cv *View, offset int) {
+	d := r.drag
+	t, ok := d.offsets[cv]
+	if ok && t.To == float64(offset) {
+		return
+	}
+	from := 0.0
+	if ok {
+		from = t.Value()
+	}
+	duration := r.Duration
+	if duration == 0 {
+		duration = defaultReorderDuration
+	}
+	d.offsets[cv] = &Tween{From: from, To: float64(offset), Duration: duration, Easing: "ease"}
+}
+
+// animate advances the offsets of the children and returns true when they
+// have all arrived.
+func (r *Reorderable) animate(v *View) bool {
+	d := r.drag
+	done := true
+	for cv, t := range d.offsets {
+		offset := t.Update()
+		done = done && t.Done()
+		tr := Transform{}
+		if base := d.transforms[cv]; base != nil {
+			tr = *base
+		}
+		if v.Direction == Row {
+			tr.TranslateX += offset
+		} else {
+			tr.TranslateY += offset
+		}
+		cv.Transform = &tr
+	}
+	return done
+}
+
+// dragHandles appends the drag handles in the subtree of the view.
+func (v *View) dragHandles(handles []*View) []*View {
+	if _, ok := v.Attrs["drag-handle"]; ok {
+		handles = append(handles, v)
+	}
+	for _, c := range v.children {
+		handles = c.item.dragHandles(handles)
+	}
+	return handles
+}
+
+// reorderPoint maps a point on the screen to the frames of the children of
+// the view.
+func reorderPoint(v *View, x, y int) (int, int) {
+	x, y = v.fromScreen(x, y)
+	o := v.ScrollOffset()
+	return x + o.X, y + o.Y
+}
+
+// pointerPosition returns the position of the pointer and whether it is
+// still pressed, the mouse being -1.
+func pointerPosition(touch ebiten.TouchID) (int, int, bool) {
+	if touch == -1 {
+		x, y := cursorPosition()
+		return x, y, isMouseButtonPressed(ebiten.MouseButtonLeft)
+	}
+	if !touchPressed(touch) {
+		return 0, 0, false
+	}
+	x, y := currentTouchPosition(touch)
+	return x, y, true
+}
+
+// mainCenter returns the center of the frame along the direction of the
+// view.
+func mainCenter(v *View, frame image.Rectangle) int {
+	if v.Direction == Row {
+		return (frame.Min.X + frame.Max.X) / 2
+	}
+	return (frame.Min.Y + frame.Max.Y) / 2
+}
+
+// mainSpan returns the room the child takes along the direction of the
+// view, with its margins.
+func mainSpan(v *View, cv *View) int {
+	if !cv.isDisplayed() {
+		return 0
+	}
+	if v.Direction == Row {
+		return cv.frame.Dx() + cv.MarginLeft + cv.MarginRight
+	}
+	return cv.frame.Dy() + cv.MarginTop + cv.MarginBottom
+}
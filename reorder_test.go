@@ -0,0 +1,124 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderable(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 100px; height: 200px; direction: column; justify-content: flex-start">
+		<view id="a" style="width: 100px; height: 20px"></view>
+		<view id="b" style="width: 100px; height: 20px"></view>
+		<view id="c" style="width: 100px; height: 20px"></view>
+		<view id="d" style="width: 100px; height: 20px"></view>
+	</view>`, nil)
+	var moves [][2]int
+	r := &Reorderable{OnReorder: func(from, to int) { moves = append(moves, [2]int{from, to}) }}
+	root.Handler = r
+	mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	ids := func() []string {
+		var ret []string
+		for _, c := range root.children {
+			ret = append(ret, c.item.ID)
+		}
+		return ret
+	}
+	tick(InputState{})
+
+	// a long press grabs a child without a handle
+	for i := 0; i <= 30; i++ {
+		tick(InputState{CursorX: 50, CursorY: 10, MouseButtons: mouse})
+	}
+	require.True(t, r.Dragging())
+
+	// the dragged child follows the pointer and the others make room for it
+	for y := 10; y <= 55; y += 5 {
+		tick(InputState{CursorX: 50, CursorY: y, MouseButtons: mouse})
+	}
+	for i := 0; i < 10; i++ {
+		tick(InputState{CursorX: 50, CursorY: 55, MouseButtons: mouse})
+	}
+	a := root.MustGetByID("a")
+	require.Equal(t, 45.0, a.Transform.TranslateY)
+	require.Equal(t, -20.0, root.MustGetByID("b").Transform.TranslateY)
+	require.Equal(t, -20.0, root.MustGetByID("c").Transform.TranslateY)
+	require.Equal(t, 0.0, root.MustGetByID("d").Transform.TranslateY)
+	require.Same(t, a, root.raised)
+	require.Equal(t, []string{"a", "b", "c", "d"}, ids())
+
+	// the dropped child moves into the gap
+	for i := 0; i < 20; i++ {
+		tick(InputState{CursorX: 50, CursorY: 55})
+	}
+	require.False(t, r.Dragging())
+	require.Equal(t, []string{"b", "c", "a", "d"}, ids())
+	require.Equal(t, [][2]int{{0, 2}}, moves)
+	require.Nil(t, a.Transform)
+	require.Nil(t, root.raised)
+	root.Update()
+	require.Equal(t, 40, a.frame.Min.Y)
+
+	// the pointer moving before a long press does not drag
+	tick(InputState{CursorX: 50, CursorY: 70, MouseButtons: mouse})
+	for y := 70; y >= 30; y -= 5 {
+		tick(InputState{CursorX: 50, CursorY: y, MouseButtons: mouse})
+	}
+	require.False(t, r.Dragging())
+	tick(InputState{})
+	require.Len(t, moves, 1)
+}
+
+func TestReorderableHandle(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 20px; justify-content: flex-start">
+		<view id="a" style="width: 50px; height: 20px">
+			<view drag-handle style="width: 10px; height: 20px"></view>
+			<view id="button" style="width: 40px; height: 20px"></view>
+		</view>
+		<view id="b" style="width: 50px; height: 20px"></view>
+		<view id="c" style="width: 50px; height: 20px"></view>
+	</view>`, nil)
+	button := &mockHandler{}
+	root.MustGetByID("button").Handler = button
+	var moves [][2]int
+	r := &Reorderable{OnReorder: func(from, to int) { moves = append(moves, [2]int{from, to}) }}
+	root.Handler = r
+	mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	tick(InputState{})
+
+	// a child with a handle is not grabbed out of the handle
+	for i := 0; i < 40; i++ {
+		tick(InputState{CursorX: 30, CursorY: 10, MouseButtons: mouse})
+	}
+	require.False(t, r.Dragging())
+	tick(InputState{CursorX: 30, CursorY: 10})
+	require.True(t, button.IsReleased)
+	require.False(t, button.IsCancel)
+
+	// and is grabbed at once by the handle, along the row
+	tick(InputState{CursorX: 5, CursorY: 10, MouseButtons: mouse})
+	require.True(t, r.Dragging())
+	for x := 5; x <= 120; x += 10 {
+		tick(InputState{CursorX: x, CursorY: 10, MouseButtons: mouse})
+	}
+	for i := 0; i < 20; i++ {
+		tick(InputState{CursorX: 120, CursorY: 10})
+	}
+	require.Equal(t, [][2]int{{0, 2}}, moves)
+	require.Equal(t, "a", root.children[2].item.ID)
+}
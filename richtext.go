@@ -0,0 +1,635 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/net/html"
+)
+
+// RichText is a handler that draws View.Text as inline markup: text with
+// <a href="..."> links, <img src="..."> images and <br> line breaks,
+// wrapped at the width of the frame. Images and emoji are drawn at the
+// line height keeping their aspect ratio, and wrap like words. The links are hit-tested run by run, and OnLink is called with
+// the href of the link pressed and released, e.g. a player id in a chat
+// message. Presses outside the links are not handled.
+//
+// The texts inside <span color="..."> (or <font color="...">) are drawn
+// in the color, over the color of the links, and the texts inside <b> and
+// <strong> in bold, e.g. for damage numbers and key prompts such as
+// <b><span color="#ff0">+10</span></b>. The texts inside <mark> are drawn
+// over MarkColor, e.g. for the matches of a search (see HighlightMatches).
+//
+// The characters inside <wave>, <shake> and <rainbow> are animated
+// one by one (see TextEffect), e.g. for dialogues. Ruby annotations such
+// as furigana (<ruby>漢字<rt>かんじ</rt></ruby>) are drawn above their base
+// text, the lines leaving room for them.
+//
+// In HTML, the markup inside a <rich-text> element is kept as its text.
+type RichText struct {
+	// Text has the face and the color of the text.
+	Text
+	// LinkColor is the color of the links. A light blue is used if it is nil.
+	LinkColor color.Color
+	// OnLink is called when a link is clicked or tapped.
+	OnLink func(href string)
+	// Images returns the images of <img> tags. The images are resolved
+	// like background images of the document if it is nil.
+	Images ImageResolver
+	// Emoji returns the image drawn for a rune of the text, or nil to
+	// draw the rune with the font, e.g. for emoji missing in the font.
+	Emoji func(r rune) *ebiten.Image
+	// RubyFace is the face of ruby annotations. If it is nil, the face of
+	// FaceFunc at half the font size is used, or the face of the text
+	// scaled by half.
+	RubyFace font.Face
+	// BoldFace is the face of bold texts. If it is nil, bold texts are
+	// drawn twice, one pixel apart.
+	BoldFace font.Face
+	// MarkColor is the background of the texts inside <mark>. A
+	// translucent yellow is used if it is nil.
+	MarkColor color.Color
+
+	source string
+	spans  []richSpan
+	// tick animates the text effects.
+	tick int
+	// runs are the runs drawn last time, to hit-test the links.
+	runs []richRun
+	// pressed is the link pressed by the mouse or a touch.
+	pressed    string
+	hasPressed bool
+	touchID    ebiten.TouchID
+}
+
+var _ Drawer = (*RichText)(nil)
+var _ Updater = (*RichText)(nil)
+var _ MouseLeftButtonHandler = (*RichText)(nil)
+var _ TouchHandler = (*RichText)(nil)
+var _ HeightMeasurer = (*RichText)(nil)
+
+// richSpan is a piece of text of the markup. It is a line break if
+// the text is "\n".
+type richSpan struct {
+	text string
+	href string
+	link bool
+	// src is the path of an image; image is the image resolved.
+	src   string
+	image *ebiten.Image
+	// effects are the text effects of the span.
+	effects TextEffect
+	// ruby is the ruby annotation of the text.
+	ruby string
+	// color is the color of the text, or nil for the color of the view.
+	color color.Color
+	bold  bool
+	mark  bool
+	// face is the face of the bold text, or nil for the face of the view.
+	face font.Face
+}
+
+// richRun is a run of text of a span drawn on a line.
+type richRun struct {
+	textRun
+	bounds  image.Rectangle
+	href    string
+	link    bool
+	image   *ebiten.Image
+	effects TextEffect
+	ruby    string
+	color   color.Color
+	bold    bool
+	mark    bool
+	face    font.Face
+}
+
+var defaultLinkColor = color.RGBA{0x66, 0xb3, 0xff, 0xff}
+
+var defaultMarkColor = color.RGBA{0xcc, 0xaa, 0x00, 0x99}
+
+// Draw implements Drawer.
+func (r *RichText) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	face, opacity := r.face(v), v.EffectiveOpacity()
+	lineHeight := face.Metrics().Height.Ceil()
+	ruby := r.parse(v, face)
+	r.runs = layoutRichText(r.spans, face, ruby, frame)
+	r.drawMarks(screen, opacity)
+	r.keys = r.keys[:0]
+	for _, run := range r.runs {
+		if run.image != nil {
+			drawInlineImage(screen, run.image, run.bounds, opacity)
+			continue
+		}
+		clr := r.color(v)
+		if run.link {
+			clr = r.LinkColor
+			if clr == nil {
+				clr = defaultLinkColor
+			}
+		}
+		if run.color != nil {
+			clr = run.color
+		}
+		if run.ruby != "" {
+			ruby.draw(screen, run, clr, opacity)
+		}
+		runFace := face
+		if run.face != nil {
+			runFace = run.face
+		}
+		// faux bold is drawn again one pixel to the right
+		times := 1
+		if run.bold && run.face == nil {
+			times = 2
+		}
+		for i := 0; i < times; i++ {
+			if run.effects != 0 {
+				drawEffectText(screen, run, runFace, lineHeight, r.tick, clr, opacity)
+			} else {
+				r.keys = append(r.keys, newTextKey(runFace, run.text, clr))
+				drawText(screen, run.text, runFace, run.x, run.y, clr, opacity)
+			}
+			run.x++
+		}
+	}
+	r.holdKeys()
+}
+
+// drawMarks draws the backgrounds of the marked runs.
+func (r *RichText) drawMarks(screen *ebiten.Image, opacity float64) {
+	clr := r.MarkColor
+	if clr == nil {
+		clr = defaultMarkColor
+	}
+	marked := false
+	for _, run := range r.runs {
+		if run.mark {
+			batch.FillRect(screen, run.bounds, fade(clr, opacity))
+			marked = true
+		}
+	}
+	if marked {
+		batch.Flush()
+	}
+}
+
+// MeasureHeight implements HeightMeasurer. It returns the height of the
+// markup wrapped at the width.
+func (r *RichText) MeasureHeight(v *View, width int) int {
+	face := r.face(v)
+	ruby := r.parse(v, face)
+	height := 0
+	for _, run := range layoutRichText(r.spans, face, ruby, image.Rect(0, 0, width, 0)) {
+		if run.bounds.Max.Y > height {
+			height = run.bounds.Max.Y
+		}
+	}
+	return height
+}
+
+// parse parses the markup of the view if it has changed, and returns
+// the face of the ruby annotations if the markup has any.
+func (r *RichText) parse(v *View, face font.Face) *rubyFace {
+	if r.source != v.Text || r.spans == nil {
+		r.source, r.spans = v.Text, r.resolveImages(v, splitEmoji(parseRichText(v.Text), r.Emoji))
+	}
+	for i := range r.spans {
+		if r.spans[i].bold {
+			r.spans[i].face = r.BoldFace
+		}
+	}
+	if hasRuby(r.spans) {
+		return r.rubyFace(v, face)
+	}
+	return nil
+}
+
+// Update implements Updater.
+func (r *RichText) Update(v *View) {
+	r.tick++
+}
+
+// LinkAt returns the href of the link drawn at (x, y), if any.
+func (r *RichText) LinkAt(x, y int) (string, bool) {
+	p := image.Pt(x, y)
+	for _, run := range r.runs {
+		if run.link && p.In(run.bounds) {
+			return run.href, true
+		}
+	}
+	return "", false
+}
+
+// HandleJustPressedMouseButtonLeft implements MouseLeftButtonHandler.
+func (r *RichText) HandleJustPressedMouseButtonLeft(x, y int) bool {
+	return r.press(x, y, -1)
+}
+
+// HandleJustReleasedMouseButtonLeft implements MouseLeftButtonHandler.
+func (r *RichText) HandleJustReleasedMouseButtonLeft(x, y int) {
+	r.release(x, y)
+}
+
+// HandleJustPressedTouchID implements TouchHandler.
+func (r *RichText) HandleJustPressedTouchID(touch ebiten.TouchID, x, y int) bool {
+	return r.press(x, y, touch)
+}
+
+// HandleJustReleasedTouchID implements TouchHandler.
+func (r *RichText) HandleJustReleasedTouchID(touch ebiten.TouchID, x, y int) {
+	if touch == r.touchID {
+		r.release(x, y)
+	}
+}
+
+func (r *RichText) press(x, y int, touch ebiten.TouchID) bool {
+	href, ok := r.LinkAt(x, y)
+	if !ok || r.hasPressed {
+		return false
+	}
+	r.pressed, r.hasPressed, r.touchID = href, true, touch
+	return true
+}
+
+// release calls OnLink if the link pressed is released on it.
+func (r *RichText) release(x, y int) {
+	if !r.hasPressed {
+		return
+	}
+	pressed := r.pressed
+	r.pressed, r.hasPressed = "", false
+	if href, ok := r.LinkAt(x, y); ok && href == pressed && r.OnLink != nil {
+		r.OnLink(href)
+	}
+}
+
+// parseRichText parses the markup into spans. Tags other than <a>, <img>,
+// <br>, <ruby>, <span>, <font>, <b>, <strong> and the text effects are
+// ignored but their text is kept.
+func parseRichText(markup string) []richSpan {
+	spans := []richSpan{}
+	z := html.NewTokenizer(strings.NewReader(markup))
+	var href string
+	var link bool
+	// effects counts the open tags of the effects.
+	effects := map[TextEffect]int{}
+	current := func() TextEffect {
+		var e TextEffect
+		for k, n := range effects {
+			if n > 0 {
+				e |= k
+			}
+		}
+		return e
+	}
+	// colors are the colors of the open <span> and <font> tags, nil if
+	// they have none; bold counts the open <b> and <strong> tags.
+	var colors []color.Color
+	bold, mark := 0, 0
+	// span returns the span of the text with the current styles.
+	span := func(text string) richSpan {
+		s := richSpan{text: text, href: href, link: link, effects: current(), bold: bold > 0, mark: mark > 0}
+		for i := len(colors) - 1; i >= 0 && s.color == nil; i-- {
+			s.color = colors[i]
+		}
+		return s
+	}
+	// base is the base text of the ruby element before the <rt> elements.
+	base := &strings.Builder{}
+	var inRuby, inRt, inRp bool
+	annotation := &strings.Builder{}
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			// the tokenizer stops at the end or at invalid markup
+			return spans
+		case html.TextToken:
+			switch {
+			case inRp:
+				// the parentheses for renderers without ruby support
+			case inRt:
+				annotation.Write(z.Text())
+			case inRuby:
+				base.Write(z.Text())
+			default:
+				spans = append(spans, span(string(z.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tn, hasAttr := z.TagName()
+			if e, ok := textEffectTags[string(tn)]; ok && tt == html.StartTagToken {
+				effects[e]++
+				continue
+			}
+			switch string(tn) {
+			case "ruby":
+				inRuby = true
+			case "rt":
+				inRt = inRuby
+			case "rp":
+				inRp = inRuby
+			case "br":
+				spans = append(spans, richSpan{text: "\n"})
+			case "img":
+				span := richSpan{href: href, link: link}
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					if string(k) == "src" {
+						span.src = string(v)
+					}
+				}
+				spans = append(spans, span)
+			case "a":
+				href, link = "", true
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					if string(k) == "href" {
+						href = string(v)
+					}
+				}
+			case "span", "font":
+				if tt == html.SelfClosingTagToken {
+					continue
+				}
+				var clr color.Color
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					if string(k) != "color" {
+						continue
+					}
+					c, err := parseColor(string(v))
+					if err != nil {
+						println(fmt.Sprintf("rich-text: %v", err))
+						continue
+					}
+					clr = c.(color.Color)
+				}
+				colors = append(colors, clr)
+			case "b", "strong":
+				if tt == html.StartTagToken {
+					bold++
+				}
+			case "mark":
+				if tt == html.StartTagToken {
+					mark++
+				}
+			}
+		case html.EndTagToken:
+			tn, _ := z.TagName()
+			if e, ok := textEffectTags[string(tn)]; ok && effects[e] > 0 {
+				effects[e]--
+			}
+			switch string(tn) {
+			case "a":
+				href, link = "", false
+			case "span", "font":
+				if len(colors) > 0 {
+					colors = colors[:len(colors)-1]
+				}
+			case "b", "strong":
+				if bold > 0 {
+					bold--
+				}
+			case "mark":
+				if mark > 0 {
+					mark--
+				}
+			case "rt":
+				if inRt && base.Len() > 0 {
+					s := span(base.String())
+					s.ruby = annotation.String()
+					spans = append(spans, s)
+				}
+				inRt = false
+				base.Reset()
+				annotation.Reset()
+			case "rp":
+				inRp = false
+			case "ruby":
+				if base.Len() > 0 {
+					spans = append(spans, span(base.String()))
+				}
+				inRuby, inRt, inRp = false, false, false
+				base.Reset()
+				annotation.Reset()
+			}
+		}
+	}
+}
+
+// layoutRichText wraps the spans at the width of the frame and returns
+// the runs with their bounds. Consecutive words of a span on a line are
+// drawn as a single run. The lines leave room above for the annotations
+// of ruby if it is not nil.
+func layoutRichText(spans []richSpan, face font.Face, ruby *rubyFace, frame image.Rectangle) []richRun {
+	m := face.Metrics()
+	lineHeight, ascent := m.Height.Ceil(), m.Ascent.Ceil()
+	rubyHeight := 0
+	if ruby != nil {
+		rubyHeight = ruby.height()
+	}
+	// lineTop returns the top of the text of the line.
+	lineTop := func(line int) int {
+		return frame.Min.Y + line*(lineHeight+rubyHeight) + rubyHeight
+	}
+	space := font.MeasureString(face, " ").Ceil()
+	// measure returns the width of the text of the span, one pixel
+	// wider if it is faux bold.
+	measure := func(s richSpan, text string) int {
+		if s.face != nil {
+			return font.MeasureString(s.face, text).Ceil()
+		}
+		w := font.MeasureString(face, text).Ceil()
+		if s.bold {
+			w++
+		}
+		return w
+	}
+	var runs []richRun
+	x, line := 0, 0
+	// pendingSpace is true if a space separates the next word from the
+	// previous one.
+	pendingSpace := false
+	for _, s := range spans {
+		if s.text == "\n" {
+			x, line, pendingSpace = 0, line+1, false
+			continue
+		}
+		if s.ruby != "" {
+			// the base text and its annotation are not broken
+			bw := measure(s, s.text)
+			w := bw
+			if rw := ruby.width(s.ruby); rw > w {
+				w = rw
+			}
+			gap := 0
+			if pendingSpace && x > 0 {
+				gap = space
+			}
+			if x > 0 && x+gap+w > frame.Dx() {
+				x, line, gap = 0, line+1, 0
+			}
+			y := lineTop(line)
+			runs = append(runs, richRun{
+				textRun: textRun{text: s.text, x: frame.Min.X + x + gap + (w-bw)/2, y: y + ascent},
+				bounds:  image.Rect(frame.Min.X+x+gap, y, frame.Min.X+x+gap+w, y+lineHeight),
+				href:    s.href,
+				link:    s.link,
+				effects: s.effects,
+				ruby:    s.ruby,
+				color:   s.color,
+				bold:    s.bold,
+				mark:    s.mark,
+				face:    s.face,
+			})
+			x += gap + w
+			pendingSpace = false
+			continue
+		}
+		if s.src != "" || s.image != nil {
+			if s.image == nil {
+				continue
+			}
+			size := s.image.Bounds().Size()
+			iw := int(math.Round(float64(size.X) * float64(lineHeight) / float64(size.Y)))
+			gap := 0
+			if pendingSpace && x > 0 {
+				gap = space
+			}
+			if x > 0 && x+gap+iw > frame.Dx() {
+				x, line, gap = 0, line+1, 0
+			}
+			y := lineTop(line)
+			runs = append(runs, richRun{
+				bounds: image.Rect(frame.Min.X+x+gap, y, frame.Min.X+x+gap+iw, y+lineHeight),
+				href:   s.href,
+				link:   s.link,
+				image:  s.image,
+			})
+			x += gap + iw
+			pendingSpace = false
+			continue
+		}
+		if s.text != "" && isSpace(s.text[0]) {
+			pendingSpace = true
+		}
+		words := strings.Fields(s.text)
+		var cur *richRun
+		for _, w := range words {
+			ww := measure(s, w)
+			gap := 0
+			if pendingSpace && x > 0 {
+				gap = space
+			}
+			if x > 0 && x+gap+ww > frame.Dx() {
+				x, line, gap, cur = 0, line+1, 0, nil
+			}
+			y := lineTop(line)
+			if cur != nil {
+				// the word continues the run of the span on the line
+				cur.text += " " + w
+				cur.bounds.Max.X = cur.x + measure(s, cur.text)
+			} else {
+				runs = append(runs, richRun{
+					textRun: textRun{text: w, x: frame.Min.X + x + gap, y: y + ascent},
+					bounds:  image.Rect(frame.Min.X+x+gap, y, frame.Min.X+x+gap+ww, y+lineHeight),
+					href:    s.href,
+					link:    s.link,
+					effects: s.effects,
+					color:   s.color,
+					bold:    s.bold,
+					mark:    s.mark,
+					face:    s.face,
+				})
+				cur = &runs[len(runs)-1]
+			}
+			x += gap + ww
+			pendingSpace = true
+		}
+		if s.text != "" && isSpace(s.text[len(s.text)-1]) {
+			pendingSpace = true
+		} else if len(words) > 0 {
+			pendingSpace = false
+		}
+	}
+	return runs
+}
+
+// resolveImages resolves the images of the spans.
+func (r *RichText) resolveImages(v *View, spans []richSpan) []richSpan {
+	for i, s := range spans {
+		if s.src == "" {
+			continue
+		}
+		switch {
+		case r.Images != nil:
+			spans[i].image = r.Images(s.src)
+		case v.style != nil && v.style.images != nil:
+			img, err := v.style.images.load(s.src)
+			if err != nil {
+				println(fmt.Sprintf("rich-text: %v", err))
+			}
+			spans[i].image = img
+		}
+	}
+	return spans
+}
+
+// splitEmoji splits the text of the spans at the runes with an image.
+func splitEmoji(spans []richSpan, emoji func(r rune) *ebiten.Image) []richSpan {
+	if emoji == nil {
+		return spans
+	}
+	var ret []richSpan
+	for _, s := range spans {
+		if s.text == "" || s.text == "\n" || s.ruby != "" {
+			ret = append(ret, s)
+			continue
+		}
+		start := 0
+		for i, c := range s.text {
+			img := emoji(c)
+			if img == nil {
+				continue
+			}
+			if i > start {
+				t := s
+				t.text = s.text[start:i]
+				ret = append(ret, t)
+			}
+			ret = append(ret, richSpan{href: s.href, link: s.link, image: img})
+			start = i + utf8.RuneLen(c)
+		}
+		if start < len(s.text) {
+			t := s
+			t.text = s.text[start:]
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// drawInlineImage draws the image scaled to the bounds.
+func drawInlineImage(screen, img *ebiten.Image, bounds image.Rectangle, opacity float64) {
+	bounds = targetRect(bounds)
+	size := img.Bounds().Size()
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(float64(bounds.Dx())/float64(size.X), float64(bounds.Dy())/float64(size.Y))
+	op.GeoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y))
+	op.ColorScale.ScaleAlpha(float32(opacity))
+	screen.DrawImage(img, op)
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
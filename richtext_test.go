@@ -0,0 +1,153 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestParseRichText(t *testing.T) {
+	require.Equal(t, []richSpan{
+		{text: "Hi "},
+		{text: "Alice", href: "player:1", link: true},
+		{text: "!"},
+		{text: "\n"},
+		{text: "bold", href: ""},
+	}, parseRichText(`Hi <a href="player:1">Alice</a>!<br>bold`))
+}
+
+func TestLayoutRichText(t *testing.T) {
+	// 7px wide glyphs, 13px lines
+	face := basicfont.Face7x13
+	spans := parseRichText(`ab <a href="x">cd ef</a>gh ij<br>k`)
+	runs := layoutRichText(spans, face, nil, image.Rect(0, 0, 70, 100))
+	var texts []string
+	for _, r := range runs {
+		texts = append(texts, r.text)
+	}
+	// "ab cd efgh" fits in 10 glyphs; "ij" wraps
+	require.Equal(t, []string{"ab", "cd ef", "gh", "ij", "k"}, texts)
+	require.Equal(t, image.Rect(21, 0, 56, 13), runs[1].bounds)
+	require.Equal(t, image.Rect(56, 0, 70, 13), runs[2].bounds)
+	require.Equal(t, image.Rect(0, 13, 14, 26), runs[3].bounds)
+	require.Equal(t, 26+11, runs[4].y)
+}
+
+func TestRichTextLinks(t *testing.T) {
+	view := Parse(`<view style="width: 200px; height: 100px">
+		<rich-text id="msg" style="width: 200px; height: 40px">
+			Thanks <a href="player:42">Alice</a> and <a href="player:7">Bob</a>
+		</rich-text>
+	</view>`, nil)
+	msg := view.MustGetByID("msg")
+	require.Equal(t, `Thanks <a href="player:42">Alice</a> and <a href="player:7">Bob</a>`, msg.Text)
+	require.Empty(t, msg.children)
+
+	rt := msg.Handler.(*RichText)
+	var clicked []string
+	rt.OnLink = func(href string) { clicked = append(clicked, href) }
+	view.Draw(ebiten.NewImage(200, 100))
+
+	// "Thanks " is 7 glyphs, Alice is from 49px to 84px
+	href, ok := rt.LinkAt(50, 5)
+	require.True(t, ok)
+	require.Equal(t, "player:42", href)
+	_, ok = rt.LinkAt(10, 5)
+	require.False(t, ok)
+
+	require.False(t, rt.HandleJustPressedMouseButtonLeft(10, 5))
+	require.True(t, rt.HandleJustPressedMouseButtonLeft(50, 5))
+	rt.HandleJustReleasedMouseButtonLeft(52, 6)
+	require.Equal(t, []string{"player:42"}, clicked)
+
+	// released on another link
+	require.True(t, rt.HandleJustPressedTouchID(1, 50, 5))
+	rt.HandleJustReleasedTouchID(1, 130, 5)
+	require.Equal(t, []string{"player:42"}, clicked)
+}
+
+func TestRichTextLineBreak(t *testing.T) {
+	// <br> has no end tag, so the markup ends at </rich-text>
+	view := Parse(`<view id="root">
+		<rich-text id="msg">first<br>second</rich-text>
+		<view id="after"></view>
+	</view>`, nil)
+	require.Equal(t, "first<br>second", view.MustGetByID("msg").Text)
+	require.Equal(t, view, view.MustGetByID("after").parent)
+	require.Len(t, view.getChildren(), 2)
+}
+
+func TestRichTextImages(t *testing.T) {
+	// 7px wide glyphs, 13px lines; the coin is scaled to 13px high
+	face := basicfont.Face7x13
+	coin := ebiten.NewImage(20, 26)
+	star := ebiten.NewImage(26, 26)
+	emoji := func(r rune) *ebiten.Image {
+		if r == '★' {
+			return star
+		}
+		return nil
+	}
+	spans := splitEmoji(parseRichText(`ab<img src="coin.png"> c★d`), emoji)
+	require.Equal(t, "coin.png", spans[1].src)
+	require.Equal(t, []richSpan{{text: " c"}, {image: star}, {text: "d"}}, spans[2:])
+	spans[1].image = coin
+
+	runs := layoutRichText(spans, face, nil, image.Rect(0, 0, 50, 100))
+	require.Len(t, runs, 5)
+	require.Equal(t, image.Rect(14, 0, 24, 13), runs[1].bounds)
+	require.Equal(t, coin, runs[1].image)
+	require.Equal(t, image.Rect(31, 0, 38, 13), runs[2].bounds)
+	// the star does not fit in the rest of the line and wraps like a word
+	require.Equal(t, image.Rect(0, 13, 13, 26), runs[3].bounds)
+
+	resolved := map[string]bool{}
+	view := Parse(`<view style="width: 100px; height: 100px">
+		<rich-text id="msg" style="width: 100px; height: 40px">Get <img src="coin.png"> x3</rich-text>
+		<view id="after"></view>
+	</view>`, &ParseOptions{ImageResolver: func(path string) *ebiten.Image {
+		resolved[path] = true
+		return coin
+	}})
+	view.Draw(ebiten.NewImage(100, 100))
+	require.True(t, resolved["coin.png"])
+	rt := view.MustGetByID("msg").Handler.(*RichText)
+	require.Equal(t, coin, rt.runs[1].image)
+	// <img> has no end tag, so the element after the rich text is its sibling
+	require.Equal(t, view, view.MustGetByID("after").parent)
+}
+
+func TestRichTextSpans(t *testing.T) {
+	yellow := color.NRGBA{255, 255, 0, 255}
+	spans := parseRichText(`Hit <span color="#ff0">+10 <b>crit</b></span> <strong>Press <font color="red">A</font></strong><span>!</span>`)
+	require.Equal(t, []richSpan{
+		{text: "Hit "},
+		{text: "+10 ", color: yellow},
+		{text: "crit", color: yellow, bold: true},
+		{text: " "},
+		{text: "Press ", bold: true},
+		{text: "A", color: color.RGBA{255, 0, 0, 255}, bold: true},
+		{text: "!"},
+	}, spans)
+
+	// faux bold texts are one pixel wider
+	face := basicfont.Face7x13
+	runs := layoutRichText(spans, face, nil, image.Rect(0, 0, 200, 100))
+	require.Equal(t, image.Rect(28, 0, 49, 13), runs[1].bounds)
+	require.Equal(t, image.Rect(56, 0, 85, 13), runs[2].bounds)
+	require.Equal(t, yellow, runs[2].color)
+	require.True(t, runs[2].bold)
+
+	// the words of a bold span in a run are measured together
+	runs = layoutRichText(parseRichText(`<b>ab cd</b>`), face, nil, image.Rect(0, 0, 200, 100))
+	require.Equal(t, image.Rect(0, 0, 36, 13), runs[0].bounds)
+
+	r := &RichText{BoldFace: face}
+	view := &View{Width: 200, Height: 20, Text: `<b>ab</b>`, Handler: r}
+	view.Draw(ebiten.NewImage(200, 20))
+	require.Equal(t, image.Rect(0, 0, 14, 13), r.runs[0].bounds)
+}
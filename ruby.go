@@ -0,0 +1,64 @@
+package furex
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// rubyFace is the face of the ruby annotations of rich text, drawn at
+// scale.
+type rubyFace struct {
+	face  font.Face
+	scale float64
+}
+
+// rubyFace returns the face of the annotations for the face of the text:
+// RubyFace, the face of FaceFunc at half the size, or the face of the
+// text scaled by half.
+func (r *RichText) rubyFace(v *View, face font.Face) *rubyFace {
+	if r.RubyFace != nil {
+		return &rubyFace{face: r.RubyFace, scale: 1}
+	}
+	if r.FaceFunc != nil {
+		size := int(math.Round(v.ComputedFontSize() / 2))
+		if size < 1 {
+			size = 1
+		}
+		return &rubyFace{face: r.FaceFunc(size), scale: 1}
+	}
+	return &rubyFace{face: face, scale: 0.5}
+}
+
+func (f *rubyFace) width(s string) int {
+	return int(math.Ceil(float64(font.MeasureString(f.face, s).Ceil()) * f.scale))
+}
+
+func (f *rubyFace) height() int {
+	return int(math.Ceil(float64(f.face.Metrics().Height.Ceil()) * f.scale))
+}
+
+// draw draws the annotation centered over the bounds of the base text,
+// which are below the annotation.
+func (f *rubyFace) draw(screen *ebiten.Image, run richRun, clr color.Color, opacity float64) {
+	x := run.bounds.Min.X + (run.bounds.Dx()-f.width(run.ruby))/2
+	ascent := int(math.Ceil(float64(f.face.Metrics().Ascent.Ceil()) * f.scale))
+	y := run.bounds.Min.Y - f.height() + ascent
+	if f.scale == 1 {
+		drawText(screen, run.ruby, f.face, x, y, clr, opacity)
+		return
+	}
+	drawTextScaled(screen, run.ruby, f.face, x, y, f.scale, clr, opacity)
+}
+
+// hasRuby returns true if a span has a ruby annotation.
+func hasRuby(spans []richSpan) bool {
+	for _, s := range spans {
+		if s.ruby != "" {
+			return true
+		}
+	}
+	return false
+}
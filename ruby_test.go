@@ -0,0 +1,41 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestParseRuby(t *testing.T) {
+	spans := parseRichText(`<ruby>漢<rt>kan</rt>字<rp>(</rp><rt>ji</rt><rp>)</rp></ruby> ok <ruby>x</ruby>`)
+	require.Equal(t, []richSpan{
+		{text: "漢", ruby: "kan"},
+		{text: "字", ruby: "ji"},
+		{text: " ok "},
+		{text: "x"},
+	}, spans)
+}
+
+func TestLayoutRuby(t *testing.T) {
+	face := basicfont.Face7x13
+	ruby := &rubyFace{face: face, scale: 0.5}
+	require.Equal(t, 7, ruby.height())
+	// "abcdef" is 42px at full size and 21px at half
+	spans := []richSpan{{text: "A", ruby: "abcdef"}, {text: "BC", ruby: "b"}, {text: " next"}}
+	runs := layoutRichText(spans, face, ruby, image.Rect(0, 0, 60, 100))
+	require.Len(t, runs, 3)
+	// the lines leave room for the annotations
+	require.Equal(t, image.Rect(0, 7, 21, 20), runs[0].bounds)
+	// the base text is centered under a wider annotation
+	require.Equal(t, 7, runs[0].x)
+	require.Equal(t, image.Rect(21, 7, 35, 20), runs[1].bounds)
+	require.Equal(t, 21, runs[1].x)
+	// "next" wraps to the second line
+	require.Equal(t, image.Rect(0, 27, 28, 40), runs[2].bounds)
+
+	// without annotations, the lines have no room above
+	runs = layoutRichText([]richSpan{{text: "A"}}, face, nil, image.Rect(0, 0, 60, 100))
+	require.Equal(t, image.Rect(0, 0, 7, 13), runs[0].bounds)
+}
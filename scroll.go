@@ -0,0 +1,373 @@
+package furex
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Scroll tunes the scrolling of a view whose overflow is scroll
+// (OverflowScroll). The content of such a view is dragged with the mouse
+// or a touch, moves on with momentum after a fling, decelerating, and is
+// scrolled by the mouse wheel. The view scrolls along the axes its content
+// overflows.
+type Scroll struct {
+	// Friction is the fraction of the velocity lost every tick while the
+	// content moves on after a fling, from 0 to 1. 0.05 is used if it is
+	// 0, and 1 stops the content when it is released.
+	Friction float64
+	// Bounce lets the content be dragged and flung past its edges with
+	// resistance, springing back when it is released (rubber-banding).
+	Bounce bool
+	// WheelStep is the distance scrolled by a step of the mouse wheel.
+	// 40 is used if it is 0.
+	WheelStep int
+}
+
+const (
+	defaultScrollFriction  = 0.05
+	defaultScrollWheelStep = 40
+	// scrollDragThreshold is the distance a pointer moves before it drags
+	// the content rather than pressing the views in it.
+	scrollDragThreshold = 8
+	// scrollMinVelocity is the velocity under which the content stops.
+	scrollMinVelocity = 0.1
+	// scrollResistance is the fraction of the drag moving the content
+	// past its edges.
+	scrollResistance = 0.5
+	// scrollSpring is the fraction of the distance past the edge the
+	// content springs back every tick.
+	scrollSpring = 0.2
+)
+
+// scrollState is the scrolling of a view.
+type scrollState struct {
+	// x and y are the offset of the content, out of the range while it
+	// bounces past the edges.
+	x, y float64
+	// vx and vy are the velocity in pixels per tick.
+	vx, vy     float64
+	maxX, maxY float64
+	// pressed is true while a pointer pressed in the view is held: the
+	// mouse if touch is -1, or the touch.
+	pressed        bool
+	dragging       bool
+	touch          ebiten.TouchID
+	startX, startY int
+	lastX, lastY   int
+}
+
+// scrollDrags are the views dragged by the pointers, the mouse being -1.
+var scrollDrags = map[ebiten.TouchID]*View{}
+
+// wheelScrolled is true when a view has been scrolled by the mouse wheel
+// in the update.
+var wheelScrolled bool
+
+// ScrollOffset returns the offset the content of the view is scrolled by.
+func (v *View) ScrollOffset() image.Point {
+	s := v.scroll
+	if s == nil || v.Overflow != OverflowScroll {
+		return image.Point{}
+	}
+	return image.Pt(int(math.Round(s.x)), int(math.Round(s.y)))
+}
+
+// ScrollTo scrolls the content of the view to the offset, limited to the
+// range of the content, and stops its momentum.
+func (v *View) ScrollTo(x, y int) {
+	s := v.scrollState()
+	s.x, s.y, s.vx, s.vy = float64(x), float64(y), 0, 0
+	v.measureScroll()
+	s.x, s.y = clampFloat(s.x, 0, s.maxX), clampFloat(s.y, 0, s.maxY)
+}
+
+func (v *View) scrollState() *scrollState {
+	if v.scroll == nil {
+		v.scroll = &scrollState{touch: -1}
+	}
+	return v.scroll
+}
+
+func (v *View) scrollOptions() Scroll {
+	var o Scroll
+	if v.Scroll != nil {
+		o = *v.Scroll
+	}
+	if o.Friction == 0 {
+		o.Friction = defaultScrollFriction
+	}
+	if o.WheelStep == 0 {
+		o.WheelStep = defaultScrollWheelStep
+	}
+	return o
+}
+
+// measureScroll measures the range of the offset from the margin boxes of
+// the children and the padding.
+func (v *View) measureScroll() {
+	s := v.scrollState()
+	right, bottom := 0, 0
+	for _, c := range v.children {
+		if !c.item.isDisplayed() {
+			continue
+		}
+		f := c.item.frame.Sub(v.frame.Min)
+		if r := f.Max.X + c.item.MarginRight; r > right {
+			right = r
+		}
+		if b := f.Max.Y + c.item.MarginBottom; b > bottom {
+			bottom = b
+		}
+	}
+	s.maxX = math.Max(0, float64(right+v.PaddingRight-v.frame.Dx()))
+	s.maxY = math.Max(0, float64(bottom+v.PaddingBottom-v.frame.Dy()))
+}
+
+// fromScreen maps a point on the screen to the coordinates of the frame of
+// the view, through the transforms and the scrolling of its ancestors.
+func (v *View) fromScreen(x, y int) (int, int) {
+	if v.hasParent {
+		x, y = v.parent.fromScreen(x, y)
+	}
+	return v.toLocal(v.frame, x, y)
+}
+
+// updateScrolls scrolls the views of the subtree, the innermost first so
+// that they take the drags and the wheel before their ancestors.
+func (v *View) updateScrolls() {
+	if v.Hidden || !v.isDisplayed() {
+		return
+	}
+	for _, c := range v.children {
+		c.item.updateScrolls()
+	}
+	if v.Overflow == OverflowScroll && v.hasParent {
+		v.updateScroll()
+	}
+}
+
+func (v *View) updateScroll() {
+	s := v.scrollState()
+	o := v.scrollOptions()
+	v.measureScroll()
+
+	blocked := v.isBelowModal()
+	if !s.pressed && !v.Disabled && !blocked {
+		v.pressScroll(s)
+	}
+	if s.pressed {
+		v.dragScroll(s, o)
+	} else if !blocked {
+		v.wheelScroll(s, o)
+	}
+	if s.dragging {
+		return
+	}
+
+	// the content moves on with the momentum, and springs back past the edges
+	s.x, s.vx = moveScroll(s.x, s.vx, s.maxX, o)
+	s.y, s.vy = moveScroll(s.y, s.vy, s.maxY, o)
+}
+
+// pressScroll starts tracking a pointer pressed in the view.
+func (v *View) pressScroll(s *scrollState) {
+	start := func(touch ebiten.TouchID, x, y int) bool {
+		x, y = v.fromScreen(x, y)
+		if !isInside(&v.frame, x, y) {
+			return false
+		}
+		s.pressed, s.touch = true, touch
+		s.startX, s.startY, s.lastX, s.lastY = x, y, x, y
+		// a press stops the momentum
+		s.vx, s.vy = 0, 0
+		return true
+	}
+	if isMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := cursorPosition()
+		if start(-1, x, y) {
+			return
+		}
+	}
+	for _, id := range appendJustPressedTouchIDs(nil) {
+		x, y := currentTouchPosition(id)
+		if start(id, x, y) {
+			return
+		}
+	}
+}
+
+// dragScroll drags the content with the pointer pressed in the view.
+func (v *View) dragScroll(s *scrollState, o Scroll) {
+	var x, y int
+	released := false
+	if s.touch == -1 {
+		released = !isMouseButtonPressed(ebiten.MouseButtonLeft)
+		x, y = cursorPosition()
+	} else {
+		released = isTouchJustReleased(s.touch)
+		x, y = currentTouchPosition(s.touch)
+	}
+	if released {
+		if s.dragging {
+			delete(scrollDrags, s.touch)
+		}
+		s.pressed, s.dragging = false, false
+		return
+	}
+	x, y = v.fromScreen(x, y)
+
+	if !s.dragging {
+		dx, dy := absInt(x-s.startX), absInt(y-s.startY)
+		horizontal := s.maxX > 0 && dx > scrollDragThreshold && (s.maxY == 0 || dx > dy)
+		vertical := s.maxY > 0 && dy > scrollDragThreshold && (s.maxX == 0 || dy > dx)
+		if !horizontal && !vertical {
+			return
+		}
+		if _, ok := scrollDrags[s.touch]; ok {
+			// a view inside this one is dragged
+			s.pressed = false
+			return
+		}
+		scrollDrags[s.touch] = v
+		s.dragging = true
+		s.lastX, s.lastY = x, y
+		// the views pressed in the content are released as canceled
+		v.cancelPresses()
+		return
+	}
+
+	// the content follows the pointer, and the velocity is smoothed over
+	// the last ticks for the fling
+	dx, dy := float64(s.lastX-x), float64(s.lastY-y)
+	s.lastX, s.lastY = x, y
+	s.x = dragScrollAxis(s.x, dx, s.maxX, o.Bounce)
+	s.y = dragScrollAxis(s.y, dy, s.maxY, o.Bounce)
+	s.vx, s.vy = (s.vx+dx)/2, (s.vy+dy)/2
+	if s.maxX == 0 {
+		s.vx = 0
+	}
+	if s.maxY == 0 {
+		s.vy = 0
+	}
+}
+
+func dragScrollAxis(pos, delta, max float64, bounce bool) float64 {
+	if max == 0 {
+		return 0
+	}
+	if !bounce {
+		return clampFloat(pos+delta, 0, max)
+	}
+	// the part of the drag past the edges moves the content less
+	next := pos + delta
+	switch {
+	case next < 0:
+		start := math.Min(pos, 0)
+		return start + (next-start)*scrollResistance
+	case next > max:
+		start := math.Max(pos, max)
+		return start + (next-start)*scrollResistance
+	}
+	return next
+}
+
+// wheelScroll scrolls the content by the mouse wheel over the view.
+func (v *View) wheelScroll(s *scrollState, o Scroll) {
+	wx, wy := wheel()
+	if wheelScrolled || (wx == 0 && wy == 0) || (s.maxX == 0 && s.maxY == 0) {
+		return
+	}
+	x, y := v.fromScreen(cursorPosition())
+	if !isInside(&v.frame, x, y) {
+		return
+	}
+	// a vertical wheel scrolls horizontally if the content only overflows
+	// horizontally
+	if s.maxY == 0 && wx == 0 {
+		wx, wy = wy, 0
+	}
+	s.x = clampFloat(s.x-wx*float64(o.WheelStep), 0, s.maxX)
+	s.y = clampFloat(s.y-wy*float64(o.WheelStep), 0, s.maxY)
+	s.vx, s.vy = 0, 0
+	wheelScrolled = true
+}
+
+// moveScroll moves the offset of an axis by the velocity and returns the
+// new offset and velocity.
+func moveScroll(pos, vel, max float64, o Scroll) (float64, float64) {
+	if max == 0 {
+		return 0, 0
+	}
+	pos += vel
+	vel *= 1 - clampFloat(o.Friction, 0, 1)
+	if math.Abs(vel) < scrollMinVelocity {
+		vel = 0
+	}
+	edge := clampFloat(pos, 0, max)
+	if edge == pos {
+		return pos, vel
+	}
+	if !o.Bounce {
+		return edge, 0
+	}
+	// past the edge, the content slows down quickly and springs back
+	vel *= scrollResistance
+	pos += (edge - pos) * scrollSpring
+	if math.Abs(edge-pos) < 0.5 {
+		return edge, 0
+	}
+	return pos, vel
+}
+
+// cancelPresses releases the buttons pressed in the subtree as canceled.
+func (v *View) cancelPresses() {
+	for _, c := range v.children {
+		if c.isButtonPressed {
+			c.isButtonPressed, c.isMouseLeftButtonHandler = false, false
+			c.handledTouchID = -1
+			if b, ok := c.item.Handler.(ButtonHandler); ok {
+				b.HandleRelease(c.item.frame.Min.X, c.item.frame.Min.Y, true)
+			}
+		}
+		c.item.cancelPresses()
+	}
+}
+
+// beginScrollUpdate starts an update of the scrolling, forgetting the drags
+// of the pointers released while their views were hidden.
+func beginScrollUpdate() {
+	wheelScrolled = false
+	for id := range scrollDrags {
+		if id == -1 && !isMouseButtonPressed(ebiten.MouseButtonLeft) || id != -1 && !touchPressed(id) {
+			delete(scrollDrags, id)
+		}
+	}
+}
+
+func touchPressed(id ebiten.TouchID) bool {
+	for _, t := range appendTouchIDs(nil) {
+		if t == id {
+			return true
+		}
+	}
+	return false
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func clampFloat(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
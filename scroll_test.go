@@ -0,0 +1,97 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScroll(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 200px; align-items: flex-start">
+		<view id="list" style="width: 100px; height: 100px; overflow: scroll; direction: column; padding-bottom: 10px">
+			<view style="width: 100px; height: 80px"></view>
+			<view style="width: 100px; height: 80px"></view>
+			<view style="width: 100px; height: 80px"></view>
+		</view>
+	</view>`, nil)
+	list := root.MustGetByID("list")
+	require.Equal(t, OverflowScroll, list.Overflow)
+	h := &mockHandler{}
+	list.children[1].item.Handler = h
+
+	mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	tick(InputState{})
+	require.Equal(t, 150.0, list.scroll.maxY)
+	require.Equal(t, 0.0, list.scroll.maxX)
+
+	// the wheel scrolls the content under the cursor
+	tick(InputState{CursorX: 50, CursorY: 50, WheelY: -1})
+	require.Equal(t, image.Pt(0, 40), list.ScrollOffset())
+	tick(InputState{CursorX: 150, CursorY: 50, WheelY: -1})
+	require.Equal(t, image.Pt(0, 40), list.ScrollOffset())
+	list.ScrollTo(0, 0)
+
+	// a drag cancels the press of the views in the content
+	tick(InputState{CursorX: 50, CursorY: 90, MouseButtons: mouse})
+	require.True(t, h.IsPressed)
+	tick(InputState{CursorX: 50, CursorY: 80, MouseButtons: mouse})
+	require.True(t, list.scroll.dragging)
+	require.True(t, h.IsReleased)
+	require.True(t, h.IsCancel)
+	for y := 70; y >= 30; y -= 10 {
+		tick(InputState{CursorX: 50, CursorY: y, MouseButtons: mouse})
+	}
+	require.Equal(t, image.Pt(0, 50), list.ScrollOffset())
+
+	// the content moves on after the fling, decelerating
+	tick(InputState{CursorX: 50, CursorY: 30})
+	require.False(t, list.scroll.dragging)
+	prev := list.ScrollOffset().Y
+	tick(InputState{})
+	require.Greater(t, list.ScrollOffset().Y, prev)
+	for i := 0; i < 300; i++ {
+		tick(InputState{})
+	}
+	require.Equal(t, 0.0, list.scroll.vy)
+	require.Equal(t, 150, list.ScrollOffset().Y)
+
+	// the scrolled content is hit at the offset, and not out of the view
+	h.Init()
+	list.ScrollTo(0, 40)
+	tick(InputState{CursorX: 50, CursorY: 50, MouseButtons: mouse})
+	require.True(t, h.IsPressed)
+	tick(InputState{CursorX: 50, CursorY: 50})
+	h.Init()
+	list.ScrollTo(0, 0)
+	tick(InputState{CursorX: 50, CursorY: 150, MouseButtons: mouse})
+	require.False(t, h.IsPressed)
+	tick(InputState{})
+
+	// the content bounces past the edges and springs back
+	list.Scroll = &Scroll{Bounce: true}
+	tick(InputState{CursorX: 50, CursorY: 10, MouseButtons: mouse})
+	tick(InputState{CursorX: 50, CursorY: 30, MouseButtons: mouse})
+	tick(InputState{CursorX: 50, CursorY: 50, MouseButtons: mouse})
+	require.Equal(t, image.Pt(0, -10), list.ScrollOffset())
+	tick(InputState{CursorX: 50, CursorY: 50})
+	for i := 0; i < 60; i++ {
+		tick(InputState{})
+	}
+	require.Equal(t, image.Pt(0, 0), list.ScrollOffset())
+
+	// the children are drawn at the offset
+	list.ScrollTo(0, 40)
+	screen := ebiten.NewImage(200, 200)
+	h.Init()
+	root.Draw(screen)
+	require.Equal(t, image.Rect(0, 40, 100, 120), h.Frame)
+}
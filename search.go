@@ -0,0 +1,165 @@
+package furex
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchBox is the handler of the built-in <search-box> element, a text
+// input whose text filters a list, e.g. in crafting and collection
+// screens. OnSearch is called with the query, the text without the spaces
+// around it, once the text has not changed for Debounce ticks, so a long
+// list is not filtered at every keystroke. SearchProjection filters a
+// Projection with the query, and HighlightMatches marks the matches in the
+// texts of the items:
+//
+//	box := view.MustGetByID("search").Handler.(*furex.SearchBox)
+//	furex.SearchProjection(box, recipes, func(r Recipe, q string) bool {
+//		return furex.MatchFold(r.Name, q)
+//	})
+type SearchBox struct {
+	// TextInput is the input of the query.
+	TextInput
+	// Debounce is the number of ticks the text stays unchanged before it
+	// is searched. A quarter of a second is used if it is 0.
+	Debounce int
+	// OnSearch is called with the query when it changes.
+	OnSearch func(query string)
+
+	query   string
+	pending string
+	wait    int
+}
+
+var _ Updater = (*SearchBox)(nil)
+
+// newSearchBox creates the handler of a <search-box> element from its
+// attributes, which are those of <input> and debounce.
+func newSearchBox(attrs map[string]string) Handler {
+	b := &SearchBox{TextInput: *newTextInput(attrs).(*TextInput)}
+	if val, ok := attrs["debounce"]; ok {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			println(fmt.Sprintf("search-box: %v", err))
+		} else {
+			b.Debounce = n
+		}
+	}
+	b.query = strings.TrimSpace(b.Value)
+	b.pending = b.query
+	return b
+}
+
+// Query returns the query searched last.
+func (b *SearchBox) Query() string {
+	return b.query
+}
+
+// Update implements Updater.
+func (b *SearchBox) Update(v *View) {
+	b.TextInput.Update(v)
+	if q := strings.TrimSpace(b.Value); q != b.pending {
+		b.pending, b.wait = q, b.Debounce
+		if b.wait == 0 {
+			b.wait = tps() / 4
+		}
+	}
+	if b.wait > 0 {
+		b.wait--
+		if b.wait == 0 && b.pending != b.query {
+			b.query = b.pending
+			if b.OnSearch != nil {
+				b.OnSearch(b.query)
+			}
+		}
+	}
+}
+
+// SearchProjection filters the projection with the query of the search
+// box, showing the items for which match returns true, within the filter
+// the projection has. All the items of the filter are shown while the
+// query is empty. OnSearch set before is still called.
+func SearchProjection[T any](box *SearchBox, p *Projection[T], match func(item T, query string) bool) {
+	base, prev := p.Filter, box.OnSearch
+	box.OnSearch = func(query string) {
+		p.SetFilter(func(item T) bool {
+			return (base == nil || base(item)) && (query == "" || match(item, query))
+		})
+		if prev != nil {
+			prev(query)
+		}
+	}
+}
+
+// MatchFold reports whether the text contains the query, ignoring the
+// case.
+func MatchFold(text, query string) bool {
+	for i := range text {
+		if _, ok := hasPrefixFold(text[i:], query); ok {
+			return true
+		}
+	}
+	return query == ""
+}
+
+// HighlightMatches returns the markup of RichText showing the text with
+// the matches of the query, ignoring the case, inside <mark>.
+func HighlightMatches(text, query string) string {
+	if query == "" {
+		return html.EscapeString(text)
+	}
+	sb := &strings.Builder{}
+	start := 0
+	for i := 0; i < len(text); {
+		n, ok := hasPrefixFold(text[i:], query)
+		if !ok {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[start:i]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[i : i+n]))
+		sb.WriteString("</mark>")
+		i += n
+		start = i
+	}
+	sb.WriteString(html.EscapeString(text[start:]))
+	return sb.String()
+}
+
+// hasPrefixFold reports whether s starts with the prefix, ignoring the
+// case, and returns the length of the prefix in s.
+func hasPrefixFold(s, prefix string) (int, bool) {
+	if prefix == "" {
+		return 0, false
+	}
+	n := 0
+	for _, r := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		c, size := utf8.DecodeRuneInString(s[n:])
+		if !equalFoldRune(c, r) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestSearchBox(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	type recipe struct {
+		Name    string
+		Learned bool
+	}
+	recipes := []recipe{{"Iron Sword", true}, {"Iron Shield", false}, {"Wooden Sword", true}}
+	p := NewProjection(func() []recipe { return recipes })
+	p.Filter = func(r recipe) bool { return r.Learned }
+
+	root := Parse(`<view style="width: 200px; height: 100px; align-items: flex-start">
+		<search-box id="search" placeholder="Search" debounce="3"></search-box>
+	</view>`, nil)
+	view := root.MustGetByID("search")
+	box := view.Handler.(*SearchBox)
+	require.Equal(t, 3, box.Debounce)
+	require.Equal(t, "Search", box.Placeholder)
+	var queries []string
+	box.OnSearch = func(q string) { queries = append(queries, q) }
+	SearchProjection(box, p, func(r recipe, q string) bool { return MatchFold(r.Name, q) })
+
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	view.Focus()
+	tick(InputState{Chars: []rune("s")})
+	tick(InputState{Chars: []rune("w")})
+	tick(InputState{})
+	require.Empty(t, queries)
+	// the query is searched once it stays unchanged, the spaces around it
+	// being ignored
+	tick(InputState{Chars: []rune(" ")})
+	require.Equal(t, []string{"sw"}, queries)
+	require.Equal(t, "sw", box.Query())
+	require.Len(t, p.Items(), 2)
+
+	// the filter of the projection is kept
+	box.SetValue("iron")
+	for i := 0; i < 3; i++ {
+		tick(InputState{})
+	}
+	require.Equal(t, []string{"sw", "iron"}, queries)
+	require.Equal(t, []recipe{{"Iron Sword", true}}, p.Items())
+	box.SetValue("")
+	for i := 0; i < 3; i++ {
+		tick(InputState{})
+	}
+	require.Len(t, p.Items(), 2)
+}
+
+func TestHighlightMatches(t *testing.T) {
+	require.True(t, MatchFold("Iron Sword", "SWO"))
+	require.False(t, MatchFold("Iron Sword", "axe"))
+	require.True(t, MatchFold("Straße", "STRAẞE"))
+	require.Equal(t, "<mark>Po</mark>tion &amp; <mark>po</mark>ison", HighlightMatches("Potion & poison", "po"))
+	require.Equal(t, "a &lt; b", HighlightMatches("a < b", ""))
+
+	// the marks are drawn behind the texts
+	spans := parseRichText(HighlightMatches("Iron Sword", "sw"))
+	require.Equal(t, []richSpan{{text: "Iron "}, {text: "Sw", mark: true}, {text: "ord"}}, spans)
+	r := &RichText{Text: Text{Face: basicfont.Face7x13}}
+	view := &View{Width: 200, Height: 20, Text: HighlightMatches("Iron Sword", "sw"), Handler: r}
+	view.Layout()
+	view.Draw(ebiten.NewImage(200, 20))
+	require.True(t, r.runs[1].mark)
+	require.Equal(t, image.Rect(35, 0, 49, 13), r.runs[1].bounds)
+}
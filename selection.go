@@ -0,0 +1,287 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// SelectableText is a Text whose text can be selected and copied, e.g.
+// lobby codes and error ids players need to share. The text is selected by
+// dragging the mouse, or with the keyboard while the view has the focus:
+// Shift with the arrow keys, Home and End extends the selection, Ctrl+A
+// (Cmd+A) selects all and Ctrl+C (Cmd+C) copies.
+//
+// The lines of the text are aligned with text-align and vertical-align,
+// but are not justified or flowed into columns.
+type SelectableText struct {
+	// Text has the face and the color of the text.
+	Text
+	// SelectionColor is the color of the selection behind the text.
+	// A translucent blue is used if it is nil.
+	SelectionColor color.Color
+	// OnCopy is called with the selected text when it is copied, e.g. to
+	// write it to the clipboard.
+	OnCopy func(text string)
+
+	// anchor and caret are the byte offsets where the selection started
+	// and ends.
+	anchor, caret int
+	dragging      bool
+	focused       bool
+	// lines are the lines drawn last time, to hit-test the text, shifted
+	// by linesShift from the frame of the view.
+	lines      []selectionLine
+	linesFace  font.Face
+	linesShift image.Point
+	text       string
+}
+
+var _ Drawer = (*SelectableText)(nil)
+var _ Updater = (*SelectableText)(nil)
+var _ FocusHandler = (*SelectableText)(nil)
+var _ MouseLeftButtonHandler = (*SelectableText)(nil)
+
+// selectionLine is a line of the text at the top left (x, y).
+type selectionLine struct {
+	start int
+	text  string
+	x, y  int
+}
+
+var defaultSelectionColor = color.RGBA{0x33, 0x66, 0xcc, 0x99}
+
+// Draw implements Drawer.
+func (s *SelectableText) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	s.sync(v.Text)
+	face, clr := s.face(v), s.color(v)
+	opacity := v.EffectiveOpacity()
+	s.linesFace, s.linesShift = face, frame.Min.Sub(v.frame.Min)
+	align, valign := s.align(v)
+	s.lines = layoutSelectionLines(v.Text, face, frame, align, valign)
+	lineHeight := face.Metrics().Height.Ceil()
+	if lo, hi := s.Selection(); lo < hi {
+		sc := s.SelectionColor
+		if sc == nil {
+			sc = defaultSelectionColor
+		}
+		for _, l := range s.lines {
+			a, b := clampInt(lo-l.start, 0, len(l.text)), clampInt(hi-l.start, 0, len(l.text))
+			if a >= b {
+				continue
+			}
+			x0 := l.x + font.MeasureString(face, l.text[:a]).Ceil()
+			x1 := l.x + font.MeasureString(face, l.text[:b]).Ceil()
+			batch.FillRect(screen, image.Rect(x0, l.y, x1, l.y+lineHeight), fade(sc, opacity))
+		}
+		batch.Flush()
+	}
+	ascent := face.Metrics().Ascent.Ceil()
+	s.keys = s.keys[:0]
+	for _, l := range s.lines {
+		s.keys = append(s.keys, newTextKey(face, l.text, clr))
+		drawText(screen, l.text, face, l.x, l.y+ascent, clr, opacity)
+	}
+	s.holdKeys()
+}
+
+// Update implements Updater.
+func (s *SelectableText) Update(v *View) {
+	s.sync(v.Text)
+	if s.dragging {
+		if !isMouseButtonPressed(ebiten.MouseButtonLeft) {
+			s.dragging = false
+		} else {
+			s.drag(v.fromScreen(cursorPosition()))
+		}
+	}
+	if !s.focused {
+		return
+	}
+	shift := isKeyPressed(ebiten.KeyShift)
+	cmd := isKeyPressed(ebiten.KeyControl) || isKeyPressed(ebiten.KeyMeta)
+	for _, k := range []ebiten.Key{
+		ebiten.KeyArrowLeft, ebiten.KeyArrowRight, ebiten.KeyHome, ebiten.KeyEnd, ebiten.KeyA, ebiten.KeyC,
+	} {
+		if isKeyJustPressed(k) || isKeyRepeated(k) {
+			s.handleKey(v.Text, k, shift, cmd)
+		}
+	}
+}
+
+// isKeyRepeated returns true at the key repeat rate while the key is held.
+func isKeyRepeated(k ebiten.Key) bool {
+	d := keyPressDuration(k)
+	delay, interval := tps()/2, tps()/20
+	return d > delay && interval > 0 && (d-delay)%interval == 0
+}
+
+// handleKey handles a key pressed with the modifiers.
+func (s *SelectableText) handleKey(text string, k ebiten.Key, shift, cmd bool) {
+	switch {
+	case k == ebiten.KeyA && cmd:
+		s.anchor, s.caret = 0, len(text)
+		return
+	case k == ebiten.KeyC && cmd:
+		s.Copy()
+		return
+	}
+	caret := s.caret
+	switch k {
+	case ebiten.KeyArrowLeft:
+		if !shift && s.anchor != s.caret {
+			caret, _ = s.Selection()
+		} else if caret > 0 {
+			_, n := utf8.DecodeLastRuneInString(text[:caret])
+			caret -= n
+		}
+	case ebiten.KeyArrowRight:
+		if !shift && s.anchor != s.caret {
+			_, caret = s.Selection()
+		} else if caret < len(text) {
+			_, n := utf8.DecodeRuneInString(text[caret:])
+			caret += n
+		}
+	case ebiten.KeyHome:
+		caret = strings.LastIndexByte(text[:caret], '\n') + 1
+	case ebiten.KeyEnd:
+		if i := strings.IndexByte(text[caret:], '\n'); i >= 0 {
+			caret += i
+		} else {
+			caret = len(text)
+		}
+	default:
+		return
+	}
+	s.caret = caret
+	if !shift {
+		s.anchor = caret
+	}
+}
+
+// HandleFocus implements FocusHandler.
+func (s *SelectableText) HandleFocus() {
+	s.focused = true
+}
+
+// HandleBlur implements FocusHandler.
+func (s *SelectableText) HandleBlur() {
+	s.focused = false
+}
+
+// HandleJustPressedMouseButtonLeft implements MouseLeftButtonHandler.
+func (s *SelectableText) HandleJustPressedMouseButtonLeft(x, y int) bool {
+	s.anchor = s.offsetAt(x, y)
+	s.caret, s.dragging = s.anchor, true
+	return true
+}
+
+// HandleJustReleasedMouseButtonLeft implements MouseLeftButtonHandler.
+func (s *SelectableText) HandleJustReleasedMouseButtonLeft(x, y int) {
+	if s.dragging {
+		s.drag(x, y)
+		s.dragging = false
+	}
+}
+
+func (s *SelectableText) drag(x, y int) {
+	s.caret = s.offsetAt(x, y)
+}
+
+// Selection returns the byte offsets of the selected text in View.Text.
+func (s *SelectableText) Selection() (start, end int) {
+	if s.anchor < s.caret {
+		return s.anchor, s.caret
+	}
+	return s.caret, s.anchor
+}
+
+// Select selects the text between the byte offsets.
+func (s *SelectableText) Select(start, end int) {
+	s.anchor = clampInt(start, 0, len(s.text))
+	s.caret = clampInt(end, 0, len(s.text))
+}
+
+// SelectedText returns the selected text.
+func (s *SelectableText) SelectedText() string {
+	lo, hi := s.Selection()
+	return s.text[lo:hi]
+}
+
+// Copy calls OnCopy with the selected text if any.
+func (s *SelectableText) Copy() {
+	if t := s.SelectedText(); t != "" && s.OnCopy != nil {
+		s.OnCopy(t)
+	}
+}
+
+// sync clears the selection when the text changes.
+func (s *SelectableText) sync(text string) {
+	if text != s.text {
+		s.text, s.anchor, s.caret = text, 0, 0
+	}
+}
+
+// offsetAt returns the byte offset of the text nearest to (x, y).
+func (s *SelectableText) offsetAt(x, y int) int {
+	if len(s.lines) == 0 {
+		return 0
+	}
+	x, y = x+s.linesShift.X, y+s.linesShift.Y
+	lineHeight := s.linesFace.Metrics().Height.Ceil()
+	i := 0
+	if lineHeight > 0 {
+		i = clampInt(floorDiv(y-s.lines[0].y, lineHeight), 0, len(s.lines)-1)
+	}
+	l := s.lines[i]
+	prev := 0
+	for j, r := range l.text {
+		end := j + utf8.RuneLen(r)
+		w := font.MeasureString(s.linesFace, l.text[:end]).Ceil()
+		if x < l.x+(prev+w)/2 {
+			return l.start + j
+		}
+		prev = w
+	}
+	return l.start + len(l.text)
+}
+
+// layoutSelectionLines returns the lines of the text with their offsets,
+// aligned in the frame.
+func layoutSelectionLines(text string, face font.Face, frame image.Rectangle, align TextAlign, valign VerticalAlign) []selectionLine {
+	lines := strings.Split(text, "\n")
+	ends := make([]bool, len(lines))
+	for i := range ends {
+		ends[i] = true
+	}
+	runs := alignLines(lines, ends, face, frame, align, valign)
+	ascent := face.Metrics().Ascent.Ceil()
+	ret := make([]selectionLine, len(lines))
+	start := 0
+	for i, r := range runs {
+		ret[i] = selectionLine{start: start, text: r.text, x: r.x, y: r.y - ascent}
+		start += len(r.text) + 1
+	}
+	return ret
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
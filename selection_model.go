@@ -0,0 +1,237 @@
+package furex
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SelectionMode is the number of items a Selection can hold.
+type SelectionMode int
+
+const (
+	// SelectSingle selects one item at a time.
+	SelectSingle SelectionMode = iota
+	// SelectMulti selects any number of items. A click with Ctrl (or Cmd)
+	// adds or removes an item, and a click with Shift selects the items
+	// from the last clicked one. On touch screens a long press adds an
+	// item and the taps add or remove items until none is selected.
+	SelectMulti
+)
+
+// Selection is the selected items of a list or a grid, identified by ids,
+// e.g. for selling several items of an inventory at once. The same model
+// is used by the components showing items, such as Grid and VirtualList,
+// which select the items clicked and tapped, and the views of the selected
+// items match the :selected pseudo-class:
+//
+//	sel := &furex.Selection{Mode: furex.SelectMulti}
+//	sel.OnChange = func(ids []string) { sellButton.Disabled = len(ids) == 0 }
+//	inventory.Handler = &furex.Grid{
+//		Count:     len(items),
+//		CellID:    func(i int) string { return items[i].ID },
+//		Selection: sel,
+//		...
+//	}
+type Selection struct {
+	// Mode is the number of items that can be selected.
+	Mode SelectionMode
+	// OnChange is called with the ids of the selected items when the input
+	// changes them.
+	OnChange func(ids []string)
+
+	ids []string
+	// anchor is the item a click with Shift selects from.
+	anchor   string
+	anchored bool
+	// touch is true after a long press, while taps add or remove items.
+	touch bool
+	// version counts the changes, so the components restyle their items.
+	version int
+}
+
+// IDs returns the ids of the selected items, in the order they were
+// selected.
+func (s *Selection) IDs() []string {
+	return append([]string(nil), s.ids...)
+}
+
+// IsSelected returns true if the item is selected.
+func (s *Selection) IsSelected(id string) bool {
+	return indexOfString(s.ids, id) >= 0
+}
+
+// Set selects the items without calling OnChange. Only the last one is
+// selected in SelectSingle mode.
+func (s *Selection) Set(ids ...string) {
+	if s.Mode != SelectMulti && len(ids) > 1 {
+		ids = ids[len(ids)-1:]
+	}
+	s.set(ids)
+}
+
+// Clear unselects all the items without calling OnChange.
+func (s *Selection) Clear() {
+	s.set(nil)
+}
+
+// Toggle selects the item, or unselects it if it is selected, without
+// calling OnChange.
+func (s *Selection) Toggle(id string) {
+	s.set(s.toggled(id))
+}
+
+// Click selects the item the way a click on it does, reading the
+// modifier keys, and calls OnChange if the selection changes. items are
+// the ids of all the items in order, for the clicks with Shift. It is
+// called by the components showing items, and by custom ones.
+func (s *Selection) Click(id string, items []string) {
+	i := indexOfString(items, id)
+	if i < 0 {
+		return
+	}
+	s.click(i, len(items), func(i int) string { return items[i] })
+}
+
+// click selects the item at the index of count items whose ids are
+// returned by idAt.
+func (s *Selection) click(index, count int, idAt func(int) string) {
+	id := idAt(index)
+	cmd := isKeyPressed(ebiten.KeyControl) || isKeyPressed(ebiten.KeyMeta)
+	ids := []string{id}
+	if s.Mode == SelectMulti {
+		if isKeyPressed(ebiten.KeyShift) && s.anchored {
+			if a := indexOfItem(count, idAt, s.anchor); a >= 0 {
+				ids = nil
+				if cmd {
+					ids = append(ids, s.ids...)
+				}
+				from, to := a, index
+				if from > to {
+					from, to = to, from
+				}
+				for i := from; i <= to; i++ {
+					if indexOfString(ids, idAt(i)) < 0 {
+						ids = append(ids, idAt(i))
+					}
+				}
+				s.change(ids)
+				return
+			}
+		}
+		if cmd || s.touch {
+			ids = s.toggled(id)
+		}
+	}
+	s.anchor, s.anchored = id, true
+	s.change(ids)
+}
+
+// longPress selects the item pressed long on a touch screen, after which
+// the taps add or remove items.
+func (s *Selection) longPress(id string) {
+	if s.Mode != SelectMulti {
+		return
+	}
+	s.touch = true
+	s.anchor, s.anchored = id, true
+	if !s.IsSelected(id) {
+		s.change(append(s.IDs(), id))
+	}
+}
+
+// change sets the selected items and calls OnChange if they changed.
+func (s *Selection) change(ids []string) {
+	if s.set(ids) && s.OnChange != nil {
+		s.OnChange(s.IDs())
+	}
+}
+
+// set sets the selected items and returns true if they changed.
+func (s *Selection) set(ids []string) bool {
+	if equalStrings(ids, s.ids) {
+		return false
+	}
+	s.ids = append([]string(nil), ids...)
+	s.version++
+	if len(s.ids) == 0 {
+		s.touch = false
+	}
+	return true
+}
+
+func (s *Selection) toggled(id string) []string {
+	ids := s.IDs()
+	if i := indexOfString(ids, id); i >= 0 {
+		return append(ids[:i], ids[i+1:]...)
+	}
+	return append(ids, id)
+}
+
+// selectionGesture tells the clicks on the items of a component from the
+// long presses on touch screens.
+type selectionGesture struct {
+	index   int
+	pressed bool
+	touch   bool
+	// ticks is the duration of the press, -1 after a long press.
+	ticks int
+}
+
+// press starts tracking a press on the item at the index.
+func (g *selectionGesture) press(index int, t ebiten.TouchID) {
+	g.index, g.pressed, g.touch, g.ticks = index, true, t >= 0, 0
+}
+
+// update selects the item pressed long enough on a touch screen.
+func (g *selectionGesture) update(s *Selection, idAt func(int) string) {
+	if !g.pressed || !g.touch || g.ticks < 0 || s.Mode != SelectMulti {
+		return
+	}
+	g.ticks++
+	if g.ticks >= tps()/2 {
+		g.ticks = -1
+		s.longPress(idAt(g.index))
+	}
+}
+
+// cancel ends the press without a click.
+func (g *selectionGesture) cancel() {
+	g.pressed = false
+}
+
+// release ends the press and returns true if it was a click on the item,
+// not a long press.
+func (g *selectionGesture) release(isCancel bool) bool {
+	click := g.pressed && !isCancel && g.ticks >= 0
+	g.pressed = false
+	return click
+}
+
+func indexOfItem(count int, idAt func(int) string, id string) int {
+	for i := 0; i < count; i++ {
+		if idAt(i) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOfString(s []string, v string) int {
+	for i, vv := range s {
+		if vv == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
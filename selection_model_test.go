@@ -0,0 +1,172 @@
+package furex
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelection(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	items := []string{"a", "b", "c", "d", "e"}
+	var changes [][]string
+	s := &Selection{OnChange: func(ids []string) { changes = append(changes, ids) }}
+	click := func(id string, keys ...ebiten.Key) {
+		InjectInput(InputState{Keys: keys})
+		s.Click(id, items)
+	}
+
+	// a single item is selected, even with the modifiers
+	click("b")
+	click("d", ebiten.KeyControl)
+	require.Equal(t, []string{"d"}, s.IDs())
+	s.Set("a", "c")
+	require.Equal(t, []string{"c"}, s.IDs())
+
+	// Ctrl adds and removes items, and Shift selects a range
+	s.Mode = SelectMulti
+	click("b")
+	click("d", ebiten.KeyControl)
+	require.Equal(t, []string{"b", "d"}, s.IDs())
+	click("b", ebiten.KeyMeta)
+	require.Equal(t, []string{"d"}, s.IDs())
+	click("d", ebiten.KeyShift)
+	require.Equal(t, []string{"b", "c", "d"}, s.IDs())
+	click("a", ebiten.KeyShift)
+	require.Equal(t, []string{"a", "b"}, s.IDs())
+	click("e")
+	click("d", ebiten.KeyShift, ebiten.KeyControl)
+	require.Equal(t, []string{"e", "d"}, s.IDs())
+	click("b", ebiten.KeyControl)
+	click("a", ebiten.KeyShift, ebiten.KeyControl)
+	require.Equal(t, []string{"e", "d", "b", "a"}, s.IDs())
+	click("c")
+	require.True(t, s.IsSelected("c"))
+	require.False(t, s.IsSelected("a"))
+
+	// OnChange is called only by the input changing the selection
+	n := len(changes)
+	click("c")
+	s.Toggle("e")
+	s.Clear()
+	require.Len(t, changes, n)
+	require.Equal(t, []string{"c"}, changes[n-1])
+}
+
+func TestGridSelection(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := &View{Width: 100, Height: 100}
+	inventory := &View{Width: 100}
+	root.AddChild(inventory)
+	ids := []string{"sword", "shield", "potion", "herb"}
+	sel := &Selection{Mode: SelectMulti}
+	grid := &Grid{
+		Count:     len(ids),
+		CellWidth: 50,
+		NewCell:   func(i int) *View { return &View{} },
+		CellID:    func(i int) string { return ids[i] },
+		Selection: sel,
+	}
+	inventory.Handler = grid
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	click := func(x, y int, keys ...ebiten.Key) {
+		mouse := []ebiten.MouseButton{ebiten.MouseButtonLeft}
+		tick(InputState{CursorX: x, CursorY: y, MouseButtons: mouse, Keys: keys})
+		tick(InputState{CursorX: x, CursorY: y, Keys: keys})
+	}
+	tick(InputState{})
+	tick(InputState{})
+
+	// the cells clicked are selected and match :selected
+	click(10, 10)
+	click(60, 60, ebiten.KeyControl)
+	require.Equal(t, []string{"sword", "herb"}, sel.IDs())
+	require.True(t, grid.Cell(3).hasPseudoClass("selected"))
+	require.False(t, grid.Cell(1).hasPseudoClass("selected"))
+	require.True(t, grid.Cell(3).hasPseudoClass("checked"))
+
+	// and the cells chosen with the arrow keys, from the last clicked one
+	inventory.Focus()
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift, ebiten.KeyArrowLeft}})
+	require.Equal(t, []string{"potion", "herb"}, sel.IDs())
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift, ebiten.KeyArrowUp}})
+	require.Equal(t, []string{"sword", "shield", "potion", "herb"}, sel.IDs())
+	tick(InputState{})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyArrowRight}})
+	require.Equal(t, []string{"shield"}, sel.IDs())
+
+	// the selection changed from Go is shown
+	sel.Set("herb")
+	tick(InputState{})
+	require.True(t, grid.Cell(3).hasPseudoClass("selected"))
+	require.False(t, grid.Cell(1).hasPseudoClass("selected"))
+}
+
+func TestVirtualListSelection(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<view id="list" style="width: 100px; height: 100px; overflow: scroll; direction: column"></view>
+	</view>`, nil)
+	listView := root.MustGetByID("list")
+	var changes [][]string
+	sel := &Selection{Mode: SelectMulti, OnChange: func(ids []string) { changes = append(changes, ids) }}
+	list := &VirtualList{
+		Count:     100,
+		RowHeight: 20,
+		NewRow:    func() *View { return &View{} },
+		RowID:     func(i int) string { return "row" + strconv.Itoa(i) },
+		Selection: sel,
+	}
+	listView.Handler = list
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	touch := func(y, ticks int) {
+		for i := 0; i < ticks; i++ {
+			tick(InputState{Touches: []TouchState{{ID: 1, X: 10, Y: y}}})
+		}
+		tick(InputState{})
+	}
+	tick(InputState{})
+	tick(InputState{})
+
+	// a long press selects a row, and the taps add and remove rows
+	touch(30, 40)
+	require.Equal(t, []string{"row1"}, sel.IDs())
+	require.True(t, list.Row(1).hasPseudoClass("selected"))
+	touch(70, 2)
+	require.Equal(t, []string{"row1", "row3"}, sel.IDs())
+	touch(30, 2)
+	touch(70, 2)
+	require.Empty(t, sel.IDs())
+	require.False(t, list.Row(1).hasPseudoClass("selected"))
+
+	// a tap selects a single row once none is selected
+	touch(10, 2)
+	touch(50, 2)
+	require.Equal(t, []string{"row2"}, sel.IDs())
+	require.Len(t, changes, 6)
+
+	// the rows scroll with the content
+	listView.ScrollTo(0, 200)
+	tick(InputState{})
+	tick(InputState{})
+	touch(10, 2)
+	require.Equal(t, []string{"row10"}, sel.IDs())
+	require.True(t, list.Row(10).hasPseudoClass("selected"))
+	require.False(t, list.Row(11).hasPseudoClass("selected"))
+}
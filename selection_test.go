@@ -0,0 +1,55 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestSelectableText(t *testing.T) {
+	face := basicfont.Face7x13
+	s := &SelectableText{}
+	s.sync("ABC-123\nxyz")
+	s.linesFace = face
+	s.lines = layoutSelectionLines("ABC-123\nxyz", face, image.Rect(10, 20, 200, 100), TextAlignLeft, VerticalAlignTop)
+	require.Equal(t, []selectionLine{{0, "ABC-123", 10, 20}, {8, "xyz", 10, 33}}, s.lines)
+
+	// drag from the left half of "C" to the second line
+	require.True(t, s.HandleJustPressedMouseButtonLeft(10+7*2+2, 25))
+	s.HandleJustReleasedMouseButtonLeft(10+7*2, 40)
+	require.Equal(t, "C-123\nxy", s.SelectedText())
+
+	var copied string
+	s.OnCopy = func(text string) { copied = text }
+	s.handleKey(s.text, ebiten.KeyC, false, true)
+	require.Equal(t, "C-123\nxy", copied)
+
+	// keyboard selection
+	s.Select(1, 1)
+	s.handleKey(s.text, ebiten.KeyArrowRight, true, false)
+	s.handleKey(s.text, ebiten.KeyArrowRight, true, false)
+	require.Equal(t, "BC", s.SelectedText())
+	s.handleKey(s.text, ebiten.KeyEnd, true, false)
+	require.Equal(t, "BC-123", s.SelectedText())
+	s.handleKey(s.text, ebiten.KeyArrowLeft, false, false)
+	require.Equal(t, "", s.SelectedText())
+	require.Equal(t, 1, s.caret)
+	s.handleKey(s.text, ebiten.KeyA, false, true)
+	require.Equal(t, "ABC-123\nxyz", s.SelectedText())
+
+	// the selection is cleared when the text changes
+	s.sync("new")
+	require.Equal(t, "", s.SelectedText())
+}
+
+func TestSelectableTextFocus(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px"><selectable-text id="code">ABC-123</selectable-text></view>`, nil)
+	code := root.MustGetByID("code")
+	s, ok := code.Handler.(*SelectableText)
+	require.True(t, ok)
+	code.Focus()
+	require.True(t, s.focused)
+}
@@ -0,0 +1,170 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Orientation is the direction of a Separator.
+type Orientation uint8
+
+const (
+	// OrientationAuto is horizontal in a column and vertical in a row.
+	OrientationAuto Orientation = iota
+	OrientationHorizontal
+	OrientationVertical
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case OrientationAuto:
+		return "auto"
+	case OrientationHorizontal:
+		return "horizontal"
+	case OrientationVertical:
+		return "vertical"
+	}
+	return fmt.Sprintf("unknown orientation: %d", o)
+}
+
+func parseOrientation(val string) (Orientation, error) {
+	switch val {
+	case "auto":
+		return OrientationAuto, nil
+	case "horizontal":
+		return OrientationHorizontal, nil
+	case "vertical":
+		return OrientationVertical, nil
+	}
+	return OrientationAuto, fmt.Errorf("unknown orientation: %s", val)
+}
+
+// Separator is the handler of the built-in <hr> element. It draws a line
+// across its parent, e.g. between the items of menus and lists. The view
+// is sized to the thickness of the line and to the content box of the
+// parent, less its margins, along the line.
+//
+// In HTML, the fields are set by the orientation, thickness and inset
+// attributes, e.g. <hr thickness="2" inset="8">, and the color of the
+// line by the color property. An image can be drawn as the background
+// image of the view, whose frame is the line without an inset.
+type Separator struct {
+	Orientation Orientation
+	// Thickness is the thickness of the line in pixels. 1 is used if it is 0.
+	Thickness int
+	// Color is the color of the line. The color of the view
+	// (View.ComputedColor) or gray is used if it is nil.
+	Color color.Color
+	// Image is stretched along the line instead of the color if it is set.
+	Image *ebiten.Image
+	// Inset is the space left at both ends of the line.
+	Inset int
+}
+
+var _ Drawer = (*Separator)(nil)
+var _ Sizer = (*Separator)(nil)
+
+var defaultSeparatorColor = color.RGBA{0x80, 0x80, 0x80, 0xff}
+
+// newSeparator creates the handler of an <hr> element from its attributes.
+func newSeparator(attrs map[string]string) Handler {
+	s := &Separator{}
+	var err error
+	if o, ok := attrs["orientation"]; ok {
+		s.Orientation, err = parseOrientation(o)
+	}
+	if t, ok := attrs["thickness"]; ok && err == nil {
+		s.Thickness, err = strconv.Atoi(strings.TrimSuffix(t, "px"))
+	}
+	if i, ok := attrs["inset"]; ok && err == nil {
+		s.Inset, err = strconv.Atoi(strings.TrimSuffix(i, "px"))
+	}
+	if err != nil {
+		println(fmt.Sprintf("hr: %v", err))
+	}
+	return s
+}
+
+// vertical returns true if the line of the view is vertical.
+func (s *Separator) vertical(v *View) bool {
+	switch s.Orientation {
+	case OrientationHorizontal:
+		return false
+	case OrientationVertical:
+		return true
+	}
+	return v.hasParent && v.parent.Direction == Row
+}
+
+func (s *Separator) thickness() int {
+	if s.Thickness <= 0 {
+		return 1
+	}
+	return s.Thickness
+}
+
+// Size implements Sizer.
+func (s *Separator) Size(v *View) (width, height int) {
+	length := 0
+	if v.hasParent {
+		p := v.parent
+		if s.vertical(v) {
+			length = p.frame.Dy() - p.PaddingTop - p.PaddingBottom - v.MarginTop - v.MarginBottom
+		} else {
+			length = p.frame.Dx() - p.PaddingLeft - p.PaddingRight - v.MarginLeft - v.MarginRight
+		}
+	}
+	if length < 0 {
+		length = 0
+	}
+	if s.vertical(v) {
+		return s.thickness(), length
+	}
+	return length, s.thickness()
+}
+
+// Draw implements Drawer.
+func (s *Separator) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	s.drawBatch(screen, frame, v)
+	batch.Flush()
+}
+
+func (s *Separator) drawBatch(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	line := s.line(frame, v)
+	if line.Empty() {
+		return
+	}
+	opacity := v.EffectiveOpacity()
+	if img := s.Image; img != nil {
+		var clr color.Color
+		if opacity < 1 {
+			clr = fade(nil, opacity)
+		}
+		batch.DrawImage(screen, img, img.Bounds(), line, clr)
+		return
+	}
+	clr := s.Color
+	if clr == nil {
+		clr = v.ComputedColor()
+	}
+	if clr == nil {
+		clr = defaultSeparatorColor
+	}
+	batch.FillRect(screen, line, fade(clr, opacity))
+}
+
+// line returns the bounds of the line, centered in the frame.
+func (s *Separator) line(frame image.Rectangle, v *View) image.Rectangle {
+	t := s.thickness()
+	if s.vertical(v) {
+		x := frame.Min.X + (frame.Dx()-t)/2
+		return image.Rect(x, frame.Min.Y+s.Inset, x+t, frame.Max.Y-s.Inset)
+	}
+	y := frame.Min.Y + (frame.Dy()-t)/2
+	return image.Rect(frame.Min.X+s.Inset, y, frame.Max.X-s.Inset, y+t)
+}
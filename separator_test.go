@@ -0,0 +1,41 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeparator(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px; flex-direction: column; align-items: flex-start; padding: 5px">
+		<view id="a" style="height: 10px"></view>
+		<hr id="line" style="margin: 2px 4px">
+		<view id="row" style="height: 20px; flex-direction: row; color: red">
+			<view style="width: 10px"></view>
+			<hr id="bar" thickness="3" inset="2">
+			<view style="width: 10px"></view>
+		</view>
+		<hr id="last" orientation="vertical" />
+	</view>`, nil)
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+
+	// the line spans the parent less its margins, even without stretch
+	line := root.MustGetByID("line")
+	require.Equal(t, image.Rect(9, 17, 91, 18), line.frame)
+	require.Equal(t, "row", root.Children()[2].ID)
+	require.Equal(t, image.Rect(9, 17, 91, 18), line.Handler.(*Separator).line(line.frame, line))
+
+	// in a row, the line is vertical
+	bar := root.MustGetByID("bar")
+	require.Equal(t, image.Rect(15, 20, 18, 40), bar.frame)
+	require.Equal(t, image.Rect(15, 22, 18, 38), bar.Handler.(*Separator).line(bar.frame, bar))
+	require.Equal(t, color.Color(color.RGBA{255, 0, 0, 255}), bar.ComputedColor())
+
+	last := root.MustGetByID("last")
+	require.Equal(t, OrientationVertical, last.Handler.(*Separator).Orientation)
+	require.Equal(t, image.Rect(5, 40, 6, 130), last.frame)
+}
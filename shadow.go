@@ -0,0 +1,142 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BoxShadow is a soft shadow drawn behind the background of a view.
+type BoxShadow struct {
+	OffsetX int
+	OffsetY int
+	// Blur is the width of the soft edge of the shadow.
+	Blur int
+	// Spread grows the shadow beyond the frame.
+	Spread int
+	// Color is the color of the shadow. Black is used if it is nil.
+	Color color.Color
+}
+
+// shadowKey identifies the rendered shadow mask of a view.
+type shadowKey struct {
+	size   image.Point
+	blur   int
+	spread int
+	radius int
+}
+
+// drawShadow draws the shadow of the view behind the frame.
+// The color of the shadow is faded by the opacity.
+func (v *View) drawShadow(screen *ebiten.Image, frame image.Rectangle, opacity float64) {
+	s := v.BoxShadow
+	if s == nil || frame.Empty() {
+		return
+	}
+	key := shadowKey{size: frame.Size(), blur: s.Blur, spread: s.Spread, radius: v.BorderRadius}
+	if v.shadowImage == nil || v.shadowKey != key {
+		v.releaseShadowImage()
+		v.shadowImage = ebiten.NewImageFromImage(shadowMask(key))
+		v.shadowKey = key
+	}
+	c := s.Color
+	if c == nil {
+		c = color.Black
+	}
+	ext := s.Blur + s.Spread
+	dst := image.Rect(frame.Min.X-ext, frame.Min.Y-ext, frame.Max.X+ext, frame.Max.Y+ext).
+		Add(image.Pt(s.OffsetX, s.OffsetY))
+	batch.DrawImage(screen, v.shadowImage, v.shadowImage.Bounds(), dst, fade(c, opacity))
+}
+
+func (v *View) releaseShadowImage() {
+	if v.shadowImage != nil {
+		v.shadowImage.Dispose()
+		v.shadowImage = nil
+	}
+}
+
+// shadowMask renders the alpha mask of a shadow: the frame grown by
+// the spread with rounded corners, faded out over the blur width.
+func shadowMask(k shadowKey) *image.Alpha {
+	ext := k.blur + k.spread
+	img := image.NewAlpha(image.Rect(0, 0, k.size.X+ext*2, k.size.Y+ext*2))
+	// half extents and radius of the spread box centered at the origin
+	hw := float64(k.size.X)/2 + float64(k.spread)
+	hh := float64(k.size.Y)/2 + float64(k.spread)
+	r := math.Min(float64(k.radius+k.spread), math.Min(hw, hh))
+	if r < 0 {
+		r = 0
+	}
+	cx, cy := float64(img.Rect.Dx())/2, float64(img.Rect.Dy())/2
+	blur := float64(k.blur)
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			d := roundedBoxDistance(float64(x)+0.5-cx, float64(y)+0.5-cy, hw, hh, r)
+			img.Pix[y*img.Stride+x] = uint8(shadowAlpha(d, blur)*255 + 0.5)
+		}
+	}
+	return img
+}
+
+// roundedBoxDistance returns the signed distance from the point to
+// a box with rounded corners (negative inside).
+func roundedBoxDistance(x, y, hw, hh, r float64) float64 {
+	qx := math.Abs(x) - hw + r
+	qy := math.Abs(y) - hh + r
+	outside := math.Hypot(math.Max(qx, 0), math.Max(qy, 0))
+	inside := math.Min(math.Max(qx, qy), 0)
+	return outside + inside - r
+}
+
+func shadowAlpha(d, blur float64) float64 {
+	if blur <= 0 {
+		if d <= 0 {
+			return 1
+		}
+		return 0
+	}
+	// fade from the inner to the outer edge of the blur
+	t := (d + blur/2) / blur
+	if t <= 0 {
+		return 1
+	}
+	if t >= 1 {
+		return 0
+	}
+	return 1 - t*t*(3-2*t)
+}
+
+// parseBoxShadow parses `<offset-x> <offset-y> [<blur> [<spread>]]` with
+// an optional `<color>` before or after the lengths.
+func parseBoxShadow(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "none" {
+		return (*BoxShadow)(nil), nil
+	}
+	s := &BoxShadow{}
+	var lengths []int
+	// colors such as rgba() may contain spaces
+	fields := splitFields(val)
+	for i, f := range fields {
+		if n, err := parseNumber(f); err == nil {
+			lengths = append(lengths, n.(int))
+			continue
+		}
+		c, err := parseColor(f)
+		if err != nil || s.Color != nil || (i != 0 && i != len(fields)-1) {
+			return nil, fmt.Errorf("invalid box-shadow: %s", val)
+		}
+		s.Color = c.(color.Color)
+	}
+	if len(lengths) < 2 || len(lengths) > 4 {
+		return nil, fmt.Errorf("invalid box-shadow: %s", val)
+	}
+	lengths = append(lengths, 0, 0)
+	s.OffsetX, s.OffsetY, s.Blur, s.Spread = lengths[0], lengths[1], lengths[2], lengths[3]
+	return s, nil
+}
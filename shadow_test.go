@@ -0,0 +1,65 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoxShadow(t *testing.T) {
+	tests := []struct {
+		val  string
+		want *BoxShadow
+	}{
+		{"2px 4px", &BoxShadow{OffsetX: 2, OffsetY: 4}},
+		{"0 2px 8px #000", &BoxShadow{OffsetY: 2, Blur: 8, Color: color.NRGBA{0, 0, 0, 255}}},
+		{"1px 1px 4px 2px rgba(0, 0, 0, 0.5)", &BoxShadow{1, 1, 4, 2, color.NRGBA{0, 0, 0, 128}}},
+		{"rgba(0, 0, 0, .5) 0 2px 4px", &BoxShadow{OffsetY: 2, Blur: 4, Color: color.NRGBA{0, 0, 0, 128}}},
+		{"#fff 1px 1px", &BoxShadow{OffsetX: 1, OffsetY: 1, Color: color.NRGBA{255, 255, 255, 255}}},
+		{"none", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseBoxShadow(tt.val)
+		require.NoError(t, err, tt.val)
+		require.Equal(t, tt.want, got, tt.val)
+	}
+	for _, val := range []string{"2px", "1px 2px 3px 4px 5px", "1px 2px wavy", "1px red 2px", "red 1px 2px blue"} {
+		_, err := parseBoxShadow(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestShadowMask(t *testing.T) {
+	mask := shadowMask(shadowKey{size: image.Pt(20, 10), blur: 4, spread: 2})
+	require.Equal(t, image.Rect(0, 0, 32, 22), mask.Bounds())
+	// opaque in the center and transparent at the corners
+	require.Equal(t, uint8(255), mask.AlphaAt(16, 11).A)
+	require.Equal(t, uint8(0), mask.AlphaAt(0, 0).A)
+	// half transparent around the edge of the spread box
+	edge := mask.AlphaAt(4, 11).A
+	require.True(t, edge > 64 && edge < 192, edge)
+
+	hard := shadowMask(shadowKey{size: image.Pt(4, 4)})
+	for _, p := range hard.Pix {
+		require.Equal(t, uint8(255), p)
+	}
+}
+
+func TestDrawShadow(t *testing.T) {
+	view := Parse(`<view style="width: 100px; height: 100px"><view id="card" style="width: 20px; height: 10px; box-shadow: 0 2px 4px black"></view></view>`, nil)
+	card := view.MustGetByID("card")
+	view.Draw(ebiten.NewImage(100, 100))
+	require.NotNil(t, card.shadowImage)
+	require.Equal(t, image.Pt(28, 18), card.shadowImage.Bounds().Size())
+	img := card.shadowImage
+
+	// the mask is reused while the size does not change
+	view.Draw(ebiten.NewImage(100, 100))
+	require.Same(t, img, card.shadowImage)
+
+	card.Release()
+	require.Nil(t, card.shadowImage)
+}
@@ -0,0 +1,301 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Slider is the handler of the built-in <slider> element. It draws a
+// track with a thumb that is dragged by the mouse or a touch to choose a
+// value between Min and Max, e.g. for the volume and the sensitivity in
+// settings screens. Pressing the track moves the thumb there. While the
+// view has the focus, the arrow keys move the thumb by the step.
+//
+// The track is drawn across the middle of the frame, from the left to
+// the right or from the bottom to the top if it is vertical, and the
+// thumb is a circle of the size of the frame across the track.
+//
+// In HTML, the fields are set by the min (0), max (100), step (1), value
+// (halfway) and orientation attributes, and the color of the filled part
+// of the track by the color property:
+//
+//	<slider id="volume" min="0" max="10" value="8" style="width: 200px; height: 24px"></slider>
+type Slider struct {
+	// Min and Max are the range of the value. The value is Min if Max is
+	// not greater than Min.
+	Min float64
+	Max float64
+	// Step rounds the value to multiples of it from Min. The value is not
+	// rounded if it is 0.
+	Step float64
+	// Value is the current value.
+	Value float64
+	// Orientation is the direction of the track. OrientationAuto is
+	// vertical if the frame is taller than wide.
+	Orientation Orientation
+	// OnChange is called with the value when it is changed by the input.
+	OnChange func(value float64)
+
+	// TrackThickness is the thickness of the track in pixels. 4 is used
+	// if it is 0.
+	TrackThickness int
+	// TrackColor is the color of the track. Gray is used if it is nil.
+	TrackColor color.Color
+	// FillColor is the color of the track between Min and the value. The
+	// color of the view (View.ComputedColor) or blue is used if it is nil.
+	FillColor color.Color
+	// ThumbColor is the color of the thumb. White is used if it is nil.
+	ThumbColor color.Color
+	// ThumbImage is stretched to the thumb instead of the color if it is set.
+	ThumbImage *ebiten.Image
+
+	// frame is the frame of the view in the last update.
+	frame    image.Rectangle
+	dragging bool
+	touch    ebiten.TouchID
+	focused  bool
+}
+
+var _ ButtonHandler = (*Slider)(nil)
+var _ Updater = (*Slider)(nil)
+var _ Drawer = (*Slider)(nil)
+var _ FocusHandler = (*Slider)(nil)
+
+var defaultSliderFillColor = color.RGBA{0x33, 0x66, 0xcc, 0xff}
+
+// newSlider creates the handler of a <slider> element from its attributes.
+func newSlider(attrs map[string]string) Handler {
+	s := &Slider{Max: 100, Step: 1}
+	var err error
+	for name, f := range map[string]*float64{"min": &s.Min, "max": &s.Max, "step": &s.Step} {
+		if val, ok := attrs[name]; ok && err == nil {
+			*f, err = strconv.ParseFloat(val, 64)
+		}
+	}
+	s.Value = s.Min + (s.Max-s.Min)/2
+	if val, ok := attrs["value"]; ok && err == nil {
+		s.Value, err = strconv.ParseFloat(val, 64)
+	}
+	if o, ok := attrs["orientation"]; ok && err == nil {
+		s.Orientation, err = parseOrientation(o)
+	}
+	if err != nil {
+		println(fmt.Sprintf("slider: %v", err))
+	}
+	s.Value = s.snap(s.Value)
+	return s
+}
+
+// isVertical returns true if the track in the frame is vertical.
+func (s *Slider) isVertical(frame image.Rectangle) bool {
+	switch s.Orientation {
+	case OrientationHorizontal:
+		return false
+	case OrientationVertical:
+		return true
+	}
+	return frame.Dy() > frame.Dx()
+}
+
+// thumbSize returns the size of the thumb in the frame.
+func (s *Slider) thumbSize(frame image.Rectangle) int {
+	if s.isVertical(frame) {
+		return frame.Dx()
+	}
+	return frame.Dy()
+}
+
+// ratio returns the position of the value between Min and Max from 0 to 1.
+func (s *Slider) ratio() float64 {
+	if s.Max <= s.Min {
+		return 0
+	}
+	return math.Max(0, math.Min(1, (s.Value-s.Min)/(s.Max-s.Min)))
+}
+
+// snap rounds the value to the step and limits it to the range.
+func (s *Slider) snap(value float64) float64 {
+	if s.Max <= s.Min {
+		return s.Min
+	}
+	if s.Step > 0 {
+		value = s.Min + math.Round((value-s.Min)/s.Step)*s.Step
+		if value > s.Max {
+			// the last step may not reach Max
+			value -= s.Step
+		}
+	}
+	return math.Max(s.Min, math.Min(s.Max, value))
+}
+
+// SetValue sets the value rounded to the step without calling OnChange.
+func (s *Slider) SetValue(value float64) {
+	s.Value = s.snap(value)
+}
+
+// change sets the value and calls OnChange if it has changed.
+func (s *Slider) change(value float64) {
+	value = s.snap(value)
+	if value == s.Value {
+		return
+	}
+	s.Value = value
+	if s.OnChange != nil {
+		s.OnChange(value)
+	}
+}
+
+// valueAt returns the value at the location on the track.
+func (s *Slider) valueAt(x, y int) float64 {
+	frame := s.frame
+	half := s.thumbSize(frame) / 2
+	var t float64
+	if s.isVertical(frame) {
+		if l := frame.Dy() - half*2; l > 0 {
+			t = float64(frame.Max.Y-half-y) / float64(l)
+		}
+	} else if l := frame.Dx() - half*2; l > 0 {
+		t = float64(x-frame.Min.X-half) / float64(l)
+	}
+	t = math.Max(0, math.Min(1, t))
+	return s.Min + t*(s.Max-s.Min)
+}
+
+// HandlePress implements ButtonHandler.
+func (s *Slider) HandlePress(x, y int, t ebiten.TouchID) {
+	s.dragging, s.touch = true, t
+	s.change(s.valueAt(x, y))
+}
+
+// HandleRelease implements ButtonHandler.
+func (s *Slider) HandleRelease(x, y int, isCancel bool) {
+	if s.dragging && !isCancel {
+		s.change(s.valueAt(x, y))
+	}
+	s.dragging = false
+}
+
+// HandleFocus implements FocusHandler.
+func (s *Slider) HandleFocus() {
+	s.focused = true
+}
+
+// HandleBlur implements FocusHandler.
+func (s *Slider) HandleBlur() {
+	s.focused = false
+}
+
+// Update implements Updater.
+func (s *Slider) Update(v *View) {
+	s.frame = v.frame
+	if v.Disabled {
+		s.dragging = false
+		return
+	}
+	if s.dragging {
+		s.drag(v)
+	}
+	if !s.focused {
+		return
+	}
+	step := s.Step
+	if step <= 0 {
+		step = (s.Max - s.Min) / 100
+	}
+	for _, k := range []ebiten.Key{ebiten.KeyArrowLeft, ebiten.KeyArrowDown, ebiten.KeyArrowRight, ebiten.KeyArrowUp} {
+		if !isKeyJustPressed(k) && !isKeyRepeated(k) {
+			continue
+		}
+		if k == ebiten.KeyArrowLeft || k == ebiten.KeyArrowDown {
+			s.change(s.Value - step)
+		} else {
+			s.change(s.Value + step)
+		}
+	}
+}
+
+// drag moves the thumb to the mouse cursor or the touch dragging it.
+func (s *Slider) drag(v *View) {
+	var x, y int
+	if s.touch == -1 {
+		if !isMouseButtonPressed(ebiten.MouseButtonLeft) {
+			return
+		}
+		x, y = cursorPosition()
+	} else {
+		if isTouchJustReleased(s.touch) {
+			return
+		}
+		x, y = currentTouchPosition(s.touch)
+	}
+	x, y = v.fromScreen(x, y)
+	s.change(s.valueAt(x, y))
+}
+
+// Draw implements Drawer.
+func (s *Slider) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	s.drawBatch(screen, frame, v)
+	batch.Flush()
+}
+
+func (s *Slider) drawBatch(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	if frame.Empty() {
+		return
+	}
+	opacity := v.EffectiveOpacity()
+	size := s.thumbSize(frame)
+	half := size / 2
+	thickness := s.TrackThickness
+	if thickness <= 0 {
+		thickness = 4
+	}
+	vertical := s.isVertical(frame)
+
+	// the track runs between the centers of the thumb at Min and Max
+	var track, fill, thumb image.Rectangle
+	if vertical {
+		x := frame.Min.X + (frame.Dx()-thickness)/2
+		track = image.Rect(x, frame.Min.Y+half, x+thickness, frame.Max.Y-half)
+		y := track.Max.Y - int(math.Round(float64(track.Dy())*s.ratio()))
+		fill = image.Rect(track.Min.X, y, track.Max.X, track.Max.Y)
+		thumb = image.Rect(frame.Min.X, y-half, frame.Min.X+size, y-half+size)
+	} else {
+		y := frame.Min.Y + (frame.Dy()-thickness)/2
+		track = image.Rect(frame.Min.X+half, y, frame.Max.X-half, y+thickness)
+		x := track.Min.X + int(math.Round(float64(track.Dx())*s.ratio()))
+		fill = image.Rect(track.Min.X, track.Min.Y, x, track.Max.Y)
+		thumb = image.Rect(x-half, frame.Min.Y, x-half+size, frame.Min.Y+size)
+	}
+
+	trackColor := s.TrackColor
+	if trackColor == nil {
+		trackColor = defaultSeparatorColor
+	}
+	fillColor := s.FillColor
+	if fillColor == nil {
+		fillColor = v.ComputedColor()
+	}
+	if fillColor == nil {
+		fillColor = defaultSliderFillColor
+	}
+	batch.FillRoundedRect(screen, track, thickness/2, fade(trackColor, opacity))
+	batch.FillRoundedRect(screen, fill, thickness/2, fade(fillColor, opacity))
+	if img := s.ThumbImage; img != nil {
+		var tint color.Color
+		if opacity < 1 {
+			tint = fade(nil, opacity)
+		}
+		batch.DrawImage(screen, img, img.Bounds(), thumb, tint)
+		return
+	}
+	thumbColor := s.ThumbColor
+	if thumbColor == nil {
+		thumbColor = color.White
+	}
+	batch.FillRoundedRect(screen, thumb, half, fade(thumbColor, opacity))
+}
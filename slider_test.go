@@ -0,0 +1,58 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlider(t *testing.T) {
+	root := Parse(`<view style="width: 200px; height: 200px; align-items: flex-start">
+		<slider id="volume" min="0" max="10" style="width: 120px; height: 20px"></slider>
+		<slider id="gain" step="0" value="30" style="width: 20px; height: 120px"></slider>
+	</view>`, nil)
+	root.Update()
+	volume := root.MustGetByID("volume").Handler.(*Slider)
+	require.Equal(t, 5.0, volume.Value)
+	require.Equal(t, 1.0, volume.Step)
+	gain := root.MustGetByID("gain").Handler.(*Slider)
+	require.Equal(t, 100.0, gain.Max)
+	require.Equal(t, 30.0, gain.Value)
+
+	var changes []float64
+	volume.OnChange = func(value float64) { changes = append(changes, value) }
+	// the track runs from 10 to 110 between the centers of the thumb
+	root.handleMouseButtonLeftPressed(34, 10)
+	require.True(t, volume.dragging)
+	require.Equal(t, 2.0, volume.Value)
+	root.handleMouseButtonLeftReleased(200, 10)
+	require.False(t, volume.dragging)
+	require.Equal(t, []float64{2}, changes)
+	root.handleMouseButtonLeftPressed(0, 10)
+	root.handleMouseButtonLeftReleased(0, 10)
+	require.Equal(t, []float64{2, 0}, changes)
+
+	// the vertical slider goes up from the bottom
+	require.Equal(t, 25.0, gain.valueAt(130, 85))
+	require.Equal(t, 100.0, gain.valueAt(130, 0))
+
+	// the value is rounded to the step and limited to the range
+	volume.SetValue(7.4)
+	require.Equal(t, 7.0, volume.Value)
+	volume.SetValue(20)
+	require.Equal(t, 10.0, volume.Value)
+	s := &Slider{Min: 0, Max: 10, Step: 3}
+	s.SetValue(10)
+	require.Equal(t, 9.0, s.Value)
+
+	// the thumb image is drawn over the track at the value
+	screen := ebiten.NewImage(200, 200)
+	volume.ThumbImage = ebiten.NewImage(8, 8)
+	batch.Flush()
+	volume.drawBatch(screen, image.Rect(0, 0, 120, 20), root.MustGetByID("volume"))
+	require.Equal(t, 2, batch.Len())
+	batch.Flush()
+	root.Draw(screen)
+}
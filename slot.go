@@ -0,0 +1,76 @@
+package furex
+
+// findSlots returns the <slot> elements in the template of a component
+// view in the document order.
+func findSlots(v *View) []*View {
+	var slots []*View
+	for _, c := range v.getChildren() {
+		if c.TagName == "slot" {
+			slots = append(slots, c)
+			continue
+		}
+		slots = append(slots, findSlots(c)...)
+	}
+	return slots
+}
+
+// fillSlots replaces the <slot> elements of the template of the component
+// view with the children written at the call site, which are the children
+// of the view after the first n ones of the template. A child with the
+// slot attribute goes to the slot of the same name, and the others go to
+// the slot without a name. A slot without children keeps its own
+// children as the fallback content. The children with no slot to go to
+// are left at the end of the view.
+func fillSlots(view *View, n int) {
+	content := view.getChildren()[n:]
+	for range content {
+		view.PopChild()
+	}
+	// the children of the call site can have slots of an outer template
+	slots := findSlots(view)
+	if len(slots) == 0 {
+		view.AddChild(content...)
+		return
+	}
+	named := map[string][]*View{}
+	for _, c := range content {
+		name := c.Attrs["slot"]
+		named[name] = append(named[name], c)
+	}
+	for _, s := range slots {
+		name := s.Attrs["name"]
+		fill, ok := named[name]
+		delete(named, name)
+		if !ok {
+			fill = s.getChildren()
+			s.RemoveAll()
+		}
+		s.parent.replaceChild(s, fill)
+	}
+	for _, c := range content {
+		if _, ok := named[c.Attrs["slot"]]; ok {
+			view.AddChild(c)
+		}
+	}
+}
+
+// replaceChild replaces the child view with the views in its place.
+func (v *View) replaceChild(cv *View, views []*View) {
+	for i, c := range v.children {
+		if c.item != cv {
+			continue
+		}
+		children := make([]*child, 0, len(v.children)+len(views)-1)
+		children = append(children, v.children[:i]...)
+		for _, vv := range views {
+			children = append(children, &child{item: vv, handledTouchID: -1})
+			vv.hasParent = true
+			vv.parent = v
+		}
+		v.children = append(children, v.children[i+1:]...)
+		cv.hasParent = false
+		cv.parent = nil
+		v.isDirty = true
+		return
+	}
+}
@@ -0,0 +1,77 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tagNames(views []*View) []string {
+	var names []string
+	for _, v := range views {
+		names = append(names, v.TagName)
+	}
+	return names
+}
+
+func TestSlots(t *testing.T) {
+	panel := func() *View {
+		return Parse(`<view class="panel">
+			<view class="title"><slot name="title"><view id="untitled"></view></slot></view>
+			<view class="body"><slot></slot></view>
+		</view>`, nil)
+	}
+	opts := &ParseOptions{Components: ComponentsMap{"framed-panel": panel}}
+
+	t.Run("fill", func(t *testing.T) {
+		root := Parse(`<view>
+			<framed-panel id="panel">
+				<view id="a"></view>
+				<view id="title" slot="title"></view>
+				<view id="b"></view>
+			</framed-panel>
+		</view>`, opts)
+		p := root.MustGetByID("panel")
+		title, body := p.Children()[0], p.Children()[1]
+		require.Len(t, p.Children(), 2)
+		require.Equal(t, []*View{root.MustGetByID("title")}, title.Children())
+		require.Equal(t, []*View{root.MustGetByID("a"), root.MustGetByID("b")}, body.Children())
+		require.Equal(t, body, root.MustGetByID("a").parent)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		root := Parse(`<view><framed-panel id="panel" /></view>`, opts)
+		p := root.MustGetByID("panel")
+		require.Equal(t, []string{"view"}, tagNames(p.Children()[0].Children()))
+		require.Empty(t, p.Children()[1].Children())
+		_, ok := root.GetByID("untitled")
+		require.True(t, ok)
+	})
+
+	t.Run("unknown slot", func(t *testing.T) {
+		root := Parse(`<view><framed-panel id="panel"><view id="a" slot="footer"></view></framed-panel></view>`, opts)
+		p := root.MustGetByID("panel")
+		require.Len(t, p.Children(), 3)
+		require.Equal(t, root.MustGetByID("a"), p.Children()[2])
+	})
+
+	t.Run("nested templates", func(t *testing.T) {
+		// a template passes its children through to the slot of a component
+		window := func() *View {
+			return Parse(`<view><framed-panel><view id="window-title" slot="title"></view><slot></slot></framed-panel></view>`, opts)
+		}
+		opts := &ParseOptions{Components: ComponentsMap{"framed-panel": panel, "titled-window": window}}
+		root := Parse(`<view><titled-window><view id="content"></view></titled-window></view>`, opts)
+		content := root.MustGetByID("content")
+		require.Equal(t, "body", content.parent.Attrs["class"])
+		require.Equal(t, "title", root.MustGetByID("window-title").parent.Attrs["class"])
+	})
+
+	t.Run("for", func(t *testing.T) {
+		root := Parse(`<view><framed-panel for="item in items"><view>{{ item }}</view></framed-panel></view>`,
+			&ParseOptions{Components: opts.Components, Data: map[string]any{"items": []string{"a", "b"}}})
+		for i, p := range root.Children() {
+			require.Equal(t, []string{"a", "b"}[i], p.Children()[1].Children()[0].Text)
+		}
+	})
+}
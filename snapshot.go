@@ -0,0 +1,84 @@
+package furex
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// Snapshot renders the view and its descendants into a new image of the
+// size of the frame of the view multiplied by the scale, e.g. 2 for
+// sharing cards and photo-mode overlays sharper than the screen. It
+// returns nil if the frame is empty. The view is drawn even if it is
+// hidden.
+//
+// The backgrounds, borders and built-in handlers are drawn at the
+// resolution of the image, not scaled up: the texts are drawn with the
+// faces of the scaled font sizes if their faces are created by
+// Text.FaceFunc or RegisterFontFunc, and scaled otherwise. Other handlers
+// are passed the frames in the image and can draw at the scale of
+// DrawScale.
+func (v *View) Snapshot(scale float64) *ebiten.Image {
+	if scale <= 0 {
+		scale = 1
+	}
+	if v.isDirty {
+		v.startLayout()
+	}
+	frame := v.frame
+	w := int(math.Ceil(float64(frame.Dx()) * scale))
+	h := int(math.Ceil(float64(frame.Dy()) * scale))
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	img := ebiten.NewImage(w, h)
+	batch.Flush()
+	saved := batch.Transform
+	batch.Transform = &graphic.Transform{Origin: frame.Min, Scale: scale}
+	defer func() {
+		batch.Flush()
+		batch.Transform = saved
+	}()
+	if v.isTransparent() || !v.isDisplayed() {
+		return img
+	}
+	v.handleDrawRoot(img, frame)
+	v.drawChildren(img)
+	return img
+}
+
+// DrawScale returns the scale of the frames passed to the handlers being
+// drawn: 1 on the screen, or the scale of the snapshot being drawn (see
+// View.Snapshot), so handlers can draw their contents at the resolution
+// of the image.
+func DrawScale() float64 {
+	if t := batch.Transform; t != nil {
+		return t.Scale
+	}
+	return 1
+}
+
+// targetRect returns the rect of the layout in the coordinates of the
+// image being drawn.
+func targetRect(r image.Rectangle) image.Rectangle {
+	return batch.Transform.Rect(r)
+}
+
+// layoutFrameDrawer is implemented by the built-in handlers that draw
+// with the functions of the package transforming the frames of the layout
+// themselves, such as the text handlers.
+type layoutFrameDrawer interface {
+	drawsLayoutFrame()
+}
+
+// handlerFrame returns the frame passed to the handler: the frame of the
+// layout in the coordinates of the image being drawn, unless the handler
+// transforms it itself.
+func handlerFrame(h Handler, frame image.Rectangle) image.Rectangle {
+	if _, ok := h.(layoutFrameDrawer); ok {
+		return frame
+	}
+	return targetRect(frame)
+}
@@ -0,0 +1,53 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestSnapshot(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 100px; background-color: #000">
+		<view id="card" style="width: 40px; height: 30px; margin-left: 10px; margin-top: 20px; background-color: red; border-radius: 4px; overflow: hidden">
+			<canvas id="canvas" style="width: 20px; height: 10px"></canvas>
+			<text id="label" style="font-size: 10px">Hi</text>
+		</view>
+	</view>`, nil)
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+
+	canvas := root.MustGetByID("canvas").Handler.(*Canvas)
+	var drawn image.Rectangle
+	var scale float64
+	canvas.DrawFunc = func(screen *ebiten.Image, frame image.Rectangle) {
+		drawn, scale = frame, DrawScale()
+	}
+	var sizes []int
+	label := root.MustGetByID("label").Handler.(*Text)
+	label.FaceFunc = func(size int) font.Face {
+		sizes = append(sizes, size)
+		// a face per call, as fonts return
+		f := *basicfont.Face7x13
+		return &f
+	}
+
+	card := root.MustGetByID("card")
+	img := card.Snapshot(2)
+	require.Equal(t, image.Rect(0, 0, 80, 60), img.Bounds())
+	// handlers draw in the coordinates of the image
+	require.Equal(t, image.Rect(0, 0, 40, 20), drawn)
+	require.Equal(t, 2.0, scale)
+	require.Equal(t, 1.0, DrawScale())
+	require.Nil(t, batch.Transform)
+	// the frame on the screen is kept
+	require.Equal(t, image.Rect(10, 20, 30, 30), canvas.Frame())
+	// the text is drawn with the face of the doubled size
+	require.Equal(t, []int{10, 20}, sizes)
+
+	require.Equal(t, image.Rect(0, 0, 100, 100), root.Snapshot(0).Bounds())
+	require.Nil(t, (&View{}).Snapshot(1))
+}
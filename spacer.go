@@ -0,0 +1,52 @@
+package furex
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Spacer returns a view that takes the free space of the line of its
+// parent in proportion to the weight, like the flex-grow of the items,
+// e.g. to push the next items to the end of a row.
+func Spacer(weight float64) *View {
+	return &View{TagName: "spacer", Grow: weight}
+}
+
+// fixedSpacer is the handler of a spacer of a fixed size along the
+// direction of its parent, taking no space across it.
+type fixedSpacer struct {
+	size int
+}
+
+var _ Sizer = (*fixedSpacer)(nil)
+
+// Size implements Sizer.
+func (s *fixedSpacer) Size(v *View) (width, height int) {
+	if v.hasParent && v.parent.Direction == Column {
+		return 0, s.size
+	}
+	return s.size, 0
+}
+
+// newSpacer creates the view of a <spacer> element. It has a weight of 1,
+// or the weight of its weight attribute, unless it has a size attribute:
+// it then has the size along the direction of its parent.
+func newSpacer(attrs map[string]string) *View {
+	if s, ok := attrs["size"]; ok {
+		size, err := strconv.Atoi(strings.TrimSuffix(s, "px"))
+		if err != nil {
+			println("spacer: invalid size: " + s)
+		}
+		return &View{TagName: "spacer", Handler: &fixedSpacer{size: size}}
+	}
+	weight := 1.0
+	if w, ok := attrs["weight"]; ok {
+		f, err := strconv.ParseFloat(w, 64)
+		if err != nil {
+			println("spacer: invalid weight: " + w)
+		} else {
+			weight = f
+		}
+	}
+	return Spacer(weight)
+}
@@ -0,0 +1,53 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpacer(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 10px; flex-direction: row">
+		<view id="a" style="width: 10px"></view>
+		<spacer></spacer>
+		<view id="b" style="width: 10px"></view>
+		<spacer weight="3"></spacer>
+		<view id="c" style="width: 10px"></view>
+		<spacer size="6px"></spacer>
+		<view id="d" style="width: 10px"></view>
+	</view>`, nil)
+	root.Update()
+	// the spacers share the 54 pixels left by weight
+	require.Equal(t, image.Rect(0, 0, 10, 10), root.MustGetByID("a").frame)
+	require.Equal(t, image.Rect(24, 0, 34, 10), root.MustGetByID("b").frame)
+	require.Equal(t, image.Rect(74, 0, 84, 10), root.MustGetByID("c").frame)
+	require.Equal(t, image.Rect(90, 0, 100, 10), root.MustGetByID("d").frame)
+
+	// a fixed spacer only takes its size along the rows and the columns
+	// sized to their content
+	root = Parse(`<view style="width: 100px; height: 100px; align-items: flex-start; justify-content: flex-start">
+		<view id="row" style="flex-direction: row">
+			<view style="width: 10px; height: 10px"></view>
+			<spacer size="40"></spacer>
+			<view id="e" style="width: 10px; height: 10px"></view>
+		</view>
+		<view id="column" style="flex-direction: column">
+			<view style="width: 10px; height: 10px"></view>
+			<spacer size="40"></spacer>
+			<view id="f" style="width: 10px; height: 10px"></view>
+		</view>
+	</view>`, nil)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 60, 10), root.MustGetByID("row").frame)
+	require.Equal(t, image.Rect(50, 0, 60, 10), root.MustGetByID("e").frame)
+	require.Equal(t, image.Rect(60, 0, 70, 60), root.MustGetByID("column").frame)
+	require.Equal(t, image.Rect(60, 50, 70, 60), root.MustGetByID("f").frame)
+
+	// from Go
+	row := &View{Width: 100, Height: 10, Direction: Row}
+	item := &View{Width: 10}
+	row.AddChild(Spacer(1), item)
+	row.Update()
+	require.Equal(t, image.Rect(90, 0, 100, 10), item.frame)
+}
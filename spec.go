@@ -0,0 +1,257 @@
+package furex
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// specVersion is the version of the design spec format.
+const specVersion = 1
+
+// Spec is a design spec of a view tree: a tree of frames with their
+// colors, fonts and images, in a simple JSON format that design tools
+// can import, and that can be converted back to views with ImportSpec.
+type Spec struct {
+	Version int       `json:"version"`
+	Root    *SpecNode `json:"root"`
+}
+
+// SpecNode is a frame or a text in a Spec.
+// The position is relative to the parent node and the colors are
+// written as #rrggbbaa.
+type SpecNode struct {
+	// Name is the id of the view or its tag name.
+	Name   string `json:"name"`
+	Type   string `json:"type"` // "frame" or "text"
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Hidden bool   `json:"hidden,omitempty"`
+	// Opacity is the opacity of the node itself, not multiplied by the parents.
+	Opacity *float64 `json:"opacity,omitempty"`
+
+	Fill         string        `json:"fill,omitempty"`
+	Gradient     *SpecGradient `json:"gradient,omitempty"`
+	Image        string        `json:"image,omitempty"`
+	Stroke       string        `json:"stroke,omitempty"`
+	StrokeWidth  int           `json:"strokeWidth,omitempty"`
+	CornerRadius int           `json:"cornerRadius,omitempty"`
+	Shadow       *SpecShadow   `json:"shadow,omitempty"`
+	ClipsContent bool          `json:"clipsContent,omitempty"`
+
+	Text      string    `json:"text,omitempty"`
+	TextColor string    `json:"textColor,omitempty"`
+	Font      *SpecFont `json:"font,omitempty"`
+
+	Children []*SpecNode `json:"children,omitempty"`
+}
+
+// SpecGradient is a linear gradient fill.
+type SpecGradient struct {
+	Angle float64    `json:"angle"`
+	Stops []SpecStop `json:"stops"`
+}
+
+// SpecStop is a color stop of a SpecGradient.
+type SpecStop struct {
+	Offset float64 `json:"offset"`
+	Color  string  `json:"color"`
+}
+
+// SpecShadow is a drop shadow.
+type SpecShadow struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Blur   int    `json:"blur"`
+	Spread int    `json:"spread"`
+	Color  string `json:"color"`
+}
+
+// SpecFont is the font of a text node in pixels.
+type SpecFont struct {
+	Size       int `json:"size"`
+	LineHeight int `json:"lineHeight"`
+}
+
+// ExportSpec returns the design spec of the view tree as JSON.
+// The tree is laid out first if needed. Images are referenced by the path
+// in the document they were loaded from, if any.
+func ExportSpec(v *View) ([]byte, error) {
+	layoutTree(v)
+	spec := &Spec{Version: specVersion, Root: exportNode(v, v.frame, v.frame.Min)}
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+// exportNode returns the node of the view at the frame.
+// origin is the position of the parent.
+func exportNode(v *View, frame image.Rectangle, origin image.Point) *SpecNode {
+	n := &SpecNode{
+		Name:         v.ID,
+		Type:         "frame",
+		X:            frame.Min.X - origin.X,
+		Y:            frame.Min.Y - origin.Y,
+		Width:        frame.Dx(),
+		Height:       frame.Dy(),
+		Hidden:       v.Hidden || !v.isDisplayed(),
+		Opacity:      v.Opacity,
+		Fill:         specColor(v.BackgroundColor),
+		StrokeWidth:  v.BorderWidth,
+		CornerRadius: v.BorderRadius,
+		ClipsContent: v.Overflow != OverflowVisible,
+	}
+	if n.Name == "" {
+		n.Name = v.TagName
+	}
+	if v.BorderWidth > 0 {
+		c := v.BorderColor
+		if c == nil {
+			c = color.Black
+		}
+		n.Stroke = specColor(c)
+	}
+	if g := v.BackgroundGradient; g != nil {
+		n.Gradient = &SpecGradient{Angle: g.Angle}
+		for _, s := range g.Stops {
+			n.Gradient.Stops = append(n.Gradient.Stops, SpecStop{Offset: s.Offset, Color: specColor(s.Color)})
+		}
+	}
+	if v.BackgroundImage != nil && v.style != nil && v.style.images != nil {
+		for path, img := range v.style.images.images {
+			if img == v.BackgroundImage {
+				n.Image = path
+			}
+		}
+	}
+	if s := v.BoxShadow; s != nil {
+		c := s.Color
+		if c == nil {
+			c = color.Black
+		}
+		n.Shadow = &SpecShadow{X: s.OffsetX, Y: s.OffsetY, Blur: s.Blur, Spread: s.Spread, Color: specColor(c)}
+	}
+	if t, ok := v.Handler.(*Text); ok {
+		n.Type = "text"
+		n.Text = v.Text
+		n.TextColor = specColor(t.color(v))
+		m := t.face(v).Metrics()
+		n.Font = &SpecFont{Size: (m.Ascent + m.Descent).Ceil(), LineHeight: m.Height.Ceil()}
+	}
+	for _, c := range v.children {
+		// the frames of the descendants are computed from the parent
+		// as in drawing, since they can be stale after a layout
+		b := v.computeBounds(c).Add(frame.Min.Sub(v.frame.Min))
+		n.Children = append(n.Children, exportNode(c.item, b, frame.Min))
+	}
+	return n
+}
+
+// layoutTree lays out the dirty views of the tree, including the children
+// of absolutely positioned views that are otherwise laid out when drawn.
+func layoutTree(v *View) {
+	if v.isDirty {
+		v.startLayout()
+	}
+	for _, c := range v.children {
+		layoutTree(c.item)
+	}
+}
+
+// specColor returns the color as #rrggbbaa, or "" if it is nil.
+func specColor(c color.Color) string {
+	if c == nil {
+		return ""
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// ImportSpec creates a view tree from a design spec in JSON, such as one
+// written by ExportSpec or edited in a design tool. The children are
+// positioned absolutely in their parents as in the spec, so the tree is a
+// starting point to be replaced by flex layout where it fits.
+// opts are used to resolve the images as in Parse; it can be nil.
+func ImportSpec(data []byte, opts *ParseOptions) (*View, error) {
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	if spec.Version > specVersion {
+		return nil, fmt.Errorf("unsupported spec version: %d", spec.Version)
+	}
+	if spec.Root == nil {
+		return nil, fmt.Errorf("spec has no root")
+	}
+	if opts == nil {
+		opts = &ParseOptions{}
+	}
+	images := &imageLoader{resolver: opts.ImageResolver, fsys: opts.FS}
+	errs := &ErrorList{}
+	root := importNode(spec.Root, images, errs)
+	root.Left, root.Top = 0, 0
+	root.Position = PositionStatic
+	if errs.HasErrors() {
+		return nil, errs
+	}
+	root.isDirty = true
+	return root, nil
+}
+
+func importNode(n *SpecNode, images *imageLoader, errs *ErrorList) *View {
+	v := &View{
+		ID:           n.Name,
+		Position:     PositionAbsolute,
+		Left:         n.X,
+		Top:          n.Y,
+		Width:        n.Width,
+		Height:       n.Height,
+		Hidden:       n.Hidden,
+		Opacity:      n.Opacity,
+		BorderWidth:  n.StrokeWidth,
+		BorderRadius: n.CornerRadius,
+		style:        &viewStyle{images: images},
+	}
+	if n.ClipsContent {
+		v.Overflow = OverflowHidden
+	}
+	v.BackgroundColor = importColor(n.Fill, errs)
+	v.BorderColor = importColor(n.Stroke, errs)
+	if g := n.Gradient; g != nil {
+		v.BackgroundGradient = &LinearGradient{Angle: g.Angle}
+		for _, s := range g.Stops {
+			v.BackgroundGradient.Stops = append(v.BackgroundGradient.Stops, GradientStop{Offset: s.Offset, Color: importColor(s.Color, errs)})
+		}
+	}
+	if n.Image != "" {
+		img, err := images.load(n.Image)
+		if err != nil {
+			errs.Add(err)
+		}
+		v.BackgroundImage = img
+	}
+	if s := n.Shadow; s != nil {
+		v.BoxShadow = &BoxShadow{OffsetX: s.X, OffsetY: s.Y, Blur: s.Blur, Spread: s.Spread, Color: importColor(s.Color, errs)}
+	}
+	if n.Type == "text" {
+		v.Text = n.Text
+		v.Handler = &Text{Color: importColor(n.TextColor, errs)}
+	}
+	for _, c := range n.Children {
+		v.AddChild(importNode(c, images, errs))
+	}
+	return v
+}
+
+func importColor(s string, errs *ErrorList) color.Color {
+	if s == "" {
+		return nil
+	}
+	c, err := parseColor(s)
+	if err != nil {
+		errs.Add(err)
+		return nil
+	}
+	return c.(color.Color)
+}
@@ -0,0 +1,70 @@
+package furex
+
+import (
+	"encoding/json"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSpec(t *testing.T) {
+	panel := ebiten.NewImage(8, 8)
+	opts := &ParseOptions{
+		Components:    ComponentsMap{"label": func() Handler { return &Text{Color: color.Black} }},
+		ImageResolver: func(string) *ebiten.Image { return panel },
+	}
+	view := Parse(`
+		<view style="width: 200px; height: 100px; padding: 10px; background-color: #102030">
+			<view id="card" style="width: 80px; height: 40px; border: 2px solid #fff; border-radius: 4px; background-image: url(panel.png)">
+				<label id="title" style="width: 60px; height: 13px; margin-left: 5px">Title</label>
+			</view>
+		</view>`, opts)
+
+	data, err := ExportSpec(view)
+	require.NoError(t, err)
+	spec := &Spec{}
+	require.NoError(t, json.Unmarshal(data, spec))
+	require.Equal(t, 1, spec.Version)
+
+	root := spec.Root
+	require.Equal(t, "view", root.Name)
+	require.Equal(t, 200, root.Width)
+	require.Equal(t, "#102030ff", root.Fill)
+
+	card := root.Children[0]
+	require.Equal(t, "card", card.Name)
+	require.Equal(t, 10, card.X)
+	require.Equal(t, 10, card.Y)
+	require.Equal(t, "#ffffffff", card.Stroke)
+	require.Equal(t, 2, card.StrokeWidth)
+	require.Equal(t, 4, card.CornerRadius)
+	require.Equal(t, "panel.png", card.Image)
+
+	title := card.Children[0]
+	require.Equal(t, "text", title.Type)
+	require.Equal(t, 5, title.X)
+	require.Equal(t, "Title", title.Text)
+	require.Equal(t, "#000000ff", title.TextColor)
+	require.Equal(t, &SpecFont{Size: 13, LineHeight: 13}, title.Font)
+
+	// the imported tree has the same frames
+	imported, err := ImportSpec(data, opts)
+	require.NoError(t, err)
+	require.Equal(t, panel, imported.MustGetByID("card").BackgroundImage)
+	require.Equal(t, "Title", imported.MustGetByID("title").Text)
+
+	again, err := ExportSpec(imported)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(again))
+}
+
+func TestImportSpecErrors(t *testing.T) {
+	_, err := ImportSpec([]byte(`{"version": 2, "root": {}}`), nil)
+	require.Error(t, err)
+	_, err = ImportSpec([]byte(`{"version": 1}`), nil)
+	require.Error(t, err)
+	_, err = ImportSpec([]byte(`{"version": 1, "root": {"fill": "nocolor"}}`), nil)
+	require.Error(t, err)
+}
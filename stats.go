@@ -0,0 +1,69 @@
+package furex
+
+import "time"
+
+// StyleStats reports how the stylesheet of a view tree created from HTML
+// has been used. It helps to find rules that never match any view.
+type StyleStats struct {
+	// Rules are the stylesheet rules in the declared order.
+	// A rule with a selector list is reported once per selector.
+	Rules []RuleStats
+	// Matches is the number of style calculations for views,
+	// including recalculations caused by pseudo-classes and media queries.
+	Matches int
+	// MatchTime is the total time spent matching selectors.
+	MatchTime time.Duration
+}
+
+// RuleStats reports how many times a rule matched a view.
+type RuleStats struct {
+	Selector string
+	Media    bool
+	Hits     int
+}
+
+// UnusedRules returns the selectors of the rules that never matched.
+func (s StyleStats) UnusedRules() []string {
+	var ret []string
+	for _, r := range s.Rules {
+		if r.Hits == 0 {
+			ret = append(ret, r.Selector)
+		}
+	}
+	return ret
+}
+
+// StyleStats returns the statistics of the stylesheet the view was
+// created with. It returns empty stats for views not created from HTML.
+func (v *View) StyleStats() StyleStats {
+	if v.style == nil || v.style.sheet == nil {
+		return StyleStats{}
+	}
+	sheet := v.style.sheet
+	stats := StyleStats{
+		Matches:   sheet.matches,
+		MatchTime: sheet.matchTime,
+	}
+	for _, r := range sheet.rules {
+		stats.Rules = append(stats.Rules, RuleStats{
+			Selector: r.selector.text,
+			Media:    r.media != nil,
+			Hits:     r.hits,
+		})
+	}
+	return stats
+}
+
+// ResetStyleStats clears the statistics of the stylesheet the view was
+// created with, e.g. to measure only a single screen of the UI.
+func (v *View) ResetStyleStats() {
+	if v.style == nil || v.style.sheet == nil {
+		return
+	}
+	sheet := v.style.sheet
+	sheet.matches = 0
+	sheet.matchTime = 0
+	for _, r := range sheet.rules {
+		r.hits = 0
+	}
+}
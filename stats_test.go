@@ -0,0 +1,46 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleStats(t *testing.T) {
+	view := Parse(`
+		<head>
+			<style>
+				.item { width: 10px }
+				.unused, .item:hover { height: 10px }
+				@media (max-width: 100px) {
+					.item { width: 5px }
+				}
+			</style>
+		</head>
+		<body>
+			<view>
+				<view class="item"></view>
+				<view class="item"></view>
+			</view>
+		</body>`, &ParseOptions{Width: 200, Height: 200})
+
+	stats := view.StyleStats()
+	require.Equal(t, 3, stats.Matches)
+	require.Equal(t, []RuleStats{
+		{Selector: ".item", Hits: 2},
+		{Selector: ".unused", Hits: 0},
+		{Selector: ".item:hover", Hits: 0},
+		{Selector: ".item", Media: true, Hits: 0},
+	}, stats.Rules)
+	require.Equal(t, []string{".unused", ".item:hover", ".item"}, stats.UnusedRules())
+
+	view.ResetStyleStats()
+	view.Width, view.Height = 100, 100
+	view.restyle()
+	stats = view.StyleStats()
+	require.Equal(t, 3, stats.Matches)
+	require.Equal(t, 2, stats.Rules[3].Hits)
+	require.Equal(t, 2, stats.Rules[0].Hits)
+
+	require.Equal(t, StyleStats{}, (&View{}).StyleStats())
+}
@@ -0,0 +1,208 @@
+package furex
+
+import (
+	"image"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// viewStyle holds the style sources of a view created from HTML,
+// so the style can be recalculated when the state of the view
+// or the size of the root view changes.
+type viewStyle struct {
+	sheet  *stylesheet
+	inline []cssDecl
+	// dynamic is the set of properties declared by the dynamic rules
+	// that matched the view the last time the style was applied.
+	dynamic map[string]bool
+	// images loads the images referenced by the style.
+	images *imageLoader
+	// face is the default face of the texts of the document.
+	face font.Face
+}
+
+func (s *viewStyle) isDynamic() bool {
+	return s != nil && s.sheet != nil && s.sheet.hasDynamicRules
+}
+
+// applyStyle applies the matching rules and the inline style to the view.
+// ancestors are the ancestors of the view from the root. It returns the
+// errors of the invalid declarations, which are skipped.
+func (v *View) applyStyle(ancestors []*View) error {
+	rules := v.style.sheet.matchRules(v, ancestors)
+	v.style.dynamic = dynamicProps(rules)
+	return setDecls(v, cascade(flattenRules(rules), v.style.inline))
+}
+
+// restyle recalculates the properties declared by dynamic rules
+// for the view and its descendants.
+// Only the properties declared by the dynamic rules that matched before
+// or match now are reset and reapplied, so other values set from Go code
+// are kept.
+func (v *View) restyle() {
+	if v.style.isDynamic() {
+		rules := v.style.sheet.matchRules(v, v.ancestors())
+		current := dynamicProps(rules)
+		props := map[string]bool{}
+		for p := range v.style.dynamic {
+			props[p] = true
+		}
+		for p := range current {
+			props[p] = true
+		}
+		v.style.dynamic = current
+		if len(props) > 0 {
+			for p := range props {
+				if m, ok := styleMapper[p]; ok {
+					m.setFunc(v, nil)
+				}
+			}
+			var decls []cssDecl
+			for _, d := range cascade(flattenRules(rules), v.style.inline) {
+				if isRelatedProp(d.property, props) {
+					decls = append(decls, d)
+				}
+			}
+			applyDecls(v, decls)
+			v.Layout()
+		}
+	}
+	for _, c := range v.children {
+		c.item.restyle()
+	}
+}
+
+func dynamicProps(rules []*cssRule) map[string]bool {
+	var props map[string]bool
+	for _, r := range rules {
+		if !r.isDynamic() {
+			continue
+		}
+		for _, d := range r.decls {
+			if props == nil {
+				props = map[string]bool{}
+			}
+			props[d.property] = true
+		}
+	}
+	return props
+}
+
+func flattenRules(rules []*cssRule) []cssDecl {
+	var decls []cssDecl
+	for _, r := range rules {
+		decls = append(decls, r.decls...)
+	}
+	return decls
+}
+
+// isRelatedProp returns true if the property is one of props or
+// a shorthand or longhand of them (e.g. margin and margin-left).
+func isRelatedProp(prop string, props map[string]bool) bool {
+	if props[prop] {
+		return true
+	}
+	for p := range props {
+		if strings.HasPrefix(prop, p+"-") || strings.HasPrefix(p, prop+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *View) hasPseudoClass(name string) bool {
+	switch name {
+	case "hover":
+		return v.hovered
+	case "active":
+		return v.active
+	case "disabled":
+		return v.Disabled
+	case "focus":
+		return v.IsFocused()
+	case "checked":
+		if c, ok := v.Handler.(*Checkbox); ok {
+			return c.Checked
+		}
+		return v.isSelectedOption() || v.isSelectedCell()
+	case "selected":
+		return v.inGridSelection() || v.inListSelection()
+	}
+	return false
+}
+
+// ancestors returns the ancestors of the view from the root.
+func (v *View) ancestors() []*View {
+	var ret []*View
+	for p := v.parent; p != nil; p = p.parent {
+		ret = append(ret, p)
+	}
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret
+}
+
+func (v *View) handlePseudoClassEvents() {
+	if !v.style.isDynamic() {
+		return
+	}
+	x, y := cursorPosition()
+	hover := []image.Point{image.Pt(x, y)}
+	var active []image.Point
+	if isMouseButtonPressed(ebiten.MouseButtonLeft) {
+		active = append(active, image.Pt(x, y))
+	}
+	for _, id := range appendTouchIDs(nil) {
+		p := image.Pt(currentTouchPosition(id))
+		hover = append(hover, p)
+		active = append(active, p)
+	}
+	v.updatePseudoClasses(hover, active)
+}
+
+// updatePseudoClasses updates the hover and active states of the views
+// and restyles the views whose state has changed.
+func (v *View) updatePseudoClasses(hover, active []image.Point) {
+	var changed []*View
+	if m := v.TopModal(); m != nil && !v.hasParent {
+		// the views beneath the modal are neither hovered nor pressed
+		for _, c := range v.children {
+			if c.item == m.Overlay {
+				c.item.collectPseudoClassChanges(hover, active, &changed)
+			} else {
+				c.item.collectPseudoClassChanges(nil, nil, &changed)
+			}
+		}
+	} else {
+		v.collectPseudoClassChanges(hover, active, &changed)
+	}
+	for _, vv := range changed {
+		vv.restyle()
+	}
+}
+
+func (v *View) collectPseudoClassChanges(hover, active []image.Point, changed *[]*View) {
+	visible := !v.Hidden && v.isDisplayed()
+	hover, active = v.untransformPoints(v.frame, hover), v.untransformPoints(v.frame, active)
+	hovered := visible && containsAny(v.frame, hover)
+	pressed := hovered && !v.Disabled && containsAny(v.frame, active)
+	if hovered != v.hovered || pressed != v.active {
+		v.hovered, v.active = hovered, pressed
+		*changed = append(*changed, v)
+	}
+	for _, c := range v.children {
+		c.item.collectPseudoClassChanges(hover, active, changed)
+	}
+}
+
+func containsAny(r image.Rectangle, points []image.Point) bool {
+	for _, p := range points {
+		if isInside(&r, p.X, p.Y) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package furex
+
+import "fmt"
+
+// styleBinding is a style with {{ path }} placeholders bound to the data.
+type styleBinding struct {
+	template string
+	data     map[string]any
+	// decls are the declarations applied last.
+	decls []cssDecl
+}
+
+// BindStyle sets the inline style with the {{ path }} placeholders replaced
+// with the values of the data, like the placeholders in the style
+// attribute, e.g. "width: {{ player.HP }}%". The placeholders are replaced
+// again every update and the declarations whose values changed are
+// applied, so the view follows the values changed in the data, such as the
+// fields of the structs it points to. The other properties keep the values
+// set from Go. An empty style removes the binding.
+func (v *View) BindStyle(style string, data map[string]any) error {
+	if style == "" {
+		v.styleBinding = nil
+		return nil
+	}
+	v.styleBinding = &styleBinding{template: style, data: data}
+	return v.applyStyleBinding()
+}
+
+// applyStyleBinding applies the declarations of the bound style that
+// changed since the last time.
+func (v *View) applyStyleBinding() error {
+	b := v.styleBinding
+	decls := parseDecls(expandPlaceholders(b.data, b.template, fmt.Sprint, nil))
+	var changed []cssDecl
+	for _, d := range decls {
+		if !containsDecl(b.decls, d) {
+			changed = append(changed, d)
+		}
+	}
+	b.decls = decls
+	if len(changed) == 0 {
+		return nil
+	}
+	if v.style != nil {
+		// the dynamic rules are restyled with the current values
+		v.style.inline = decls
+	}
+	v.Layout()
+	return setDecls(v, changed)
+}
+
+// updateStyleBindings applies the bound styles of the views of the
+// subtree.
+func (v *View) updateStyleBindings() {
+	if v.styleBinding != nil {
+		if err := v.applyStyleBinding(); err != nil {
+			println(fmt.Sprintf("bind style errors: %v", err))
+		}
+	}
+	for _, c := range v.children {
+		c.item.updateStyleBindings()
+	}
+}
+
+func containsDecl(decls []cssDecl, d cssDecl) bool {
+	for _, e := range decls {
+		if e == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindStyle(t *testing.T) {
+	type player struct{ HP int }
+	p := &player{HP: 50}
+	data := map[string]any{"player": p, "color": "#ff0000"}
+	root := Parse(`<view style="width: 200px; height: 100px; align-items: flex-start; justify-content: flex-start">
+		<view id="gauge" style="width: {{ .player.HP }}%; height: 10px; background-color: {{ color }}"></view>
+	</view>`, &ParseOptions{Data: data})
+	gauge := root.MustGetByID("gauge")
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 100, 10), gauge.frame)
+
+	// the style follows the data every update
+	p.HP = 25
+	data["color"] = "#00ff00"
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 50, 10), gauge.frame)
+	require.Equal(t, color.NRGBA{0, 0xff, 0, 0xff}, gauge.BackgroundColor)
+
+	// the properties whose values did not change keep the values set from Go
+	gauge.SetHeight(20)
+	p.HP = 75
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 150, 20), gauge.frame)
+
+	// from Go
+	require.NoError(t, gauge.BindStyle("height: {{ player.HP }}px", data))
+	root.Update()
+	require.Equal(t, 75, gauge.frame.Dy())
+	p.HP = 30
+	root.Update()
+	require.Equal(t, 30, gauge.frame.Dy())
+	require.NoError(t, gauge.BindStyle("", nil))
+	p.HP = 40
+	root.Update()
+	require.Equal(t, 30, gauge.frame.Dy())
+	require.Error(t, gauge.BindStyle("colr: {{ color }}", data))
+}
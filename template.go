@@ -0,0 +1,116 @@
+package furex
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// interpolate replaces the {{ path }} placeholders in s with the values
+// of the data of the document (ParseOptions.Data). The errors are reported
+// for the view.
+func (p *parser) interpolate(view *View, s string) string {
+	return p.replacePlaceholders(view, s, fmt.Sprint)
+}
+
+// interpolateMarkup is like interpolate, but escapes the values inserted
+// in the markup.
+func (p *parser) interpolateMarkup(view *View, s string) string {
+	return p.replacePlaceholders(view, s, func(val ...any) string {
+		return html.EscapeString(fmt.Sprint(val...))
+	})
+}
+
+func (p *parser) replacePlaceholders(view *View, s string, format func(...any) string) string {
+	return expandPlaceholders(p.data, s, format, func(path string) {
+		p.error(view, fmt.Errorf("undefined data: %s", path))
+	})
+}
+
+// expandPlaceholders replaces the {{ path }} placeholders in s with the
+// values of the data formatted by format. The path may start with a dot,
+// as in Go templates. undefined is called with the paths not in the data
+// if it is not nil.
+func expandPlaceholders(data map[string]any, s string, format func(...any) string, undefined func(path string)) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	sb := &strings.Builder{}
+	for {
+		start := strings.Index(s, "{{")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(s[start:], "}}")
+		if end == -1 {
+			break
+		}
+		sb.WriteString(s[:start])
+		path := strings.TrimPrefix(strings.TrimSpace(s[start+2:start+end]), ".")
+		if val, ok := lookupData(data, path); ok {
+			sb.WriteString(format(val))
+		} else if undefined != nil {
+			undefined(path)
+		}
+		s = s[start+end+2:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// lookupData returns the value at the dotted path in the data, e.g.
+// "player.name". The path can go through maps with string keys, struct
+// fields, and slices by index.
+func lookupData(data map[string]any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	keys := strings.Split(path, ".")
+	val, ok := data[keys[0]]
+	if !ok {
+		return nil, false
+	}
+	for _, k := range keys[1:] {
+		if val, ok = dataField(val, k); !ok {
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// dataField returns the field of the value with the name.
+func dataField(val any, name string) (any, bool) {
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		f := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !f.IsValid() {
+			return nil, false
+		}
+		return f.Interface(), true
+	case reflect.Struct:
+		f := rv.FieldByName(name)
+		if !f.IsValid() || !f.CanInterface() {
+			return nil, false
+		}
+		return f.Interface(), true
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= rv.Len() {
+			return nil, false
+		}
+		return rv.Index(i).Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,44 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolation(t *testing.T) {
+	type player struct {
+		Name  string
+		Items []string
+	}
+	view, err := ParseStrict(`
+		<view id="root" data-count="{{ count }}">
+			<view id="name" class="{{ theme }}-label">{{ player.Name }} ({{ player.Items.1 }})</view>
+			<rich-text id="rich">Hi <a href="player:{{ id }}">{{ player.Name }}</a></rich-text>
+		</view>`, &ParseOptions{Data: map[string]any{
+		"count":  3,
+		"theme":  "dark",
+		"id":     42,
+		"player": &player{Name: "<Alice>", Items: []string{"sword", "shield"}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "3", view.Attrs["data-count"])
+	name := view.MustGetByID("name")
+	require.Equal(t, "<Alice> (shield)", name.Text)
+	require.Equal(t, "dark-label", name.Attrs["class"])
+	require.Equal(t, `Hi <a href="player:42">&lt;Alice&gt;</a>`, view.MustGetByID("rich").Text)
+
+	_, err = ParseStrict(`<view>{{ missing }}</view>`, nil)
+	require.EqualError(t, err, "line 1: <view>: undefined data: missing")
+}
+
+func TestLookupData(t *testing.T) {
+	data := map[string]any{"a": map[string]any{"b": []int{1, 2}}}
+	v, ok := lookupData(data, "a.b.1")
+	require.True(t, ok)
+	require.Equal(t, 2, v)
+	_, ok = lookupData(data, "a.c")
+	require.False(t, ok)
+	_, ok = lookupData(data, "a.b.2")
+	require.False(t, ok)
+}
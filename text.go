@@ -0,0 +1,373 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Text is a handler that draws the text of the view (View.Text).
+// Rendered text is kept in a cache shared by all text components.
+type Text struct {
+	// Face is the font face. The face of the view (View.ComputedFace) or
+	// basicfont.Face7x13 is used if it is nil.
+	Face font.Face
+	// FaceFunc returns the font face of a size in pixels. If it is set,
+	// it is used instead of Face with the font size of the view
+	// (View.ComputedFontSize) rounded to pixels.
+	FaceFunc func(size int) font.Face
+	// Color is the color of the text. The color of the view
+	// (View.ComputedColor) or white is used if it is nil.
+	Color color.Color
+	// Align and VerticalAlign align the text in the frame. The alignments
+	// of the view (View.TextAlign and View.VerticalAlign) are used if
+	// they are left and top.
+	Align         TextAlign
+	VerticalAlign VerticalAlign
+
+	// keys are the runs drawn last time in the text cache, and held the
+	// runs counted as drawn by the text (see holdKeys).
+	keys []textKey
+	held []textKey
+	// sized is the face of FaceFunc at size.
+	sized font.Face
+	size  int
+}
+
+var _ Drawer = (*Text)(nil)
+var _ MemoryReporter = (*Text)(nil)
+var _ Releaser = (*Text)(nil)
+var _ HeightMeasurer = (*Text)(nil)
+
+// Draw implements Drawer.
+func (t *Text) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	if v.Text == "" {
+		return
+	}
+	face, clr := t.face(v), t.color(v)
+	opacity := v.EffectiveOpacity()
+	t.keys = t.keys[:0]
+	align, valign := t.align(v)
+	var runs []textRun
+	if v.WritingMode == WritingModeVerticalRL {
+		runs = verticalRuns(v.Text, face, frame, align, valign)
+	} else if v.Columns > 1 {
+		runs = columnRuns(v, face, frame, align)
+	} else if v.TextWrap == TextWrapWrap {
+		lines, ends := wrapText(v.Text, face, frame.Dx())
+		runs = alignLines(lines, ends, face, frame, align, valign)
+	} else {
+		runs = alignText(v.Text, face, frame, align, valign)
+	}
+	for _, r := range runs {
+		t.keys = append(t.keys, newTextKey(face, r.text, clr))
+		drawText(screen, r.text, face, r.x, r.y, clr, opacity)
+	}
+	t.holdKeys()
+}
+
+// Size returns the size of the text of the view in its writing mode,
+// e.g. to size the view to the text.
+func (t *Text) Size(v *View) (width, height int) {
+	s := textSize(v.Text, t.face(v), v.WritingMode)
+	return s.X, s.Y
+}
+
+// MeasureHeight implements HeightMeasurer. It returns the height of the
+// text wrapped at the width if the view wraps its text or flows it across
+// columns, or the height of the text otherwise.
+func (t *Text) MeasureHeight(v *View, width int) int {
+	face := t.face(v)
+	lineHeight := face.Metrics().Height.Ceil()
+	if v.WritingMode == WritingModeHorizontalTB {
+		switch {
+		case v.Columns > 1:
+			w := (width - v.ColumnGap*(v.Columns-1)) / v.Columns
+			lines, _ := wrapText(v.Text, face, w)
+			return (len(lines) + v.Columns - 1) / v.Columns * lineHeight
+		case v.TextWrap == TextWrapWrap:
+			lines, _ := wrapText(v.Text, face, width)
+			return len(lines) * lineHeight
+		}
+	}
+	_, h := t.Size(v)
+	return h
+}
+
+// textRun is a run of text at the origin (x, y).
+type textRun struct {
+	text string
+	x, y int
+}
+
+// alignText returns the runs of the text aligned in the frame.
+// The text is a single run if it is aligned to the top left.
+func alignText(s string, face font.Face, frame image.Rectangle, align TextAlign, valign VerticalAlign) []textRun {
+	if align == TextAlignLeft && valign == VerticalAlignTop {
+		return []textRun{{s, frame.Min.X, frame.Min.Y + face.Metrics().Ascent.Ceil()}}
+	}
+	lines := strings.Split(s, "\n")
+	ends := make([]bool, len(lines))
+	ends[len(ends)-1] = true
+	return alignLines(lines, ends, face, frame, align, valign)
+}
+
+// alignLines returns the runs of the lines aligned one by one in the
+// frame. The lines are justified except those ending a paragraph (ends).
+func alignLines(lines []string, ends []bool, face font.Face, frame image.Rectangle, align TextAlign, valign VerticalAlign) []textRun {
+	m := face.Metrics()
+	lineHeight := m.Height.Ceil()
+	y := frame.Min.Y
+	switch valign {
+	case VerticalAlignMiddle:
+		y += (frame.Dy() - lineHeight*len(lines)) / 2
+	case VerticalAlignBottom:
+		y += frame.Dy() - lineHeight*len(lines)
+	}
+	var runs []textRun
+	for i, line := range lines {
+		baseline := y + i*lineHeight + m.Ascent.Ceil()
+		if align == TextAlignJustify && !ends[i] {
+			runs = append(runs, justifyLine(line, face, frame, baseline)...)
+			continue
+		}
+		x := frame.Min.X
+		switch align {
+		case TextAlignCenter:
+			x += (frame.Dx() - font.MeasureString(face, line).Ceil()) / 2
+		case TextAlignRight:
+			x += frame.Dx() - font.MeasureString(face, line).Ceil()
+		}
+		runs = append(runs, textRun{line, x, baseline})
+	}
+	return runs
+}
+
+// justifyLine returns the words of the line with the spaces between them
+// stretched to fill the width of the frame.
+func justifyLine(line string, face font.Face, frame image.Rectangle, baseline int) []textRun {
+	words := strings.Fields(line)
+	widths := make([]int, len(words))
+	total := 0
+	for i, w := range words {
+		widths[i] = font.MeasureString(face, w).Ceil()
+		total += widths[i]
+	}
+	gaps := len(words) - 1
+	extra := frame.Dx() - total
+	x := frame.Min.X
+	runs := make([]textRun, len(words))
+	for i, w := range words {
+		runs[i] = textRun{w, x, baseline}
+		x += widths[i]
+		if i < gaps {
+			// the rounding errors are spread over the gaps
+			x += extra*(i+1)/gaps - extra*i/gaps
+		}
+	}
+	return runs
+}
+
+// ReportMemory implements MemoryReporter.
+func (t *Text) ReportMemory(usage *MemoryUsage) {
+	for _, k := range t.keys {
+		usage.GlyphBytes += sharedTextCache.size(k)
+	}
+}
+
+// Release implements Releaser. The runs drawn by other texts too are kept
+// in the cache.
+func (t *Text) Release() {
+	sharedTextCache.release(t.held)
+	t.keys, t.held = nil, nil
+}
+
+// holdKeys counts the runs drawn last time as drawn by the text in the
+// cache if they have changed, so that releasing the text removes only
+// the runs no other text draws.
+func (t *Text) holdKeys() {
+	if equalTextKeys(t.keys, t.held) {
+		return
+	}
+	sharedTextCache.hold(t.keys, t.held)
+	t.held = append(t.held[:0], t.keys...)
+}
+
+func equalTextKeys(a, b []textKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// drawsLayoutFrame implements layoutFrameDrawer.
+func (t *Text) drawsLayoutFrame() {}
+
+func (t *Text) face(v *View) font.Face {
+	if t.FaceFunc != nil {
+		size := int(math.Round(v.ComputedFontSize()))
+		if t.sized == nil || t.size != size {
+			t.sized, t.size = t.FaceFunc(size), size
+			recordSizedFace(t.sized, t.FaceFunc, size)
+		}
+		if t.sized != nil {
+			return t.sized
+		}
+	}
+	if t.Face != nil {
+		return t.Face
+	}
+	if face := v.ComputedFace(); face != nil {
+		return face
+	}
+	return basicfont.Face7x13
+}
+
+func (t *Text) color(v *View) color.Color {
+	if t.Color != nil {
+		return t.Color
+	}
+	if c := v.ComputedColor(); c != nil {
+		return c
+	}
+	return color.White
+}
+
+// align returns the alignments of the text in the frame.
+func (t *Text) align(v *View) (TextAlign, VerticalAlign) {
+	align, valign := t.Align, t.VerticalAlign
+	if align == TextAlignLeft {
+		align = v.TextAlign
+	}
+	if valign == VerticalAlignTop {
+		valign = v.VerticalAlign
+	}
+	return align, valign
+}
+
+// ComputedColor returns the color of the texts of the view, inherited
+// from the parent if Color is nil. It returns nil if the view and its
+// ancestors have no color.
+func (v *View) ComputedColor() color.Color {
+	for p := v; p != nil; p = p.parent {
+		if p.Color != nil {
+			return p.Color
+		}
+	}
+	return nil
+}
+
+// TextAlign is the 'text-align' property. It aligns the lines of the text
+// of the view horizontally in the frame.
+type TextAlign uint8
+
+const (
+	TextAlignLeft TextAlign = iota
+	TextAlignCenter
+	TextAlignRight
+	// TextAlignJustify stretches the spaces between the words so that the
+	// lines fill the width of the frame, except the last one.
+	TextAlignJustify
+)
+
+func (a TextAlign) String() string {
+	switch a {
+	case TextAlignLeft:
+		return "left"
+	case TextAlignCenter:
+		return "center"
+	case TextAlignRight:
+		return "right"
+	case TextAlignJustify:
+		return "justify"
+	}
+	return fmt.Sprintf("unknown text-align: %d", a)
+}
+
+// VerticalAlign is the 'vertical-align' property. It aligns the text of
+// the view vertically in the frame.
+type VerticalAlign uint8
+
+const (
+	VerticalAlignTop VerticalAlign = iota
+	VerticalAlignMiddle
+	VerticalAlignBottom
+)
+
+func (a VerticalAlign) String() string {
+	switch a {
+	case VerticalAlignTop:
+		return "top"
+	case VerticalAlignMiddle:
+		return "middle"
+	case VerticalAlignBottom:
+		return "bottom"
+	}
+	return fmt.Sprintf("unknown vertical-align: %d", a)
+}
+
+// TextWrap is the 'text-wrap' property. It decides whether the text of
+// the view is wrapped at the width of the frame.
+type TextWrap uint8
+
+const (
+	TextWrapNoWrap TextWrap = iota
+	TextWrapWrap
+)
+
+func (w TextWrap) String() string {
+	switch w {
+	case TextWrapNoWrap:
+		return "nowrap"
+	case TextWrapWrap:
+		return "wrap"
+	}
+	return fmt.Sprintf("unknown text-wrap: %d", w)
+}
+
+func parseTextAlign(val string) (any, error) {
+	switch val {
+	case "left", "start":
+		return TextAlignLeft, nil
+	case "center":
+		return TextAlignCenter, nil
+	case "right", "end":
+		return TextAlignRight, nil
+	case "justify":
+		return TextAlignJustify, nil
+	}
+	return TextAlignLeft, fmt.Errorf("unknown text-align: %s", val)
+}
+
+func parseVerticalAlign(val string) (any, error) {
+	switch val {
+	case "top":
+		return VerticalAlignTop, nil
+	case "middle":
+		return VerticalAlignMiddle, nil
+	case "bottom":
+		return VerticalAlignBottom, nil
+	}
+	return VerticalAlignTop, fmt.Errorf("unknown vertical-align: %s", val)
+}
+
+func parseTextWrap(val string) (any, error) {
+	switch val {
+	case "nowrap":
+		return TextWrapNoWrap, nil
+	case "wrap":
+		return TextWrapWrap, nil
+	}
+	return TextWrapNoWrap, fmt.Errorf("unknown text-wrap: %s", val)
+}
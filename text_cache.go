@@ -0,0 +1,241 @@
+package furex
+
+import (
+	"container/list"
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// DefaultTextCacheSize is the default size of the text cache in bytes.
+const DefaultTextCacheSize = 16 << 20
+
+// textCache keeps rendered text runs so that a run is drawn with
+// a single DrawImage call instead of one call per glyph.
+// Consecutive DrawImage calls are batched by Ebitengine, so drawing many
+// cached runs results in a few draw calls. The cache is shared by all
+// text components and evicts the least recently used runs.
+type textCache struct {
+	mu       sync.Mutex
+	entries  map[textKey]*list.Element
+	lru      *list.List
+	bytes    int64
+	maxBytes int64
+}
+
+// textKey identifies a rendered run. The face includes the size.
+type textKey struct {
+	face  font.Face
+	color color.RGBA64
+	text  string
+}
+
+type textEntry struct {
+	key   textKey
+	image *ebiten.Image
+	// bounds is the bounds of the run relative to the origin (dot).
+	bounds image.Rectangle
+	// holders is the number of the components drawing the run, which is
+	// removed when the last one is released (see Text.Release).
+	holders int
+}
+
+var sharedTextCache = newTextCache(DefaultTextCacheSize)
+
+func newTextCache(maxBytes int64) *textCache {
+	return &textCache{
+		entries:  map[textKey]*list.Element{},
+		lru:      list.New(),
+		maxBytes: maxBytes,
+	}
+}
+
+// SetTextCacheSize sets the maximum size of the text cache in bytes.
+// Zero disables the cache.
+func SetTextCacheSize(bytes int64) {
+	c := sharedTextCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = bytes
+	c.evict()
+}
+
+// DrawText draws the text with the origin of the first line at (x, y)
+// using the shared text cache. Custom text components can use it to
+// share the cache with the built-in ones.
+func DrawText(screen *ebiten.Image, s string, face font.Face, x, y int, clr color.Color) {
+	drawText(screen, s, face, x, y, clr, 1)
+}
+
+// drawText draws the text faded by the opacity. The cached run is drawn
+// with a scaled alpha, so fading does not render the run again. In a
+// snapshot, the text is drawn with the face of the scaled size if there
+// is one, or scaled.
+func drawText(screen *ebiten.Image, s string, face font.Face, x, y int, clr color.Color, opacity float64) {
+	if s == "" {
+		return
+	}
+	if t := batch.Transform; t != nil {
+		p := t.Point(image.Pt(x, y))
+		if scaled, ok := scaledFace(face, t.Scale); ok {
+			drawTextAt(screen, s, scaled, p.X, p.Y, clr, opacity)
+			return
+		}
+		drawTextScaledAt(screen, s, face, p.X, p.Y, t.Scale, clr, opacity)
+		return
+	}
+	drawTextAt(screen, s, face, x, y, clr, opacity)
+}
+
+// drawTextAt draws the text in the coordinates of the screen.
+func drawTextAt(screen *ebiten.Image, s string, face font.Face, x, y int, clr color.Color, opacity float64) {
+	e := sharedTextCache.get(face, s, clr)
+	if e == nil {
+		text.Draw(screen, s, face, x, y, fade(clr, opacity))
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x+e.bounds.Min.X), float64(y+e.bounds.Min.Y))
+	op.ColorScale.ScaleAlpha(float32(opacity))
+	screen.DrawImage(e.image, op)
+}
+
+// drawTextScaled draws the text like drawText, scaled around the origin.
+func drawTextScaled(screen *ebiten.Image, s string, face font.Face, x, y int, scale float64, clr color.Color, opacity float64) {
+	if s == "" {
+		return
+	}
+	if t := batch.Transform; t != nil {
+		p := t.Point(image.Pt(x, y))
+		x, y, scale = p.X, p.Y, scale*t.Scale
+	}
+	drawTextScaledAt(screen, s, face, x, y, scale, clr, opacity)
+}
+
+// drawTextScaledAt draws the scaled text in the coordinates of the screen.
+func drawTextScaledAt(screen *ebiten.Image, s string, face font.Face, x, y int, scale float64, clr color.Color, opacity float64) {
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	e := sharedTextCache.get(face, s, clr)
+	if e == nil {
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate(float64(x), float64(y))
+		op.ColorScale.ScaleWithColor(fade(clr, opacity))
+		text.DrawWithOptions(screen, s, face, op)
+		return
+	}
+	op.GeoM.Translate(float64(e.bounds.Min.X), float64(e.bounds.Min.Y))
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleAlpha(float32(opacity))
+	screen.DrawImage(e.image, op)
+}
+
+func newTextKey(face font.Face, s string, clr color.Color) textKey {
+	r, g, b, a := clr.RGBA()
+	return textKey{
+		face:  face,
+		color: color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)},
+		text:  s,
+	}
+}
+
+// get returns the rendered run, rendering it if needed.
+// It returns nil if the run does not fit in the cache.
+func (c *textCache) get(face font.Face, s string, clr color.Color) *textEntry {
+	key := newTextKey(face, s, clr)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		return el.Value.(*textEntry)
+	}
+	b := text.BoundString(face, s)
+	if b.Empty() {
+		return nil
+	}
+	size := int64(b.Dx()) * int64(b.Dy()) * 4
+	if size > c.maxBytes {
+		return nil
+	}
+	img := ebiten.NewImage(b.Dx(), b.Dy())
+	text.Draw(img, s, face, -b.Min.X, -b.Min.Y, clr)
+	e := &textEntry{key: key, image: img, bounds: b}
+	c.entries[key] = c.lru.PushFront(e)
+	c.bytes += size
+	c.evict()
+	return e
+}
+
+// remove removes the run from the cache.
+func (c *textCache) remove(key textKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// hold counts the components drawing the runs of keys instead of the runs
+// of prev.
+func (c *textCache) hold(keys, prev []textKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		if el, ok := c.entries[k]; ok {
+			el.Value.(*textEntry).holders++
+		}
+	}
+	for _, k := range prev {
+		if el, ok := c.entries[k]; ok && el.Value.(*textEntry).holders > 0 {
+			el.Value.(*textEntry).holders--
+		}
+	}
+}
+
+// release stops holding the runs, and removes the runs no other component
+// draws. The runs rendered again after they were evicted are not counted,
+// and are left to the eviction.
+func (c *textCache) release(keys []textKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		el, ok := c.entries[k]
+		if !ok {
+			continue
+		}
+		e := el.Value.(*textEntry)
+		if e.holders > 0 {
+			e.holders--
+		}
+		if e.holders == 0 {
+			c.removeElement(el)
+		}
+	}
+}
+
+// size returns the memory used by the run, or zero if it is not cached.
+func (c *textCache) size(key textKey) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		return ImageBytes(el.Value.(*textEntry).image)
+	}
+	return 0
+}
+
+func (c *textCache) evict() {
+	for c.bytes > c.maxBytes && c.lru.Len() > 0 {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *textCache) removeElement(el *list.Element) {
+	e := c.lru.Remove(el).(*textEntry)
+	delete(c.entries, e.key)
+	c.bytes -= ImageBytes(e.image)
+	e.image.Dispose()
+}
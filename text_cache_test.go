@@ -0,0 +1,91 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestTextCache(t *testing.T) {
+	face := basicfont.Face7x13
+	c := newTextCache(DefaultTextCacheSize)
+
+	e := c.get(face, "Hello", color.White)
+	require.NotNil(t, e)
+	require.Same(t, e, c.get(face, "Hello", color.White))
+	require.NotSame(t, e, c.get(face, "Hello", color.Black))
+	require.Equal(t, 2, c.lru.Len())
+
+	size := ImageBytes(e.image)
+	require.Equal(t, size, c.size(e.key))
+	require.Equal(t, size*2, c.bytes)
+
+	c.remove(e.key)
+	require.Equal(t, int64(0), c.size(e.key))
+	require.Equal(t, size, c.bytes)
+
+	require.Nil(t, c.get(face, "", color.White))
+}
+
+func TestTextCacheEviction(t *testing.T) {
+	face := basicfont.Face7x13
+	c := newTextCache(0)
+	require.Nil(t, c.get(face, "too large", color.White))
+
+	// room for two runs of two characters
+	c.maxBytes = ImageBytes(newTextCache(DefaultTextCacheSize).get(face, "ab", color.White).image) * 2
+	first := c.get(face, "ab", color.White)
+	c.get(face, "cd", color.White)
+	// touch the first run so that the second one is evicted
+	c.get(face, "ab", color.White)
+	c.get(face, "ef", color.White)
+	require.Equal(t, 2, c.lru.Len())
+	require.Same(t, first, c.get(face, "ab", color.White))
+	require.Equal(t, int64(0), c.size(newTextKey(face, "cd", color.White)))
+}
+
+func TestTextHandler(t *testing.T) {
+	screen := ebiten.NewImage(100, 100)
+	handler := &Text{Color: color.White}
+	view := &View{Text: "Hello", Handler: handler}
+	handler.Draw(screen, image.Rect(0, 0, 100, 20), view)
+
+	usage := view.MemoryUsage()
+	require.Greater(t, usage.GlyphBytes, int64(0))
+
+	handler.Release()
+	require.Equal(t, int64(0), view.MemoryUsage().GlyphBytes)
+}
+
+func TestTextReleaseShared(t *testing.T) {
+	screen := ebiten.NewImage(100, 100)
+	hidden, shown := &Text{Color: color.White}, &Text{Color: color.White}
+	hiddenView := &View{Text: "Shared", Handler: hidden}
+	shownView := &View{Text: "Shared", Handler: shown}
+	hidden.Draw(screen, image.Rect(0, 0, 100, 20), hiddenView)
+	shown.Draw(screen, image.Rect(0, 20, 100, 40), shownView)
+	key := shown.keys[0]
+	entry := sharedTextCache.entries[key].Value.(*textEntry)
+	require.Equal(t, 2, entry.holders)
+
+	// the run drawn by the other text is kept
+	hidden.Release()
+	require.Greater(t, shownView.MemoryUsage().GlyphBytes, int64(0))
+	shown.Draw(screen, image.Rect(0, 20, 100, 40), shownView)
+	require.Same(t, entry, sharedTextCache.entries[key].Value.(*textEntry))
+
+	// and removed with the last one
+	shown.Release()
+	require.NotContains(t, sharedTextCache.entries, key)
+
+	// a text drawing another run stops holding the previous one
+	shown.Draw(screen, image.Rect(0, 20, 100, 40), shownView)
+	shownView.Text = "Other"
+	shown.Draw(screen, image.Rect(0, 20, 100, 40), shownView)
+	require.Equal(t, 0, sharedTextCache.entries[key].Value.(*textEntry).holders)
+	shown.Release()
+}
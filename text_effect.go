@@ -0,0 +1,115 @@
+package furex
+
+import (
+	"image/color"
+	"math"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// TextEffect is a set of per-character animations of rich text.
+type TextEffect int
+
+const (
+	// TextEffectWave moves the characters up and down in a wave (<wave>).
+	TextEffectWave TextEffect = 1 << iota
+	// TextEffectShake jitters the characters (<shake>).
+	TextEffectShake
+	// TextEffectRainbow cycles the colors of the characters (<rainbow>).
+	TextEffectRainbow
+)
+
+// textEffectTags are the tags of the effects in rich text.
+var textEffectTags = map[string]TextEffect{
+	"wave":    TextEffectWave,
+	"shake":   TextEffectShake,
+	"rainbow": TextEffectRainbow,
+}
+
+const (
+	// waveTicks is the period of the wave in ticks at 60 TPS.
+	waveTicks = 60
+	// wavePhase is the phase between two characters of the wave.
+	wavePhase = 0.6
+	// shakeTicks is the number of ticks a jitter of the shake lasts.
+	shakeTicks = 3
+	// rainbowTicks is the period of the colors in ticks at 60 TPS.
+	rainbowTicks = 120
+	// rainbowPhase is the hue between two characters, in degrees.
+	rainbowPhase = 30
+)
+
+// glyphEffect returns the offset and the color of the index-th character
+// of a run with the effects at the tick. clr is the color of the text.
+func glyphEffect(effects TextEffect, tick, index, lineHeight int, clr color.Color) (dx, dy int, c color.Color) {
+	c = clr
+	// the periods are in ticks at 60 TPS
+	t := float64(tick) * 60 / float64(tps())
+	if effects&TextEffectWave != 0 {
+		amp := float64(lineHeight) / 8
+		dy += int(math.Round(amp * math.Sin(2*math.Pi*t/waveTicks+float64(index)*wavePhase)))
+	}
+	if effects&TextEffectShake != 0 {
+		amp := lineHeight/12 + 1
+		h := jitterHash(int(t)/shakeTicks, index)
+		dx += int(h%uint32(2*amp+1)) - amp
+		dy += int((h>>16)%uint32(2*amp+1)) - amp
+	}
+	if effects&TextEffectRainbow != 0 {
+		_, _, _, a := clr.RGBA()
+		hue := math.Mod(360*t/rainbowTicks+float64(index)*rainbowPhase, 360)
+		c = hueColor(hue, uint8(a>>8))
+	}
+	return dx, dy, c
+}
+
+// jitterHash returns a pseudo-random number for the step and the index.
+func jitterHash(step, index int) uint32 {
+	h := uint32(step)*0x9e3779b1 ^ uint32(index)*0x85ebca77
+	h ^= h >> 15
+	h *= 0xc2b2ae3d
+	h ^= h >> 13
+	return h
+}
+
+// hueColor returns the fully saturated color of the hue in degrees.
+func hueColor(hue float64, alpha uint8) color.Color {
+	x := 1 - math.Abs(math.Mod(hue/60, 2)-1)
+	var r, g, b float64
+	switch int(hue / 60) {
+	case 0:
+		r, g = 1, x
+	case 1:
+		r, g = x, 1
+	case 2:
+		g, b = 1, x
+	case 3:
+		g, b = x, 1
+	case 4:
+		r, b = x, 1
+	default:
+		r, b = 1, x
+	}
+	a := float64(alpha) / 0xff
+	// premultiplied by the alpha
+	return color.RGBA{uint8(r * a * 0xff), uint8(g * a * 0xff), uint8(b * a * 0xff), alpha}
+}
+
+// drawEffectText draws the text of the run character by character with
+// the effects.
+func drawEffectText(screen *ebiten.Image, run richRun, face font.Face, lineHeight, tick int, clr color.Color, opacity float64) {
+	index := 0
+	for i, r := range run.text {
+		if r == ' ' {
+			index++
+			continue
+		}
+		s := run.text[i : i+utf8.RuneLen(r)]
+		x := run.x + font.MeasureString(face, run.text[:i]).Round()
+		dx, dy, c := glyphEffect(run.effects, tick, index, lineHeight, clr)
+		drawText(screen, s, face, x+dx, run.y+dy, c, opacity)
+		index++
+	}
+}
@@ -0,0 +1,41 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTextEffects(t *testing.T) {
+	spans := parseRichText(`<wave>Hi <rainbow>there</rainbow></wave> <shake>!</shake>`)
+	require.Equal(t, []richSpan{
+		{text: "Hi ", effects: TextEffectWave},
+		{text: "there", effects: TextEffectWave | TextEffectRainbow},
+		{text: " "},
+		{text: "!", effects: TextEffectShake},
+	}, spans)
+}
+
+func TestGlyphEffect(t *testing.T) {
+	white := color.White
+	dx, dy, c := glyphEffect(TextEffectWave, 0, 0, 16, white)
+	require.Equal(t, 0, dx)
+	require.Equal(t, 0, dy)
+	require.Equal(t, white, c)
+
+	// a quarter of the period later, the first character is at the lowest
+	_, dy, _ = glyphEffect(TextEffectWave, waveTicks/4, 0, 16, white)
+	require.Equal(t, 2, dy)
+
+	for tick := 0; tick < 30; tick++ {
+		dx, dy, _ := glyphEffect(TextEffectShake, tick, tick%4, 12, white)
+		require.LessOrEqual(t, dx*dx, 4)
+		require.LessOrEqual(t, dy*dy, 4)
+	}
+
+	_, _, c = glyphEffect(TextEffectRainbow, 0, 0, 16, white)
+	require.Equal(t, color.RGBA{0xff, 0, 0, 0xff}, c)
+	_, _, c = glyphEffect(TextEffectRainbow, 0, 4, 16, white)
+	require.Equal(t, color.RGBA{0, 0xff, 0, 0xff}, c)
+}
@@ -0,0 +1,579 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/textinput"
+	"golang.org/x/image/font"
+)
+
+// TextInput is the handler of the built-in <input> element: an editable
+// line of text, e.g. for player names and chat boxes. While the view has
+// the focus, the typed characters are inserted at the caret, Backspace
+// and Delete delete, the arrow keys, Home and End move the caret (with
+// Shift, they extend the selection), Ctrl+A (Cmd+A) selects all, Ctrl+C,
+// Ctrl+X and Ctrl+V (Cmd) copy, cut and paste, and Enter submits. The
+// text is selected by dragging the mouse. A disabled view (View.Disabled)
+// is not edited.
+//
+// In HTML, the value, placeholder and maxlength attributes set the
+// fields, type="password" sets Password, inputmode="numeric" sets Numeric
+// and pattern sets Pattern:
+//
+//	<input id="name" placeholder="Your name" maxlength="16">
+//	<input id="password" type="password">
+//	<input id="quantity" inputmode="numeric" maxlength="3">
+//	<input id="code" pattern="[A-Z0-9]{0,4}(-[A-Z0-9]{0,4})?">
+//
+// Japanese, Chinese and Korean texts are composed with the input method
+// of the platform where Ebitengine supports it (see the exp/textinput
+// package), and the text being composed is drawn underlined at the caret.
+//
+// Ebitengine has no clipboard, so the text is copied with OnCopy and
+// pasted from OnPaste.
+type TextInput struct {
+	// Text has the face and the color of the text.
+	Text
+	// Value is the text in the input.
+	Value string
+	// Placeholder is shown while the value is empty.
+	Placeholder string
+	// PlaceholderColor is the color of the placeholder. The color of the
+	// text at half opacity is used if it is nil.
+	PlaceholderColor color.Color
+	// MaxLength limits the number of characters of the value typed or
+	// pasted. It is unlimited if it is 0.
+	MaxLength int
+	// Numeric only accepts the digits 0 to 9 typed or pasted, e.g. for
+	// quantities, and Filter only accepts the characters it returns true
+	// for.
+	Numeric bool
+	Filter  func(r rune) bool
+	// Pattern rejects the characters typed or pasted if the value would
+	// not match it, e.g. for codes. The values being typed must match,
+	// so the pattern accepts the beginnings of the values.
+	Pattern *regexp.Regexp
+	// Password masks the characters of the value with MaskChar, '*' if it
+	// is 0, unless Reveal is true, e.g. while a button showing the password
+	// is pressed. The masked value is not copied or cut.
+	Password bool
+	MaskChar rune
+	Reveal   bool
+	// DisablePaste stops pasting with OnPaste, e.g. to have the players
+	// type the confirmation of a password.
+	DisablePaste bool
+	// SelectionColor is the color of the selection behind the text.
+	// A translucent blue is used if it is nil.
+	SelectionColor color.Color
+	// CaretColor is the color of the caret. The color of the text is used
+	// if it is nil.
+	CaretColor color.Color
+
+	// OnChange is called with the value when it is edited by the input.
+	OnChange func(value string)
+	// OnSubmit is called with the value when Enter is pressed.
+	OnSubmit func(value string)
+	// OnCopy is called with the selected text when it is copied or cut,
+	// e.g. to write it to the clipboard.
+	OnCopy func(text string)
+	// OnPaste returns the text pasted, e.g. read from the clipboard.
+	OnPaste func() string
+
+	// anchor and caret are the byte offsets where the selection started
+	// and ends.
+	anchor, caret int
+	dragging      bool
+	focused       bool
+	// blink counts the updates since the caret moved, to blink it.
+	blink int
+	// scroll is the offset of the text scrolled to show the caret.
+	scroll int
+	// frame and inputFace are the frame of the view and the face drawn
+	// last time, to hit-test the text.
+	frame     image.Rectangle
+	inputFace font.Face
+	chars     []rune
+	// ime is the session of the input method of the platform, and
+	// composition the text being composed in it.
+	ime         chan textinput.State
+	imeEnd      func()
+	composition textinput.State
+	// imePos is the position of the caret drawn last time, where the
+	// input method shows its candidates.
+	imePos image.Point
+}
+
+var _ Drawer = (*TextInput)(nil)
+var _ Updater = (*TextInput)(nil)
+var _ Sizer = (*TextInput)(nil)
+var _ FocusHandler = (*TextInput)(nil)
+var _ MouseLeftButtonHandler = (*TextInput)(nil)
+
+// newTextInput creates the handler of an <input> element from its
+// attributes.
+func newTextInput(attrs map[string]string) Handler {
+	t := &TextInput{Value: attrs["value"], Placeholder: attrs["placeholder"], Password: attrs["type"] == "password"}
+	if val, ok := attrs["maxlength"]; ok {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			println(fmt.Sprintf("input: %v", err))
+		} else {
+			t.MaxLength = n
+		}
+	}
+	t.Numeric = attrs["inputmode"] == "numeric"
+	if val, ok := attrs["pattern"]; ok {
+		// the pattern matches the whole value like in HTML
+		re, err := regexp.Compile("^(?:" + val + ")$")
+		if err != nil {
+			println(fmt.Sprintf("input: %v", err))
+		} else {
+			t.Pattern = re
+		}
+	}
+	return t
+}
+
+// SetValue sets the value without calling OnChange and moves the caret to
+// the end.
+func (t *TextInput) SetValue(value string) {
+	t.Value = value
+	t.anchor, t.caret = len(value), len(value)
+}
+
+// Selection returns the byte offsets of the selected text in Value.
+func (t *TextInput) Selection() (start, end int) {
+	if t.anchor < t.caret {
+		return t.anchor, t.caret
+	}
+	return t.caret, t.anchor
+}
+
+// Select selects the text between the byte offsets.
+func (t *TextInput) Select(start, end int) {
+	t.anchor = clampInt(start, 0, len(t.Value))
+	t.caret = clampInt(end, 0, len(t.Value))
+}
+
+// SelectedText returns the selected text.
+func (t *TextInput) SelectedText() string {
+	lo, hi := t.Selection()
+	return t.Value[lo:hi]
+}
+
+// HandleFocus implements FocusHandler.
+func (t *TextInput) HandleFocus() {
+	t.focused = true
+	t.blink = 0
+}
+
+// HandleBlur implements FocusHandler.
+func (t *TextInput) HandleBlur() {
+	t.focused = false
+	t.dragging = false
+	t.endComposition()
+}
+
+// HandleJustPressedMouseButtonLeft implements MouseLeftButtonHandler.
+func (t *TextInput) HandleJustPressedMouseButtonLeft(x, y int) bool {
+	t.anchor = t.offsetAt(x)
+	t.caret, t.dragging, t.blink = t.anchor, true, 0
+	return true
+}
+
+// HandleJustReleasedMouseButtonLeft implements MouseLeftButtonHandler.
+func (t *TextInput) HandleJustReleasedMouseButtonLeft(x, y int) {
+	if t.dragging {
+		t.caret = t.offsetAt(x)
+		t.dragging = false
+	}
+}
+
+// Size implements Sizer.
+func (t *TextInput) Size(v *View) (width, height int) {
+	return 160, t.face(v).Metrics().Height.Ceil()
+}
+
+// MeasureHeight implements HeightMeasurer.
+func (t *TextInput) MeasureHeight(v *View, width int) int {
+	_, h := t.Size(v)
+	return h
+}
+
+// Update implements Updater.
+func (t *TextInput) Update(v *View) {
+	// the value is also changed from Go
+	t.anchor = clampInt(t.anchor, 0, len(t.Value))
+	t.caret = clampInt(t.caret, 0, len(t.Value))
+	t.blink++
+	if t.dragging {
+		if !isMouseButtonPressed(ebiten.MouseButtonLeft) {
+			t.dragging = false
+		} else {
+			x, _ := v.fromScreen(cursorPosition())
+			t.caret = t.offsetAt(x)
+		}
+	}
+	if !t.focused || v.Disabled {
+		t.endComposition()
+		return
+	}
+	processed := t.updateComposition()
+	if t.ime == nil {
+		// without an input method, the characters are typed directly
+		t.chars = appendInputChars(t.chars[:0])
+		if len(t.chars) > 0 {
+			t.insert(string(t.chars))
+		}
+	}
+	if processed || t.composition.Text != "" {
+		// the keys are handled by the input method while composing
+		return
+	}
+	shift := isKeyPressed(ebiten.KeyShift)
+	cmd := isKeyPressed(ebiten.KeyControl) || isKeyPressed(ebiten.KeyMeta)
+	for _, k := range []ebiten.Key{
+		ebiten.KeyArrowLeft, ebiten.KeyArrowRight, ebiten.KeyHome, ebiten.KeyEnd,
+		ebiten.KeyBackspace, ebiten.KeyDelete, ebiten.KeyEnter, ebiten.KeyNumpadEnter,
+		ebiten.KeyA, ebiten.KeyC, ebiten.KeyX, ebiten.KeyV,
+	} {
+		if isKeyJustPressed(k) || isKeyRepeated(k) {
+			t.handleKey(k, shift, cmd)
+		}
+	}
+}
+
+// handleKey handles a key pressed with the modifiers.
+func (t *TextInput) handleKey(k ebiten.Key, shift, cmd bool) {
+	lo, hi := t.Selection()
+	switch {
+	case k == ebiten.KeyA && cmd:
+		t.anchor, t.caret = 0, len(t.Value)
+		return
+	case (k == ebiten.KeyC || k == ebiten.KeyX) && cmd && t.masked():
+		// the masked value stays in the input
+		return
+	case k == ebiten.KeyC && cmd:
+		t.copy()
+		return
+	case k == ebiten.KeyX && cmd:
+		t.copy()
+		t.replace(lo, hi, "")
+		return
+	case k == ebiten.KeyV && cmd:
+		if t.OnPaste != nil && !t.DisablePaste {
+			t.insert(t.OnPaste())
+		}
+		return
+	}
+	switch k {
+	case ebiten.KeyEnter, ebiten.KeyNumpadEnter:
+		if t.OnSubmit != nil {
+			t.OnSubmit(t.Value)
+		}
+		return
+	case ebiten.KeyBackspace:
+		if lo == hi {
+			lo = prevRune(t.Value, lo)
+		}
+		t.replace(lo, hi, "")
+		return
+	case ebiten.KeyDelete:
+		if lo == hi {
+			hi = nextRune(t.Value, hi)
+		}
+		t.replace(lo, hi, "")
+		return
+	}
+	caret := t.caret
+	switch k {
+	case ebiten.KeyArrowLeft:
+		if !shift && lo != hi {
+			caret = lo
+		} else {
+			caret = prevRune(t.Value, caret)
+		}
+	case ebiten.KeyArrowRight:
+		if !shift && lo != hi {
+			caret = hi
+		} else {
+			caret = nextRune(t.Value, caret)
+		}
+	case ebiten.KeyHome:
+		caret = 0
+	case ebiten.KeyEnd:
+		caret = len(t.Value)
+	default:
+		return
+	}
+	t.caret, t.blink = caret, 0
+	if !shift {
+		t.anchor = caret
+	}
+}
+
+// insert replaces the selection with the text, without the control
+// characters and the characters not accepted, up to MaxLength.
+func (t *TextInput) insert(s string) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || !t.accepts(r) {
+			return -1
+		}
+		return r
+	}, s)
+	lo, hi := t.Selection()
+	if t.MaxLength > 0 {
+		room := t.MaxLength - utf8.RuneCountInString(t.Value) + utf8.RuneCountInString(t.Value[lo:hi])
+		if room <= 0 {
+			return
+		}
+		if utf8.RuneCountInString(s) > room {
+			s = string([]rune(s)[:room])
+		}
+	}
+	if s == "" {
+		return
+	}
+	if t.Pattern != nil && !t.Pattern.MatchString(t.Value[:lo]+s+t.Value[hi:]) {
+		return
+	}
+	t.replace(lo, hi, s)
+}
+
+// accepts returns true if the character can be typed or pasted.
+func (t *TextInput) accepts(r rune) bool {
+	if t.Numeric && (r < '0' || r > '9') {
+		return false
+	}
+	return t.Filter == nil || t.Filter(r)
+}
+
+// replace replaces the text between the byte offsets and calls OnChange.
+func (t *TextInput) replace(lo, hi int, s string) {
+	if lo == hi && s == "" {
+		return
+	}
+	t.Value = t.Value[:lo] + s + t.Value[hi:]
+	t.anchor, t.caret, t.blink = lo+len(s), lo+len(s), 0
+	if t.OnChange != nil {
+		t.OnChange(t.Value)
+	}
+}
+
+func (t *TextInput) copy() {
+	if s := t.SelectedText(); s != "" && t.OnCopy != nil {
+		t.OnCopy(s)
+	}
+}
+
+// masked returns true if the value is drawn masked.
+func (t *TextInput) masked() bool {
+	return t.Password && !t.Reveal
+}
+
+// shown returns the text drawn for the text of the value, masked in a
+// password input.
+func (t *TextInput) shown(s string) string {
+	if !t.masked() {
+		return s
+	}
+	mask := t.MaskChar
+	if mask == 0 {
+		mask = '*'
+	}
+	return strings.Repeat(string(mask), utf8.RuneCountInString(s))
+}
+
+// measure returns the width of the text up to the byte offset as drawn.
+func (t *TextInput) measure(face font.Face, s string, i int) int {
+	return font.MeasureString(face, t.shown(s[:i])).Ceil()
+}
+
+func prevRune(s string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	_, n := utf8.DecodeLastRuneInString(s[:i])
+	return i - n
+}
+
+func nextRune(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	_, n := utf8.DecodeRuneInString(s[i:])
+	return i + n
+}
+
+// offsetAt returns the byte offset of the value nearest to x.
+func (t *TextInput) offsetAt(x int) int {
+	if t.inputFace == nil {
+		return len(t.Value)
+	}
+	x -= t.frame.Min.X - t.scroll
+	prev := 0
+	for i, r := range t.Value {
+		w := t.measure(t.inputFace, t.Value, i+utf8.RuneLen(r))
+		if x < (prev+w)/2 {
+			return i
+		}
+		prev = w
+	}
+	return len(t.Value)
+}
+
+// Draw implements Drawer.
+func (t *TextInput) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	face, clr := t.face(v), t.color(v)
+	opacity := v.EffectiveOpacity()
+	t.frame, t.inputFace = v.frame, face
+	clip := targetRect(frame).Intersect(screen.Bounds())
+	if clip.Empty() {
+		return
+	}
+	target := screen.SubImage(clip).(*ebiten.Image)
+	metrics := face.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	top := frame.Min.Y + (frame.Dy()-lineHeight)/2
+	t.keys = t.keys[:0]
+
+	// the text being composed replaces the selection
+	text, caret := t.Value, t.caret
+	lo, hi := t.Selection()
+	composing := t.composition.Text
+	if composing != "" {
+		text = t.Value[:lo] + composing + t.Value[hi:]
+		caret = lo + t.composition.CompositionSelectionEndInBytes
+	}
+	width := lineHeight / 12
+	if width < 1 {
+		width = 1
+	}
+
+	if text == "" {
+		t.scroll = 0
+		if t.Placeholder != "" {
+			pc, po := t.PlaceholderColor, opacity
+			if pc == nil {
+				pc, po = clr, opacity/2
+			}
+			t.keys = append(t.keys, newTextKey(face, t.Placeholder, pc))
+			drawText(target, t.Placeholder, face, frame.Min.X, top+metrics.Ascent.Ceil(), pc, po)
+		}
+	} else {
+		t.scrollTo(face, text, caret, frame.Dx())
+		x := frame.Min.X - t.scroll
+		at := func(i int) int { return x + t.measure(face, text, i) }
+		if lo < hi && composing == "" {
+			sc := t.SelectionColor
+			if sc == nil {
+				sc = defaultSelectionColor
+			}
+			batch.FillRect(target, image.Rect(at(lo), top, at(hi), top+lineHeight), fade(sc, opacity))
+			batch.Flush()
+		}
+		shown := t.shown(text)
+		t.keys = append(t.keys, newTextKey(face, shown, clr))
+		drawText(target, shown, face, x, top+metrics.Ascent.Ceil(), clr, opacity)
+		if composing != "" {
+			// the composed text is underlined, and the segment being
+			// converted thicker
+			y := top + lineHeight - width
+			batch.FillRect(target, image.Rect(at(lo), y, at(lo+len(composing)), y+width), fade(clr, opacity))
+			c := t.composition
+			if c.CompositionSelectionStartInBytes < c.CompositionSelectionEndInBytes {
+				x0, x1 := at(lo+c.CompositionSelectionStartInBytes), at(lo+c.CompositionSelectionEndInBytes)
+				batch.FillRect(target, image.Rect(x0, y-width, x1, y+width), fade(clr, opacity))
+			}
+			batch.Flush()
+		}
+	}
+	t.holdKeys()
+	caretX := frame.Min.X - t.scroll + t.measure(face, text, caret)
+	// the candidates of the input method are shown below the caret
+	t.imePos = image.Pt(caretX, top+lineHeight)
+
+	// the caret blinks every half a second while the view has the focus
+	period := tps() / 2
+	if period < 1 {
+		period = 1
+	}
+	if !t.focused || v.Disabled || (t.blink/period)%2 == 1 {
+		return
+	}
+	cc := t.CaretColor
+	if cc == nil {
+		cc = clr
+	}
+	batch.FillRect(target, image.Rect(caretX, top, caretX+width, top+lineHeight), fade(cc, opacity))
+	batch.Flush()
+}
+
+// scrollTo scrolls the text to show the caret in the width.
+func (t *TextInput) scrollTo(face font.Face, text string, caret, width int) {
+	x := t.measure(face, text, caret)
+	end := t.measure(face, text, len(text))
+	// the room for the caret at the end
+	width--
+	switch {
+	case x-t.scroll > width:
+		t.scroll = x - width
+	case x < t.scroll:
+		t.scroll = x
+	}
+	if end-t.scroll < width {
+		t.scroll = end - width
+	}
+	if t.scroll < 0 {
+		t.scroll = 0
+	}
+}
+
+// updateComposition reads the text of the input method of the platform,
+// e.g. to compose Japanese, and returns true if it has input in this
+// update. The committed text is inserted and the text being composed is
+// drawn at the caret.
+func (t *TextInput) updateComposition() bool {
+	if t.ime == nil {
+		t.ime, t.imeEnd = startTextInput(t.imePos.X, t.imePos.Y)
+		if t.ime == nil {
+			return false
+		}
+	}
+	processed := false
+	for {
+		select {
+		case s, ok := <-t.ime:
+			processed = true
+			if !ok {
+				// the session is started again in the next update
+				t.ime, t.imeEnd, t.composition = nil, nil, textinput.State{}
+				return processed
+			}
+			if s.Committed {
+				t.composition = textinput.State{}
+				t.insert(s.Text)
+				continue
+			}
+			t.composition, t.blink = s, 0
+		default:
+			return processed
+		}
+	}
+}
+
+// endComposition ends the session of the input method.
+func (t *TextInput) endComposition() {
+	if t.imeEnd != nil {
+		t.imeEnd()
+	}
+	t.ime, t.imeEnd, t.composition = nil, nil, textinput.State{}
+}
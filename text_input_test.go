@@ -0,0 +1,239 @@
+package furex
+
+import (
+	"image"
+	"regexp"
+	"testing"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/textinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextInput(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 100px; align-items: flex-start">
+		<input id="name" value="ab" placeholder="Your name" maxlength="5">
+		<input id="chat" style="width: 35px">
+	</view>`, nil)
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	tick(InputState{})
+	name := root.MustGetByID("name")
+	input := name.Handler.(*TextInput)
+	require.Equal(t, "ab", input.Value)
+	require.Equal(t, "Your name", input.Placeholder)
+	require.Equal(t, 5, input.MaxLength)
+	require.Equal(t, image.Rect(0, 0, 160, 13), name.frame)
+	chat := root.MustGetByID("chat")
+	require.Equal(t, image.Rect(160, 0, 195, 13), chat.frame)
+
+	screen := ebiten.NewImage(200, 100)
+	root.Draw(screen)
+
+	var changes []string
+	input.OnChange = func(value string) { changes = append(changes, value) }
+	// the click focuses the input and puts the caret between the characters
+	tick(InputState{CursorX: 8, CursorY: 5, MouseButtons: []ebiten.MouseButton{ebiten.MouseButtonLeft}})
+	tick(InputState{CursorX: 8, CursorY: 5})
+	require.True(t, input.focused)
+	require.Equal(t, 1, input.caret)
+
+	// the characters are typed at the caret up to the max length
+	tick(InputState{Chars: []rune("xyzw")})
+	require.Equal(t, "axyzb", input.Value)
+	require.Equal(t, []string{"axyzb"}, changes)
+	require.Equal(t, 4, input.caret)
+
+	// backspace and delete
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyBackspace}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyDelete}})
+	require.Equal(t, "axy", input.Value)
+
+	// shift selects and the selection is cut and pasted
+	var copied string
+	input.OnCopy = func(text string) { copied = text }
+	input.OnPaste = func() string { return "q\nr" }
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift, ebiten.KeyArrowLeft}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyShift, ebiten.KeyArrowLeft}})
+	require.Equal(t, "xy", input.SelectedText())
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyX}})
+	require.Equal(t, "xy", copied)
+	require.Equal(t, "a", input.Value)
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyV}})
+	require.Equal(t, "aqr", input.Value)
+
+	// enter submits
+	var submitted string
+	input.OnSubmit = func(value string) { submitted = value }
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyEnter}})
+	require.Equal(t, "aqr", submitted)
+
+	// a disabled input is not edited
+	name.Disabled = true
+	tick(InputState{Chars: []rune("s")})
+	require.Equal(t, "aqr", input.Value)
+
+	// the text is scrolled to show the caret at the end
+	c := chat.Handler.(*TextInput)
+	c.SetValue("hello world")
+	root.Draw(screen)
+	require.Equal(t, 77-34, c.scroll)
+	c.Select(0, 0)
+	root.Draw(screen)
+	require.Equal(t, 0, c.scroll)
+}
+
+func TestTextInputComposition(t *testing.T) {
+	states := make(chan textinput.State, 4)
+	ended := false
+	startIME = func(x, y int) (chan textinput.State, func()) {
+		return states, func() { ended = true }
+	}
+	defer func() { startIME = textinput.Start }()
+
+	root := Parse(`<view style="width: 200px; height: 100px; align-items: flex-start">
+		<input id="name" value="ab">
+	</view>`, nil)
+	name := root.MustGetByID("name")
+	input := name.Handler.(*TextInput)
+	input.Select(1, 1)
+	name.Focus()
+	root.Update()
+	require.True(t, input.focused)
+	require.NotNil(t, input.ime)
+
+	// the text being composed is drawn at the caret until it is committed
+	states <- textinput.State{Text: "にほん", CompositionSelectionStartInBytes: 0, CompositionSelectionEndInBytes: 9}
+	root.Update()
+	require.Equal(t, "ab", input.Value)
+	require.Equal(t, "にほん", input.composition.Text)
+	screen := ebiten.NewImage(200, 100)
+	root.Draw(screen)
+	require.Equal(t, image.Pt(1*7+3*7, 13), input.imePos)
+
+	states <- textinput.State{Text: "日本", Committed: true}
+	root.Update()
+	require.Equal(t, "a日本b", input.Value)
+	require.Equal(t, "", input.composition.Text)
+	require.Equal(t, 7, input.caret)
+
+	// the session ends with the focus
+	name.Blur()
+	root.Update()
+	require.True(t, ended)
+	require.Nil(t, input.ime)
+}
+
+func TestTextInputPassword(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 100px; align-items: flex-start">
+		<input id="password" type="password" value="abc">
+	</view>`, nil)
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	view := root.MustGetByID("password")
+	input := view.Handler.(*TextInput)
+	require.True(t, input.Password)
+	view.Focus()
+	tick(InputState{})
+
+	// the value is drawn masked, one character for each
+	input.SetValue("ab日")
+	screen := ebiten.NewImage(200, 100)
+	root.Draw(screen)
+	require.Equal(t, "***", input.shown(input.Value))
+	require.Equal(t, image.Pt(3*7, 13), input.imePos)
+	input.MaskChar = '#'
+	require.Equal(t, "###", input.shown(input.Value))
+
+	// the masked value is not copied or cut
+	copied := ""
+	input.OnCopy = func(text string) { copied = text }
+	input.Select(0, len(input.Value))
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyC}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyX}})
+	require.Equal(t, "", copied)
+	require.Equal(t, "ab日", input.Value)
+
+	// until it is revealed
+	input.Reveal = true
+	require.Equal(t, "ab日", input.shown(input.Value))
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyC}})
+	require.Equal(t, "ab日", copied)
+
+	// pasting can be disabled
+	input.OnPaste = func() string { return "xyz" }
+	input.DisablePaste = true
+	input.Select(0, 0)
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyV}})
+	require.Equal(t, "ab日", input.Value)
+	input.DisablePaste = false
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyV}})
+	require.Equal(t, "xyzab日", input.Value)
+}
+
+func TestTextInputFilter(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 100px; align-items: flex-start">
+		<input id="quantity" inputmode="numeric" maxlength="3">
+		<input id="code" pattern="[A-Z0-9]{0,4}(-[A-Z0-9]{0,4})?">
+	</view>`, nil)
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	typeInto := func(id string, chars string) *TextInput {
+		view := root.MustGetByID(id)
+		view.Focus()
+		tick(InputState{})
+		tick(InputState{Chars: []rune(chars)})
+		return view.Handler.(*TextInput)
+	}
+
+	// a numeric input only takes the digits, typed or pasted
+	quantity := typeInto("quantity", "1a2-")
+	require.True(t, quantity.Numeric)
+	require.Equal(t, "12", quantity.Value)
+	quantity.OnPaste = func() string { return "x9y8" }
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl}})
+	tick(InputState{Keys: []ebiten.Key{ebiten.KeyControl, ebiten.KeyV}})
+	require.Equal(t, "129", quantity.Value)
+
+	// a pattern rejects the characters making the value not match
+	code := typeInto("code", "AB1")
+	require.Equal(t, "AB1", code.Value)
+	tick(InputState{Chars: []rune("x")})
+	tick(InputState{Chars: []rune("2")})
+	tick(InputState{Chars: []rune("3")})
+	require.Equal(t, "AB12", code.Value)
+	tick(InputState{Chars: []rune("-C")})
+	require.Equal(t, "AB12-C", code.Value)
+	tick(InputState{Chars: []rune("--")})
+	require.Equal(t, "AB12-C", code.Value)
+
+	// and the filter the characters it returns false for
+	code.Pattern = regexp.MustCompile(`^[A-Z0-9-]*$`)
+	code.Filter = func(r rune) bool { return !unicode.IsLetter(r) }
+	tick(InputState{Chars: []rune("D5")})
+	require.Equal(t, "AB12-C5", code.Value)
+}
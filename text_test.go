@@ -0,0 +1,112 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestAlignText(t *testing.T) {
+	// basicfont.Face7x13 has 7px wide glyphs, 13px lines and an ascent of 11px
+	face := basicfont.Face7x13
+	frame := image.Rect(10, 20, 110, 70)
+
+	require.Equal(t, []textRun{{"ab\ncd", 10, 31}}, alignText("ab\ncd", face, frame, TextAlignLeft, VerticalAlignTop))
+	require.Equal(t, []textRun{{"ab", 53, 31}, {"abcd", 46, 44}}, alignText("ab\nabcd", face, frame, TextAlignCenter, VerticalAlignTop))
+	require.Equal(t, []textRun{{"ab", 96, 49}}, alignText("ab", face, frame, TextAlignRight, VerticalAlignMiddle))
+	require.Equal(t, []textRun{{"ab", 10, 55}, {"cd", 10, 68}}, alignText("ab\ncd", face, frame, TextAlignLeft, VerticalAlignBottom))
+
+	// the last line is not justified
+	runs := alignText("a bb c\nd e", face, frame, TextAlignJustify, VerticalAlignTop)
+	require.Equal(t, []textRun{{"a", 10, 31}, {"bb", 10 + 7 + 36, 31}, {"c", 103, 31}, {"d e", 10, 44}}, runs)
+}
+
+func TestParseTextAlign(t *testing.T) {
+	v := Parse(`<view style="text-align: center; vertical-align: bottom"></view>`, nil)
+	require.Equal(t, TextAlignCenter, v.TextAlign)
+	require.Equal(t, VerticalAlignBottom, v.VerticalAlign)
+
+	_, err := parseTextAlign("middle")
+	require.Error(t, err)
+	_, err = parseVerticalAlign("center")
+	require.Error(t, err)
+}
+
+func TestTextComponent(t *testing.T) {
+	view := Parse(`<view style="color: #ff0000">
+		<text id="title" style="font-size: 20px; text-align: center">Title</text>
+		<view style="color: rgb(0, 0, 255)"><text id="note">Note</text></view>
+	</view>`, nil)
+	title := view.MustGetByID("title")
+	text, ok := title.Handler.(*Text)
+	require.True(t, ok)
+	require.Equal(t, "Title", title.Text)
+	require.Equal(t, TextAlignCenter, title.TextAlign)
+	require.Equal(t, 20.0, title.ComputedFontSize())
+
+	// the color is inherited, and the color of the handler comes first
+	require.Equal(t, color.NRGBA{255, 0, 0, 255}, text.color(title))
+	note := view.MustGetByID("note")
+	require.Equal(t, color.NRGBA{0, 0, 255, 255}, note.Handler.(*Text).color(note))
+	text.Color = color.White
+	require.Equal(t, color.White, text.color(title))
+	require.Equal(t, color.White, (&Text{}).color(&View{}))
+}
+
+func TestTextWrap(t *testing.T) {
+	// the text wraps at the max width and the dialog fits the lines
+	root := Parse(`<view style="width: 200px; height: 200px; flex-direction: column">
+		<view id="dialog" style="flex-direction: column; padding: 5px">
+			<text id="line" style="text-wrap: wrap; max-width: 100px">aaaa bbbb cccc dddd</text>
+		</view>
+	</view>`, nil)
+	root.Update()
+	// 7 pixels per character and 13 pixels per line
+	line := root.MustGetByID("line")
+	require.Equal(t, TextWrapWrap, line.TextWrap)
+	require.Equal(t, image.Rect(5, 5, 105, 31), line.frame)
+	require.Equal(t, image.Rect(0, 0, 200, 36), root.MustGetByID("dialog").frame)
+
+	// the height follows the width
+	line.MaxWidth = 60
+	root.Layout()
+	root.Update()
+	require.Equal(t, image.Rect(5, 5, 65, 57), line.frame)
+	require.Equal(t, image.Rect(0, 0, 200, 62), root.MustGetByID("dialog").frame)
+
+	text := &Text{Face: basicfont.Face7x13}
+	v := &View{Text: "aaaa bbbb", Handler: text}
+	require.Equal(t, 13, text.MeasureHeight(v, 20))
+	v.TextWrap = TextWrapWrap
+	require.Equal(t, 26, text.MeasureHeight(v, 20))
+
+	_, err := parseTextWrap("balance")
+	require.Error(t, err)
+}
+
+func TestTextViewAlignment(t *testing.T) {
+	root := Parse(`<view style="width: 100px; height: 80px; flex-direction: column">
+		<view id="title" style="height: 40px; flex-direction: column; text-align: center">Hi</view>
+		<view id="label" style="height: 40px; vertical-align: middle">Hi</view>
+	</view>`, nil)
+	root.Update()
+
+	// the text views are aligned like the elements they are written in
+	title := root.MustGetByID("title").Children()[0]
+	require.Equal(t, TextAlignCenter, title.TextAlign)
+	require.Equal(t, image.Rect(0, 0, 100, 13), title.frame)
+	label := root.MustGetByID("label").Children()[0]
+	require.Equal(t, VerticalAlignMiddle, label.VerticalAlign)
+	require.Equal(t, image.Rect(0, 40, 14, 80), label.frame)
+
+	// the alignments of the handler come first
+	text := &Text{Align: TextAlignRight}
+	align, valign := text.align(label)
+	require.Equal(t, TextAlignRight, align)
+	require.Equal(t, VerticalAlignMiddle, valign)
+	runs := alignText("Hi", basicfont.Face7x13, label.frame, align, valign)
+	require.Equal(t, []textRun{{"Hi", 0, 40 + 13 + 11}}, runs)
+}
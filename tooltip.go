@@ -0,0 +1,192 @@
+package furex
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// TooltipStyle is how the tooltips of the views (View.Tooltip) are shown
+// under a root view (see SetTooltipStyle).
+type TooltipStyle struct {
+	// Delay is the time the pointer rests on a view before its tooltip
+	// appears. 500ms is used if it is 0.
+	Delay time.Duration
+	// Face, Color and Background are the font face, the color of the text
+	// and the color of the box of the tooltips. The face of the root view
+	// (View.ComputedFace), white and dark gray are used if they are nil.
+	Face       font.Face
+	Color      color.Color
+	Background color.Color
+	// Padding is the space around the text. 4 is used if it is 0.
+	Padding int
+	// NewView creates the view of the tooltip showing the text, for
+	// tooltips of their own look. The view must have a size.
+	NewView func(text string) *View
+}
+
+const (
+	defaultTooltipDelay   = 500 * time.Millisecond
+	defaultTooltipPadding = 4
+	// tooltipOffset is the distance of the tooltips from the pointer.
+	tooltipOffset = 12
+)
+
+// tooltipState is the tooltip of a root view.
+type tooltipState struct {
+	style TooltipStyle
+	// target is the view under the pointer, ticks the time the pointer
+	// has rested on it.
+	target *View
+	ticks  int
+	// text is the text shown, and holder the root of the tooltip view.
+	text   string
+	holder *View
+	// pressed is true after a press on the target, which hides its tooltip
+	// until the pointer leaves it.
+	pressed bool
+}
+
+// SetTooltipStyle sets how the tooltips are shown. It must be called on
+// the root view.
+func (v *View) SetTooltipStyle(s TooltipStyle) {
+	v.tooltipState().style = s
+	v.tooltip.hide()
+}
+
+func (v *View) tooltipState() *tooltipState {
+	if v.tooltip == nil {
+		v.tooltip = &tooltipState{}
+	}
+	return v.tooltip
+}
+
+// updateTooltip shows the tooltip of the view the mouse cursor rests on.
+func (v *View) updateTooltip() {
+	x, y := cursorPosition()
+	scope := v
+	if m := v.TopModal(); m != nil {
+		// the views beneath the modal show no tooltips
+		scope = m.Overlay
+	}
+	var target *View
+	if len(appendTouchIDs(nil)) == 0 {
+		target = scope.tooltipAt(x, y)
+	}
+	if target == nil && v.tooltip == nil {
+		return
+	}
+	s := v.tooltipState()
+	if target != s.target {
+		s.target, s.ticks, s.pressed = target, 0, false
+		s.hide()
+	}
+	if target == nil {
+		return
+	}
+	if isMouseButtonPressed(ebiten.MouseButtonLeft) {
+		s.pressed = true
+		s.hide()
+	}
+	if s.pressed {
+		return
+	}
+	delay := s.style.Delay
+	if delay == 0 {
+		delay = defaultTooltipDelay
+	}
+	if s.ticks < durationTicks(delay) {
+		s.ticks++
+		return
+	}
+	if s.holder == nil || s.text != target.Tooltip {
+		s.show(v, target.Tooltip, x, y)
+	}
+}
+
+// tooltipAt returns the view whose tooltip is shown at the point: the
+// innermost view with a tooltip under the topmost child at the point.
+func (v *View) tooltipAt(x, y int) *View {
+	for i := len(v.children) - 1; i >= 0; i-- {
+		c := v.children[i]
+		if c.item.Hidden || !c.item.isDisplayed() {
+			continue
+		}
+		frame := v.childFrame(c)
+		cx, cy := c.item.untransform(*frame, x, y)
+		if isInside(frame, cx, cy) {
+			if t := c.item.tooltipAt(cx, cy); t != nil {
+				return t
+			}
+			break
+		}
+	}
+	if v.Tooltip != "" {
+		return v
+	}
+	return nil
+}
+
+// show shows the tooltip near the pointer, inside the frame of the root
+// view.
+func (s *tooltipState) show(root *View, text string, x, y int) {
+	view := s.newView(root, text)
+	w, h := view.Width, view.Height
+	bounds := root.frame
+	left, top := x+tooltipOffset, y+tooltipOffset
+	if left+w > bounds.Max.X {
+		left = x - tooltipOffset - w
+	}
+	if top+h > bounds.Max.Y {
+		top = y - tooltipOffset - h
+	}
+	left = clampInt(left, bounds.Min.X, bounds.Max.X-w)
+	top = clampInt(top, bounds.Min.Y, bounds.Max.Y-h)
+	s.text = text
+	s.holder = &View{Left: left, Top: top, Width: w, Height: h}
+	s.holder.AddChild(view)
+}
+
+// newView creates the view of the tooltip showing the text.
+func (s *tooltipState) newView(root *View, text string) *View {
+	if s.style.NewView != nil {
+		return s.style.NewView(text)
+	}
+	face := s.style.Face
+	if face == nil {
+		face = root.ComputedFace()
+	}
+	t := &Text{Face: face, Color: s.style.Color}
+	size := textSize(text, t.face(root), WritingModeHorizontalTB)
+	padding := s.style.Padding
+	if padding == 0 {
+		padding = defaultTooltipPadding
+	}
+	bg := s.style.Background
+	if bg == nil {
+		bg = color.RGBA{0x20, 0x20, 0x20, 0xe8}
+	}
+	view := &View{
+		Width:           size.X + padding*2,
+		Height:          size.Y + padding*2,
+		BackgroundColor: bg,
+		Justify:         JustifyCenter,
+		AlignItems:      AlignItemCenter,
+	}
+	view.AddChild(&View{Width: size.X, Height: size.Y, Text: text, Handler: t})
+	return view
+}
+
+func (s *tooltipState) hide() {
+	s.holder, s.text = nil, ""
+}
+
+// drawTooltip draws the tooltip shown over the views.
+func (v *View) drawTooltip(screen *ebiten.Image) {
+	if v.tooltip == nil || v.tooltip.holder == nil {
+		return
+	}
+	v.tooltip.holder.Draw(screen)
+}
@@ -0,0 +1,67 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTooltip(t *testing.T) {
+	SetDeterministic(60)
+	defer SetDeterministic(0)
+
+	root := Parse(`<view style="width: 200px; height: 100px; justify-content: flex-start; align-items: flex-start">
+		<view id="sword" tooltip="Equip item" style="width: 40px; height: 40px">
+			<view id="icon" style="width: 20px; height: 20px"></view>
+		</view>
+		<view id="shield" style="width: 40px; height: 40px"></view>
+		<view id="bow" tooltip="{{name}}" style="width: 120px; height: 100px"></view>
+	</view>`, &ParseOptions{Data: map[string]any{"name": "Longbow"}})
+	tick := func(s InputState) {
+		InjectInput(s)
+		root.Update()
+	}
+	frame := func() image.Rectangle {
+		h := root.tooltip.holder
+		require.NotNil(t, h)
+		return image.Rect(h.Left, h.Top, h.Left+h.Width, h.Top+h.Height)
+	}
+	require.Equal(t, "Longbow", root.MustGetByID("bow").Tooltip)
+	tick(InputState{CursorX: 50, CursorY: 10})
+
+	// the tooltip of a view appears after the pointer rests on it, also on
+	// its children
+	for i := 0; i < 30; i++ {
+		tick(InputState{CursorX: 10, CursorY: 10})
+	}
+	require.Nil(t, root.tooltip.holder)
+	tick(InputState{CursorX: 10, CursorY: 10})
+	require.Same(t, root.MustGetByID("sword"), root.tooltip.target)
+	require.Equal(t, image.Pt(22, 22), frame().Min)
+	require.Equal(t, "Equip item", root.tooltip.holder.children[0].item.children[0].item.Text)
+	root.Draw(ebiten.NewImage(200, 100))
+
+	// a press hides it, and a view without a tooltip shows none
+	tick(InputState{CursorX: 10, CursorY: 10, MouseButtons: []ebiten.MouseButton{ebiten.MouseButtonLeft}})
+	require.Nil(t, root.tooltip.holder)
+	for i := 0; i < 40; i++ {
+		tick(InputState{CursorX: 50, CursorY: 10})
+	}
+	require.Nil(t, root.tooltip.target)
+	require.Nil(t, root.tooltip.holder)
+
+	// the tooltip stays on the screen
+	for i := 0; i < 40; i++ {
+		tick(InputState{CursorX: 190, CursorY: 95})
+	}
+	f := frame()
+	require.Equal(t, 190-tooltipOffset, f.Max.X)
+	require.Equal(t, 95-tooltipOffset, f.Max.Y)
+
+	// the views beneath a modal show no tooltips
+	root.ShowModal(&View{Width: 10, Height: 10})
+	tick(InputState{CursorX: 190, CursorY: 95})
+	require.Nil(t, root.tooltip.holder)
+}
@@ -0,0 +1,245 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Transform moves, scales and rotates a view and its children around the
+// center of the frame when they are drawn, without changing the layout.
+// Input is hit-tested with the inverse transform, so a transformed button
+// is pressed where it is drawn.
+//
+// The subtree is drawn offscreen at the size of the frame (grown by the
+// box shadow), so children drawn outside the frame are clipped.
+// The transform of the root view is ignored.
+type Transform struct {
+	TranslateX float64
+	TranslateY float64
+	// ScaleX and ScaleY scale the view. Zero is treated as 1 so that the
+	// zero Transform is the identity; scale(0) in CSS becomes minScale.
+	ScaleX float64
+	ScaleY float64
+	// Rotate is the clockwise rotation in degrees.
+	Rotate float64
+}
+
+// minScale is the scale of views scaled to nothing.
+const minScale = 1e-9
+
+func (t *Transform) scale() (float64, float64) {
+	sx, sy := t.ScaleX, t.ScaleY
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+	return sx, sy
+}
+
+func (t *Transform) isIdentity() bool {
+	sx, sy := t.scale()
+	return t.TranslateX == 0 && t.TranslateY == 0 && sx == 1 && sy == 1 && t.Rotate == 0
+}
+
+// geoM returns the transform around the center of the frame.
+func (t *Transform) geoM(frame image.Rectangle) ebiten.GeoM {
+	cx := float64(frame.Min.X+frame.Max.X) / 2
+	cy := float64(frame.Min.Y+frame.Max.Y) / 2
+	g := ebiten.GeoM{}
+	g.Translate(-cx, -cy)
+	g.Scale(t.scale())
+	g.Rotate(t.Rotate * math.Pi / 180)
+	g.Translate(cx+t.TranslateX, cy+t.TranslateY)
+	return g
+}
+
+// untransform maps a point on the screen to the view before its
+// transform and the scrolling of its parent. frame is the frame of the
+// view.
+func (v *View) untransform(frame image.Rectangle, x, y int) (int, int) {
+	if v.hasParent && v.parent.Overflow == OverflowScroll && !isInside(&v.parent.frame, x, y) {
+		// the content scrolled out of the parent is not hit
+		return math.MinInt32, math.MinInt32
+	}
+	return v.toLocal(frame, x, y)
+}
+
+// toLocal maps a point in the coordinates of the parent to the view like
+// untransform, even if it is out of a scrolling parent.
+func (v *View) toLocal(frame image.Rectangle, x, y int) (int, int) {
+	if v.hasParent && v.parent.Overflow == OverflowScroll {
+		o := v.parent.ScrollOffset()
+		x, y = x+o.X, y+o.Y
+	}
+	t := v.Transform
+	if t == nil || t.isIdentity() || !v.hasParent {
+		return x, y
+	}
+	g := t.geoM(frame)
+	if !g.IsInvertible() {
+		return math.MinInt32, math.MinInt32
+	}
+	g.Invert()
+	fx, fy := g.Apply(float64(x), float64(y))
+	return int(math.Floor(fx)), int(math.Floor(fy))
+}
+
+// untransformPoints maps the points like untransform.
+func (v *View) untransformPoints(frame image.Rectangle, points []image.Point) []image.Point {
+	if !v.hasParent || (v.Transform == nil || v.Transform.isIdentity()) && v.parent.Overflow != OverflowScroll {
+		return points
+	}
+	ret := make([]image.Point, len(points))
+	for i, p := range points {
+		ret[i].X, ret[i].Y = v.untransform(frame, p.X, p.Y)
+	}
+	return ret
+}
+
+// drawTransformed draws the subtree of the view with draw onto an offscreen
+// image and draws it onto the screen with the transform.
+func (v *View) drawTransformed(screen *ebiten.Image, frame image.Rectangle, draw func(target *ebiten.Image)) {
+	bounds := frame
+	if s := v.BoxShadow; s != nil {
+		ext := s.Blur + s.Spread
+		bounds = bounds.Union(frame.Inset(-ext).Add(image.Pt(s.OffsetX, s.OffsetY)))
+	}
+	if bounds.Empty() {
+		return
+	}
+	// the offscreen image is in the coordinates of the screen, which are
+	// scaled in a snapshot
+	target := targetRect(bounds)
+	if target.Empty() {
+		return
+	}
+	if v.transformImage == nil || v.transformImage.Bounds() != target {
+		v.releaseTransformImage()
+		v.transformImage = ebiten.NewImageWithOptions(target, nil)
+	}
+	batch.Flush()
+	v.transformImage.Clear()
+	draw(v.transformImage)
+	batch.Flush()
+
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Translate(float64(target.Min.X), float64(target.Min.Y))
+	if t := batch.Transform; t != nil {
+		// the transform of the view applies to the coordinates of the layout
+		op.GeoM.Scale(1/t.Scale, 1/t.Scale)
+		op.GeoM.Translate(float64(t.Origin.X), float64(t.Origin.Y))
+		op.GeoM.Concat(v.Transform.geoM(frame))
+		op.GeoM.Translate(-float64(t.Origin.X), -float64(t.Origin.Y))
+		op.GeoM.Scale(t.Scale, t.Scale)
+	} else {
+		op.GeoM.Concat(v.Transform.geoM(frame))
+	}
+	screen.DrawImage(v.transformImage, op)
+}
+
+func (v *View) releaseTransformImage() {
+	if v.transformImage != nil {
+		v.transformImage.Dispose()
+		v.transformImage = nil
+	}
+}
+
+// parseTransform parses a list of transform functions: translate(),
+// translateX(), translateY(), scale(), scaleX(), scaleY() and rotate().
+// The functions are combined into a single Transform, which scales,
+// rotates and translates in this order regardless of the order of the
+// functions.
+func parseTransform(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "none" {
+		return (*Transform)(nil), nil
+	}
+	t := &Transform{ScaleX: 1, ScaleY: 1}
+	for val != "" {
+		open := strings.IndexByte(val, '(')
+		end := strings.IndexByte(val, ')')
+		if open == -1 || end < open {
+			return nil, fmt.Errorf("invalid transform: %s", val)
+		}
+		name := strings.TrimSpace(val[:open])
+		args := splitArgs(val[open+1 : end])
+		val = strings.TrimSpace(val[end+1:])
+
+		switch name {
+		case "translate", "translateX", "translateY":
+			xy, err := parseTransformArgs(args, parseTranslate)
+			if err != nil {
+				return nil, err
+			}
+			switch name {
+			case "translate":
+				t.TranslateX += xy[0]
+				if len(xy) > 1 {
+					t.TranslateY += xy[1]
+				}
+			case "translateX":
+				t.TranslateX += xy[0]
+			case "translateY":
+				t.TranslateY += xy[0]
+			}
+		case "scale", "scaleX", "scaleY":
+			s, err := parseTransformArgs(args, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+			if err != nil {
+				return nil, err
+			}
+			switch name {
+			case "scale":
+				sy := s[0]
+				if len(s) > 1 {
+					sy = s[1]
+				}
+				t.ScaleX, t.ScaleY = t.ScaleX*s[0], t.ScaleY*sy
+			case "scaleX":
+				t.ScaleX *= s[0]
+			case "scaleY":
+				t.ScaleY *= s[0]
+			}
+		case "rotate":
+			a, err := parseTransformArgs(args, parseAngle)
+			if err != nil {
+				return nil, err
+			}
+			t.Rotate += a[0]
+		default:
+			return nil, fmt.Errorf("unknown transform function: %s", name)
+		}
+	}
+	if t.ScaleX == 0 {
+		t.ScaleX = minScale
+	}
+	if t.ScaleY == 0 {
+		t.ScaleY = minScale
+	}
+	return t, nil
+}
+
+func parseTransformArgs(args []string, parse func(string) (float64, error)) ([]float64, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("invalid transform arguments: %v", args)
+	}
+	ret := make([]float64, len(args))
+	for i, a := range args {
+		f, err := parse(a)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = f
+	}
+	return ret, nil
+}
+
+func parseTranslate(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(s, "px"), 64)
+}
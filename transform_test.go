@@ -0,0 +1,75 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		val  string
+		want *Transform
+	}{
+		{"translate(10px, -5px)", &Transform{TranslateX: 10, TranslateY: -5, ScaleX: 1, ScaleY: 1}},
+		{"translateY(4px) scale(1.5)", &Transform{TranslateY: 4, ScaleX: 1.5, ScaleY: 1.5}},
+		{"scale(2, 0.5) rotate(0.25turn)", &Transform{ScaleX: 2, ScaleY: 0.5, Rotate: 90}},
+		{"scaleX(0)", &Transform{ScaleX: minScale, ScaleY: 1}},
+		{"none", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseTransform(tt.val)
+		require.NoError(t, err, tt.val)
+		require.Equal(t, tt.want, got, tt.val)
+	}
+	for _, val := range []string{"skew(10deg)", "rotate(10)", "translate(1px, 2px, 3px)", "scale"} {
+		_, err := parseTransform(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestTransformHitTest(t *testing.T) {
+	h := &mockHandler{}
+	root := &View{Width: 200, Height: 200}
+	button := &View{Position: PositionAbsolute, Left: 50, Top: 50, Width: 20, Height: 20, Handler: h}
+	root.AddChild(button)
+	root.startLayout()
+
+	// the button is drawn from (40, 40) to (80, 80)
+	button.Transform = &Transform{ScaleX: 2, ScaleY: 2}
+	require.Equal(t, image.Pt(50, 50), pt(button.untransform(button.frame, 40, 40)))
+	root.handleMouseButtonLeftPressed(42, 42)
+	require.True(t, h.IsPressed)
+	root.handleMouseButtonLeftReleased(42, 42)
+	require.False(t, h.IsCancel)
+
+	// moved away from the original frame
+	h.Init()
+	button.Transform = &Transform{TranslateX: 100}
+	root.handleMouseButtonLeftPressed(55, 55)
+	require.False(t, h.IsPressed)
+	root.handleMouseButtonLeftPressed(155, 55)
+	require.True(t, h.IsPressed)
+
+	button.Transform = &Transform{Rotate: 90}
+	require.Equal(t, image.Pt(69, 50), pt(button.untransform(button.frame, 70, 69)))
+}
+
+func pt(x, y int) image.Point { return image.Pt(x, y) }
+
+func TestDrawTransformed(t *testing.T) {
+	screen := ebiten.NewImage(100, 100)
+	h := &mockHandler{}
+	view := Parse(`<view style="width: 100px; height: 100px"><view id="hud" style="width: 40px; height: 20px; transform: rotate(45deg)"></view></view>`, nil)
+	hud := view.MustGetByID("hud")
+	hud.Handler = h
+	view.Draw(screen)
+	require.True(t, h.IsDrawn)
+	require.Equal(t, image.Rect(0, 0, 40, 20), hud.transformImage.Bounds())
+	require.Equal(t, int64(40*20*4), hud.MemoryUsage().ImageBytes)
+
+	hud.Release()
+	require.Nil(t, hud.transformImage)
+}
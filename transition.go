@@ -0,0 +1,296 @@
+package furex
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Transition animates the changes of a property of a view, like the CSS
+// transition property. When the value of the property changes, by a style
+// rule or from Go code, the view moves from the old value to the new one
+// over the following Update calls instead of snapping.
+type Transition struct {
+	// Property is the name of the CSS property, such as "opacity", or "all".
+	Property string
+	Duration time.Duration
+	Delay    time.Duration
+	// Easing is the name of the timing function (see RegisterEasing).
+	// "ease" is used if it is empty.
+	Easing string
+}
+
+// animatable is a property that can be interpolated. Its value is a list
+// of numbers, e.g. the four components of a color.
+type animatable struct {
+	get func(v *View) []float64
+	set func(v *View, val []float64)
+	// layout is true if the property affects the layout.
+	layout bool
+}
+
+func intProp(f func(v *View) *int) animatable {
+	return animatable{
+		get:    func(v *View) []float64 { return []float64{float64(*f(v))} },
+		set:    func(v *View, val []float64) { *f(v) = int(math.Round(val[0])) },
+		layout: true,
+	}
+}
+
+func colorProp(f func(v *View) *color.Color) animatable {
+	return animatable{
+		get: func(v *View) []float64 {
+			c := *f(v)
+			if c == nil {
+				return []float64{0, 0, 0, 0}
+			}
+			r, g, b, a := c.RGBA()
+			return []float64{float64(r), float64(g), float64(b), float64(a)}
+		},
+		set: func(v *View, val []float64) {
+			c := color.RGBA64{}
+			for i, p := range []*uint16{&c.R, &c.G, &c.B, &c.A} {
+				*p = uint16(math.Round(math.Max(0, math.Min(0xffff, val[i]))))
+			}
+			*f(v) = c
+		},
+	}
+}
+
+// animatables are the properties that can be transitioned.
+var animatables = map[string]animatable{
+	"left":             intProp(func(v *View) *int { return &v.Left }),
+	"top":              intProp(func(v *View) *int { return &v.Top }),
+	"width":            intProp(func(v *View) *int { return &v.Width }),
+	"height":           intProp(func(v *View) *int { return &v.Height }),
+	"margin-left":      intProp(func(v *View) *int { return &v.MarginLeft }),
+	"margin-top":       intProp(func(v *View) *int { return &v.MarginTop }),
+	"margin-right":     intProp(func(v *View) *int { return &v.MarginRight }),
+	"margin-bottom":    intProp(func(v *View) *int { return &v.MarginBottom }),
+	"border-width":     intProp(func(v *View) *int { return &v.BorderWidth }),
+	"border-radius":    intProp(func(v *View) *int { return &v.BorderRadius }),
+	"background-color": colorProp(func(v *View) *color.Color { return &v.BackgroundColor }),
+	"border-color":     colorProp(func(v *View) *color.Color { return &v.BorderColor }),
+	"opacity": {
+		get: func(v *View) []float64 {
+			if v.Opacity == nil {
+				return []float64{1}
+			}
+			return []float64{*v.Opacity}
+		},
+		set: func(v *View, val []float64) { v.Opacity = Float(val[0]) },
+	},
+	"transform": {
+		get: func(v *View) []float64 {
+			t := v.Transform
+			if t == nil {
+				return []float64{0, 0, 1, 1, 0}
+			}
+			sx, sy := t.scale()
+			return []float64{t.TranslateX, t.TranslateY, sx, sy, t.Rotate}
+		},
+		set: func(v *View, val []float64) {
+			v.Transform = &Transform{TranslateX: val[0], TranslateY: val[1], ScaleX: val[2], ScaleY: val[3], Rotate: val[4]}
+		},
+	},
+}
+
+// transitionState is the state of the transitions of a view.
+type transitionState struct {
+	// shown is the value of each property shown in the last update.
+	shown   map[string][]float64
+	running map[string]*runningTransition
+}
+
+type runningTransition struct {
+	from, to []float64
+	// tick is the number of updates since the transition started.
+	tick   int
+	delay  int
+	ticks  int
+	easing func(float64) float64
+	setter func(v *View, val []float64)
+}
+
+// transition returns the transition of the property, if any.
+func (v *View) transition(prop string) (Transition, bool) {
+	for i := len(v.Transitions) - 1; i >= 0; i-- {
+		t := v.Transitions[i]
+		if t.Property == prop || t.Property == "all" {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// updateAnimations advances the transitions, the animations and the
+// layout animations of the views in the tree by one tick, and starts transitions for the
+// properties changed since the last update.
+func (v *View) updateAnimations() {
+	if len(v.Transitions) > 0 || v.transitions != nil {
+		v.advanceTransitions()
+	}
+	if v.Animation != nil || v.animation != nil {
+		v.advanceAnimation()
+	}
+	if v.LayoutAnimation != nil {
+		v.advanceLayoutAnimations()
+	}
+	for _, c := range v.children {
+		c.item.updateAnimations()
+	}
+}
+
+func (v *View) advanceTransitions() {
+	if len(v.Transitions) == 0 {
+		// the transitions were removed; the values snap to the targets
+		for _, r := range v.transitions.running {
+			r.setter(v, r.to)
+		}
+		v.transitions = nil
+		v.Layout()
+		return
+	}
+	s := v.transitions
+	if s == nil {
+		s = &transitionState{shown: map[string][]float64{}, running: map[string]*runningTransition{}}
+		v.transitions = s
+	}
+	relayout := false
+	var started, ended []string
+	for name, prop := range animatables {
+		t, ok := v.transition(name)
+		if !ok {
+			delete(s.shown, name)
+			delete(s.running, name)
+			continue
+		}
+		val := prop.get(v)
+		shown, seen := s.shown[name]
+		r := s.running[name]
+		if !seen {
+			s.shown[name] = val
+			continue
+		}
+		if !equalValues(val, shown) {
+			// the value was changed since the last update
+			if t.Duration <= 0 && t.Delay <= 0 {
+				s.shown[name] = val
+				delete(s.running, name)
+				continue
+			}
+			r = &runningTransition{
+				from:   shown,
+				to:     val,
+				delay:  durationTicks(t.Delay),
+				ticks:  durationTicks(t.Duration),
+				easing: easingFunc(t.Easing),
+				setter: prop.set,
+			}
+			s.running[name] = r
+			started = append(started, name)
+		}
+		if r == nil {
+			continue
+		}
+		r.tick++
+		progress := 1.0
+		if r.ticks > 0 {
+			progress = math.Max(0, math.Min(1, float64(r.tick-r.delay)/float64(r.ticks)))
+		}
+		cur := interpolate(r.from, r.to, r.easing(progress))
+		if r.tick >= r.delay+r.ticks {
+			cur = r.to
+			delete(s.running, name)
+			ended = append(ended, name)
+		}
+		prop.set(v, cur)
+		// the value set may be rounded, e.g. for integer properties
+		s.shown[name] = prop.get(v)
+		relayout = relayout || prop.layout
+	}
+	if relayout {
+		v.Layout()
+	}
+	// the callbacks are called in a stable order once the values are set
+	sort.Strings(started)
+	sort.Strings(ended)
+	for _, name := range started {
+		v.emitTransitionStart(name)
+	}
+	for _, name := range ended {
+		v.emitTransitionEnd(name)
+	}
+}
+
+// IsTransitioning returns true if a transition of the view is running.
+func (v *View) IsTransitioning() bool {
+	return v.transitions != nil && len(v.transitions.running) > 0
+}
+
+func equalValues(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func interpolate(from, to []float64, t float64) []float64 {
+	ret := make([]float64, len(from))
+	for i := range from {
+		ret[i] = from[i] + (to[i]-from[i])*t
+	}
+	return ret
+}
+
+// durationTicks returns the number of updates of the duration.
+func durationTicks(d time.Duration) int {
+	return int(math.Round(d.Seconds() * float64(tps())))
+}
+
+// parseTransition parses a comma-separated list of transitions such as
+// `opacity 200ms ease-out, width 300ms`.
+func parseTransition(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "none" {
+		return []Transition(nil), nil
+	}
+	var ret []Transition
+	for _, item := range splitArgs(val) {
+		t := Transition{}
+		durations := 0
+		for _, f := range strings.Fields(item) {
+			if d, err := time.ParseDuration(f); err == nil {
+				if durations == 0 {
+					t.Duration = d
+				} else {
+					t.Delay = d
+				}
+				durations++
+				continue
+			}
+			if _, ok := easings[f]; ok {
+				t.Easing = f
+				continue
+			}
+			if _, ok := animatables[f]; ok || f == "all" {
+				t.Property = f
+				continue
+			}
+			return nil, fmt.Errorf("invalid transition: %s", item)
+		}
+		if t.Property == "" {
+			t.Property = "all"
+		}
+		ret = append(ret, t)
+	}
+	return ret, nil
+}
@@ -0,0 +1,88 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransition(t *testing.T) {
+	val, err := parseTransition("opacity 200ms ease-out, width 1s linear 100ms, 300ms")
+	require.NoError(t, err)
+	require.Equal(t, []Transition{
+		{Property: "opacity", Duration: 200 * time.Millisecond, Easing: "ease-out"},
+		{Property: "width", Duration: time.Second, Delay: 100 * time.Millisecond, Easing: "linear"},
+		{Property: "all", Duration: 300 * time.Millisecond},
+	}, val)
+
+	val, err = parseTransition("none")
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	_, err = parseTransition("color 200ms")
+	require.Error(t, err)
+	_, err = parseTransition("opacity fast")
+	require.Error(t, err)
+}
+
+func TestTransition(t *testing.T) {
+	ticks := durationTicks(100 * time.Millisecond)
+	require.Equal(t, ebiten.TPS()/10, ticks)
+
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<view id="box" style="width: 10px; height: 10px; transition: opacity 100ms linear, width 100ms linear"></view>
+	</view>`, nil)
+	box := root.MustGetByID("box")
+	require.Len(t, box.Transitions, 2)
+	root.updateAnimations()
+
+	// the values set while no transition runs are animated
+	box.Opacity = Float(0)
+	box.Width = 110
+	box.Height = 50
+	root.updateAnimations()
+	require.InDelta(t, 1-1/float64(ticks), *box.Opacity, 1e-9)
+	require.InDelta(t, 10+100/float64(ticks), box.Width, 0.5)
+	// height has no transition
+	require.Equal(t, 50, box.Height)
+	require.True(t, box.IsTransitioning())
+
+	for i := 1; i < ticks; i++ {
+		root.updateAnimations()
+	}
+	require.Equal(t, 0.0, *box.Opacity)
+	require.Equal(t, 110, box.Width)
+	require.False(t, box.IsTransitioning())
+
+	// a change in the middle starts from the shown value
+	box.Opacity = Float(1)
+	root.updateAnimations()
+	root.updateAnimations()
+	shown := *box.Opacity
+	box.Opacity = Float(0)
+	root.updateAnimations()
+	require.Less(t, *box.Opacity, shown)
+	require.Greater(t, *box.Opacity, 0.0)
+
+	// removing the transitions snaps to the targets
+	box.Transitions = nil
+	root.updateAnimations()
+	require.Equal(t, 0.0, *box.Opacity)
+	require.False(t, box.IsTransitioning())
+}
+
+func TestTransitionColor(t *testing.T) {
+	box := &View{
+		BackgroundColor: color.RGBA{0, 0, 0, 255},
+		Transitions:     []Transition{{Property: "all", Duration: 2 * time.Second / time.Duration(ebiten.TPS()), Easing: "linear"}},
+	}
+	box.updateAnimations()
+	box.BackgroundColor = color.RGBA{255, 255, 255, 255}
+	box.updateAnimations()
+	require.Equal(t, color.RGBA64{0x8000, 0x8000, 0x8000, 0xffff}, box.BackgroundColor)
+	box.updateAnimations()
+	require.Equal(t, color.RGBA64{0xffff, 0xffff, 0xffff, 0xffff}, box.BackgroundColor)
+}
@@ -0,0 +1,102 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+)
+
+// WritingMode is the 'writing-mode' property. It sets the direction of
+// the text drawn by the Text handler.
+type WritingMode uint8
+
+const (
+	// WritingModeHorizontalTB draws the lines from left to right, stacked
+	// from the top to the bottom.
+	WritingModeHorizontalTB WritingMode = iota
+	// WritingModeVerticalRL draws the lines from the top to the bottom,
+	// stacked from the right to the left, as traditional Japanese text.
+	WritingModeVerticalRL
+)
+
+func (m WritingMode) String() string {
+	switch m {
+	case WritingModeHorizontalTB:
+		return "horizontal-tb"
+	case WritingModeVerticalRL:
+		return "vertical-rl"
+	}
+	return fmt.Sprintf("unknown writing-mode: %d", m)
+}
+
+func parseWritingMode(val string) (any, error) {
+	switch val {
+	case "horizontal-tb":
+		return WritingModeHorizontalTB, nil
+	case "vertical-rl":
+		return WritingModeVerticalRL, nil
+	}
+	return WritingModeHorizontalTB, fmt.Errorf("unknown writing-mode: %s", val)
+}
+
+// verticalRuns returns the characters of the text in vertical lines from
+// the right of the frame. Each character takes a square of the line
+// height and is centered in its line. The text-align aligns the
+// characters in the lines vertically, and the vertical-align aligns the
+// lines horizontally: top to the right, bottom to the left.
+func verticalRuns(s string, face font.Face, frame image.Rectangle, align TextAlign, valign VerticalAlign) []textRun {
+	m := face.Metrics()
+	size, ascent := m.Height.Ceil(), m.Ascent.Ceil()
+	lines := strings.Split(s, "\n")
+	right := frame.Max.X
+	switch valign {
+	case VerticalAlignMiddle:
+		right -= (frame.Dx() - size*len(lines)) / 2
+	case VerticalAlignBottom:
+		right -= frame.Dx() - size*len(lines)
+	}
+	var runs []textRun
+	for i, line := range lines {
+		x := right - (i+1)*size
+		y := frame.Min.Y
+		n := utf8.RuneCountInString(line)
+		switch align {
+		case TextAlignCenter:
+			y += (frame.Dy() - size*n) / 2
+		case TextAlignRight:
+			y += frame.Dy() - size*n
+		}
+		for _, r := range line {
+			c := string(r)
+			w := font.MeasureString(face, c).Ceil()
+			runs = append(runs, textRun{c, x + (size-w)/2, y + ascent})
+			y += size
+		}
+	}
+	return runs
+}
+
+// textSize returns the size of the text drawn in the writing mode.
+func textSize(s string, face font.Face, mode WritingMode) image.Point {
+	size := face.Metrics().Height.Ceil()
+	lines := strings.Split(s, "\n")
+	longest := 0
+	for _, line := range lines {
+		l := 0
+		if mode == WritingModeVerticalRL {
+			l = utf8.RuneCountInString(line) * size
+		} else {
+			l = font.MeasureString(face, line).Ceil()
+		}
+		if l > longest {
+			longest = l
+		}
+	}
+	if mode == WritingModeVerticalRL {
+		return image.Pt(size*len(lines), longest)
+	}
+	return image.Pt(longest, size*len(lines))
+}
@@ -0,0 +1,35 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestVerticalRuns(t *testing.T) {
+	face := basicfont.Face7x13
+	runs := verticalRuns("ab\nc", face, image.Rect(0, 0, 100, 50), TextAlignLeft, VerticalAlignTop)
+	// each character takes a 13px square, the lines from the right
+	require.Equal(t, []textRun{{"a", 90, 11}, {"b", 90, 24}, {"c", 77, 11}}, runs)
+
+	runs = verticalRuns("ab", face, image.Rect(0, 0, 100, 50), TextAlignCenter, VerticalAlignMiddle)
+	require.Equal(t, []textRun{{"a", 47, 23}, {"b", 47, 36}}, runs)
+	runs = verticalRuns("a", face, image.Rect(0, 0, 100, 50), TextAlignRight, VerticalAlignBottom)
+	require.Equal(t, []textRun{{"a", 3, 48}}, runs)
+}
+
+func TestTextSize(t *testing.T) {
+	root := Parse(`<view id="title" style="writing-mode: vertical-rl">abc</view>`, nil)
+	require.Equal(t, WritingModeVerticalRL, root.WritingMode)
+	root.Text = "ab\nabc"
+	text := &Text{}
+	w, h := text.Size(root)
+	require.Equal(t, 26, w)
+	require.Equal(t, 39, h)
+	root.WritingMode = WritingModeHorizontalTB
+	w, h = text.Size(root)
+	require.Equal(t, 21, w)
+	require.Equal(t, 26, h)
+}
@@ -3,6 +3,7 @@ package furex
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"strings"
 	"sync"
 
@@ -14,34 +15,166 @@ import (
 // Handlers can be set to create custom component such as button or list.
 type View struct {
 	// TODO: Remove these fields in the future.
-	Left         int
-	Right        *int
-	Top          int
-	Bottom       *int
-	Width        int
-	WidthInPct   float64
-	Height       int
-	HeightInPct  float64
-	MarginLeft   int
-	MarginTop    int
-	MarginRight  int
-	MarginBottom int
-	Position     Position
-	Direction    Direction
-	Wrap         FlexWrap
-	Justify      Justify
-	AlignItems   AlignItem
-	AlignContent AlignContent
-	Grow         float64
-	Shrink       float64
-	Display      Display
+	Left          int
+	Right         *int
+	Top           int
+	Bottom        *int
+	Width         int
+	WidthInPct    float64
+	Height        int
+	HeightInPct   float64
+	MarginLeft    int
+	MarginTop     int
+	MarginRight   int
+	MarginBottom  int
+	PaddingLeft   int
+	PaddingTop    int
+	PaddingRight  int
+	PaddingBottom int
+	Position      Position
+	Direction     Direction
+	Wrap          FlexWrap
+	Justify       Justify
+	AlignItems    AlignItem
+	AlignContent  AlignContent
+	Grow          float64
+	Shrink        float64
+	Display       Display
+	// AspectRatio is the ratio of the width to the height of the view.
+	// The height of a view without a height set follows its width.
+	AspectRatio float64
+	// ShrinkPriority orders the shrinking of the items of a line that
+	// overflows: the items of lower priorities shrink first, and those
+	// of higher priorities only once the lower ones have no size left.
+	ShrinkPriority int
+	// CollapseBelow hides the item when the line overflows and the item
+	// would shrink below this main size, leaving its space to the others.
+	// The items of the lowest priorities collapse first.
+	CollapseBelow int
+	// MaxWidth limits the width of the view given by the layout, e.g. to
+	// keep the lines of a wrapped text short. 0 means no limit.
+	MaxWidth int
+
+	// BackgroundColor fills the frame of the view before the handler draws.
+	BackgroundColor color.Color
+	// BackgroundGradient fills the frame with a gradient over the background color.
+	BackgroundGradient *LinearGradient
+	// BackdropBlur blurs what is drawn behind the view by the radius in
+	// pixels and draws it under the background, e.g. for the frosted glass
+	// of pause menus. It is clipped by BorderRadius.
+	BackdropBlur int
+	// BackdropSource is blurred instead of the screen if it is set, e.g.
+	// the offscreen render of the game under the UI. It has the
+	// coordinates of the screen.
+	BackdropSource *ebiten.Image
+	// BackgroundCheckerboard fills the frame with squares under the
+	// background color. It is not clipped by BorderRadius.
+	BackgroundCheckerboard *Checkerboard
+	// BackgroundImage is drawn over the background gradient as decided by
+	// BackgroundRepeat and BackgroundSlice. It is not clipped by BorderRadius.
+	BackgroundImage  *ebiten.Image
+	BackgroundRepeat BackgroundRepeat
+	// BackgroundSlice is the size of the corners of BackgroundImage kept
+	// unscaled when the image is stretched.
+	BackgroundSlice Insets
+	// BorderWidth and BorderColor stroke the frame of the view.
+	// The border is drawn inside the frame and does not affect the layout.
+	BorderWidth int
+	BorderColor color.Color
+	// BorderStyle draws the border as a solid, dashed or dotted line.
+	BorderStyle BorderStyle
+	// BorderTop, BorderRight, BorderBottom and BorderLeft replace the
+	// border of their sides if they are not nil. The border is then drawn
+	// without the rounded corners of BorderRadius.
+	BorderTop    *Border
+	BorderRight  *Border
+	BorderBottom *Border
+	BorderLeft   *Border
+	// BorderRadius rounds the corners of the background and the border.
+	BorderRadius int
+	// OutlineWidth, OutlineColor and OutlineStyle stroke a line around the
+	// frame, OutlineOffset pixels outside it, e.g. to show the focus. The
+	// outline is drawn over the children and does not affect the layout.
+	OutlineWidth  int
+	OutlineColor  color.Color
+	OutlineStyle  BorderStyle
+	OutlineOffset int
+	// Overflow decides whether the children are clipped to the frame.
+	Overflow Overflow
+	// Scroll tunes the scrolling of the children if the overflow is
+	// scroll. The defaults are used if it is nil.
+	Scroll *Scroll
+	// BoxShadow draws a soft shadow behind the background.
+	BoxShadow *BoxShadow
+	// Opacity fades the view and its children. It is multiplied down
+	// the tree (see EffectiveOpacity). nil means fully opaque.
+	Opacity *float64
+	// Transform moves, scales and rotates the view and its children
+	// when they are drawn and hit-tested. It does not affect the layout.
+	Transform *Transform
+	// RenderScale draws the view and its children at a lower resolution,
+	// from 0 to 1, and scales them up to the frame, e.g. to draw heavy
+	// layers faster on low-end devices. 0 and 1 draw them at the
+	// resolution of the screen. Children drawn outside the frame are
+	// clipped. The render scale of the root view is ignored.
+	RenderScale float64
+	// ImageRendering is the filter used to scale the view drawn at
+	// RenderScale up.
+	ImageRendering ImageRendering
+	// Transitions animate the changes of the properties over the
+	// following updates instead of snapping to the new values.
+	Transitions []Transition
+	// Animation plays keyframes on the view while it is updated.
+	Animation *Animation
+	// LayoutAnimation animates the children to their new frames
+	// when a relayout moves them.
+	LayoutAnimation *LayoutAnimation
+	// FontSize is the font size of the texts of the view and its
+	// children (see ComputedFontSize). nil inherits the parent's.
+	FontSize *FontSize
+	// FontFamily is the names of the fonts of the texts of the view and
+	// its children; the first one registered with RegisterFont is used
+	// (see ComputedFace). nil inherits the parent's.
+	FontFamily []string
+	// Color is the color of the texts of the view and its children
+	// drawn by the Text handlers without a color (see ComputedColor).
+	// nil inherits the parent's.
+	Color color.Color
+	// TextAlign and VerticalAlign align the text in the frame
+	// when it is drawn by the Text handler.
+	TextAlign     TextAlign
+	VerticalAlign VerticalAlign
+	// TextWrap wraps the text drawn by the Text handler at the width of
+	// the frame. The height of the view then follows the wrapped lines.
+	TextWrap TextWrap
+	// WritingMode sets the direction of the text drawn by the Text handler.
+	WritingMode WritingMode
+	// Columns flows the text drawn by the Text handler across the number
+	// of columns, ColumnGap pixels apart, wrapping it at their width.
+	Columns   int
+	ColumnGap int
 
 	ID      string
 	Raw     string
 	TagName string
 	Text    string
-	Attrs   map[string]string
+	// Attrs are the attributes of the element, including the data-*
+	// attributes attaching game data such as item ids (see Data).
+	Attrs map[string]string
+	// Tooltip is the text shown near the pointer resting on the view, like
+	// the tooltip attribute (see SetTooltipStyle).
+	Tooltip string
 	Hidden  bool
+	// TabIndex controls the focus traversal like the tabindex attribute.
+	// Views with a positive value are visited first in ascending order,
+	// zero follows the document order and negative values are skipped.
+	// A view with a non-nil TabIndex can receive the focus.
+	TabIndex *int
+	// Modal traps the focus inside the view while it is visible.
+	// The previous focus is restored when the view is hidden or removed.
+	Modal bool
+	// Disabled stops the view and its children from receiving input.
+	Disabled bool
 
 	Handler Handler
 
@@ -50,10 +183,72 @@ type View struct {
 	lock      sync.Mutex
 	hasParent bool
 	parent    *View
+	focus     *focusState
+	observers *observers
+	style     *viewStyle
+	lazy      *lazySubtree
+	hovered   bool
+	active    bool
+
+	releasePolicy *ReleasePolicy
+	modalBackdrop *modalBackdropState
+	hiddenTicks   int
+	clipImage     *ebiten.Image
+	shadowImage   *ebiten.Image
+	// transformImage is the offscreen image of the transformed subtree.
+	transformImage *ebiten.Image
+	// backdropImages are the offscreen images to blur the backdrop.
+	backdropImages []*ebiten.Image
+	// renderScaleImage is the offscreen image of the subtree drawn at
+	// the render scale.
+	renderScaleImage *ebiten.Image
+	shadowKey        shadowKey
+	transitions      *transitionState
+	animation        *animationState
+	flip             *flipState
+	// animationEvents are the callbacks of the transitions and the animation.
+	animationEvents *animationEvents
+	// notifiedFrame is the frame notified to the FrameChangedHandler.
+	notifiedFrame image.Rectangle
+	// layoutWidth is the width of the frame in the last layout.
+	layoutWidth int
+	// collapsed is true if the parent collapsed the view (see CollapseBelow).
+	collapsed bool
+	// visibleIf hides the view while its condition is false (see SetVisibleIf).
+	visibleIf *visibleCondition
+	// styleBinding is the style bound to the data (see BindStyle).
+	styleBinding *styleBinding
+	// keyedLists are the lists of the children following the data.
+	keyedLists []*keyedList
+	// scroll is the scrolling of the children if the overflow is scroll.
+	scroll *scrollState
+	// modals are the modals shown over the root view, from the bottom
+	// (see ShowModal).
+	modals []*Modal
+	// tooltip is the tooltip shown over the root view.
+	tooltip *tooltipState
+	// updating is true while the root view updates the tree, and mutations
+	// are the changes of the children deferred until it is traversed.
+	updating  bool
+	mutations []func()
+	// revision counts the changes of the view and its descendants (see
+	// Revision).
+	revision uint64
 }
 
 // Update updates the view
 func (v *View) Update() {
+	if !v.hasParent {
+		// the lists follow the data, the views are hidden or shown, and the
+		// bound styles applied, before the layout
+		v.updateKeyedLists()
+		v.updateVisibility()
+		v.updateStyleBindings()
+		v.updateModals()
+		// the children added and removed by the handlers are changed after
+		// the tree is traversed
+		v.updating = true
+	}
 	if v.isDirty {
 		v.startLayout()
 	}
@@ -65,7 +260,45 @@ func (v *View) Update() {
 		v.item.processHandler()
 	}
 	if !v.hasParent {
-		v.processEvent()
+		v.notifyFrameChanged()
+		beginScrollUpdate()
+		v.updateScrolls()
+		if m := v.TopModal(); m != nil {
+			// the views beneath the modal receive no input
+			m.Overlay.processEvent()
+		} else {
+			v.processEvent()
+		}
+		v.handleFocusEvents()
+		v.handlePseudoClassEvents()
+		v.updateTooltip()
+		v.updateNotifications()
+		v.updateAnimations()
+		v.handleRelease()
+		v.applyMutations()
+	}
+}
+
+// isUpdating returns true while the root view of the view updates the
+// tree, when the changes of the children are deferred (deferMutation).
+func (v *View) isUpdating() bool {
+	return v.root().updating
+}
+
+// deferMutation defers the change of the children of the view until the
+// root view has traversed the tree.
+func (v *View) deferMutation(fn func()) {
+	r := v.root()
+	r.mutations = append(r.mutations, fn)
+}
+
+// applyMutations changes the children as deferred during the update.
+func (v *View) applyMutations() {
+	v.updating = false
+	mutations := v.mutations
+	v.mutations = nil
+	for _, fn := range mutations {
+		fn()
 	}
 }
 
@@ -94,8 +327,88 @@ func (v *View) startLayout() {
 		}
 	}
 
-	v.layout(v.frame.Dx(), v.frame.Dy(), &v.containerEmbed)
+	if w, h, ok := v.fitContentSize(); ok {
+		// the size to content is measured with the lines wrapped at the
+		// space available in the parent, rather than at the current frame
+		v.layout(w, h, &v.containerEmbed)
+		cw, ch := v.calculatedWidth, v.calculatedHeight
+		v.layout(v.frame.Dx(), v.frame.Dy(), &v.containerEmbed)
+		v.calculatedWidth, v.calculatedHeight = cw, ch
+	} else {
+		v.layout(v.frame.Dx(), v.frame.Dy(), &v.containerEmbed)
+	}
+	if v.remeasureChildren() {
+		// the children are arranged again with their heights measured
+		// at the widths resolved by the first pass
+		v.layout(v.frame.Dx(), v.frame.Dy(), &v.containerEmbed)
+	}
+	v.layoutWidth = v.frame.Dx()
 	v.isDirty = false
+	v.touch()
+}
+
+// remeasureChildren lays out the children whose heights depend on their
+// widths again if the layout changed the widths, and returns true if
+// their heights changed.
+func (v *View) remeasureChildren() bool {
+	changed := false
+	for _, child := range v.children {
+		c := child.item
+		if c.Position != PositionStatic || !c.isDisplayed() ||
+			c.frame.Dx() == c.layoutWidth || !c.dependsOnWidth() {
+			continue
+		}
+		h := c.calculatedHeight
+		c.startLayout()
+		changed = changed || c.calculatedHeight != h
+	}
+	return changed
+}
+
+// dependsOnWidth returns true if the height of the view without a height
+// set depends on its width, e.g. if it has a wrapped text.
+func (v *View) dependsOnWidth() bool {
+	if v.isHeightFixed() {
+		return false
+	}
+	if v.AspectRatio > 0 {
+		return true
+	}
+	if _, ok := v.Handler.(HeightMeasurer); ok && len(v.children) == 0 {
+		return true
+	}
+	for _, c := range v.children {
+		if c.item.Position == PositionStatic && c.item.isDisplayed() && c.item.dependsOnWidth() {
+			return true
+		}
+	}
+	return false
+}
+
+// fitContentSize returns the size the view sized to its content is
+// measured in, if it wraps its children without a main size: the content
+// box of the parent less the margins of the view on the main axis.
+func (v *View) fitContentSize() (int, int, bool) {
+	if !v.hasParent || v.Wrap == NoWrap {
+		return 0, 0, false
+	}
+	p := v.parent
+	w, h := v.frame.Dx(), v.frame.Dy()
+	switch {
+	case v.Direction == Row && !v.isWidthFixed():
+		w = p.frame.Dx() - p.PaddingLeft - p.PaddingRight - v.MarginLeft - v.MarginRight
+	case v.Direction == Column && !v.isHeightFixed():
+		h = p.frame.Dy() - p.PaddingTop - p.PaddingBottom - v.MarginTop - v.MarginBottom
+	default:
+		return 0, 0, false
+	}
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	return w, h, true
 }
 
 // UpdateWithSize the view with modified height and width
@@ -104,30 +417,60 @@ func (v *View) UpdateWithSize(width, height int) {
 		v.Height = height
 		v.Width = width
 		v.isDirty = true
+		// re-evaluate media queries for the new size
+		v.restyle()
 	}
 	v.Update()
 }
 
+// notifyFrameChanged calls the FrameChangedHandlers in the tree whose
+// frames have changed since the previous layout.
+func (v *View) notifyFrameChanged() {
+	if h, ok := v.Handler.(FrameChangedHandler); ok && v.frame != v.notifiedFrame {
+		old := v.notifiedFrame
+		v.notifiedFrame = v.frame
+		h.HandleFrameChanged(old, v.frame, v)
+	}
+	for _, c := range v.children {
+		c.item.notifyFrameChanged()
+	}
+}
+
 // Layout marks the view as dirty
 func (v *View) Layout() {
 	v.isDirty = true
 	if v.hasParent {
 		v.parent.isDirty = true
 	}
+	v.touch()
 }
 
 // Draw draws the view
 func (v *View) Draw(screen *ebiten.Image) {
+	if v.lazy != nil && !v.Hidden && v.isDisplayed() {
+		v.ExpandLazy()
+	}
 	if v.isDirty {
 		v.startLayout()
 	}
+	if !v.hasParent && v.isTransparent() {
+		return
+	}
 	if !v.hasParent {
+		v.beginModalLayer(screen)
+		defer endModalLayer()
 		v.handleDrawRoot(screen, v.frame)
 	}
-	if !v.Hidden && v.Display != DisplayNone {
-		v.containerEmbed.Draw(screen)
+	if !v.Hidden && v.isDisplayed() {
+		v.drawChildren(screen)
 	}
-	if Debug && !v.hasParent && v.Display != DisplayNone {
+	if !v.hasParent {
+		batch.Flush()
+		// the notifications and the tooltip are drawn above the views
+		v.drawNotifications(screen)
+		v.drawTooltip(screen)
+	}
+	if Debug && !v.hasParent && v.isDisplayed() {
 		debugBorders(screen, v.containerEmbed)
 	}
 }
@@ -141,48 +484,110 @@ func (v *View) AddTo(parent *View) *View {
 	return v
 }
 
-// AddChild adds one or multiple child views
+// AddChild adds one or multiple child views. During the Update of the
+// tree, e.g. in the handlers, they are added after it is traversed.
 func (v *View) AddChild(views ...*View) *View {
+	if v.isUpdating() {
+		v.deferMutation(func() { v.AddChild(views...) })
+		return v
+	}
 	for _, vv := range views {
 		v.addChild(vv)
 	}
 	return v
 }
 
-// RemoveChild removes a specified view
+// RemoveChild removes a specified view and returns true if it is a child.
+// During the Update of the tree, e.g. in the handlers, it is removed after
+// the tree is traversed, and is no longer a child for the later calls.
 func (v *View) RemoveChild(cv *View) bool {
+	if !v.isUpdating() {
+		return v.removeChild(cv)
+	}
+	for _, c := range v.children {
+		if c.item == cv {
+			if c.removing {
+				return false
+			}
+			c.removing = true
+			v.deferMutation(func() { v.removeChild(cv) })
+			return true
+		}
+	}
+	// the view may be added earlier in the update
+	v.deferMutation(func() { v.removeChild(cv) })
+	return false
+}
+
+func (v *View) removeChild(cv *View) bool {
 	for i, child := range v.children {
 		if child.item == cv {
+			root := v.root()
 			v.children = append(v.children[:i], v.children[i+1:]...)
 			v.isDirty = true
+			v.touch()
 			cv.hasParent = false
 			cv.parent = nil
+			v.notifyRemoved(root, cv)
 			return true
 		}
 	}
 	return false
 }
 
-// RemoveAll removes all children view
+// RemoveAll removes all children view. During the Update of the tree,
+// e.g. in the handlers, they are removed after it is traversed.
 func (v *View) RemoveAll() {
+	if v.isUpdating() {
+		for _, c := range v.children {
+			c.removing = true
+		}
+		v.deferMutation(v.removeAll)
+		return
+	}
+	v.removeAll()
+}
+
+func (v *View) removeAll() {
+	root := v.root()
 	v.isDirty = true
-	for _, child := range v.children {
+	v.touch()
+	children := v.children
+	for _, child := range children {
 		child.item.hasParent = false
 		child.item.parent = nil
 	}
 	v.children = []*child{}
+	for _, child := range children {
+		v.notifyRemoved(root, child.item)
+	}
 }
 
-// PopChild remove the last child view add to this view
+// PopChild remove the last child view add to this view. During the Update
+// of the tree, e.g. in the handlers, it is removed after the tree is
+// traversed, and the later calls pop the children before it.
 func (v *View) PopChild() *View {
 	if len(v.children) == 0 {
 		return nil
 	}
+	if v.isUpdating() {
+		for i := len(v.children) - 1; i >= 0; i-- {
+			if c := v.children[i]; !c.removing {
+				c.removing = true
+				v.deferMutation(func() { v.removeChild(c.item) })
+				return c.item
+			}
+		}
+		return nil
+	}
+	root := v.root()
 	c := v.children[len(v.children)-1]
 	v.children = v.children[:len(v.children)-1]
 	v.isDirty = true
+	v.touch()
 	c.item.hasParent = false
 	c.item.parent = nil
+	v.notifyRemoved(root, c.item)
 	return c.item
 }
 
@@ -190,8 +595,10 @@ func (v *View) addChild(cv *View) *View {
 	child := &child{item: cv, handledTouchID: -1}
 	v.children = append(v.children, child)
 	v.isDirty = true
+	v.touch()
 	cv.hasParent = true
 	cv.parent = v
+	v.notifyAdded(cv)
 	return v
 }
 
@@ -199,6 +606,20 @@ func (v *View) isWidthFixed() bool {
 	return v.Width != 0 || v.WidthInPct != 0
 }
 
+// isDisplayed returns false if the view is not displayed, by display: none
+// or because it collapsed (see CollapseBelow).
+func (v *View) isDisplayed() bool {
+	return v.Display != DisplayNone && !v.collapsed
+}
+
+// clampWidth returns the width limited by MaxWidth.
+func (v *View) clampWidth(w float64) float64 {
+	if v.MaxWidth > 0 && w > float64(v.MaxWidth) {
+		return float64(v.MaxWidth)
+	}
+	return w
+}
+
 func (v *View) width() int {
 	if v.Width == 0 {
 		return v.calculatedWidth
@@ -217,6 +638,11 @@ func (v *View) height() int {
 	return v.Height
 }
 
+// Children returns the child views of the view.
+func (v *View) Children() []*View {
+	return v.getChildren()
+}
+
 func (v *View) getChildren() []*View {
 	if v == nil || v.children == nil {
 		return nil
@@ -252,6 +678,13 @@ func (v *View) MustGetByID(id string) *View {
 	return vv
 }
 
+// Data returns the value of the data-* attribute of the name, e.g.
+// Data("item-id") for data-item-id="potion".
+func (v *View) Data(name string) (string, bool) {
+	val, ok := v.Attrs["data-"+name]
+	return val, ok
+}
+
 // SetLeft sets the left position of the view.
 func (v *View) SetLeft(left int) {
 	v.Left = left
@@ -312,6 +745,30 @@ func (v *View) SetMarginBottom(marginBottom int) {
 	v.Layout()
 }
 
+// SetPaddingLeft sets the left padding of the view.
+func (v *View) SetPaddingLeft(paddingLeft int) {
+	v.PaddingLeft = paddingLeft
+	v.Layout()
+}
+
+// SetPaddingTop sets the top padding of the view.
+func (v *View) SetPaddingTop(paddingTop int) {
+	v.PaddingTop = paddingTop
+	v.Layout()
+}
+
+// SetPaddingRight sets the right padding of the view.
+func (v *View) SetPaddingRight(paddingRight int) {
+	v.PaddingRight = paddingRight
+	v.Layout()
+}
+
+// SetPaddingBottom sets the bottom padding of the view.
+func (v *View) SetPaddingBottom(paddingBottom int) {
+	v.PaddingBottom = paddingBottom
+	v.Layout()
+}
+
 // SetPosition sets the position of the view.
 func (v *View) SetPosition(position Position) {
 	v.Position = position
@@ -372,29 +829,41 @@ func (v *View) SetHidden(hidden bool) {
 	v.Layout()
 }
 
+// SetDisabled sets the disabled property of the view.
+func (v *View) SetDisabled(disabled bool) {
+	v.Disabled = disabled
+	v.touch()
+	v.restyle()
+}
+
 func (v *View) Config() ViewConfig {
 	cfg := ViewConfig{
-		TagName:      v.TagName,
-		ID:           v.ID,
-		Left:         v.Left,
-		Right:        v.Right,
-		Top:          v.Top,
-		Bottom:       v.Bottom,
-		Width:        v.Width,
-		Height:       v.Height,
-		MarginLeft:   v.MarginLeft,
-		MarginTop:    v.MarginTop,
-		MarginRight:  v.MarginRight,
-		MarginBottom: v.MarginBottom,
-		Position:     v.Position,
-		Direction:    v.Direction,
-		Wrap:         v.Wrap,
-		Justify:      v.Justify,
-		AlignItems:   v.AlignItems,
-		AlignContent: v.AlignContent,
-		Grow:         v.Grow,
-		Shrink:       v.Shrink,
-		children:     []ViewConfig{},
+		TagName:       v.TagName,
+		ID:            v.ID,
+		Left:          v.Left,
+		Right:         v.Right,
+		Top:           v.Top,
+		Bottom:        v.Bottom,
+		Width:         v.Width,
+		Height:        v.Height,
+		MarginLeft:    v.MarginLeft,
+		MarginTop:     v.MarginTop,
+		MarginRight:   v.MarginRight,
+		MarginBottom:  v.MarginBottom,
+		PaddingLeft:   v.PaddingLeft,
+		PaddingTop:    v.PaddingTop,
+		PaddingRight:  v.PaddingRight,
+		PaddingBottom: v.PaddingBottom,
+		Position:      v.Position,
+		Direction:     v.Direction,
+		Wrap:          v.Wrap,
+		Justify:       v.Justify,
+		AlignItems:    v.AlignItems,
+		AlignContent:  v.AlignContent,
+		Grow:          v.Grow,
+		Shrink:        v.Shrink,
+		AspectRatio:   v.AspectRatio,
+		children:      []ViewConfig{},
 	}
 	for _, child := range v.getChildren() {
 		cfg.children = append(cfg.children, child.Config())
@@ -403,38 +872,48 @@ func (v *View) Config() ViewConfig {
 }
 
 func (v *View) handleDrawRoot(screen *ebiten.Image, b image.Rectangle) {
-	if h, ok := v.Handler.(DrawHandler); ok {
-		h.HandleDraw(screen, b)
-		return
-	}
-	if h, ok := v.Handler.(Drawer); ok {
-		h.Draw(screen, b, v)
+	v.drawBackground(screen, b)
+	switch h := v.Handler.(type) {
+	case batchDrawer:
+		h.drawBatch(screen, b, v)
+	case DrawHandler:
+		batch.Flush()
+		h.HandleDraw(screen, handlerFrame(h, b))
+	case Drawer:
+		batch.Flush()
+		h.Draw(screen, handlerFrame(h, b), v)
 	}
+	v.drawBorder(screen, b)
 }
 
 // This is for debugging and testing.
 type ViewConfig struct {
-	TagName      string
-	ID           string
-	Left         int
-	Right        *int
-	Top          int
-	Bottom       *int
-	Width        int
-	Height       int
-	MarginLeft   int
-	MarginTop    int
-	MarginRight  int
-	MarginBottom int
-	Position     Position
-	Direction    Direction
-	Wrap         FlexWrap
-	Justify      Justify
-	AlignItems   AlignItem
-	AlignContent AlignContent
-	Grow         float64
-	Shrink       float64
-	children     []ViewConfig
+	TagName       string
+	ID            string
+	Left          int
+	Right         *int
+	Top           int
+	Bottom        *int
+	Width         int
+	Height        int
+	MarginLeft    int
+	MarginTop     int
+	MarginRight   int
+	MarginBottom  int
+	PaddingLeft   int
+	PaddingTop    int
+	PaddingRight  int
+	PaddingBottom int
+	Position      Position
+	Direction     Direction
+	Wrap          FlexWrap
+	Justify       Justify
+	AlignItems    AlignItem
+	AlignContent  AlignContent
+	Grow          float64
+	Shrink        float64
+	AspectRatio   float64
+	children      []ViewConfig
 }
 
 func (cfg ViewConfig) Tree() string {
@@ -450,8 +929,8 @@ func (cfg ViewConfig) tree(indent string) string {
 	}
 	sb.WriteString("style=\"")
 	sb.WriteString(
-		fmt.Sprintf("left: %d, right: %d, top: %d, bottom: %d, width: %d, height: %d, marginLeft: %d, marginTop: %d, marginRight: %d, marginBottom: %d, position: %s, direction: %s, wrap: %s, justify: %s, alignItems: %s, alignContent: %s, grow: %f, shrink: %f",
-			cfg.Left, *cfg.Right, cfg.Top, *cfg.Bottom, cfg.Width, cfg.Height, cfg.MarginLeft, cfg.MarginTop, cfg.MarginRight, cfg.MarginBottom, cfg.Position, cfg.Direction, cfg.Wrap, cfg.Justify, cfg.AlignItems, cfg.AlignContent, cfg.Grow, cfg.Shrink))
+		fmt.Sprintf("left: %d, right: %d, top: %d, bottom: %d, width: %d, height: %d, marginLeft: %d, marginTop: %d, marginRight: %d, marginBottom: %d, paddingLeft: %d, paddingTop: %d, paddingRight: %d, paddingBottom: %d, position: %s, direction: %s, wrap: %s, justify: %s, alignItems: %s, alignContent: %s, grow: %f, shrink: %f",
+			cfg.Left, *cfg.Right, cfg.Top, *cfg.Bottom, cfg.Width, cfg.Height, cfg.MarginLeft, cfg.MarginTop, cfg.MarginRight, cfg.MarginBottom, cfg.PaddingLeft, cfg.PaddingTop, cfg.PaddingRight, cfg.PaddingBottom, cfg.Position, cfg.Direction, cfg.Wrap, cfg.Justify, cfg.AlignItems, cfg.AlignContent, cfg.Grow, cfg.Shrink))
 	sb.WriteString("\">\n")
 	for _, child := range cfg.children {
 		sb.WriteString(child.tree(indent + "  "))
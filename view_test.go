@@ -147,3 +147,104 @@ func TestUpdateOnlyOnce(t *testing.T) {
 	require.True(t, rootHandler.Times == 1)
 	require.True(t, nestedHandler.Times == 1)
 }
+
+type mutatingHandler struct {
+	CountingHandler
+	mutate func(v *View)
+}
+
+func (h *mutatingHandler) Update(v *View) {
+	h.Times++
+	if h.mutate != nil {
+		h.mutate(v)
+		h.mutate = nil
+	}
+}
+
+func TestMutationDuringUpdate(t *testing.T) {
+	handlers := [4]mutatingHandler{}
+	root := &View{Width: 100, Height: 100}
+	views := [4]*View{}
+	for i := range views {
+		views[i] = &View{Width: 10, Height: 10, Handler: &handlers[i]}
+	}
+	root.AddChild(views[0], views[1], views[2])
+	ids := func() []*View {
+		var ret []*View
+		for _, c := range root.children {
+			ret = append(ret, c.item)
+		}
+		return ret
+	}
+
+	// the children removed and added by a handler are changed after the
+	// traversal
+	handlers[0].mutate = func(v *View) {
+		require.True(t, root.RemoveChild(views[1]))
+		root.AddChild(views[3])
+		require.Len(t, root.children, 3)
+	}
+	root.Update()
+	require.Equal(t, []*View{views[0], views[2], views[3]}, ids())
+	require.Equal(t, []int{1, 1, 1, 0}, []int{handlers[0].Times, handlers[1].Times, handlers[2].Times, handlers[3].Times})
+	require.False(t, views[1].hasParent)
+	require.True(t, root.isDirty)
+
+	// also all of them, and each child is updated once
+	handlers[2].mutate = func(v *View) {
+		require.True(t, root.RemoveChild(views[3]))
+		require.False(t, root.RemoveChild(views[3]))
+		root.RemoveAll()
+		require.Nil(t, root.PopChild())
+	}
+	root.Update()
+	require.Empty(t, root.children)
+	require.Equal(t, []int{2, 1, 2, 1}, []int{handlers[0].Times, handlers[1].Times, handlers[2].Times, handlers[3].Times})
+
+	// the children are popped in a loop
+	root.AddChild(views[0], views[1], views[2])
+	var popped []*View
+	handlers[0].mutate = func(v *View) {
+		for c := root.PopChild(); c != nil; c = root.PopChild() {
+			popped = append(popped, c)
+		}
+	}
+	root.Update()
+	require.Equal(t, []*View{views[2], views[1], views[0]}, popped)
+	require.Empty(t, root.children)
+	require.Empty(t, root.mutations)
+
+	// out of the update, they are changed at once
+	root.AddChild(views[1])
+	require.Equal(t, []*View{views[1]}, ids())
+	require.Same(t, views[1], root.PopChild())
+	require.Empty(t, root.mutations)
+}
+
+type frameRecorder struct {
+	changes [][2]image.Rectangle
+}
+
+func (r *frameRecorder) HandleFrameChanged(old, new image.Rectangle, v *View) {
+	r.changes = append(r.changes, [2]image.Rectangle{old, new})
+}
+
+func TestFrameChanged(t *testing.T) {
+	rec := &frameRecorder{}
+	root := &View{Width: 100, Height: 100}
+	child := &View{Width: 10, Height: 20, Handler: rec}
+	root.AddChild(child)
+
+	root.Update()
+	require.Equal(t, [][2]image.Rectangle{{{}, image.Rect(0, 0, 10, 20)}}, rec.changes)
+
+	// not notified while the frame is the same
+	root.Update()
+	root.Layout()
+	root.Update()
+	require.Len(t, rec.changes, 1)
+
+	child.SetWidth(30)
+	root.Update()
+	require.Equal(t, [2]image.Rectangle{image.Rect(0, 0, 10, 20), image.Rect(0, 0, 30, 20)}, rec.changes[1])
+}
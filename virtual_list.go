@@ -0,0 +1,314 @@
+package furex
+
+import (
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// VirtualList is the handler of a view showing a long list of rows of the
+// same height, such as a leaderboard of thousands of entries. Only the
+// rows in sight and a few beyond have views, which are recycled for other
+// rows as the list scrolls, so the cost of the list does not depend on the
+// number of rows.
+//
+// The view should scroll (overflow: scroll) and lay out its children in a
+// column (direction: column). The list owns the children of the view: a
+// spacer before the rows, the views of the rows, and a spacer after them
+// keeping the height of the content. Scroll to a row with
+// View.ScrollTo(0, index*RowHeight).
+//
+// With a Selection, the rows clicked and tapped are selected in it, and
+// the views of the selected rows match the :selected pseudo-class.
+//
+// Paged data, e.g. from a server, is loaded as the list scrolls with
+// OnNearEnd, which is called when the end of the rows comes near. Footer is
+// shown after the rows while Loading is true, or the child of the view with
+// the slot="loading" attribute:
+//
+//	<view id="board" style="overflow: scroll; direction: column">
+//		<text slot="loading">Loading...</text>
+//	</view>
+//
+//	list.OnNearEnd = func() {
+//		list.Loading = true
+//		// appends the entries, then sets Count and Loading = false
+//		requestPage(len(entries))
+//	}
+type VirtualList struct {
+	// Count is the number of rows.
+	Count int
+	// RowHeight is the height of the rows.
+	RowHeight int
+	// Buffer is the number of rows with views before and after the
+	// visible ones, so the rows are shown while they scroll in. 2 is used
+	// if it is 0.
+	Buffer int
+	// NewRow creates a view for the rows. It is called only when there
+	// are not enough views to recycle.
+	NewRow func() *View
+	// BindRow sets the view to show the row at the index. It is called
+	// when a view is used for a row, and for all the rows with views after
+	// Refresh or when Count changes.
+	BindRow func(row *View, index int)
+	// Selection selects the rows clicked and tapped.
+	Selection *Selection
+	// RowID returns the id of the row at the index in the Selection. The
+	// index is the id if it is nil.
+	RowID func(index int) string
+	// OnNearEnd is called when the rows with views come within NearEnd
+	// rows of the end, e.g. to load the next page. It is called once for
+	// each Count, also when there are no rows.
+	OnNearEnd func()
+	// NearEnd is the number of the rows before the end where OnNearEnd is
+	// called. 5 is used if it is 0.
+	NearEnd int
+	// Loading shows the Footer, e.g. while the next page is loaded.
+	Loading bool
+	// Footer is the view shown after the rows while Loading is true.
+	Footer *View
+
+	view        *View
+	top, bottom *View
+	// first and last are the range of the rows with views.
+	first, last int
+	rows        map[int]*View
+	pool        []*View
+	count       int
+	stale       bool
+	gesture     selectionGesture
+	// selected is the version of the Selection the rows were styled with.
+	selected int
+	// nearEnd is the Count OnNearEnd was called for, or -1.
+	nearEnd int
+	// footer is the Footer among the children.
+	footer *View
+}
+
+var _ Updater = (*VirtualList)(nil)
+var _ ButtonHandler = (*VirtualList)(nil)
+var _ NotButton = (*VirtualList)(nil)
+
+const (
+	defaultVirtualListBuffer  = 2
+	defaultVirtualListNearEnd = 5
+)
+
+// Refresh binds the rows with views again, e.g. after the data of the rows
+// changed.
+func (l *VirtualList) Refresh() {
+	l.stale = true
+}
+
+// Row returns the view of the row at the index, or nil if the row has no
+// view because it is out of sight.
+func (l *VirtualList) Row(index int) *View {
+	return l.rows[index]
+}
+
+// rowID returns the id of the row at the index in the Selection.
+func (l *VirtualList) rowID(index int) string {
+	if l.RowID != nil {
+		return l.RowID(index)
+	}
+	return strconv.Itoa(index)
+}
+
+// rowAt returns the index of the row at the point, or -1.
+func (l *VirtualList) rowAt(x, y int) int {
+	if l.view == nil {
+		return -1
+	}
+	// the rows scroll with the content
+	o := l.view.ScrollOffset()
+	x, y = x+o.X, y+o.Y
+	for i, row := range l.rows {
+		if !row.Hidden && !row.Disabled && isInside(&row.frame, x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsButton implements NotButton. The rows are pressed only if the list has
+// a Selection.
+func (l *VirtualList) IsButton() bool {
+	return l.Selection != nil
+}
+
+// HandlePress implements ButtonHandler.
+func (l *VirtualList) HandlePress(x, y int, t ebiten.TouchID) {
+	if i := l.rowAt(x, y); i >= 0 {
+		l.gesture.press(i, t)
+	}
+}
+
+// HandleRelease implements ButtonHandler.
+func (l *VirtualList) HandleRelease(x, y int, isCancel bool) {
+	if !l.gesture.release(isCancel) || l.Selection == nil {
+		return
+	}
+	if i := l.rowAt(x, y); i >= 0 {
+		l.Selection.click(i, l.Count, l.rowID)
+	}
+}
+
+// Update implements Updater.
+func (l *VirtualList) Update(v *View) {
+	if l.RowHeight <= 0 || l.NewRow == nil {
+		return
+	}
+	l.view = v
+	if s := l.Selection; s != nil {
+		if v.scroll != nil && v.scroll.dragging {
+			// the rows are not clicked by the drags scrolling the list
+			l.gesture.cancel()
+		}
+		l.gesture.update(s, l.rowID)
+		if l.selected != s.version {
+			l.selected = s.version
+			v.restyle()
+		}
+	}
+	if l.top == nil {
+		l.top, l.bottom = &View{}, &View{}
+		l.rows, l.nearEnd = map[int]*View{}, -1
+		for _, c := range v.getChildren() {
+			if c.Attrs["slot"] == "loading" && l.Footer == nil {
+				l.Footer = c
+			}
+		}
+		v.removeAll()
+		v.addChild(l.top)
+		v.addChild(l.bottom)
+	}
+	footer := l.updateFooter(v)
+	buffer := l.Buffer
+	if buffer == 0 {
+		buffer = defaultVirtualListBuffer
+	}
+	offset := v.ScrollOffset().Y - v.PaddingTop
+	first := clampInt(floorDiv(offset, l.RowHeight)-buffer, 0, l.Count)
+	last := clampInt(floorDiv(offset+v.frame.Dy()+l.RowHeight-1, l.RowHeight)+buffer, first, l.Count)
+	l.notifyNearEnd(last)
+	stale := l.stale || l.Count != l.count
+	if first == l.first && last == l.last && !stale && !footer {
+		return
+	}
+	l.stale, l.count = false, l.Count
+
+	// the views of the rows out of the range are recycled
+	for i, row := range l.rows {
+		if i < first || i >= last {
+			delete(l.rows, i)
+			v.removeChild(row)
+			l.pool = append(l.pool, row)
+		} else if stale && l.BindRow != nil {
+			l.BindRow(row, i)
+		}
+	}
+	for i := first; i < last; i++ {
+		if _, ok := l.rows[i]; ok {
+			continue
+		}
+		var row *View
+		if n := len(l.pool); n > 0 {
+			row, l.pool = l.pool[n-1], l.pool[:n-1]
+		} else {
+			row = l.NewRow()
+			row.Height = l.RowHeight
+		}
+		if l.BindRow != nil {
+			l.BindRow(row, i)
+		}
+		l.rows[i] = row
+		v.addChild(row)
+	}
+	l.first, l.last = first, last
+	l.place(v)
+	if l.Selection != nil {
+		// the recycled rows are styled for their items
+		v.restyle()
+	}
+}
+
+// place orders the children of the view: the spacers around the rows in
+// order, the spacers taking the height of the rows without views.
+func (l *VirtualList) place(v *View) {
+	views := map[*View]*child{}
+	for _, c := range v.children {
+		views[c.item] = c
+	}
+	children := make([]*child, 0, l.last-l.first+2)
+	children = append(children, views[l.top])
+	for i := l.first; i < l.last; i++ {
+		children = append(children, views[l.rows[i]])
+	}
+	children = append(children, views[l.bottom])
+	if l.footer != nil {
+		children = append(children, views[l.footer])
+	}
+	v.children = children
+	l.top.SetHeight(l.first * l.RowHeight)
+	l.bottom.SetHeight((l.Count - l.last) * l.RowHeight)
+	v.Layout()
+}
+
+// updateFooter keeps the Footer among the children and shows it while
+// Loading is true. It returns true if the children changed.
+func (l *VirtualList) updateFooter(v *View) bool {
+	changed := false
+	if l.footer != l.Footer {
+		if l.footer != nil {
+			v.removeChild(l.footer)
+		}
+		l.footer = l.Footer
+		if l.footer != nil {
+			v.addChild(l.footer)
+		}
+		changed = true
+	}
+	if f := l.footer; f != nil && f.isDisplayed() != l.Loading {
+		if l.Loading {
+			f.SetDisplay(DisplayFlex)
+		} else {
+			f.SetDisplay(DisplayNone)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// notifyNearEnd calls OnNearEnd if the rows with views up to last come
+// near the end.
+func (l *VirtualList) notifyNearEnd(last int) {
+	if l.OnNearEnd == nil || l.Loading || l.nearEnd == l.Count {
+		return
+	}
+	n := l.NearEnd
+	if n == 0 {
+		n = defaultVirtualListNearEnd
+	}
+	if last+n >= l.Count {
+		l.nearEnd = l.Count
+		l.OnNearEnd()
+	}
+}
+
+// inListSelection returns true if the view is a row of a virtual list
+// selected in the Selection of the list.
+func (v *View) inListSelection() bool {
+	if !v.hasParent {
+		return false
+	}
+	l, ok := v.parent.Handler.(*VirtualList)
+	if !ok || l.Selection == nil {
+		return false
+	}
+	for i, row := range l.rows {
+		if row == v {
+			return l.Selection.IsSelected(l.rowID(i))
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package furex
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualList(t *testing.T) {
+	root := Parse(`<view style="width: 200px; height: 100px">
+		<view id="board" style="width: 200px; height: 100px; overflow: scroll; direction: column"></view>
+	</view>`, nil)
+	board := root.MustGetByID("board")
+	names := make([]string, 10000)
+	for i := range names {
+		names[i] = "player" + strconv.Itoa(i)
+	}
+	created := 0
+	list := &VirtualList{
+		Count:     len(names),
+		RowHeight: 20,
+		NewRow: func() *View {
+			created++
+			return &View{}
+		},
+		BindRow: func(row *View, i int) { row.Text = names[i] },
+	}
+	board.Handler = list
+	root.Update()
+	root.Update()
+
+	// only the visible rows and the buffer have views
+	require.Len(t, board.children, 7+2)
+	require.Equal(t, "player0", list.Row(0).Text)
+	require.Nil(t, list.Row(7))
+	require.Equal(t, 200000.0-100, board.scroll.maxY)
+
+	// the views are recycled as the list scrolls
+	board.ScrollTo(0, 1000)
+	root.Update()
+	root.Update()
+	require.Nil(t, list.Row(0))
+	require.Equal(t, "player50", list.Row(50).Text)
+	require.Equal(t, 1000, list.Row(50).frame.Min.Y)
+	require.Equal(t, 48, list.first)
+	require.Equal(t, 57, list.last)
+	require.Equal(t, 9, created)
+
+	// the rows are bound again after Refresh and when the count changes
+	names[50] = "renamed"
+	list.Refresh()
+	root.Update()
+	require.Equal(t, "renamed", list.Row(50).Text)
+	list.Count = 52
+	root.Update()
+	require.Nil(t, list.Row(52))
+	require.Equal(t, "player51", list.Row(51).Text)
+	require.Equal(t, 9, created)
+}
+
+func TestVirtualListNearEnd(t *testing.T) {
+	root := Parse(`<view style="width: 200px; height: 100px">
+		<view id="board" style="width: 200px; height: 100px; overflow: scroll; direction: column">
+			<view id="loading" slot="loading" style="height: 30px"></view>
+		</view>
+	</view>`, nil)
+	board := root.MustGetByID("board")
+	loading := root.MustGetByID("loading")
+	pages := 0
+	list := &VirtualList{
+		RowHeight: 20,
+		NewRow:    func() *View { return &View{} },
+	}
+	list.OnNearEnd = func() {
+		pages++
+		list.Loading = true
+	}
+	board.Handler = list
+	root.Update()
+	root.Update()
+
+	// the first page is requested while there are no rows, and the footer
+	// is shown while it loads
+	require.Equal(t, 1, pages)
+	require.Same(t, loading, list.Footer)
+	require.Same(t, loading, board.children[len(board.children)-1].item)
+	require.True(t, loading.isDisplayed())
+	require.Equal(t, 30, loading.frame.Dy())
+
+	list.Count, list.Loading = 20, false
+	root.Update()
+	root.Update()
+	require.False(t, loading.isDisplayed())
+	require.Equal(t, 1, pages)
+	require.Equal(t, 400.0-100, board.scroll.maxY)
+
+	// the next page is requested once when the end comes near
+	board.ScrollTo(0, 200)
+	root.Update()
+	require.Equal(t, 2, pages)
+	root.Update()
+	list.Loading = false
+	root.Update()
+	require.Equal(t, 2, pages)
+	list.Count = 40
+	root.Update()
+	root.Update()
+	require.Equal(t, 2, pages)
+	board.ScrollTo(0, 600)
+	root.Update()
+	require.Equal(t, 3, pages)
+}
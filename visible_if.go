@@ -0,0 +1,40 @@
+package furex
+
+// visibleCondition is the expression of the visible-if attribute and the
+// data it is evaluated against.
+type visibleCondition struct {
+	expr expr
+	data map[string]any
+}
+
+// SetVisibleIf hides the view while the expression is false for the data,
+// like the visible-if attribute, e.g. "player.hp < 0.3". The expression
+// is evaluated every update, so the view follows the values changed in
+// the data, such as the fields of the structs it points to. An empty
+// expression removes the condition.
+func (v *View) SetVisibleIf(cond string, data map[string]any) error {
+	if cond == "" {
+		v.visibleIf = nil
+		return nil
+	}
+	e, err := parseExpr(cond)
+	if err != nil {
+		return err
+	}
+	v.visibleIf = &visibleCondition{expr: e, data: data}
+	v.updateVisibility()
+	return nil
+}
+
+// updateVisibility hides or shows the views of the subtree with their
+// visible-if conditions.
+func (v *View) updateVisibility() {
+	if c := v.visibleIf; c != nil {
+		if hidden := !truthy(c.expr.eval(c.data)); hidden != v.Hidden {
+			v.SetHidden(hidden)
+		}
+	}
+	for _, c := range v.children {
+		c.item.updateVisibility()
+	}
+}
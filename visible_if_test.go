@@ -0,0 +1,48 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisibleIf(t *testing.T) {
+	type player struct{ HP float64 }
+	p := &player{HP: 1}
+	data := map[string]any{"player": p, "prompt": ""}
+	root := Parse(`<view style="width: 100px; height: 100px">
+		<view id="warning" visible-if="player.HP < 0.3" style="width: 10px; height: 10px"></view>
+		<view id="prompt" visible-if="prompt" style="width: 10px; height: 10px"></view>
+		<view id="other" style="width: 10px; height: 10px"></view>
+	</view>`, &ParseOptions{Data: data})
+	warning, prompt, other := root.MustGetByID("warning"), root.MustGetByID("prompt"), root.MustGetByID("other")
+	require.True(t, warning.Hidden)
+	require.True(t, prompt.Hidden)
+	root.Update()
+	// hidden views keep their space
+	require.Equal(t, 20, other.frame.Min.X)
+
+	// the conditions follow the data every update
+	p.HP = 0.2
+	data["prompt"] = "Press E"
+	root.Update()
+	require.False(t, warning.Hidden)
+	require.False(t, prompt.Hidden)
+
+	p.HP = 0.5
+	root.Update()
+	require.True(t, warning.Hidden)
+
+	// from Go
+	require.NoError(t, other.SetVisibleIf("!player", data))
+	require.True(t, other.Hidden)
+	require.NoError(t, other.SetVisibleIf("", nil))
+	other.SetHidden(false)
+	root.Update()
+	require.False(t, other.Hidden)
+	require.Error(t, other.SetVisibleIf("player.HP <", data))
+
+	// an invalid expression is reported
+	_, err := ParseStrict(`<view visible-if="a &&"></view>`, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,231 @@
+package furex
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval to check the files watched
+// by a Watcher.
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// Watcher reloads a view tree from an HTML file when the file or the
+// files it loads from ParseOptions.FS, such as stylesheets and included
+// fragments, change, for iterating on the UI without recompiling the
+// game. It is meant for development only. The files are compared by their
+// modification times and sizes, so files of an FS without them, such as
+// an embed.FS, only change with the build.
+//
+// The tree is rebuilt in place: Root keeps pointing to the same view, and
+// the handlers of the views are kept by id, so the handlers set from Go
+// code (e.g. callbacks of buttons) survive reloads.
+type Watcher struct {
+	// Root is the root of the view tree.
+	Root *View
+	// Interval is the interval to check the files. DefaultWatchInterval
+	// is used if it is zero.
+	Interval time.Duration
+	// OnReload is called after the tree is reloaded.
+	OnReload func(root *View)
+	// OnError is called when the document cannot be reloaded. The current
+	// tree is kept. The errors are logged if it is nil.
+	OnError func(err error)
+
+	path string
+	opts ParseOptions
+	// fsys is the FS of the files loaded by the document, and files the
+	// names of the files opened from it by the last parse.
+	fsys      fs.FS
+	files     map[string]bool
+	parsing   bool
+	stamp     string
+	lastCheck time.Time
+}
+
+// Watch parses the HTML file at the path and returns a Watcher reloading
+// it on change. The stylesheets linked by the document are loaded from the
+// directory of the file unless opts.FS is set. Call Update every frame,
+// before updating the tree.
+func Watch(path string, opts *ParseOptions) (*Watcher, error) {
+	w := &Watcher{path: path}
+	if opts != nil {
+		w.opts = *opts
+	}
+	w.fsys = w.opts.FS
+	if w.fsys == nil {
+		w.fsys = os.DirFS(filepath.Dir(path))
+	}
+	w.opts.FS = &recordFS{w}
+	root, err := w.parse()
+	if err != nil {
+		return nil, err
+	}
+	stamp, err := w.readStamp()
+	if err != nil {
+		return nil, err
+	}
+	w.Root, w.stamp, w.lastCheck = root, stamp, time.Now()
+	return w, nil
+}
+
+// Update checks the files at the interval and reloads the tree if they
+// have changed. It returns true if the tree has been reloaded.
+func (w *Watcher) Update() bool {
+	interval := w.Interval
+	if interval == 0 {
+		interval = DefaultWatchInterval
+	}
+	if time.Since(w.lastCheck) < interval {
+		return false
+	}
+	w.lastCheck = time.Now()
+	stamp, err := w.readStamp()
+	if err != nil {
+		w.error(err)
+		return false
+	}
+	if stamp == w.stamp {
+		return false
+	}
+	w.stamp = stamp
+	return w.Reload()
+}
+
+// Reload reloads the tree. It returns false if the document could not be
+// parsed, keeping the current tree.
+func (w *Watcher) Reload() bool {
+	root, err := w.parse()
+	// the document may load other files than before
+	if stamp, err := w.readStamp(); err == nil {
+		w.stamp = stamp
+	}
+	if err != nil {
+		w.error(err)
+		return false
+	}
+	keepHandlers(w.Root, root)
+	replaceView(w.Root, root)
+	if w.OnReload != nil {
+		w.OnReload(w.Root)
+	}
+	return true
+}
+
+func (w *Watcher) error(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+		return
+	}
+	log.Printf("furex: watch %s: %v", w.path, err)
+}
+
+// parse parses the document, recovering from the panics of Parse
+// on invalid markup.
+func (w *Watcher) parse() (root *View, err error) {
+	w.files, w.parsing = map[string]bool{}, true
+	defer func() {
+		w.parsing = false
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return ParseFS(os.DirFS(filepath.Dir(w.path)), filepath.Base(w.path), &w.opts)
+}
+
+// readStamp returns a stamp of the modification times and the sizes of
+// the document and the files it loaded, which changes when they do.
+func (w *Watcher) readStamp() (string, error) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return "", err
+	}
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%d:%d;", info.ModTime().UnixNano(), info.Size())
+	names := make([]string, 0, len(w.files))
+	for name := range w.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info, err := fs.Stat(w.fsys, name)
+		if err != nil {
+			// the file may be created later
+			fmt.Fprintf(sb, "%s:-;", name)
+			continue
+		}
+		fmt.Fprintf(sb, "%s:%d:%d;", name, info.ModTime().UnixNano(), info.Size())
+	}
+	return sb.String(), nil
+}
+
+// recordFS is the FS of the watcher, recording the names of the files
+// opened while the document is parsed. The images loaded when they are
+// drawn are not watched.
+type recordFS struct {
+	w *Watcher
+}
+
+func (r *recordFS) Open(name string) (fs.File, error) {
+	if r.w.parsing {
+		r.w.files[name] = true
+	}
+	return r.w.fsys.Open(name)
+}
+
+// keepHandlers sets the handlers of the views of the old tree to the views
+// of the new tree with the same id and tag.
+func keepHandlers(old, src *View) {
+	handlers := map[string]*View{}
+	var collect func(v *View)
+	collect = func(v *View) {
+		if v.ID != "" && v.Handler != nil {
+			handlers[v.ID] = v
+		}
+		for _, c := range v.children {
+			collect(c.item)
+		}
+	}
+	collect(old)
+	var apply func(v *View)
+	apply = func(v *View) {
+		if o, ok := handlers[v.ID]; ok && v.ID != "" && o.TagName == v.TagName {
+			v.Handler = o.Handler
+		}
+		for _, c := range v.children {
+			apply(c.item)
+		}
+	}
+	apply(src)
+}
+
+// replaceView replaces the properties and the children of the view with
+// those of src, so that the references to the view stay valid.
+func replaceView(dst, src *View) {
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := 0; i < dv.NumField(); i++ {
+		if dv.Type().Field(i).IsExported() {
+			dv.Field(i).Set(sv.Field(i))
+		}
+	}
+	dst.style = src.style
+	// the bindings of the view to the data follow the new document, with the
+	// keyed lists of its children
+	dst.keyedLists, dst.visibleIf, dst.styleBinding = src.keyedLists, src.visibleIf, src.styleBinding
+	src.keyedLists, src.visibleIf, src.styleBinding = nil, nil, nil
+	// the old views are not released as their handlers may be kept
+	dst.RemoveAll()
+	children := make([]*View, len(src.children))
+	for i, c := range src.children {
+		children[i] = c.item
+	}
+	src.RemoveAll()
+	dst.AddChild(children...)
+	dst.Layout()
+}
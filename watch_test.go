@@ -0,0 +1,131 @@
+package furex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ui.html")
+	write := func(name, src string, age time.Duration) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(src), 0o644))
+		mt := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(p, mt, mt))
+	}
+	write("ui.css", ".ok { width: 10px }", time.Hour)
+	write("ui.html", `<link rel="stylesheet" href="ui.css"><view id="root"><view id="ok" class="ok"></view></view>`, time.Hour)
+
+	w, err := Watch(path, nil)
+	require.NoError(t, err)
+	w.Interval = -1
+	root := w.Root
+	ok := root.MustGetByID("ok")
+	require.Equal(t, 10, ok.Width)
+	handler := &mockHandler{}
+	ok.Handler = handler
+	require.False(t, w.Update())
+
+	// the stylesheet changes
+	write("ui.css", ".ok { width: 20px }", time.Minute)
+	reloaded := 0
+	w.OnReload = func(*View) { reloaded++ }
+	require.True(t, w.Update())
+	require.Equal(t, 1, reloaded)
+	require.Same(t, root, w.Root)
+	ok2 := root.MustGetByID("ok")
+	require.NotSame(t, ok, ok2)
+	require.Equal(t, 20, ok2.Width)
+	require.Same(t, handler, ok2.Handler)
+
+	// the tree is kept on errors
+	var errs []error
+	w.OnError = func(err error) { errs = append(errs, err) }
+	require.NoError(t, os.Remove(path))
+	require.False(t, w.Update())
+	require.Len(t, errs, 1)
+	require.Same(t, ok2, root.MustGetByID("ok"))
+
+	write("ui.html", `<view id="root" style="height: 5px"><view id="ok"></view><view id="new"></view></view>`, 0)
+	require.True(t, w.Update())
+	require.Equal(t, 5, root.Height)
+	require.Len(t, root.children, 2)
+	require.Same(t, handler, root.MustGetByID("ok").Handler)
+	require.NotNil(t, root.MustGetByID("new"))
+}
+
+func TestWatchBindings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ui.html")
+	write := func(src string, age time.Duration) {
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+		mt := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(path, mt, mt))
+	}
+	write(`<view style="width: {{ size }}px"><view for="it in items" key="it">{{ it }}</view></view>`, time.Hour)
+	data := map[string]any{"items": []string{"a", "b"}, "size": 10}
+	w, err := Watch(path, &ParseOptions{Data: data})
+	require.NoError(t, err)
+	w.Interval = -1
+	root := w.Root
+	texts := func() []string {
+		var ret []string
+		for _, c := range root.children {
+			ret = append(ret, c.item.Text)
+		}
+		return ret
+	}
+	root.Update()
+	require.Equal(t, []string{"a", "b"}, texts())
+
+	// the list and the style of the root follow the data with the new
+	// document
+	write(`<view style="height: {{ size }}px"><view for="it in items" key="it">item {{ it }}</view></view>`, 0)
+	require.True(t, w.Update())
+	data["items"] = []string{"b", "c"}
+	data["size"] = 20
+	root.Update()
+	require.Equal(t, []string{"item b", "item c"}, texts())
+	require.Equal(t, 20, root.Height)
+	require.Equal(t, 0, root.Width)
+}
+
+func TestWatchFS(t *testing.T) {
+	dir, assets := t.TempDir(), t.TempDir()
+	write := func(dir, name, src string, age time.Duration) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(src), 0o644))
+		mt := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(p, mt, mt))
+	}
+	write(assets, "ui.css", ".ok { width: 10px }", time.Hour)
+	write(assets, "header.html", `<view id="header" style="height: 4px"></view>`, time.Hour)
+	write(assets, "unused.css", ".ok { width: 99px }", time.Hour)
+	write(dir, "ui.html", `<link rel="stylesheet" href="ui.css"><view><include src="header.html"/><view id="ok" class="ok"></view></view>`, time.Hour)
+
+	w, err := Watch(filepath.Join(dir, "ui.html"), &ParseOptions{FS: os.DirFS(assets)})
+	require.NoError(t, err)
+	w.Interval = -1
+	root := w.Root
+	require.Equal(t, 10, root.MustGetByID("ok").Width)
+	require.False(t, w.Update())
+
+	// the files loaded from the FS are watched
+	write(assets, "ui.css", ".ok { width: 20px }", time.Minute)
+	require.True(t, w.Update())
+	require.Equal(t, 20, root.MustGetByID("ok").Width)
+	require.False(t, w.Update())
+
+	write(assets, "header.html", `<view id="header" style="height: 8px"></view>`, time.Minute)
+	require.True(t, w.Update())
+	require.Equal(t, 8, root.MustGetByID("header").Height)
+
+	// but not the other files
+	write(assets, "unused.css", ".ok { width: 30px }", 0)
+	require.False(t, w.Update())
+}